@@ -23,6 +23,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/srcmap"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/fafdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -507,6 +508,59 @@ func (b *SimulatedBackend) EstimateGas(ctx context.Context, call ethereum.CallMs
 	return hi, nil
 }
 
+// TraceTransaction re-executes a mined transaction on top of its parent state,
+// returning its execution trace mapped to the sources of the given contracts.
+func (b *SimulatedBackend) TraceTransaction(ctx context.Context, txHash common.Hash, contracts ...*bind.SourceMap) (*srcmap.Trace, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	mapper, err := bind.NewSourceMapper(contracts...)
+	if err != nil {
+		return nil, err
+	}
+	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(b.database, txHash)
+	if tx == nil {
+		return nil, errTransactionDoesNotExist
+	}
+	block := b.blockchain.GetBlock(blockHash, blockNumber)
+	if block == nil {
+		return nil, errBlockDoesNotExist
+	}
+	statedb, err := b.blockchain.StateAt(b.blockchain.GetHeader(block.ParentHash(), blockNumber-1).Root)
+	if err != nil {
+		return nil, err
+	}
+	// Replay the preceding transactions of the block, tracing the requested one
+	var (
+		signer       = types.MakeSigner(b.config, block.Number())
+		blockContext = core.NewEVMBlockContext(block.Header(), b.blockchain, nil)
+	)
+	for i, tx := range block.Transactions()[:index+1] {
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			tracer *srcmap.Tracer
+			config vm.Config
+		)
+		if uint64(i) == index {
+			tracer = srcmap.NewTracer(mapper, nil)
+			config = vm.Config{Debug: true, Tracer: tracer}
+		}
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		vmenv := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), statedb, b.config, config)
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(tx.Gas())); err != nil {
+			return nil, err
+		}
+		if tracer != nil {
+			return tracer.Trace(), nil
+		}
+		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+	}
+	return nil, errTransactionDoesNotExist
+}
+
 // callContract implements common code between normal and pending contract calls.
 // state is modified during execution, make sure to copy it if necessary.
 func (b *SimulatedBackend) callContract(ctx context.Context, call ethereum.CallMsg, block *types.Block, stateDB *state.StateDB) (*core.ExecutionResult, error) {
@@ -1100,3 +1100,48 @@ func TestSimulatedBackend_CallContractRevert(t *testing.T) {
 		sim.Commit()
 	}
 }
+
+func TestSimulatedBackend_TraceTransaction(t *testing.T) {
+	testAddr := crypto.PubkeyToAddress(testKey.PublicKey)
+	sim := simTestBackend(testAddr)
+	defer sim.Close()
+	bgCtx := context.Background()
+
+	source := "contract Store {\n    function set() public {\n        x = 1;\n        revert();\n    }\n}\n"
+	store := &bind.SourceMap{
+		Name:          "Store",
+		RuntimeBin:    "0x6001600055600080fd", // PUSH1 1, PUSH1 0, SSTORE, PUSH1 0, DUP1, REVERT
+		SrcMapRuntime: "53:5:0:-;;;68:8;;",
+		SourceList:    []string{"Store.sol"},
+		Sources:       []string{source},
+	}
+	// Deploy the contract and send a transaction reverting in it
+	deploy := types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), common.FromHex("6009600c60003960096000f36001600055600080fd"))
+	deploy, _ = types.SignTx(deploy, types.HomesteadSigner{}, testKey)
+	if err := sim.SendTransaction(bgCtx, deploy); err != nil {
+		t.Fatalf("could not deploy contract: %v", err)
+	}
+	call := types.NewTransaction(1, crypto.CreateAddress(testAddr, 0), big.NewInt(0), 100000, big.NewInt(1), nil)
+	call, _ = types.SignTx(call, types.HomesteadSigner{}, testKey)
+	if err := sim.SendTransaction(bgCtx, call); err != nil {
+		t.Fatalf("could not send transaction: %v", err)
+	}
+	sim.Commit()
+
+	trace, err := sim.TraceTransaction(bgCtx, call.Hash(), store)
+	if err != nil {
+		t.Fatalf("could not trace transaction: %v", err)
+	}
+	if len(trace.Steps) != 6 {
+		t.Fatalf("step count mismatch: have %d, want 6", len(trace.Steps))
+	}
+	if len(trace.Reverts) != 1 || trace.Reverts[0] != 5 {
+		t.Fatalf("reverts mismatch: have %v, want [5]", trace.Reverts)
+	}
+	if loc := trace.Steps[5].Source; loc == nil || loc.String() != "Store.sol:4:9 (Store.set)" {
+		t.Errorf("revert location mismatch: have %v, want Store.sol:4:9 (Store.set)", loc)
+	}
+	if _, err := sim.TraceTransaction(bgCtx, common.Hash{1}); err != errTransactionDoesNotExist {
+		t.Errorf("unknown transaction error mismatch: have %v, want %v", err, errTransactionDoesNotExist)
+	}
+}
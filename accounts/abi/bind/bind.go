@@ -34,6 +34,14 @@ const (
 // enforces compile time type safety and naming convention opposed to having to
 // manually maintain hard coded strings that break on runtime.
 func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]string, pkg string, lang Lang, libs map[string]string, aliases map[string]string) (string, error) {
+	return BindWithSourceMaps(types, abis, bytecodes, fsigs, nil, pkg, lang, libs, aliases)
+}
+
+// BindWithSourceMaps generates the same wrapper as Bind, additionally embedding
+// the runtime source mappings of the contracts into Go bindings, so traces of
+// their execution can be mapped back to the sources. Contracts without a source
+// map may be given a nil entry.
+func BindWithSourceMaps(types []string, abis []string, bytecodes []string, fsigs []map[string]string, srcmaps []*SourceMap, pkg string, lang Lang, libs map[string]string, aliases map[string]string) (string, error) {
 	var (
 		// contracts is the map of each individual contract requested binding
 		contracts = make(map[string]*tmplContract)
@@ -173,6 +181,10 @@ func Bind(types []string, abis []string, bytecodes []string, fsigs []map[string]
 		if len(fsigs) > i {
 			contracts[types[i]].FuncSigs = fsigs[i]
 		}
+		// Runtime source mappings are likewise optional, only used by Go bindings
+		if len(srcmaps) > i && lang == LangGo {
+			contracts[types[i]].SourceMap = srcmaps[i]
+		}
 		// Parse library references.
 		for pattern, name := range libs {
 			matched, err := regexp.Match("__\\$"+pattern+"\\$__", []byte(contracts[types[i]].InputBin))
@@ -1745,6 +1745,39 @@ func TestGolangBindings(t *testing.T) {
 	}
 }
 
+// Tests that runtime source mappings are embedded into the Go bindings.
+func TestBindSourceMap(t *testing.T) {
+	srcmap := &SourceMap{
+		Name:          "Store",
+		RuntimeBin:    "0x6001600055",
+		SrcMapRuntime: "20:5:0:-;;",
+		SourceList:    []string{"Store.sol"},
+		Sources:       []string{"contract Store {\n    \"x\"\n}\n"},
+	}
+	code, err := BindWithSourceMaps([]string{"Store"}, []string{`[]`}, []string{"0x00"}, nil, []*SourceMap{srcmap}, "bindtest", LangGo, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	want := `var StoreSourceMap = &bind.SourceMap{
+	Name:          "Store",
+	RuntimeBin:    "0x6001600055",
+	SrcMapRuntime: "20:5:0:-;;",
+	SourceList:    []string{"Store.sol"},
+	Sources:       []string{"contract Store {\n    \"x\"\n}\n"},
+}`
+	if !strings.Contains(code, want) {
+		t.Errorf("source map not embedded, have:\n%s", code)
+	}
+	// Java bindings don't support source mappings, make sure they're omitted
+	code, err = BindWithSourceMaps([]string{"Store"}, []string{`[]`}, []string{"0x00"}, nil, []*SourceMap{srcmap}, "bindtest", LangJava, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate java binding: %v", err)
+	}
+	if strings.Contains(code, "SourceMap") {
+		t.Errorf("source map embedded into java binding")
+	}
+}
+
 // Tests that java binding generated by the binder is exactly matched.
 func TestJavaBindings(t *testing.T) {
 	var cases = []struct {
@@ -0,0 +1,67 @@
+
+
+package bind
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/core/vm/srcmap"
+)
+
+// SourceMap holds the solc source mapping of a contract's runtime code along with
+// the sources it refers to, used to map execution traces to the contract sources.
+type SourceMap struct {
+	Name          string   // Name of the contract
+	RuntimeBin    string   // Hex encoded runtime code, possibly with library placeholders
+	SrcMapRuntime string   // Source mapping of the runtime code
+	SourceList    []string // Names of the sources referenced by the source mapping
+	Sources       []string // Contents of the sources, in the order of the source list
+}
+
+// NewSourceMap collects the source mapping of a compiled contract's runtime code,
+// with the sources looked up as by compiler.Contract's SourceFiles. It returns nil
+// if the contract doesn't have any runtime code.
+func NewSourceMap(name string, contract *compiler.Contract, sources map[string]string) (*SourceMap, error) {
+	if contract.RuntimeCode == "" || contract.RuntimeCode == "0x" || contract.Info.SrcMapRuntime == "" {
+		return nil, nil
+	}
+	files, err := contract.SourceFiles(sources)
+	if err != nil {
+		return nil, err
+	}
+	m := &SourceMap{
+		Name:          name,
+		RuntimeBin:    contract.RuntimeCode,
+		SrcMapRuntime: contract.Info.SrcMapRuntime,
+		SourceList:    contract.Info.SourceList,
+		Sources:       make([]string, len(files)),
+	}
+	for i, file := range files {
+		m.Sources[i] = file.Content
+	}
+	return m, nil
+}
+
+// NewSourceMapper creates a mapper of execution traces to the sources of the
+// given contracts.
+func NewSourceMapper(maps ...*SourceMap) (*srcmap.Mapper, error) {
+	mapper := srcmap.NewMapper()
+	for _, m := range maps {
+		if len(m.Sources) != len(m.SourceList) {
+			return nil, fmt.Errorf("contract %s: %d sources for %d source names", m.Name, len(m.Sources), len(m.SourceList))
+		}
+		code, err := compiler.DecodeLinkedCode(m.RuntimeBin)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %v", m.Name, err)
+		}
+		files := make([]compiler.SourceFile, len(m.SourceList))
+		for i, name := range m.SourceList {
+			files[i] = compiler.SourceFile{Name: name, Content: m.Sources[i]}
+		}
+		if err := mapper.Add(m.Name, code, m.SrcMapRuntime, files); err != nil {
+			return nil, fmt.Errorf("contract %s: %v", m.Name, err)
+		}
+	}
+	return mapper, nil
+}
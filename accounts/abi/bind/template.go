@@ -18,6 +18,7 @@ type tmplContract struct {
 	InputABI    string                 // JSON ABI used as the input to generate the binding from
 	InputBin    string                 // Optional EVM bytecode used to generate deploy code from
 	FuncSigs    map[string]string      // Optional map: string signature -> 4-byte signature
+	SourceMap   *SourceMap             // Optional runtime source mapping for source-mapped traces
 	Constructor abi.Method             // Contract constructor for deploy parametrization
 	Calls       map[string]*tmplMethod // Contract calls that only read state data
 	Transacts   map[string]*tmplMethod // Contract calls that write state data
@@ -117,6 +118,17 @@ var (
 		}
 	{{end}}
 
+	{{if .SourceMap}}
+		// {{.Type}}SourceMap maps the execution of the contract's runtime code to its sources.
+		var {{.Type}}SourceMap = &bind.SourceMap{
+			Name:          {{printf "%q" .SourceMap.Name}},
+			RuntimeBin:    {{printf "%q" .SourceMap.RuntimeBin}},
+			SrcMapRuntime: {{printf "%q" .SourceMap.SrcMapRuntime}},
+			SourceList:    []string{ {{range .SourceMap.SourceList}}{{printf "%q" .}}, {{end}} },
+			Sources:       []string{ {{range .SourceMap.Sources}}{{printf "%q" .}}, {{end}} },
+		}
+	{{end}}
+
 	{{if .InputBin}}
 		// {{.Type}}Bin is the compiled bytecode used for deploying new contracts.
 		var {{.Type}}Bin = "0x{{.InputBin}}"
@@ -100,6 +100,7 @@ const (
 	WalletArrived WalletEventType = iota
 
 
+	WalletOpened
 	WalletDropped
 )
 
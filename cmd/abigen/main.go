@@ -98,6 +98,10 @@ var (
 		Name:  "alias",
 		Usage: "Comma separated aliases for function and event renaming, e.g. foo=bar",
 	}
+	srcmapFlag = cli.BoolFlag{
+		Name:  "srcmap",
+		Usage: "Embed the runtime source mappings and sources for source-mapped traces (Go, --sol or --combined-json only)",
+	}
 )
 
 func init() {
@@ -116,6 +120,7 @@ func init() {
 		outFlag,
 		langFlag,
 		aliasFlag,
+		srcmapFlag,
 	}
 	app.Action = utils.MigrateFlags(abigen)
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
@@ -126,6 +131,9 @@ func abigen(c *cli.Context) error {
 	if c.GlobalString(pkgFlag.Name) == "" {
 		utils.Fatalf("No destination package specified (--pkg)")
 	}
+	if c.GlobalBool(srcmapFlag.Name) && !c.GlobalIsSet(solFlag.Name) && !c.GlobalIsSet(jsonFlag.Name) {
+		utils.Fatalf("Source mappings require a Solidity source or combined-json (--sol, --combined-json)")
+	}
 	var lang bind.Lang
 	switch c.GlobalString(langFlag.Name) {
 	case "go":
@@ -144,6 +152,7 @@ func abigen(c *cli.Context) error {
 		bins    []string
 		types   []string
 		sigs    []map[string]string
+		srcmaps []*bind.SourceMap
 		libs    = make(map[string]string)
 		aliases = make(map[string]string)
 	)
@@ -237,6 +246,14 @@ func abigen(c *cli.Context) error {
 			nameParts := strings.Split(name, ":")
 			types = append(types, nameParts[len(nameParts)-1])
 
+			if c.GlobalBool(srcmapFlag.Name) {
+				srcmap, err := bind.NewSourceMap(nameParts[len(nameParts)-1], contract, nil)
+				if err != nil {
+					utils.Fatalf("Failed to collect source mapping of %s: %v", name, err)
+				}
+				srcmaps = append(srcmaps, srcmap)
+			}
+
 			libPattern := crypto.Keccak256Hash([]byte(name)).String()[2:36]
 			libs[libPattern] = nameParts[len(nameParts)-1]
 		}
@@ -254,7 +271,7 @@ func abigen(c *cli.Context) error {
 		}
 	}
 	// Generate the contract binding
-	code, err := bind.Bind(types, abis, bins, sigs, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
+	code, err := bind.BindWithSourceMaps(types, abis, bins, sigs, srcmaps, c.GlobalString(pkgFlag.Name), lang, libs, aliases)
 	if err != nil {
 		utils.Fatalf("Failed to generate ABI binding: %v", err)
 	}
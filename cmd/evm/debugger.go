@@ -0,0 +1,102 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/ethereum/go-ethereum/cmd/evm/internal/debugger"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	DebugRPCFlag = cli.StringFlag{
+		Name:  "rpc",
+		Usage: "Endpoint of a node exposing the debug API, to debug a historical transaction",
+	}
+	DebugTxFlag = cli.StringFlag{
+		Name:  "tx",
+		Usage: "Hash of the historical transaction to debug (requires --rpc)",
+	}
+)
+
+var debugCommand = cli.Command{
+	Action:    debugCmd,
+	Name:      "debug",
+	Usage:     "interactively debug evm code or a historical transaction",
+	ArgsUsage: "<code>",
+	Flags: []cli.Flag{
+		DebugRPCFlag,
+		DebugTxFlag,
+	},
+	Description: `
+The debug command executes the code configured as for the run command step by
+step, reading debugger commands from stdin. Execution can be paused at program
+counters, opcodes, storage writes and call depths, and every executed step is
+recorded so that it's possible to go backwards through the execution too.
+
+With --rpc and --tx, the trace of a historical transaction is retrieved through
+the debug_traceTransaction method of the node and stepped through instead.`,
+}
+
+func debugCmd(ctx *cli.Context) error {
+	if url := ctx.String(DebugRPCFlag.Name); url != "" {
+		if !ctx.IsSet(DebugTxFlag.Name) {
+			return errors.New("transaction hash required (--tx)")
+		}
+		hash, err := debugger.ParseTxHash(ctx.String(DebugTxFlag.Name))
+		if err != nil {
+			return err
+		}
+		src, err := debugger.AttachTransaction(context.Background(), url, hash)
+		if err != nil {
+			return err
+		}
+		debugger.NewSession(src, os.Stdin, os.Stdout).Run()
+		return nil
+	}
+	if ctx.IsSet(DebugTxFlag.Name) {
+		return errors.New("debugging a transaction requires a node (--rpc)")
+	}
+	// Commands are read from stdin, so neither the code nor the benchmark loop
+	// can rely on it
+	if ctx.GlobalString(CodeFileFlag.Name) == "-" {
+		return errors.New("code can't be read from stdin while debugging")
+	}
+	if ctx.GlobalBool(BenchFlag.Name) {
+		return errors.New("benchmarking is not supported while debugging")
+	}
+	tracer := debugger.NewTracer(&vm.LogConfig{
+		DisableMemory:     ctx.GlobalBool(DisableMemoryFlag.Name),
+		DisableStack:      ctx.GlobalBool(DisableStackFlag.Name),
+		DisableStorage:    ctx.GlobalBool(DisableStorageFlag.Name),
+		DisableReturnData: ctx.GlobalBool(DisableReturnDataFlag.Name),
+	})
+	errc := make(chan error, 1)
+	go func() {
+		errc <- runCode(ctx, tracer)
+		tracer.Finish()
+	}()
+	debugger.NewSession(tracer, os.Stdin, os.Stdout).Run()
+
+	// Let the execution run to completion if the session was quit early
+	tracer.Detach()
+	return <-errc
+}
@@ -17,6 +17,7 @@
 package main
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -26,11 +27,19 @@ import (
 	"gopkg.in/urfave/cli.v1"
 )
 
+var DisasmSourceFlag = cli.BoolFlag{
+	Name:  "source",
+	Usage: "Output assembly source that compiles back to the same binary",
+}
+
 var disasmCommand = cli.Command{
 	Action:    disasmCmd,
 	Name:      "disasm",
 	Usage:     "disassembles evm binary",
 	ArgsUsage: "<file>",
+	Flags: []cli.Flag{
+		DisasmSourceFlag,
+	},
 }
 
 func disasmCmd(ctx *cli.Context) error {
@@ -50,6 +59,14 @@ func disasmCmd(ctx *cli.Context) error {
 	}
 
 	code := strings.TrimSpace(in)
+	if ctx.Bool(DisasmSourceFlag.Name) {
+		bin, err := hex.DecodeString(strings.TrimPrefix(code, "0x"))
+		if err != nil {
+			return err
+		}
+		fmt.Print(asm.DisassembleSource(bin))
+		return nil
+	}
 	fmt.Printf("%v\n", code)
 	return asm.PrintDisassembled(code)
 }
@@ -0,0 +1,198 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/tests"
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	FillStateOutputFlag = cli.StringFlag{
+		Name:  "output.state",
+		Usage: "Determines where to put the filled state tests. Use 'stdout' or a file name",
+		Value: "stdout",
+	}
+	FillBlockchainOutputFlag = cli.StringFlag{
+		Name:  "output.blockchain",
+		Usage: "Determines where to put the filled blockchain tests, if any. Use 'stdout' or a file name",
+	}
+)
+
+var fillCommand = cli.Command{
+	Action:    fillCmd,
+	Name:      "fill",
+	Usage:     "generates state and blockchain tests from test fillers",
+	ArgsUsage: "<filler>",
+	Flags: []cli.Flag{
+		FillStateOutputFlag,
+		FillBlockchainOutputFlag,
+	},
+	Description: `
+The fill command executes the tests described by a JSON or YAML filler on every
+fork listed in their expectations, verifies the expected post-state of the
+accounts and writes the filled state tests, and optionally the equivalent
+blockchain tests, in the standard fixture format.
+
+A filler maps test names to their pre-state, environment and transaction matrix
+(as in a state test) along with a list of expectations:
+
+  "expect": [{
+    "indexes": {"data": 0, "gas": -1, "value": -1},
+    "network": [">=Byzantium"],
+    "result": {"<address>": {"balance": "...", "nonce": "...", "code": "...", "storage": {...}}}
+  }]
+
+Every combination of fork and transaction indexes must be covered by exactly one
+expectation.`,
+}
+
+func fillCmd(ctx *cli.Context) error {
+	if len(ctx.Args().First()) == 0 {
+		return errors.New("path-to-filler argument required")
+	}
+	// Configure the go-ethereum logger
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	// Load the fillers from the input file, converting YAML to JSON first
+	path := ctx.Args().First()
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yml" || ext == ".yaml" {
+		if src, err = yamlToJSON(src); err != nil {
+			return fmt.Errorf("invalid yaml filler: %v", err)
+		}
+	}
+	var fillers map[string]*tests.StateTestFiller
+	if err := json.Unmarshal(src, &fillers); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(fillers))
+	for name := range fillers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Fill all the tests, aborting on the first unmet expectation
+	var (
+		states = make(map[string]*tests.StateTest)
+		blocks = make(map[string]*tests.BlockTest)
+	)
+	for _, name := range names {
+		state, err := fillers[name].FillStateTest()
+		if err != nil {
+			return fmt.Errorf("test %s: %v", name, err)
+		}
+		states[name] = state
+		log.Info("Filled state test", "name", name)
+
+		if ctx.String(FillBlockchainOutputFlag.Name) == "" {
+			continue
+		}
+		filled, err := fillers[name].FillBlockTests()
+		if err != nil {
+			return fmt.Errorf("test %s: %v", name, err)
+		}
+		for subtest, block := range filled {
+			blocks[name+"_"+subtest] = block
+		}
+		log.Info("Filled blockchain tests", "name", name, "count", len(filled))
+	}
+	if err := writeFilled(ctx.String(FillStateOutputFlag.Name), states); err != nil {
+		return err
+	}
+	if out := ctx.String(FillBlockchainOutputFlag.Name); out != "" {
+		return writeFilled(out, blocks)
+	}
+	return nil
+}
+
+// writeFilled writes the filled tests as indented JSON to stdout or a file.
+func writeFilled(out string, filled interface{}) error {
+	blob, err := json.MarshalIndent(filled, "", "  ")
+	if err != nil {
+		return err
+	}
+	if out == "stdout" {
+		fmt.Println(string(blob))
+		return nil
+	}
+	return ioutil.WriteFile(out, append(blob, '\n'), 0644)
+}
+
+// yamlNode is a YAML value in its JSON representation. Scalars are kept in their
+// textual form so that e.g. hex quantities aren't reinterpreted as integers.
+type yamlNode struct {
+	value interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (n *yamlNode) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var mapping map[string]*yamlNode
+	if err := unmarshal(&mapping); err == nil {
+		n.value = mapping
+		return nil
+	}
+	var sequence []*yamlNode
+	if err := unmarshal(&sequence); err == nil {
+		n.value = sequence
+		return nil
+	}
+	var scalar interface{}
+	if err := unmarshal(&scalar); err != nil {
+		return err
+	}
+	if _, ok := scalar.(bool); ok || scalar == nil {
+		n.value = scalar
+		return nil
+	}
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+	n.value = text
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (n *yamlNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.value)
+}
+
+// yamlToJSON converts a YAML document into JSON.
+func yamlToJSON(src []byte) ([]byte, error) {
+	var root yamlNode
+	if err := yaml.Unmarshal(src, &root); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&root)
+}
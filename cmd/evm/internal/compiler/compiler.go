@@ -25,13 +25,14 @@ import (
 
 func Compile(fn string, src []byte, debug bool) (string, error) {
 	compiler := asm.NewCompiler(debug)
+	compiler.SetFile(fn)
 	compiler.Feed(asm.Lex(src, debug))
 
 	bin, compileErrors := compiler.Compile()
 	if len(compileErrors) > 0 {
-		// report errors
+		// report errors, which are prefixed by the file they're in
 		for _, err := range compileErrors {
-			fmt.Printf("%s:%v\n", fn, err)
+			fmt.Println(err)
 		}
 		return "", errors.New("compiling failed")
 	}
@@ -0,0 +1,145 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Breakpoint kinds.
+const (
+	breakPc     = "pc"     // Execution reaching a program counter
+	breakOp     = "op"     // Execution of an opcode
+	breakSstore = "sstore" // Storage write, optionally to a single slot
+	breakDepth  = "depth"  // Call depth changing to a value
+)
+
+// Breakpoint is a condition pausing the execution at the steps meeting it.
+type Breakpoint struct {
+	kind  string
+	pc    uint64
+	op    vm.OpCode
+	slot  *common.Hash
+	depth int
+}
+
+// parseBreakpoint parses a breakpoint definition, e.g. "pc 12", "op SSTORE",
+// "sstore 0x01" or "depth 2".
+func parseBreakpoint(args []string) (*Breakpoint, error) {
+	if len(args) == 0 {
+		return nil, errors.New("breakpoint kind required (pc, op, sstore, depth)")
+	}
+	b := &Breakpoint{kind: args[0]}
+	switch b.kind {
+	case breakPc:
+		if len(args) != 2 {
+			return nil, errors.New("usage: break pc <pc>")
+		}
+		pc, err := strconv.ParseUint(args[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pc %q", args[1])
+		}
+		b.pc = pc
+
+	case breakOp:
+		if len(args) != 2 {
+			return nil, errors.New("usage: break op <opcode>")
+		}
+		b.op = vm.StringToOp(strings.ToUpper(args[1]))
+		if b.op.String() != strings.ToUpper(args[1]) {
+			return nil, fmt.Errorf("unknown opcode %q", args[1])
+		}
+
+	case breakSstore:
+		if len(args) > 2 {
+			return nil, errors.New("usage: break sstore [<slot>]")
+		}
+		if len(args) == 2 {
+			slot, ok := math.ParseBig256(args[1])
+			if !ok {
+				return nil, fmt.Errorf("invalid slot %q", args[1])
+			}
+			hash := common.BigToHash(slot)
+			b.slot = &hash
+		}
+
+	case breakDepth:
+		if len(args) != 2 {
+			return nil, errors.New("usage: break depth <depth>")
+		}
+		depth, err := strconv.Atoi(args[1])
+		if err != nil || depth < 1 {
+			return nil, fmt.Errorf("invalid depth %q", args[1])
+		}
+		b.depth = depth
+
+	default:
+		return nil, fmt.Errorf("unknown breakpoint kind %q", b.kind)
+	}
+	return b, nil
+}
+
+// matches reports whether the breakpoint pauses at the given step, reached from
+// the previous one (nil at the start of the execution).
+func (b *Breakpoint) matches(prev, step *vm.StructLog) bool {
+	switch b.kind {
+	case breakPc:
+		return step.Pc == b.pc
+	case breakOp:
+		return step.Op == b.op
+	case breakSstore:
+		if step.Op != vm.SSTORE {
+			return false
+		}
+		if b.slot == nil {
+			return true
+		}
+		// Without a recorded stack the slot can't be checked, pause regardless
+		if len(step.Stack) == 0 {
+			return true
+		}
+		return common.BigToHash(step.Stack[len(step.Stack)-1]) == *b.slot
+	case breakDepth:
+		return step.Depth == b.depth && (prev == nil || prev.Depth != b.depth)
+	}
+	return false
+}
+
+func (b *Breakpoint) String() string {
+	switch b.kind {
+	case breakPc:
+		return fmt.Sprintf("pc %d", b.pc)
+	case breakOp:
+		return fmt.Sprintf("op %v", b.op)
+	case breakSstore:
+		if b.slot == nil {
+			return "sstore"
+		}
+		return fmt.Sprintf("sstore %#x", new(big.Int).SetBytes(b.slot[:]))
+	case breakDepth:
+		return fmt.Sprintf("depth %d", b.depth)
+	}
+	return b.kind
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Tests that breakpoint definitions are parsed and printed back canonically, and
+// that malformed ones are rejected.
+func TestParseBreakpoint(t *testing.T) {
+	tests := []struct {
+		def  string
+		want string // Canonical form, empty if the definition is invalid
+	}{
+		{"pc 12", "pc 12"},
+		{"pc 0x0c", "pc 12"},
+		{"op sstore", "op SSTORE"},
+		{"op PUSH1", "op PUSH1"},
+		{"sstore", "sstore"},
+		{"sstore 0x01", "sstore 0x1"},
+		{"sstore 16", "sstore 0x10"},
+		{"depth 2", "depth 2"},
+
+		{"", ""},
+		{"pc", ""},
+		{"pc 1 2", ""},
+		{"pc -1", ""},
+		{"op", ""},
+		{"op NOTANOP", ""},
+		{"sstore 1 2", ""},
+		{"sstore 0xzz", ""},
+		{"depth 0", ""},
+		{"depth deep", ""},
+		{"gas 100", ""},
+	}
+	for _, tt := range tests {
+		b, err := parseBreakpoint(strings.Fields(tt.def))
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q: invalid breakpoint accepted: %v", tt.def, b)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: failed to parse breakpoint: %v", tt.def, err)
+			continue
+		}
+		if b.String() != tt.want {
+			t.Errorf("%q: breakpoint mismatch: have %q, want %q", tt.def, b, tt.want)
+		}
+	}
+}
+
+// Tests which execution steps the breakpoints pause at.
+func TestBreakpointMatches(t *testing.T) {
+	var (
+		push   = &vm.StructLog{Pc: 0, Op: vm.PUSH1, Depth: 1}
+		sstore = &vm.StructLog{Pc: 4, Op: vm.SSTORE, Depth: 1, Stack: []*big.Int{big.NewInt(0x2a), big.NewInt(1)}}
+		blind  = &vm.StructLog{Pc: 4, Op: vm.SSTORE, Depth: 1}
+		nested = &vm.StructLog{Pc: 0, Op: vm.PUSH1, Depth: 2}
+		deeper = &vm.StructLog{Pc: 2, Op: vm.PUSH1, Depth: 2}
+	)
+	tests := []struct {
+		def        string
+		prev, step *vm.StructLog
+		want       bool
+	}{
+		{"pc 4", push, sstore, true},
+		{"pc 4", nil, push, false},
+		{"op SSTORE", push, sstore, true},
+		{"op SSTORE", nil, push, false},
+		{"sstore", push, sstore, true},
+		{"sstore", nil, push, false},
+		{"sstore 1", push, sstore, true},
+		{"sstore 2", push, sstore, false},
+		{"sstore 2", push, blind, true}, // Unknown slot, pause to be safe
+		{"depth 2", push, nested, true},
+		{"depth 2", nested, deeper, false}, // Only entering the depth pauses
+		{"depth 1", nil, push, true},
+	}
+	for i, tt := range tests {
+		b, err := parseBreakpoint(strings.Fields(tt.def))
+		if err != nil {
+			t.Fatalf("test %d: failed to parse breakpoint %q: %v", i, tt.def, err)
+		}
+		if have := b.matches(tt.prev, tt.step); have != tt.want {
+			t.Errorf("test %d: %q match mismatch: have %v, want %v", i, tt.def, have, tt.want)
+		}
+	}
+}
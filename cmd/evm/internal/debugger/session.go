@@ -0,0 +1,335 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// Package debugger implements an interactive EVM debugger, stepping through the
+// execution of a program forwards and backwards and pausing at breakpoints.
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+const helpText = `Commands:
+  step, s [n]          execute the next n steps (default 1)
+  back, b [n]          go back n steps (default 1)
+  continue, c          run until the next breakpoint or the end
+  reverse, rc          run backwards until the previous breakpoint or the start
+  break <kind> <arg>   add a breakpoint: pc <pc>, op <opcode>, sstore [<slot>], depth <depth>
+  breakpoints          list the breakpoints
+  delete <n>           remove the n-th breakpoint
+  stack                show the stack of the current step
+  memory               show the memory of the current step
+  storage              show the storage of the current contract known at the current step
+  where                show the current step
+  help                 show this help
+  quit, q              stop debugging`
+
+// Session is an interactive debugging session over the steps of an execution.
+// Every step is recorded as it's reached, allowing to move backwards through the
+// already executed ones.
+type Session struct {
+	src      Source
+	steps    []*vm.StructLog // Steps reached so far
+	finished bool            // Whether the source ran out of steps
+	pos      int             // Index of the current step, -1 before the first one
+
+	breaks []*Breakpoint
+
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewSession creates a debugging session reading commands from in and writing
+// the results to out.
+func NewSession(src Source, in io.Reader, out io.Writer) *Session {
+	return &Session{
+		src: src,
+		pos: -1,
+		in:  bufio.NewScanner(in),
+		out: out,
+	}
+}
+
+// Run executes commands until the user quits or the input is exhausted.
+func (s *Session) Run() {
+	fmt.Fprintln(s.out, "Type 'help' for the list of commands")
+	s.forward(1, false)
+
+	for {
+		fmt.Fprint(s.out, "> ")
+		if !s.in.Scan() {
+			fmt.Fprintln(s.out)
+			return
+		}
+		args := strings.Fields(s.in.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if err := s.execute(args[0], args[1:]); err != nil {
+			if err == io.EOF {
+				return
+			}
+			fmt.Fprintln(s.out, "Error:", err)
+		}
+	}
+}
+
+// execute runs a single command.
+func (s *Session) execute(cmd string, args []string) error {
+	switch cmd {
+	case "step", "s":
+		n, err := parseCount(args)
+		if err != nil {
+			return err
+		}
+		s.forward(n, false)
+
+	case "back", "b":
+		n, err := parseCount(args)
+		if err != nil {
+			return err
+		}
+		s.backward(n, false)
+
+	case "continue", "c":
+		s.forward(-1, true)
+
+	case "reverse", "rc":
+		s.backward(-1, true)
+
+	case "break":
+		b, err := parseBreakpoint(args)
+		if err != nil {
+			return err
+		}
+		s.breaks = append(s.breaks, b)
+		fmt.Fprintf(s.out, "Breakpoint %d: %v\n", len(s.breaks), b)
+
+	case "breakpoints":
+		if len(s.breaks) == 0 {
+			fmt.Fprintln(s.out, "No breakpoints")
+		}
+		for i, b := range s.breaks {
+			fmt.Fprintf(s.out, "%d: %v\n", i+1, b)
+		}
+
+	case "delete":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: delete <n>")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 || n > len(s.breaks) {
+			return fmt.Errorf("no breakpoint %q", args[0])
+		}
+		s.breaks = append(s.breaks[:n-1], s.breaks[n:]...)
+
+	case "stack":
+		if step := s.current(); step != nil {
+			s.printStack(step)
+		}
+
+	case "memory":
+		if step := s.current(); step != nil {
+			s.printMemory(step)
+		}
+
+	case "storage":
+		if step := s.current(); step != nil {
+			s.printStorage(step)
+		}
+
+	case "where":
+		s.printPosition()
+
+	case "help":
+		fmt.Fprintln(s.out, helpText)
+
+	case "quit", "q":
+		return io.EOF
+
+	default:
+		return fmt.Errorf("unknown command %q, type 'help' for the list of commands", cmd)
+	}
+	return nil
+}
+
+// parseCount parses the optional step count of a movement command.
+func parseCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid step count %q", args[0])
+	}
+	return n, nil
+}
+
+// current returns the current step, printing a notice if there's none.
+func (s *Session) current() *vm.StructLog {
+	if s.pos < 0 || s.pos >= len(s.steps) {
+		fmt.Fprintln(s.out, "Not at an execution step")
+		return nil
+	}
+	return s.steps[s.pos]
+}
+
+// fetch makes sure the step at index i was retrieved from the source, returning
+// false if the execution finished before reaching it.
+func (s *Session) fetch(i int) bool {
+	for len(s.steps) <= i && !s.finished {
+		step, ok := s.src.Next()
+		if !ok {
+			s.finished = true
+			break
+		}
+		s.steps = append(s.steps, step)
+	}
+	return i < len(s.steps)
+}
+
+// hit returns the breakpoint pausing the execution at step i, if any.
+func (s *Session) hit(i int) (int, *Breakpoint) {
+	var prev *vm.StructLog
+	if i > 0 {
+		prev = s.steps[i-1]
+	}
+	for n, b := range s.breaks {
+		if b.matches(prev, s.steps[i]) {
+			return n + 1, b
+		}
+	}
+	return 0, nil
+}
+
+// forward moves n steps ahead, or until a breakpoint or the end of the execution
+// if n is negative. With untilBreak set, breakpoints pause the movement.
+func (s *Session) forward(n int, untilBreak bool) {
+	for moved := 0; n < 0 || moved < n; moved++ {
+		if !s.fetch(s.pos + 1) {
+			s.pos = len(s.steps)
+			s.printResult()
+			return
+		}
+		s.pos++
+		if untilBreak {
+			if id, b := s.hit(s.pos); b != nil {
+				fmt.Fprintf(s.out, "Breakpoint %d (%v) hit\n", id, b)
+				break
+			}
+		}
+	}
+	s.printPosition()
+}
+
+// backward moves n steps back, or until a breakpoint or the start of the
+// execution if n is negative.
+func (s *Session) backward(n int, untilBreak bool) {
+	if s.pos <= 0 {
+		fmt.Fprintln(s.out, "Already at the first step")
+		return
+	}
+	for moved := 0; (n < 0 || moved < n) && s.pos > 0; moved++ {
+		s.pos--
+		if untilBreak {
+			if id, b := s.hit(s.pos); b != nil {
+				fmt.Fprintf(s.out, "Breakpoint %d (%v) hit\n", id, b)
+				break
+			}
+		}
+	}
+	s.printPosition()
+}
+
+// printPosition prints a summary of the current step.
+func (s *Session) printPosition() {
+	if s.pos >= len(s.steps) {
+		fmt.Fprintf(s.out, "Execution finished after %d steps\n", len(s.steps))
+		return
+	}
+	step := s.current()
+	if step == nil {
+		return
+	}
+	fmt.Fprintf(s.out, "[step %d] pc=%d op=%v gas=%d cost=%d depth=%d", s.pos, step.Pc, step.Op, step.Gas, step.GasCost, step.Depth)
+	if step.Err != nil {
+		fmt.Fprintf(s.out, " error=%v", step.Err)
+	}
+	fmt.Fprintln(s.out)
+}
+
+// printResult prints the outcome of the finished execution.
+func (s *Session) printResult() {
+	output, err := s.src.Result()
+	fmt.Fprintf(s.out, "Execution finished after %d steps\n", len(s.steps))
+	fmt.Fprintf(s.out, "Output: 0x%x\n", output)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+	}
+}
+
+// printStack prints the stack of a step, top item first.
+func (s *Session) printStack(step *vm.StructLog) {
+	if len(step.Stack) == 0 {
+		fmt.Fprintln(s.out, "Stack is empty")
+		return
+	}
+	for i := len(step.Stack) - 1; i >= 0; i-- {
+		fmt.Fprintf(s.out, "%4d: %x\n", len(step.Stack)-1-i, common.BigToHash(step.Stack[i]))
+	}
+}
+
+// printMemory prints a hex dump of the memory of a step, 32 bytes per line.
+func (s *Session) printMemory(step *vm.StructLog) {
+	if len(step.Memory) == 0 {
+		fmt.Fprintln(s.out, "Memory is empty")
+		return
+	}
+	for offset := 0; offset < len(step.Memory); offset += 32 {
+		end := offset + 32
+		if end > len(step.Memory) {
+			end = len(step.Memory)
+		}
+		fmt.Fprintf(s.out, "%04x: %x\n", offset, step.Memory[offset:end])
+	}
+}
+
+// printStorage prints the storage slots of the executing contract accessed up to
+// the step.
+func (s *Session) printStorage(step *vm.StructLog) {
+	if len(step.Storage) == 0 {
+		fmt.Fprintln(s.out, "No storage accessed")
+		return
+	}
+	keys := make([]common.Hash, 0, len(step.Storage))
+	for key := range step.Storage {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Compare(keys[i].Hex(), keys[j].Hex()) < 0
+	})
+	for _, key := range keys {
+		fmt.Fprintf(s.out, "%x: %x\n", key, step.Storage[key])
+	}
+}
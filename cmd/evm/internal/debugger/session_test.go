@@ -0,0 +1,144 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// testProgram is the bytecode the test steps were recorded from:
+// PUSH1 0x2a PUSH1 0x01 SSTORE PUSH1 0x02 PUSH1 0x00 MSTORE STOP
+var testProgram = common.FromHex("602a600155600260005200")
+
+// newTestTrace creates a recorded trace of executing testProgram.
+func newTestTrace() *recordedTrace {
+	slot := map[common.Hash]common.Hash{common.BigToHash(big.NewInt(1)): common.BigToHash(big.NewInt(0x2a))}
+	return &recordedTrace{
+		steps: []*vm.StructLog{
+			{Pc: 0, Op: vm.PUSH1, Gas: 100, GasCost: 3, Depth: 1},
+			{Pc: 2, Op: vm.PUSH1, Gas: 97, GasCost: 3, Depth: 1, Stack: []*big.Int{big.NewInt(0x2a)}},
+			{Pc: 4, Op: vm.SSTORE, Gas: 94, GasCost: 20000, Depth: 1, Stack: []*big.Int{big.NewInt(0x2a), big.NewInt(1)}, Storage: slot},
+			{Pc: 5, Op: vm.PUSH1, Gas: 74, GasCost: 3, Depth: 1, Storage: slot},
+			{Pc: 7, Op: vm.PUSH1, Gas: 71, GasCost: 3, Depth: 1, Stack: []*big.Int{big.NewInt(2)}, Storage: slot},
+			{Pc: 9, Op: vm.MSTORE, Gas: 68, GasCost: 6, Depth: 1, Stack: []*big.Int{big.NewInt(2), big.NewInt(0)}, Storage: slot},
+			{Pc: 10, Op: vm.STOP, Gas: 62, GasCost: 0, Depth: 1, Memory: common.LeftPadBytes([]byte{0x02}, 32), Storage: slot},
+		},
+		output: []byte{0xca, 0xfe},
+	}
+}
+
+// runSession executes the newline separated commands against the source and
+// returns the output of the session.
+func runSession(src Source, commands ...string) string {
+	var out bytes.Buffer
+	NewSession(src, strings.NewReader(strings.Join(commands, "\n")+"\n"), &out).Run()
+	return out.String()
+}
+
+// checkOutput ensures all the wanted snippets appear in the output, in order.
+func checkOutput(t *testing.T, output string, want ...string) {
+	t.Helper()
+
+	rest := output
+	for _, snippet := range want {
+		i := strings.Index(rest, snippet)
+		if i < 0 {
+			t.Fatalf("output missing %q after the preceding snippets:\n%s", snippet, output)
+		}
+		rest = rest[i+len(snippet):]
+	}
+}
+
+// Tests stepping forwards and backwards through the execution.
+func TestSessionStep(t *testing.T) {
+	output := runSession(newTestTrace(), "s", "s 2", "b", "back 2", "b", "s 100", "where")
+	checkOutput(t, output,
+		"[step 0] pc=0 op=PUSH1",
+		"[step 1] pc=2 op=PUSH1",
+		"[step 3] pc=5 op=PUSH1",
+		"[step 2] pc=4 op=SSTORE",
+		"[step 0] pc=0 op=PUSH1",
+		"Already at the first step",
+		"Execution finished after 7 steps", "Output: 0xcafe",
+		"Execution finished after 7 steps",
+	)
+}
+
+// Tests continuing to breakpoints in both directions.
+func TestSessionBreakpoints(t *testing.T) {
+	output := runSession(newTestTrace(),
+		"break op SSTORE",
+		"break pc 9",
+		"breakpoints",
+		"c",
+		"c",
+		"rc",
+		"delete 1",
+		"rc",
+		"delete 1",
+		"breakpoints",
+		"c",
+	)
+	checkOutput(t, output,
+		"Breakpoint 1: op SSTORE",
+		"Breakpoint 2: pc 9",
+		"1: op SSTORE", "2: pc 9",
+		"Breakpoint 1 (op SSTORE) hit", "[step 2] pc=4 op=SSTORE",
+		"Breakpoint 2 (pc 9) hit", "[step 5] pc=9 op=MSTORE",
+		"Breakpoint 1 (op SSTORE) hit", "[step 2] pc=4 op=SSTORE",
+		// With the SSTORE breakpoint gone, reversing runs to the start
+		"[step 0] pc=0 op=PUSH1",
+		"No breakpoints",
+		"Execution finished after 7 steps",
+	)
+}
+
+// Tests inspecting the stack, memory and storage of the current step.
+func TestSessionInspect(t *testing.T) {
+	output := runSession(newTestTrace(), "stack", "memory", "storage", "s 2", "stack", "storage", "s 4", "memory")
+	checkOutput(t, output,
+		"[step 0]", "Stack is empty", "Memory is empty", "No storage accessed",
+		"[step 2]",
+		"   0: "+common.BigToHash(big.NewInt(1)).Hex()[2:],
+		"   1: "+common.BigToHash(big.NewInt(0x2a)).Hex()[2:],
+		common.BigToHash(big.NewInt(1)).Hex()[2:]+": "+common.BigToHash(big.NewInt(0x2a)).Hex()[2:],
+		"[step 6] pc=10 op=STOP",
+		"0000: "+common.Bytes2Hex(common.LeftPadBytes([]byte{0x02}, 32)),
+	)
+}
+
+// Tests that invalid commands are reported without ending the session, and that
+// quitting stops reading commands.
+func TestSessionErrors(t *testing.T) {
+	output := runSession(newTestTrace(), "jump", "s 0", "s x", "break", "delete 3", "q", "s")
+	checkOutput(t, output,
+		`Error: unknown command "jump"`,
+		`Error: invalid step count "0"`,
+		`Error: invalid step count "x"`,
+		"Error: breakpoint kind required",
+		`Error: no breakpoint "3"`,
+	)
+	if strings.Contains(output, "[step 1]") {
+		t.Errorf("commands executed after quitting:\n%s", output)
+	}
+}
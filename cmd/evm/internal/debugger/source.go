@@ -0,0 +1,236 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// errTransactionFailed is the error reported for traced transactions that failed.
+var errTransactionFailed = errors.New("transaction failed")
+
+// Source provides the execution steps of a debugging session in order.
+type Source interface {
+	// Next blocks until the step following the previously returned one is
+	// available, returning false once the execution finished.
+	Next() (*vm.StructLog, bool)
+
+	// Result returns the output and error of the finished execution.
+	Result() ([]byte, error)
+}
+
+// Tracer is a vm.Tracer handing every execution step over to a debugging session,
+// pausing the EVM until the session requests the following step.
+type Tracer struct {
+	logger *vm.StructLogger
+
+	steps   chan struct{} // Signals a new step was recorded and the EVM paused
+	resume  chan struct{} // Resumes the paused EVM
+	done    chan struct{} // Closed when the execution finished
+	detach  chan struct{} // Closed when the session doesn't want more steps
+	waiting bool          // Whether the EVM is paused, session side only
+
+	finishOnce sync.Once
+	detachOnce sync.Once
+}
+
+// NewTracer creates a tracer recording the steps as configured.
+func NewTracer(cfg *vm.LogConfig) *Tracer {
+	return &Tracer{
+		logger: vm.NewStructLogger(cfg),
+		steps:  make(chan struct{}),
+		resume: make(chan struct{}),
+		done:   make(chan struct{}),
+		detach: make(chan struct{}),
+	}
+}
+
+func (t *Tracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return t.logger.CaptureStart(from, to, create, input, gas, value)
+}
+
+func (t *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	if err := t.logger.CaptureState(env, pc, op, gas, cost, memory, stack, rStack, rData, contract, depth, err); err != nil {
+		return err
+	}
+	// Hand the step over and wait for the session to request the next one
+	select {
+	case t.steps <- struct{}{}:
+	case <-t.detach:
+		return nil
+	}
+	select {
+	case <-t.resume:
+	case <-t.detach:
+	}
+	return nil
+}
+
+func (t *Tracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, contract *vm.Contract, depth int, err error) error {
+	return t.logger.CaptureFault(env, pc, op, gas, cost, memory, stack, rStack, contract, depth, err)
+}
+
+func (t *Tracer) CaptureEnd(output []byte, gasUsed uint64, tm time.Duration, err error) error {
+	return t.logger.CaptureEnd(output, gasUsed, tm, err)
+}
+
+// Finish signals the session that the execution finished. It must be called
+// once the traced execution returns.
+func (t *Tracer) Finish() {
+	t.finishOnce.Do(func() { close(t.done) })
+}
+
+// Detach lets the execution run to completion without pausing.
+func (t *Tracer) Detach() {
+	t.detachOnce.Do(func() { close(t.detach) })
+}
+
+// Next implements Source, resuming the EVM until it records the next step.
+func (t *Tracer) Next() (*vm.StructLog, bool) {
+	if t.waiting {
+		t.waiting = false
+		t.resume <- struct{}{}
+	}
+	select {
+	case <-t.steps:
+		t.waiting = true
+		logs := t.logger.StructLogs()
+		step := logs[len(logs)-1]
+		return &step, true
+	case <-t.done:
+		return nil, false
+	}
+}
+
+// Result implements Source.
+func (t *Tracer) Result() ([]byte, error) {
+	return t.logger.Output(), t.logger.Error()
+}
+
+// recordedTrace is a Source over the steps of an already finished execution.
+type recordedTrace struct {
+	steps  []*vm.StructLog
+	next   int
+	output []byte
+	err    error
+}
+
+// Next implements Source.
+func (r *recordedTrace) Next() (*vm.StructLog, bool) {
+	if r.next >= len(r.steps) {
+		return nil, false
+	}
+	r.next++
+	return r.steps[r.next-1], true
+}
+
+// Result implements Source.
+func (r *recordedTrace) Result() ([]byte, error) {
+	return r.output, r.err
+}
+
+// rpcTrace is the result of debug_traceTransaction with the default tracer.
+type rpcTrace struct {
+	Failed      bool   `json:"failed"`
+	ReturnValue string `json:"returnValue"`
+	StructLogs  []struct {
+		Pc      uint64             `json:"pc"`
+		Op      string             `json:"op"`
+		Gas     uint64             `json:"gas"`
+		GasCost uint64             `json:"gasCost"`
+		Depth   int                `json:"depth"`
+		Stack   *[]string          `json:"stack"`
+		Memory  *[]string          `json:"memory"`
+		Storage *map[string]string `json:"storage"`
+	} `json:"structLogs"`
+}
+
+// ParseTxHash parses the hex encoded hash of a transaction to debug, rejecting
+// malformed input instead of silently truncating or zero filling it.
+func ParseTxHash(input string) (common.Hash, error) {
+	var hash common.Hash
+	if err := hash.UnmarshalText([]byte(input)); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid transaction hash %q: %v", input, err)
+	}
+	return hash, nil
+}
+
+// AttachTransaction retrieves the trace of a historical transaction through the
+// debug API of a node, returning it as a Source to step through.
+func AttachTransaction(ctx context.Context, url string, hash common.Hash) (Source, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var trace rpcTrace
+	if err := client.CallContext(ctx, &trace, "debug_traceTransaction", hash, map[string]interface{}{}); err != nil {
+		return nil, err
+	}
+	src := &recordedTrace{steps: make([]*vm.StructLog, len(trace.StructLogs))}
+	for i, log := range trace.StructLogs {
+		step := &vm.StructLog{
+			Pc:      log.Pc,
+			Op:      vm.StringToOp(log.Op),
+			Gas:     log.Gas,
+			GasCost: log.GasCost,
+			Depth:   log.Depth,
+		}
+		if log.Stack != nil {
+			for _, item := range *log.Stack {
+				value, ok := new(big.Int).SetString(strings.TrimPrefix(item, "0x"), 16)
+				if !ok {
+					return nil, fmt.Errorf("step %d: invalid stack item %q", i, item)
+				}
+				step.Stack = append(step.Stack, value)
+			}
+		}
+		if log.Memory != nil {
+			mem, err := hex.DecodeString(strings.Join(*log.Memory, ""))
+			if err != nil {
+				return nil, fmt.Errorf("step %d: invalid memory: %v", i, err)
+			}
+			step.Memory, step.MemorySize = mem, len(mem)
+		}
+		if log.Storage != nil {
+			step.Storage = make(map[common.Hash]common.Hash)
+			for key, value := range *log.Storage {
+				step.Storage[common.HexToHash(key)] = common.HexToHash(value)
+			}
+		}
+		src.steps[i] = step
+	}
+	if src.output, err = hex.DecodeString(strings.TrimPrefix(trace.ReturnValue, "0x")); err != nil {
+		return nil, fmt.Errorf("invalid return value: %v", err)
+	}
+	if trace.Failed {
+		src.err = errTransactionFailed
+	}
+	return src, nil
+}
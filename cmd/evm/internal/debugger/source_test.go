@@ -0,0 +1,197 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package debugger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that a live execution is paused at every step while the session steps
+// through it, and that detaching lets it run to completion.
+func TestTracerSource(t *testing.T) {
+	tracer := NewTracer(&vm.LogConfig{})
+	errc := make(chan error, 1)
+	go func() {
+		_, _, err := runtime.Execute(testProgram, nil, &runtime.Config{
+			EVMConfig: vm.Config{Debug: true, Tracer: tracer},
+		})
+		tracer.Finish()
+		errc <- err
+	}()
+	output := runSession(tracer, "break op MSTORE", "c", "storage", "q")
+	checkOutput(t, output,
+		"Breakpoint 1 (op MSTORE) hit", "[step 5] pc=9 op=MSTORE",
+		common.BigToHash(big.NewInt(1)).Hex()[2:]+": "+common.BigToHash(big.NewInt(0x2a)).Hex()[2:],
+	)
+	// The session quit before the end, the execution must not stay paused
+	tracer.Detach()
+	if err := <-errc; err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	// Stepping through the whole execution yields every opcode
+	tracer = NewTracer(&vm.LogConfig{})
+	go func() {
+		runtime.Execute(testProgram, nil, &runtime.Config{
+			EVMConfig: vm.Config{Debug: true, Tracer: tracer},
+		})
+		tracer.Finish()
+	}()
+	var pcs []uint64
+	for {
+		step, ok := tracer.Next()
+		if !ok {
+			break
+		}
+		pcs = append(pcs, step.Pc)
+	}
+	if want := []uint64{0, 2, 4, 5, 7, 9, 10}; !reflect.DeepEqual(pcs, want) {
+		t.Errorf("step pc mismatch: have %v, want %v", pcs, want)
+	}
+}
+
+// testDebugAPI serves a canned transaction trace over the debug namespace.
+type testDebugAPI struct {
+	trace map[string]interface{}
+}
+
+func (api *testDebugAPI) TraceTransaction(hash common.Hash, config map[string]interface{}) (map[string]interface{}, error) {
+	return api.trace, nil
+}
+
+// attachTestTrace attaches to a transaction served by an RPC server returning
+// the given trace.
+func attachTestTrace(t *testing.T, trace map[string]interface{}) (Source, error) {
+	t.Helper()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("debug", &testDebugAPI{trace: trace}); err != nil {
+		t.Fatalf("failed to register debug API: %v", err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	return AttachTransaction(context.Background(), httpsrv.URL, common.Hash{0x01})
+}
+
+// Tests that the structured logs of a remote trace are converted into steps.
+func TestAttachTransaction(t *testing.T) {
+	src, err := attachTestTrace(t, map[string]interface{}{
+		"failed":      false,
+		"returnValue": "cafe",
+		"structLogs": []map[string]interface{}{
+			{"pc": 0, "op": "PUSH1", "gas": 100, "gasCost": 3, "depth": 1},
+			{
+				"pc": 9, "op": "MSTORE", "gas": 68, "gasCost": 6, "depth": 1,
+				"stack":   []string{"0x2", "0x0"},
+				"memory":  []string{"00000000000000000000000000000000000000000000000000000000000000ff"},
+				"storage": map[string]string{"0000000000000000000000000000000000000000000000000000000000000001": "000000000000000000000000000000000000000000000000000000000000002a"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to attach transaction: %v", err)
+	}
+	first, ok := src.Next()
+	if !ok || first.Pc != 0 || first.Op != vm.PUSH1 || first.Gas != 100 || first.GasCost != 3 || first.Depth != 1 {
+		t.Fatalf("first step mismatch: %+v", first)
+	}
+	if first.Stack != nil || first.Memory != nil || first.Storage != nil {
+		t.Errorf("first step state mismatch: stack %v, memory %x, storage %v", first.Stack, first.Memory, first.Storage)
+	}
+	second, ok := src.Next()
+	if !ok || second.Pc != 9 || second.Op != vm.MSTORE {
+		t.Fatalf("second step mismatch: %+v", second)
+	}
+	if have := fmt.Sprint(second.Stack); have != "[2 0]" {
+		t.Errorf("stack mismatch: have %v, want [2 0]", have)
+	}
+	if want := common.LeftPadBytes([]byte{0xff}, 32); !bytes.Equal(second.Memory, want) || second.MemorySize != 32 {
+		t.Errorf("memory mismatch: have %x (size %d), want %x", second.Memory, second.MemorySize, want)
+	}
+	if want := (map[common.Hash]common.Hash{common.BigToHash(big.NewInt(1)): common.BigToHash(big.NewInt(0x2a))}); !reflect.DeepEqual(second.Storage, want) {
+		t.Errorf("storage mismatch: have %v, want %v", second.Storage, want)
+	}
+	if step, ok := src.Next(); ok {
+		t.Errorf("unexpected step after the end: %+v", step)
+	}
+	if output, err := src.Result(); !bytes.Equal(output, []byte{0xca, 0xfe}) || err != nil {
+		t.Errorf("result mismatch: output %x, err %v", output, err)
+	}
+}
+
+// Tests that failed and malformed remote traces are reported.
+func TestAttachTransactionErrors(t *testing.T) {
+	src, err := attachTestTrace(t, map[string]interface{}{
+		"failed":      true,
+		"returnValue": "",
+		"structLogs":  []map[string]interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("failed to attach transaction: %v", err)
+	}
+	if _, err := src.Result(); err != errTransactionFailed {
+		t.Errorf("failure mismatch: have %v, want %v", err, errTransactionFailed)
+	}
+	malformed := []map[string]interface{}{
+		{"returnValue": "zz", "structLogs": []map[string]interface{}{}},
+		{"structLogs": []map[string]interface{}{{"op": "STOP", "stack": []string{"0xzz"}}}},
+		{"structLogs": []map[string]interface{}{{"op": "STOP", "memory": []string{"0"}}}},
+	}
+	for i, trace := range malformed {
+		if _, err := attachTestTrace(t, trace); err == nil {
+			t.Errorf("test %d: malformed trace accepted", i)
+		}
+	}
+}
+
+// Tests that transaction hashes are parsed strictly.
+func TestParseTxHash(t *testing.T) {
+	want := common.BytesToHash(common.Hex2Bytes("5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f42"))
+	for _, input := range []string{
+		"0x5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f42",
+		"fx5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f42",
+		"0X5AC2A4D3CE7E2EE5FB6A7E1F6EC0BDE8B4A5BA21D0B5C2C5B2A70E0B6D3A1F42",
+	} {
+		if hash, err := ParseTxHash(input); err != nil || hash != want {
+			t.Errorf("%q: have %x (err %v), want %x", input, hash, err, want)
+		}
+	}
+	for _, input := range []string{
+		"",
+		"0x",
+		"5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f42",
+		"0x5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f",
+		"0x5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1f4200",
+		"0x5ac2a4d3ce7e2ee5fb6a7e1f6ec0bde8b4a5ba21d0b5c2c5b2a70e0b6d3a1fzz",
+	} {
+		if hash, err := ParseTxHash(input); err == nil {
+			t.Errorf("%q: invalid hash accepted as %x", input, hash)
+		}
+	}
+}
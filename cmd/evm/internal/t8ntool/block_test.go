@@ -0,0 +1,155 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newTestBuildInput creates a block builder input with a minimal header.
+func newTestBuildInput() *bbInput {
+	return &bbInput{
+		Header: &header{
+			ParentHash: common.Hash{0x01},
+			Root:       common.Hash{0x02},
+			Difficulty: big.NewInt(16),
+			Number:     big.NewInt(1),
+			GasLimit:   8000000,
+			Time:       1000,
+		},
+		Txs:    []*types.Transaction{},
+		Ommers: []*types.Header{},
+	}
+}
+
+// Tests that the block built from the input derives the roots from the body and
+// that explicitly supplied header values take precedence.
+func TestBuildBlock(t *testing.T) {
+	input := newTestBuildInput()
+	block := input.ToBlock()
+	if block.TxHash() != types.EmptyRootHash || block.UncleHash() != types.EmptyUncleHash {
+		t.Errorf("derived roots mismatch: txs %x, ommers %x", block.TxHash(), block.UncleHash())
+	}
+	if block.Coinbase() != (common.Address{}) || block.ReceiptHash() != types.EmptyRootHash {
+		t.Errorf("default values mismatch: coinbase %x, receipts %x", block.Coinbase(), block.ReceiptHash())
+	}
+	var (
+		coinbase = common.Address{0xcc}
+		txHash   = common.Hash{0xaa}
+		nonce    = types.EncodeNonce(42)
+	)
+	input.Header.Coinbase, input.Header.TxHash, input.Header.Nonce = &coinbase, &txHash, &nonce
+
+	block = input.ToBlock()
+	if block.Coinbase() != coinbase || block.TxHash() != txHash || block.Nonce() != 42 {
+		t.Errorf("explicit values mismatch: coinbase %x, txs %x, nonce %d", block.Coinbase(), block.TxHash(), block.Nonce())
+	}
+}
+
+// Tests that blocks sealed with clique are signed by the given key and carry the
+// requested vote, and that explicit header values are never overwritten.
+func TestSealClique(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var (
+		signer    = crypto.PubkeyToAddress(key.PublicKey)
+		voted     = common.Address{0xdd}
+		authorize = true
+		vanity    = common.Hash{0x01, 0x02, 0x03}
+	)
+	input := newTestBuildInput()
+	input.Clique = &cliqueInput{Key: key, Voted: &voted, Authorize: &authorize, Vanity: vanity}
+
+	block, err := input.SealBlock(input.ToBlock())
+	if err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	if len(block.Extra()) != 32+65 || !bytes.Equal(block.Extra()[:32], vanity[:]) {
+		t.Errorf("extra data mismatch: %x", block.Extra())
+	}
+	if block.Coinbase() != voted || block.Nonce() != 0xffffffffffffffff {
+		t.Errorf("vote mismatch: coinbase %x, nonce %x", block.Coinbase(), block.Nonce())
+	}
+	engine := clique.New(params.AllCliqueProtocolChanges.Clique, rawdb.NewMemoryDatabase())
+	if author, err := engine.Author(block.Header()); err != nil || author != signer {
+		t.Errorf("signer mismatch: have %x, want %x, err %v", author, signer, err)
+	}
+	// Voting against drops the nonce
+	authorize = false
+	if block, err = input.SealBlock(input.ToBlock()); err != nil || block.Nonce() != 0 {
+		t.Errorf("vote against mismatch: nonce %x, err %v", block.Nonce(), err)
+	}
+	// Explicit values conflicting with the seal are rejected
+	tests := []func(h *header){
+		func(h *header) { h.Extra = []byte{0x01} },
+		func(h *header) { h.Coinbase = &common.Address{0xee} },
+		func(h *header) { nonce := types.EncodeNonce(1); h.Nonce = &nonce },
+	}
+	for i, tt := range tests {
+		input := newTestBuildInput()
+		input.Clique = &cliqueInput{Key: key, Voted: &voted, Authorize: &authorize}
+		tt(input.Header)
+
+		if _, err := input.SealBlock(input.ToBlock()); err == nil {
+			t.Errorf("test %d: conflicting header value accepted", i)
+		}
+	}
+}
+
+// Tests that blocks sealed with ethash carry a valid proof of work.
+func TestSealEthash(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethash-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := newTestBuildInput()
+	input.Ethash, input.EthashDir, input.PowMode = true, dir, ethash.ModeTest
+
+	unsealed := input.ToBlock()
+	block, err := input.SealBlock(unsealed)
+	if err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	if block.MixDigest() == (common.Hash{}) || block.Hash() == unsealed.Hash() {
+		t.Errorf("block not sealed: mix digest %x, nonce %d", block.MixDigest(), block.Nonce())
+	}
+	engine := ethash.NewTester(nil, false)
+	defer engine.Close()
+
+	if err := engine.VerifySeal(nil, block.Header()); err != nil {
+		t.Errorf("invalid seal: %v", err)
+	}
+	// A supplied nonce would be overwritten, reject it
+	nonce := types.EncodeNonce(1)
+	input.Header.Nonce = &nonce
+	if _, err := input.SealBlock(input.ToBlock()); err == nil {
+		t.Errorf("sealing over an explicit nonce accepted")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/tests"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// result is the validation outcome of a single transaction.
+type result struct {
+	Error        error
+	Address      common.Address
+	Hash         common.Hash
+	IntrinsicGas uint64
+}
+
+// MarshalJSON marshals as JSON with a hash.
+func (r *result) MarshalJSON() ([]byte, error) {
+	type xx struct {
+		Err          string          `json:"error,omitempty"`
+		Address      *common.Address `json:"address,omitempty"`
+		Hash         *common.Hash    `json:"hash,omitempty"`
+		IntrinsicGas hexutil.Uint64  `json:"intrinsicGas,omitempty"`
+	}
+	var out xx
+	if r.Error != nil {
+		out.Err = r.Error.Error()
+	}
+	if r.Address != (common.Address{}) {
+		out.Address = &r.Address
+	}
+	if r.Hash != (common.Hash{}) {
+		out.Hash = &r.Hash
+	}
+	out.IntrinsicGas = hexutil.Uint64(r.IntrinsicGas)
+	return json.Marshal(out)
+}
+
+// Transaction validates a list of RLP encoded transactions in isolation against
+// the rules of the configured fork, reporting the sender, hash and intrinsic gas
+// of each, or the reason it's invalid.
+func Transaction(ctx *cli.Context) error {
+	// Configure the go-ethereum logger
+	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
+	glogger.Verbosity(log.Lvl(ctx.Int(VerbosityFlag.Name)))
+	log.Root().SetHandler(glogger)
+
+	// Construct the chainconfig
+	chainConfig, _, err := tests.GetChainConfig(ctx.String(ForknameFlag.Name))
+	if err != nil {
+		return NewError(ErrorVMConfig, fmt.Errorf("failed constructing chain configuration: %v", err))
+	}
+	chainConfig.ChainID = big.NewInt(ctx.Int64(ChainIDFlag.Name))
+
+	// Load the transactions, either from stdin or from file
+	var (
+		txStr = ctx.String(InputTxsRlpFlag.Name)
+		input struct {
+			TxRlp hexutil.Bytes `json:"txs"`
+		}
+	)
+	if txStr == stdinSelector {
+		decoder := json.NewDecoder(os.Stdin)
+		if err := decoder.Decode(&input); err != nil {
+			return NewError(ErrorJson, fmt.Errorf("failed unmarshaling stdin: %v", err))
+		}
+	} else if err := readFile(txStr, "txs", &input.TxRlp); err != nil {
+		return err
+	}
+	var txs []*types.Transaction
+	if err := rlp.DecodeBytes(input.TxRlp, &txs); err != nil {
+		return NewError(ErrorRlp, fmt.Errorf("invalid transactions rlp: %v", err))
+	}
+	results := validateTransactions(chainConfig, txs)
+
+	out, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return NewError(ErrorJson, fmt.Errorf("failed marshalling output: %v", err))
+	}
+	os.Stdout.Write(out)
+	os.Stdout.WriteString("\n")
+	return nil
+}
+
+// validateTransactions checks the transactions against the rules of the given
+// chain configuration as of its first block.
+func validateTransactions(config *params.ChainConfig, txs []*types.Transaction) []result {
+	var (
+		number  = new(big.Int)
+		signer  = types.MakeSigner(config, number)
+		results []result
+	)
+	for _, tx := range txs {
+		r := result{Hash: tx.Hash()}
+
+		// Replay protected transactions are rejected by the pre EIP-155 signers
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			r.Error = err
+			results = append(results, r)
+			continue
+		}
+		r.Address = sender
+
+		// Check the intrinsic gas, the transaction needs to cover it
+		gas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, config.IsHomestead(number), config.IsIstanbul(number))
+		if err != nil {
+			r.Error = err
+			results = append(results, r)
+			continue
+		}
+		r.IntrinsicGas = gas
+		if tx.Gas() < gas {
+			r.Error = fmt.Errorf("%w: have %d, want %d", core.ErrIntrinsicGas, tx.Gas(), gas)
+		}
+		results = append(results, r)
+	}
+	return results
+}
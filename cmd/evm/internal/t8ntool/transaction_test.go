@@ -0,0 +1,132 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package t8ntool
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+// Tests that transactions are validated against the signature and intrinsic gas
+// rules of the selected fork.
+func TestValidateTransactions(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	var (
+		to        = common.Address{0xaa}
+		data      = []byte{0x00, 0x01}
+		homestead = types.HomesteadSigner{}
+		eip155    = types.NewEIP155Signer(big.NewInt(1))
+	)
+	sign := func(signer types.Signer, tx *types.Transaction) *types.Transaction {
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		return signed
+	}
+	var (
+		transfer  = sign(homestead, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil))
+		protected = sign(eip155, types.NewTransaction(0, to, big.NewInt(1), 21000, big.NewInt(1), nil))
+		calldata  = sign(homestead, types.NewTransaction(0, to, big.NewInt(1), 21050, big.NewInt(1), data))
+		creation  = sign(homestead, types.NewContractCreation(0, big.NewInt(0), 53000, big.NewInt(1), nil))
+	)
+	cases := []struct {
+		fork string
+		tx   *types.Transaction
+		gas  uint64
+		err  error // Expected error, nil for valid transactions
+		sig  bool  // Whether the signature is expected to be rejected
+	}{
+		{"Frontier", transfer, 21000, nil, false},
+		{"Homestead", transfer, 21000, nil, false},
+		{"Istanbul", transfer, 21000, nil, false},
+
+		// Replay protection is only understood from EIP-155 onwards
+		{"Homestead", protected, 0, nil, true},
+		{"EIP150", protected, 0, nil, true},
+		{"EIP158", protected, 21000, nil, false},
+		{"Berlin", protected, 21000, nil, false},
+
+		// Non-zero calldata got cheaper in Istanbul
+		{"Byzantium", calldata, 21000 + 4 + 68, core.ErrIntrinsicGas, false},
+		{"Istanbul", calldata, 21000 + 4 + 16, nil, false},
+
+		// Contract creations got more expensive in Homestead
+		{"Frontier", creation, 21000, nil, false},
+		{"Homestead", creation, 53000, nil, false},
+	}
+	for i, tt := range cases {
+		config, _, err := tests.GetChainConfig(tt.fork)
+		if err != nil {
+			t.Fatalf("test %d: failed to get %s config: %v", i, tt.fork, err)
+		}
+		config.ChainID = big.NewInt(1)
+
+		results := validateTransactions(config, []*types.Transaction{tt.tx})
+		if len(results) != 1 {
+			t.Fatalf("test %d: result count mismatch: have %d, want 1", i, len(results))
+		}
+		r := results[0]
+		if r.Hash != tt.tx.Hash() {
+			t.Errorf("test %d: hash mismatch: have %x, want %x", i, r.Hash, tt.tx.Hash())
+		}
+		if tt.sig {
+			if r.Error == nil || r.Address != (common.Address{}) {
+				t.Errorf("test %d: %s accepted signature: sender %x", i, tt.fork, r.Address)
+			}
+			continue
+		}
+		if r.Address != sender {
+			t.Errorf("test %d: sender mismatch: have %x, want %x", i, r.Address, sender)
+		}
+		if r.IntrinsicGas != tt.gas {
+			t.Errorf("test %d: %s intrinsic gas mismatch: have %d, want %d", i, tt.fork, r.IntrinsicGas, tt.gas)
+		}
+		if !errors.Is(r.Error, tt.err) {
+			t.Errorf("test %d: %s error mismatch: have %v, want %v", i, tt.fork, r.Error, tt.err)
+		}
+	}
+}
+
+// Tests the JSON encoding of the validation results.
+func TestResultMarshalling(t *testing.T) {
+	valid, err := json.Marshal(&result{Address: common.Address{0x01}, Hash: common.Hash{0x02}, IntrinsicGas: 21000})
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	if s := string(valid); strings.Contains(s, "error") || !strings.Contains(s, `"intrinsicGas":"0x5208"`) {
+		t.Errorf("valid result encoding mismatch: %s", s)
+	}
+	invalid, err := json.Marshal(&result{Hash: common.Hash{0x02}, Error: core.ErrIntrinsicGas})
+	if err != nil {
+		t.Fatalf("failed to marshal result: %v", err)
+	}
+	if s := string(invalid); strings.Contains(s, "address") || !strings.Contains(s, core.ErrIntrinsicGas.Error()) {
+		t.Errorf("invalid result encoding mismatch: %s", s)
+	}
+}
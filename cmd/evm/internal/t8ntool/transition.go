@@ -25,12 +25,14 @@ import (
 	"path"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/tests"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -39,9 +41,11 @@ const (
 	ErrorEVM              = 2
 	ErrorVMConfig         = 3
 	ErrorMissingBlockhash = 4
+	ErrorConfig           = 5
 
 	ErrorJson = 10
 	ErrorIO   = 11
+	ErrorRlp  = 12
 
 	stdinSelector = "stdin"
 )
@@ -76,21 +80,14 @@ func Main(ctx *cli.Context) error {
 	log.Root().SetHandler(glogger)
 
 	var (
-		err     error
-		tracer  vm.Tracer
-		baseDir = ""
+		err    error
+		tracer vm.Tracer
 	)
 	var getTracer func(txIndex int, txHash common.Hash) (vm.Tracer, error)
 
-	// If user specified a basedir, make sure it exists
-	if ctx.IsSet(OutputBasedir.Name) {
-		if base := ctx.String(OutputBasedir.Name); len(base) > 0 {
-			err := os.MkdirAll(base, 0755) // //rw-r--r--
-			if err != nil {
-				return NewError(ErrorIO, fmt.Errorf("failed creating output basedir: %v", err))
-			}
-			baseDir = base
-		}
+	baseDir, err := createBasedir(ctx)
+	if err != nil {
+		return NewError(ErrorIO, fmt.Errorf("failed creating output basedir: %v", err))
 	}
 	if ctx.Bool(TraceFlag.Name) {
 		// Configure the EVM logger
@@ -209,8 +206,38 @@ func Main(ctx *cli.Context) error {
 	//postAlloc := state.DumpGenesisFormat(false, false, false)
 	collector := make(Alloc)
 	state.DumpToCollector(collector, false, false, false, nil, -1)
-	return dispatchOutput(ctx, baseDir, result, collector)
 
+	// Assemble the block body out of the transactions included
+	rejected := make(map[int]bool)
+	for _, index := range result.Rejected {
+		rejected[index] = true
+	}
+	var included types.Transactions
+	for i, tx := range txs {
+		if !rejected[i] {
+			included = append(included, tx)
+		}
+	}
+	body, err := rlp.EncodeToBytes(included)
+	if err != nil {
+		return NewError(ErrorRlp, fmt.Errorf("failed encoding block body: %v", err))
+	}
+	return dispatchOutput(ctx, baseDir, result, collector, body)
+}
+
+// createBasedir makes sure the output basedir requested by the user exists,
+// returning its path.
+func createBasedir(ctx *cli.Context) (string, error) {
+	baseDir := ""
+	if ctx.IsSet(OutputBasedir.Name) {
+		if base := ctx.String(OutputBasedir.Name); len(base) > 0 {
+			if err := os.MkdirAll(base, 0755); err != nil { // //rw-r--r--
+				return "", err
+			}
+			baseDir = base
+		}
+	}
+	return baseDir, nil
 }
 
 type Alloc map[common.Address]core.GenesisAccount
@@ -249,7 +276,7 @@ func saveFile(baseDir, filename string, data interface{}) error {
 
 // dispatchOutput writes the output data to either stderr or stdout, or to the specified
 // files
-func dispatchOutput(ctx *cli.Context, baseDir string, result *ExecutionResult, alloc Alloc) error {
+func dispatchOutput(ctx *cli.Context, baseDir string, result *ExecutionResult, alloc Alloc, body hexutil.Bytes) error {
 	stdOutObject := make(map[string]interface{})
 	stdErrObject := make(map[string]interface{})
 	dispatch := func(baseDir, fName, name string, obj interface{}) error {
@@ -271,6 +298,11 @@ func dispatchOutput(ctx *cli.Context, baseDir string, result *ExecutionResult, a
 	if err := dispatch(baseDir, ctx.String(OutputResultFlag.Name), "result", result); err != nil {
 		return err
 	}
+	if name := ctx.String(OutputBodyFlag.Name); name != "" {
+		if err := dispatch(baseDir, name, "body", body); err != nil {
+			return err
+		}
+	}
 	if len(stdOutObject) > 0 {
 		b, err := json.MarshalIndent(stdOutObject, "", " ")
 		if err != nil {
@@ -131,9 +131,21 @@ var (
 	}
 	EVMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.evm",
-		Usage: "External EVM configuration (default = built-in interpreter)",
+		Usage: "Registered EVM interpreter tried before the built-in one, as name[:options]",
 		Value: "",
 	}
+	SourceMapFlag = cli.StringFlag{
+		Name:  "srcmap",
+		Usage: "solc --combined-json output to map the trace to the contract sources",
+	}
+	ProfileFlag = cli.BoolFlag{
+		Name:  "profile",
+		Usage: "print the gas and time spent per opcode and contract",
+	}
+	ProfileCollapsedFlag = cli.StringFlag{
+		Name:  "profile.collapsed",
+		Usage: "file to write the gas spent per call path to, in collapsed stack format for flame graphs",
+	}
 )
 
 var stateTransitionCommand = cli.Command{
@@ -156,6 +168,39 @@ var stateTransitionCommand = cli.Command{
 		t8ntool.ChainIDFlag,
 		t8ntool.RewardFlag,
 		t8ntool.VerbosityFlag,
+		t8ntool.OutputBodyFlag,
+	},
+}
+
+var transactionCommand = cli.Command{
+	Name:    "transaction",
+	Aliases: []string{"t9n"},
+	Usage:   "performs transaction validation",
+	Action:  t8ntool.Transaction,
+	Flags: []cli.Flag{
+		t8ntool.InputTxsRlpFlag,
+		t8ntool.ChainIDFlag,
+		t8ntool.ForknameFlag,
+		t8ntool.VerbosityFlag,
+	},
+}
+
+var blockBuilderCommand = cli.Command{
+	Name:    "block-builder",
+	Aliases: []string{"b11r"},
+	Usage:   "builds a block",
+	Action:  t8ntool.BuildBlock,
+	Flags: []cli.Flag{
+		t8ntool.OutputBasedir,
+		t8ntool.OutputBlockFlag,
+		t8ntool.InputHeaderFlag,
+		t8ntool.InputOmmersFlag,
+		t8ntool.InputTxsRlpFlag,
+		t8ntool.SealCliqueFlag,
+		t8ntool.SealEthashFlag,
+		t8ntool.SealEthashDirFlag,
+		t8ntool.SealEthashModeFlag,
+		t8ntool.VerbosityFlag,
 	},
 }
 
@@ -185,13 +230,21 @@ func init() {
 		DisableStorageFlag,
 		DisableReturnDataFlag,
 		EVMInterpreterFlag,
+		SourceMapFlag,
+		ProfileFlag,
+		ProfileCollapsedFlag,
 	}
 	app.Commands = []cli.Command{
 		compileCommand,
 		disasmCommand,
 		runCommand,
+		debugCommand,
 		stateTestCommand,
+		fillCommand,
 		stateTransitionCommand,
+		transactionCommand,
+		blockBuilderCommand,
+		statelessCommand,
 	}
 	cli.CommandHelpTemplate = flags.OriginCommandHelpTemplate
 }
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/core/vm/srcmap"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"gopkg.in/urfave/cli.v1"
@@ -104,6 +105,12 @@ func timedExec(bench bool, execFunc func() ([]byte, uint64, error)) (output []by
 }
 
 func runCmd(ctx *cli.Context) error {
+	return runCode(ctx, nil)
+}
+
+// runCode executes the code configured by the global flags, tracing it with the
+// given tracer instead of the flag selected one if set.
+func runCode(ctx *cli.Context, override vm.Tracer) error {
 	glogger := log.NewGlogHandler(log.StreamHandler(os.Stderr, log.TerminalFormat(false)))
 	glogger.Verbosity(log.Lvl(ctx.GlobalInt(VerbosityFlag.Name)))
 	log.Root().SetHandler(glogger)
@@ -118,14 +125,35 @@ func runCmd(ctx *cli.Context) error {
 	var (
 		tracer        vm.Tracer
 		debugLogger   *vm.StructLogger
+		sourceTracer  *srcmap.Tracer
+		profiler      *vm.Profiler
 		statedb       *state.StateDB
 		chainConfig   *params.ChainConfig
 		sender        = common.BytesToAddress([]byte("sender"))
 		receiver      = common.BytesToAddress([]byte("receiver"))
 		genesisConfig *core.Genesis
 	)
-	if ctx.GlobalBool(MachineFlag.Name) {
+	if override != nil {
+		tracer = override
+	} else if ctx.GlobalBool(MachineFlag.Name) {
 		tracer = vm.NewJSONLogger(logconfig, os.Stdout)
+	} else if path := ctx.GlobalString(SourceMapFlag.Name); path != "" {
+		combined, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		mapper, err := srcmap.FromCombinedJSON(combined, nil)
+		if err != nil {
+			return fmt.Errorf("invalid source map: %v", err)
+		}
+		// The output is printed along with the mapped trace, not by the logger
+		cfg := *logconfig
+		cfg.Debug = false
+		sourceTracer = srcmap.NewTracer(mapper, &cfg)
+		tracer = sourceTracer
+	} else if ctx.GlobalBool(ProfileFlag.Name) || ctx.GlobalString(ProfileCollapsedFlag.Name) != "" {
+		profiler = vm.NewProfiler()
+		tracer = profiler
 	} else if ctx.GlobalBool(DebugFlag.Name) {
 		debugLogger = vm.NewStructLogger(logconfig)
 		tracer = debugLogger
@@ -212,7 +240,7 @@ func runCmd(ctx *cli.Context) error {
 		BlockNumber: new(big.Int).SetUint64(genesisConfig.Number),
 		EVMConfig: vm.Config{
 			Tracer:         tracer,
-			Debug:          ctx.GlobalBool(DebugFlag.Name) || ctx.GlobalBool(MachineFlag.Name),
+			Debug:          tracer != nil,
 			EVMInterpreter: ctx.GlobalString(EVMInterpreterFlag.Name),
 		},
 	}
@@ -286,6 +314,27 @@ func runCmd(ctx *cli.Context) error {
 		f.Close()
 	}
 
+	if sourceTracer != nil {
+		fmt.Fprintln(os.Stderr, "#### SOURCE TRACE ####")
+		srcmap.WriteTrace(os.Stderr, sourceTracer.Trace())
+	}
+	if profiler != nil {
+		profile := profiler.Profile()
+		if ctx.GlobalBool(ProfileFlag.Name) {
+			fmt.Fprintln(os.Stderr, "#### PROFILE ####")
+			vm.WriteProfile(os.Stderr, profile)
+		}
+		if path := ctx.GlobalString(ProfileCollapsedFlag.Name); path != "" {
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			vm.WriteCollapsedStacks(f, profile)
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
 	if ctx.GlobalBool(DebugFlag.Name) {
 		if debugLogger != nil {
 			fmt.Fprintln(os.Stderr, "#### TRACE ####")
@@ -302,7 +351,7 @@ allocations:     %d
 allocated bytes: %d
 `, initialGas-leftOverGas, stats.time, stats.allocs, stats.bytesAllocated)
 	}
-	if tracer == nil {
+	if tracer == nil || sourceTracer != nil || profiler != nil {
 		fmt.Printf("0x%x\n", output)
 		if err != nil {
 			fmt.Printf(" error: %v\n", err)
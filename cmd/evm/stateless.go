@@ -0,0 +1,124 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var statelessCommand = cli.Command{
+	Action:    statelessCmd,
+	Name:      "stateless",
+	Usage:     "verifies a block using only its execution witness",
+	ArgsUsage: "<block> <witness>",
+	Description: `
+The stateless command re-executes a block on top of the parent state contained in
+its execution witness (as returned by debug_getBlockWitness) and confirms that the
+resulting receipts and post-state root match the block header. Both files may be
+either raw or hex encoded RLP.
+
+The chain configuration is taken from the genesis file given by --prestate, the
+mainnet configuration is used by default.`,
+}
+
+// StatelessResult contains the outcome of a stateless block verification.
+type StatelessResult struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Root   common.Hash `json:"root"`
+	Pass   bool        `json:"pass"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// readRLPFile reads a raw or hex encoded RLP blob from a file.
+func readRLPFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if blob, err := hexutil.Decode(strings.TrimSpace(string(data))); err == nil {
+		return blob, nil
+	}
+	return data, nil
+}
+
+func statelessCmd(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return errors.New("block and witness files required")
+	}
+	blob, err := readRLPFile(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blob, block); err != nil {
+		return fmt.Errorf("invalid block: %v", err)
+	}
+	if blob, err = readRLPFile(ctx.Args().Get(1)); err != nil {
+		return err
+	}
+	witness := new(types.BlockWitness)
+	if err := rlp.DecodeBytes(blob, witness); err != nil {
+		return fmt.Errorf("invalid witness: %v", err)
+	}
+	config := params.MainnetChainConfig
+	if ctx.GlobalString(GenesisFlag.Name) != "" {
+		if config = readGenesis(ctx.GlobalString(GenesisFlag.Name)).Config; config == nil {
+			return errors.New("genesis file without chain configuration")
+		}
+	}
+	// Only the block finalization of the engines is used, no seals are verified
+	var engine consensus.Engine = ethash.NewFaker()
+	if config.Clique != nil {
+		engine = clique.New(config.Clique, rawdb.NewMemoryDatabase())
+	}
+	root, err := core.ExecuteStateless(config, engine, block, witness, vm.Config{})
+
+	result := &StatelessResult{
+		Number: block.NumberU64(),
+		Hash:   block.Hash(),
+		Root:   root,
+		Pass:   err == nil,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if ctx.GlobalBool(MachineFlag.Name) {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(out))
+	} else if err == nil {
+		fmt.Printf("Block #%d [%x] verified, post-state root %x\n", result.Number, result.Hash, result.Root)
+	}
+	return err
+}
@@ -63,12 +63,16 @@ There are a few (not many) errors that can occur, those are defined below.
 - Block history is not supplied, but needed for a \`BLOCKHASH\` operation. If \`BLOCKHASH\`
   is invoked targeting a block which history has not been provided for, the program will
   exit with code \`4\`.
+- Inconsistent block building parameters, e.g. sealing with both ethash and clique, or
+  sealing data overwriting explicitly supplied header fields. Exit code \`5\`.
 
 #### IO errors (\`10\`-\`20\`)
 
 - Invalid input json: the supplied data could not be marshalled.
   The program will exit with code \`10\`
 - IO problems: failure to load or save files, the program will exit with code \`11\`
+- Invalid RLP: the supplied transactions, ommers or block could not be decoded or encoded,
+  the program will exit with code \`12\`
 
 EOF
 
@@ -189,3 +193,53 @@ echo ""
 echo "In order to meaningfully chain invocations, one would need to provide meaningful new \`env\`, otherwise the"
 echo "actual blocknumber (exposed to the EVM) would not increase."
 echo ""
+
+cat << EOF
+## Transaction validation tool
+
+The \`evm t9n\` tool validates a list of transactions in isolation against the rules
+of a given fork, without any state. The transactions are supplied as a JSON string
+holding their RLP encoded list, such as the one written by \`evm t8n --output.body\`:
+\`\`\`
+./evm t9n --input.txs=txs.rlp --state.fork=Istanbul
+\`\`\`
+For every transaction, the hash is reported, along with either the sender and the
+intrinsic gas, or the reason it's invalid (invalid signature, wrong chain id,
+insufficient gas to cover the intrinsic costs). The results are written to \`stdout\`:
+\`\`\`json
+[
+  {
+    "address": "0x8a8eafb1cf62bfbeb1741769dae1a9dd47996192",
+    "hash": "0x0557bacce3375c98d806609b8d5043072f0b6a8bae45ae5a67a00d3a1a18d673",
+    "intrinsicGas": "0x5208"
+  }
+]
+\`\`\`
+When reading from \`stdin\`, the input is expected as \`{"txs": "<rlp>"}\`.
+
+## Block builder tool
+
+The \`evm b11r\` tool assembles a block out of a header, an RLP encoded list of
+transactions and optionally a list of RLP encoded ommer headers, and writes its
+RLP and hash:
+\`\`\`
+./evm b11r --input.header=header.json --input.txs=txs.rlp --input.ommers=ommers.json --output.block=stdout
+\`\`\`
+The header is given in the usual JSON format. Only \`stateRoot\`, \`number\`, \`gasLimit\`
+and \`timestamp\` are required, the \`transactionsRoot\` and \`sha3Uncles\` are derived
+from the body unless explicitly supplied. The state root, receipts root, bloom and
+gas used are typically taken from the result of a preceding \`evm t8n\` invocation.
+When reading from \`stdin\`, the input is expected as
+\`{"header": {...}, "txs": "<rlp>", "ommers": ["<rlp>", ...], "clique": {...}}\`.
+
+The block can optionally be sealed:
+
+- \`--seal.ethash\` mines the block with ethash, using the DAG in \`--seal.ethash.dir\`.
+  The \`--seal.ethash.mode\` may be \`normal\`, \`test\` (small DAG) or \`fake\` (no PoW).
+- \`--seal.clique=<file>\` signs the block with clique, given a JSON file with the
+  \`secretKey\` of the signer and optionally the \`vanity\`, the \`voted\` address and
+  whether to \`authorize\` or deauthorize it.
+
+Sealing parameters conflicting with explicitly supplied header fields (e.g. a nonce
+when mining, or the extra data when signing) are rejected with exit code \`5\`.
+EOF
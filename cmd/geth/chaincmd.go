@@ -19,6 +19,8 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -28,15 +30,19 @@ import (
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -148,6 +154,25 @@ be gzipped.`,
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 The export-preimages command export hash preimages to an RLP encoded stream`,
+	}
+	backfillPreimagesCommand = cli.Command{
+		Action:    utils.MigrateFlags(backfillPreimages),
+		Name:      "backfill-preimages",
+		Usage:     "Derive missing state key preimages by re-executing blocks",
+		ArgsUsage: "[<firstNum> [<lastNum>]]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The backfill-preimages command re-executes the given range of blocks, by default
+from block 1 up to the head, on top of the state of the parent of the first one,
+and stores the preimages of all the account addresses and storage slot keys
+accessed. Backfilling from block 1 also stores the preimages of the genesis
+allocation and completes the preimages of the entire state, as needed by state
+dumps and export-preimages.`,
 	}
 	copydbCommand = cli.Command{
 		Action:    utils.MigrateFlags(copyDb),
@@ -200,6 +225,70 @@ Remove blockchain and state databases`,
 		Description: `
 The arguments are interpreted as block numbers or hashes.
 Use "ethereum dump 0" to dump the genesis block.`,
+	}
+	convertBinaryCommand = cli.Command{
+		Action:    utils.MigrateFlags(convertBinary),
+		Name:      "convert-binary",
+		Usage:     "Convert the state of a block into experimental binary tries",
+		ArgsUsage: "[<blockHash> | <blockNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The convert-binary command copies the state of the given block (the current head
+by default) from the Merkle Patricia tries into binary Merkle tries, which are
+stored alongside the original state in the chain database. The root hash of the
+binary state is printed once done.`,
+	}
+	witnessSizeCommand = cli.Command{
+		Action:    utils.MigrateFlags(witnessSize),
+		Name:      "witness-size",
+		Usage:     "Measure the state witness sizes of a range of blocks",
+		ArgsUsage: "<firstNum> [<lastNum>]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.BinaryWitnessFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The witness-size command re-executes the given blocks on top of their parent
+states and measures the witness a stateless client would need to execute them:
+the proofs of all the accounts and storage slots accessed, and the code of the
+accessed contracts.
+
+If --witness.binary is set, the parent state of the first block is converted into
+binary tries, the blocks are executed on the binary state too and the sizes of
+both witness formats are reported. The converted state is stored in the chain
+database.`,
+	}
+	exportBadBlockCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportBadBlock),
+		Name:      "export-badblock",
+		Usage:     "Export a bad block with its pre-state as an evm t8n input",
+		ArgsUsage: "[<blockHash> [<filename>]]",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+Without arguments, the export-badblock command lists the bad blocks rejected by
+the node, along with the validation step they failed.
+
+Given the hash of a bad block, it re-executes the block on top of the state of
+its parent and bundles the transactions, the block environment and the accounts
+and storage slots accessed into a single JSON document, written to the given
+file or to stdout. The bundle can be replayed in isolation with:
+
+    evm t8n --input.alloc=stdin --input.env=stdin --input.txs=stdin --state.fork=<fork>
+
+The parent state must still be available in the database.`,
 	}
 	inspectCommand = cli.Command{
 		Action:    utils.MigrateFlags(inspect),
@@ -441,6 +530,57 @@ func exportPreimages(ctx *cli.Context) error {
 	return nil
 }
 
+func backfillPreimages(ctx *cli.Context) error {
+	if len(ctx.Args()) > 2 {
+		utils.Fatalf("This command accepts at most two arguments.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack, false)
+	defer chainDb.Close()
+
+	var (
+		first = uint64(1)
+		last  = chain.CurrentBlock().NumberU64()
+		err   error
+	)
+	if ctx.NArg() > 0 {
+		if first, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			utils.Fatalf("Invalid first block number: %v", err)
+		}
+	}
+	if ctx.NArg() > 1 {
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			utils.Fatalf("Invalid last block number: %v", err)
+		}
+	}
+	if first == 0 || last < first {
+		utils.Fatalf("Invalid block range %d-%d", first, last)
+	}
+	start := time.Now()
+
+	// The genesis block can't be re-executed, insert its allocation if known
+	store := chain.StateCache().TrieDB().Preimages()
+	if first == 1 {
+		genesis := utils.MakeGenesis(ctx)
+		if genesis == nil {
+			genesis = core.DefaultGenesisBlock()
+		}
+		if genesis.ToBlock(nil).Hash() == chain.Genesis().Hash() {
+			genesis.InsertPreimages(store)
+		} else {
+			log.Warn("Unknown genesis, allocation preimages not backfilled")
+		}
+	}
+	missing, err := chain.BackfillPreimages(first, last)
+	if err != nil {
+		utils.Fatalf("Backfill error: %v", err)
+	}
+	fmt.Printf("Backfilled %d preimages in %v\n", missing, time.Since(start))
+	return nil
+}
+
 func copyDb(ctx *cli.Context) error {
 	// Ensure we have a source chain directory to copy
 	if len(ctx.Args()) < 1 {
@@ -575,7 +715,7 @@ func dump(ctx *cli.Context) error {
 			fmt.Println("{}")
 			utils.Fatalf("block not found")
 		} else {
-			state, err := state.New(block.Root(), state.NewDatabase(chainDb), nil)
+			state, err := chain.StateAt(block.Root())
 			if err != nil {
 				utils.Fatalf("could not create new state: %v", err)
 			}
@@ -596,6 +736,288 @@ func dump(ctx *cli.Context) error {
 	return nil
 }
 
+func convertBinary(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		utils.Fatalf("This command requires at most one argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack, true)
+	defer chainDb.Close()
+
+	if chain.Config().BinaryTrie {
+		utils.Fatalf("Chain state is already stored in binary tries")
+	}
+	block := chain.CurrentBlock()
+	if ctx.NArg() == 1 {
+		block = blockByHashish(chain, ctx.Args().First())
+	}
+	if block == nil {
+		utils.Fatalf("Block not found")
+	}
+	start := time.Now()
+	root, err := state.ConvertToBinary(chain.StateCache(), block.Root(), state.NewBinaryDatabaseWithConfig(chainDb, nil))
+	if err != nil {
+		utils.Fatalf("Conversion failed: %v", err)
+	}
+	fmt.Printf("Converted state of block #%d (%x) in %v\n", block.NumberU64(), block.Hash(), time.Since(start))
+	fmt.Printf("Merkle Patricia root: %x\n", block.Root())
+	fmt.Printf("Binary root:          %x\n", root)
+	return nil
+}
+
+// witnessStats is the size of a block witness in a given state trie format.
+type witnessStats struct {
+	nodes, nodeBytes int
+	codes, codeBytes int
+}
+
+func (s witnessStats) String() string {
+	return fmt.Sprintf("nodes=%d nodebytes=%v codes=%d codebytes=%v total=%v",
+		s.nodes, common.StorageSize(s.nodeBytes), s.codes, common.StorageSize(s.codeBytes), common.StorageSize(s.nodeBytes+s.codeBytes))
+}
+
+// measureWitness executes the block on top of the given parent state, proves all
+// the state accessed and returns the size of the resulting witness, along with
+// the post state of the block.
+func measureWitness(chain *core.BlockChain, db state.Database, root common.Hash, block *types.Block) (witnessStats, *state.StateDB, error) {
+	statedb, err := state.New(root, db, nil)
+	if err != nil {
+		return witnessStats{}, nil, err
+	}
+	statedb.RecordAccesses()
+	if _, _, _, err := chain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+		return witnessStats{}, nil, err
+	}
+	accesses := statedb.Accesses()
+
+	proofDb := memorydb.New()
+	if err := state.ProveAccesses(db, root, accesses, proofDb); err != nil {
+		return witnessStats{}, nil, err
+	}
+	var stats witnessStats
+	it := proofDb.NewIterator(nil, nil)
+	for it.Next() {
+		stats.nodes++
+		stats.nodeBytes += len(it.Value())
+	}
+	it.Release()
+
+	// Count the pre-state code of the accessed contracts, shared code only once
+	prestate, err := state.New(root, db, nil)
+	if err != nil {
+		return witnessStats{}, nil, err
+	}
+	codes := make(map[common.Hash]struct{})
+	for addr := range accesses {
+		if size := prestate.GetCodeSize(addr); size > 0 {
+			if _, ok := codes[prestate.GetCodeHash(addr)]; !ok {
+				codes[prestate.GetCodeHash(addr)] = struct{}{}
+				stats.codes++
+				stats.codeBytes += size
+			}
+		}
+	}
+	return stats, statedb, nil
+}
+
+func witnessSize(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 || len(ctx.Args()) > 2 {
+		utils.Fatalf("This command requires one or two arguments.")
+	}
+	first, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+	if err != nil {
+		utils.Fatalf("Invalid first block number: %v", err)
+	}
+	last := first
+	if ctx.NArg() == 2 {
+		if last, err = strconv.ParseUint(ctx.Args().Get(1), 10, 64); err != nil {
+			utils.Fatalf("Invalid last block number: %v", err)
+		}
+	}
+	if first == 0 || last < first {
+		utils.Fatalf("Invalid block range %d-%d", first, last)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack, true)
+	defer chainDb.Close()
+
+	parent := chain.GetBlockByNumber(first - 1)
+	if parent == nil {
+		utils.Fatalf("Block #%d not found", first-1)
+	}
+	// If requested, convert the starting state into binary tries to measure the
+	// binary witnesses alongside the native ones
+	var (
+		binary     = ctx.Bool(utils.BinaryWitnessFlag.Name) && !chain.Config().BinaryTrie
+		binaryDb   state.Database
+		binaryRoot common.Hash
+	)
+	if binary {
+		binaryDb = state.NewBinaryDatabaseWithConfig(chainDb, nil)
+		if binaryRoot, err = state.ConvertToBinary(chain.StateCache(), parent.Root(), binaryDb); err != nil {
+			utils.Fatalf("Failed to convert state to binary tries: %v", err)
+		}
+	}
+	var native, converted witnessStats
+	for number := first; number <= last; number++ {
+		block := chain.GetBlockByNumber(number)
+		if block == nil {
+			utils.Fatalf("Block #%d not found", number)
+		}
+		stats, _, err := measureWitness(chain, chain.StateCache(), parent.Root(), block)
+		if err != nil {
+			utils.Fatalf("Failed to measure witness of block #%d: %v", number, err)
+		}
+		native.nodes, native.nodeBytes = native.nodes+stats.nodes, native.nodeBytes+stats.nodeBytes
+		native.codes, native.codeBytes = native.codes+stats.codes, native.codeBytes+stats.codeBytes
+
+		fmt.Printf("Block #%d: txs=%d native: %v\n", number, len(block.Transactions()), stats)
+		if binary {
+			stats, statedb, err := measureWitness(chain, binaryDb, binaryRoot, block)
+			if err != nil {
+				utils.Fatalf("Failed to measure binary witness of block #%d: %v", number, err)
+			}
+			// Persist the binary post state to use it as the next parent
+			if binaryRoot, err = statedb.Commit(chain.Config().IsEIP158(block.Number())); err != nil {
+				utils.Fatalf("Failed to commit binary state of block #%d: %v", number, err)
+			}
+			if err := binaryDb.TrieDB().Commit(binaryRoot, false, nil); err != nil {
+				utils.Fatalf("Failed to flush binary state of block #%d: %v", number, err)
+			}
+			converted.nodes, converted.nodeBytes = converted.nodes+stats.nodes, converted.nodeBytes+stats.nodeBytes
+			converted.codes, converted.codeBytes = converted.codes+stats.codes, converted.codeBytes+stats.codeBytes
+
+			fmt.Printf("Block #%d: txs=%d binary: %v\n", number, len(block.Transactions()), stats)
+		}
+		parent = block
+	}
+	fmt.Printf("Total of %d blocks native: %v\n", last-first+1, native)
+	if binary {
+		fmt.Printf("Total of %d blocks binary: %v\n", last-first+1, converted)
+	}
+	return nil
+}
+
+// blockByHashish retrieves a block by its number or hash.
+func blockByHashish(chain *core.BlockChain, arg string) *types.Block {
+	if hashish(arg) {
+		return chain.GetBlockByHash(common.HexToHash(arg))
+	}
+	num, _ := strconv.ParseUint(arg, 10, 64)
+	return chain.GetBlockByNumber(num)
+}
+
+// t8nEnv is the block environment of an evm t8n input.
+type t8nEnv struct {
+	Coinbase    common.UnprefixedAddress            `json:"currentCoinbase"`
+	Difficulty  *math.HexOrDecimal256               `json:"currentDifficulty"`
+	GasLimit    math.HexOrDecimal64                 `json:"currentGasLimit"`
+	Number      math.HexOrDecimal64                 `json:"currentNumber"`
+	Timestamp   math.HexOrDecimal64                 `json:"currentTimestamp"`
+	BlockHashes map[math.HexOrDecimal64]common.Hash `json:"blockHashes,omitempty"`
+	Ommers      []t8nOmmer                          `json:"ommers,omitempty"`
+}
+
+// t8nOmmer is an uncle of the block in an evm t8n input.
+type t8nOmmer struct {
+	Delta   uint64         `json:"delta"`
+	Address common.Address `json:"address"`
+}
+
+// t8nInput is a self contained evm t8n input, as read from stdin.
+type t8nInput struct {
+	Alloc core.GenesisAlloc  `json:"alloc"`
+	Env   *t8nEnv            `json:"env"`
+	Txs   types.Transactions `json:"txs"`
+}
+
+// t8nFork returns the name of the evm t8n fork rules active at the given block.
+func t8nFork(config *params.ChainConfig, number *big.Int) string {
+	switch {
+	case config.IsYoloV2(number):
+		return "YOLOv2"
+	case config.IsIstanbul(number):
+		return "Istanbul"
+	case config.IsPetersburg(number):
+		return "ConstantinopleFix"
+	case config.IsConstantinople(number):
+		return "Constantinople"
+	case config.IsByzantium(number):
+		return "Byzantium"
+	case config.IsEIP158(number):
+		return "EIP158"
+	case config.IsEIP150(number):
+		return "EIP150"
+	case config.IsHomestead(number):
+		return "Homestead"
+	default:
+		return "Frontier"
+	}
+}
+
+func exportBadBlock(ctx *cli.Context) error {
+	if len(ctx.Args()) > 2 {
+		utils.Fatalf("This command accepts at most two arguments.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, chainDb := utils.MakeChain(ctx, stack, true)
+	defer chainDb.Close()
+
+	// List the known bad blocks if none was requested
+	if ctx.NArg() == 0 {
+		for _, bad := range rawdb.ReadAllBadBlocks(chainDb) {
+			fmt.Printf("#%d %x stage=%s err=%q\n", bad.Block.NumberU64(), bad.Block.Hash(), bad.Stage, bad.Error)
+		}
+		return nil
+	}
+	bad := chain.BadBlock(common.HexToHash(ctx.Args().Get(0)))
+	if bad == nil {
+		utils.Fatalf("Bad block %s not found", ctx.Args().Get(0))
+	}
+	alloc, err := chain.BadBlockPrestate(bad)
+	if err != nil {
+		utils.Fatalf("Failed to gather pre-state: %v", err)
+	}
+	block := bad.Block
+	env := &t8nEnv{
+		Coinbase:   common.UnprefixedAddress(block.Coinbase()),
+		Difficulty: (*math.HexOrDecimal256)(block.Difficulty()),
+		GasLimit:   math.HexOrDecimal64(block.GasLimit()),
+		Number:     math.HexOrDecimal64(block.NumberU64()),
+		Timestamp:  math.HexOrDecimal64(block.Time()),
+	}
+	for _, uncle := range block.Uncles() {
+		env.Ommers = append(env.Ommers, t8nOmmer{Delta: block.NumberU64() - uncle.Number.Uint64(), Address: uncle.Coinbase})
+	}
+	// Include the ancestor hashes reachable through BLOCKHASH
+	env.BlockHashes = make(map[math.HexOrDecimal64]common.Hash)
+	for header := chain.GetHeader(block.ParentHash(), block.NumberU64()-1); header != nil && header.Number.Uint64()+256 >= block.NumberU64(); {
+		env.BlockHashes[math.HexOrDecimal64(header.Number.Uint64())] = header.Hash()
+		if header.Number.Uint64() == 0 {
+			break
+		}
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	out, err := json.MarshalIndent(&t8nInput{Alloc: alloc, Env: env, Txs: block.Transactions()}, "", "  ")
+	if err != nil {
+		utils.Fatalf("Failed to encode bundle: %v", err)
+	}
+	if ctx.NArg() < 2 {
+		fmt.Println(string(out))
+	} else if err := ioutil.WriteFile(ctx.Args().Get(1), out, 0644); err != nil {
+		utils.Fatalf("Failed to write bundle: %v", err)
+	}
+	log.Info("Exported bad block", "number", block.Number(), "hash", block.Hash(), "stage", bad.Stage,
+		"accounts", len(alloc), "fork", t8nFork(chain.Config(), block.Number()), "chainid", chain.Config().ChainID)
+	return nil
+}
+
 func inspect(ctx *cli.Context) error {
 	node, _ := makeConfigNode(ctx)
 	defer node.Close()
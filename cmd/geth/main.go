@@ -81,6 +81,9 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolDumpFlag,
+		utils.TxPoolDumpLimitFlag,
+		utils.TxPoolDumpAgeFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -88,9 +91,15 @@ var (
 		utils.TxPoolAccountQueueFlag,
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
+		utils.TxPoolPrivateLifetimeFlag,
+		utils.TxPoolPrivateReleaseFlag,
 		utils.SyncModeFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
+		utils.StateDiffsFlag,
+		utils.WitnessesFlag,
+		utils.ChainEventsFlag,
+		utils.ParallelExecFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
 		utils.LightServeFlag,
@@ -113,6 +122,7 @@ var (
 		utils.CacheGCFlag,
 		utils.CacheSnapshotFlag,
 		utils.CacheNoPrefetchFlag,
+		utils.CacheNoTriePrefetchFlag,
 		utils.CachePreimagesFlag,
 		utils.ListenPortFlag,
 		utils.MaxPeersFlag,
@@ -132,6 +142,9 @@ var (
 		utils.LegacyMinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerfiyFlag,
+		utils.MinerMaxBlockSizeFlag,
+		utils.MinerMaxCalldataFlag,
+		utils.MinerMaxTxsFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -226,10 +239,14 @@ func init() {
 		exportCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
+		backfillPreimagesCommand,
 		copydbCommand,
 		removedbCommand,
 		dumpCommand,
 		dumpGenesisCommand,
+		convertBinaryCommand,
+		witnessSizeCommand,
+		exportBadBlockCommand,
 		inspectCommand,
 		// See accountcmd.go:
 		accountCommand,
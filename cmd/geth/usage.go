@@ -47,6 +47,10 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.SyncModeFlag,
 			utils.ExitWhenSyncedFlag,
 			utils.GCModeFlag,
+			utils.StateDiffsFlag,
+			utils.WitnessesFlag,
+			utils.ChainEventsFlag,
+			utils.ParallelExecFlag,
 			utils.TxLookupLimitFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
@@ -94,6 +98,9 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolNoLocalsFlag,
 			utils.TxPoolJournalFlag,
 			utils.TxPoolRejournalFlag,
+			utils.TxPoolDumpFlag,
+			utils.TxPoolDumpLimitFlag,
+			utils.TxPoolDumpAgeFlag,
 			utils.TxPoolPriceLimitFlag,
 			utils.TxPoolPriceBumpFlag,
 			utils.TxPoolAccountSlotsFlag,
@@ -101,6 +108,8 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolLifetimeFlag,
+			utils.TxPoolPrivateLifetimeFlag,
+			utils.TxPoolPrivateReleaseFlag,
 		},
 	},
 	{
@@ -114,6 +123,7 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.CacheGCFlag,
 			utils.CacheSnapshotFlag,
 			utils.CacheNoPrefetchFlag,
+			utils.CacheNoTriePrefetchFlag,
 			utils.CachePreimagesFlag,
 		},
 	},
@@ -183,6 +193,9 @@ var AppHelpFlagGroups = []flags.FlagGroup{
 			utils.MinerExtraDataFlag,
 			utils.MinerRecommitIntervalFlag,
 			utils.MinerNoVerfiyFlag,
+			utils.MinerMaxBlockSizeFlag,
+			utils.MinerMaxCalldataFlag,
+			utils.MinerMaxTxsFlag,
 		},
 	},
 	{
@@ -180,6 +180,10 @@ var (
 		Name:  "incompletes",
 		Usage: "Include accounts for which we don't have the address (missing preimage)",
 	}
+	BinaryWitnessFlag = cli.BoolFlag{
+		Name:  "witness.binary",
+		Usage: "Also measure witnesses of the state converted into binary tries",
+	}
 	ExcludeCodeFlag = cli.BoolFlag{
 		Name:  "nocode",
 		Usage: "Exclude contract code (save db lookups)",
@@ -199,6 +203,23 @@ var (
 		Name:  "snapshot",
 		Usage: `Enables snapshot-database mode -- experimental work in progress feature`,
 	}
+	StateDiffsFlag = cli.BoolFlag{
+		Name:  "statediffs",
+		Usage: "Persist per-block state diffs to serve historical state queries without an archive node",
+	}
+	WitnessesFlag = cli.BoolFlag{
+		Name:  "witnesses",
+		Usage: "Generate and persist execution witnesses of imported blocks for stateless verification",
+	}
+	ChainEventsFlag = cli.BoolFlag{
+		Name:  "chainevents",
+		Usage: "Persist an ordered log of canonical chain changes for resumable event subscriptions",
+	}
+	ParallelExecFlag = cli.IntFlag{
+		Name:  "parallelexec",
+		Usage: "Number of workers executing block transactions speculatively in parallel (0 = sequential)",
+		Value: 0,
+	}
 	TxLookupLimitFlag = cli.Int64Flag{
 		Name:  "txlookuplimit",
 		Usage: "Number of recent blocks to maintain transactions index by-hash for (default = index all blocks)",
@@ -308,6 +329,21 @@ var (
 		Usage: "Time interval to regenerate the local transaction journal",
 		Value: core.DefaultTxPoolConfig.Rejournal,
 	}
+	TxPoolDumpFlag = cli.StringFlag{
+		Name:  "txpool.dump",
+		Usage: "Disk dump of remote transactions to survive node restarts (empty = disabled)",
+		Value: core.DefaultTxPoolConfig.Dump,
+	}
+	TxPoolDumpLimitFlag = cli.Uint64Flag{
+		Name:  "txpool.dumplimit",
+		Usage: "Maximum number of remote transactions to persist into the disk dump",
+		Value: core.DefaultTxPoolConfig.DumpLimit,
+	}
+	TxPoolDumpAgeFlag = cli.DurationFlag{
+		Name:  "txpool.dumpage",
+		Usage: "Maximum age of dumped remote transactions to restore on startup",
+		Value: core.DefaultTxPoolConfig.DumpAge,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -343,6 +379,15 @@ var (
 		Usage: "Maximum amount of time non-executable transaction are queued",
 		Value: eth.DefaultConfig.TxPool.Lifetime,
 	}
+	TxPoolPrivateLifetimeFlag = cli.DurationFlag{
+		Name:  "txpool.privatelifetime",
+		Usage: "Maximum amount of time private transactions are kept unannounced",
+		Value: eth.DefaultConfig.TxPool.PrivateLifetime,
+	}
+	TxPoolPrivateReleaseFlag = cli.BoolFlag{
+		Name:  "txpool.privaterelease",
+		Usage: "Announce expired private transactions to the network instead of dropping them",
+	}
 	// Performance tuning settings
 	CacheFlag = cli.IntFlag{
 		Name:  "cache",
@@ -383,9 +428,13 @@ var (
 		Name:  "cache.noprefetch",
 		Usage: "Disable heuristic state prefetch during block import (less CPU and disk IO, more time waiting for data)",
 	}
+	CacheNoTriePrefetchFlag = cli.BoolFlag{
+		Name:  "cache.notrieprefetch",
+		Usage: "Disable concurrent trie node prefetching during block processing",
+	}
 	CachePreimagesFlag = cli.BoolTFlag{
 		Name:  "cache.preimages",
-		Usage: "Enable recording the SHA3/keccak preimages of trie keys (default: true)",
+		Usage: "Enable recording the SHA3/keccak preimages of trie keys (deprecated, always enabled)",
 	}
 	// Miner settings
 	MiningEnabledFlag = cli.BoolFlag{
@@ -434,6 +483,21 @@ var (
 		Name:  "miner.noverify",
 		Usage: "Disable remote sealing verification",
 	}
+	MinerMaxBlockSizeFlag = cli.Uint64Flag{
+		Name:  "miner.maxblocksize",
+		Usage: "Maximum approximate size of mined blocks in bytes (0 = unlimited)",
+		Value: eth.DefaultConfig.Miner.MaxBlockSize,
+	}
+	MinerMaxCalldataFlag = cli.Uint64Flag{
+		Name:  "miner.maxcalldata",
+		Usage: "Maximum total transaction calldata of mined blocks in bytes (0 = unlimited)",
+		Value: eth.DefaultConfig.Miner.MaxBlockCalldata,
+	}
+	MinerMaxTxsFlag = cli.Uint64Flag{
+		Name:  "miner.maxtxs",
+		Usage: "Maximum number of transactions in mined blocks (0 = unlimited)",
+		Value: eth.DefaultConfig.Miner.MaxBlockTxs,
+	}
 	// Account settings
 	UnlockedAccountFlag = cli.StringFlag{
 		Name:  "unlock",
@@ -718,12 +782,12 @@ var (
 	}
 	EWASMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.ewasm",
-		Usage: "External ewasm configuration (default = built-in interpreter)",
+		Usage: "Registered ewasm interpreter, as name[:options] (default = built-in wasm interpreter)",
 		Value: "",
 	}
 	EVMInterpreterFlag = cli.StringFlag{
 		Name:  "vm.evm",
-		Usage: "External EVM configuration (default = built-in interpreter)",
+		Usage: "Registered EVM interpreter tried before the built-in one, as name[:options]",
 		Value: "",
 	}
 )
@@ -1324,6 +1388,15 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolDumpFlag.Name) {
+		cfg.Dump = ctx.GlobalString(TxPoolDumpFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolDumpLimitFlag.Name) {
+		cfg.DumpLimit = ctx.GlobalUint64(TxPoolDumpLimitFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolDumpAgeFlag.Name) {
+		cfg.DumpAge = ctx.GlobalDuration(TxPoolDumpAgeFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -1345,6 +1418,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPrivateLifetimeFlag.Name) {
+		cfg.PrivateLifetime = ctx.GlobalDuration(TxPoolPrivateLifetimeFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolPrivateReleaseFlag.Name) {
+		cfg.PrivateRelease = ctx.GlobalBool(TxPoolPrivateReleaseFlag.Name)
+	}
 }
 
 func setEthash(ctx *cli.Context, cfg *eth.Config) {
@@ -1408,6 +1487,15 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.GlobalIsSet(MinerNoVerfiyFlag.Name) {
 		cfg.Noverify = ctx.GlobalBool(MinerNoVerfiyFlag.Name)
 	}
+	if ctx.GlobalIsSet(MinerMaxBlockSizeFlag.Name) {
+		cfg.MaxBlockSize = ctx.GlobalUint64(MinerMaxBlockSizeFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerMaxCalldataFlag.Name) {
+		cfg.MaxBlockCalldata = ctx.GlobalUint64(MinerMaxCalldataFlag.Name)
+	}
+	if ctx.GlobalIsSet(MinerMaxTxsFlag.Name) {
+		cfg.MaxBlockTxs = ctx.GlobalUint64(MinerMaxTxsFlag.Name)
+	}
 }
 
 func setWhitelist(ctx *cli.Context, cfg *eth.Config) {
@@ -1527,14 +1615,26 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 	if ctx.GlobalIsSet(GCModeFlag.Name) {
 		cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
 	}
+	if ctx.GlobalIsSet(StateDiffsFlag.Name) {
+		cfg.StateDiffs = ctx.GlobalBool(StateDiffsFlag.Name)
+	}
+	if ctx.GlobalIsSet(WitnessesFlag.Name) {
+		cfg.Witnesses = ctx.GlobalBool(WitnessesFlag.Name)
+	}
+	if ctx.GlobalIsSet(ChainEventsFlag.Name) {
+		cfg.ChainEvents = ctx.GlobalBool(ChainEventsFlag.Name)
+	}
+	if ctx.GlobalIsSet(ParallelExecFlag.Name) {
+		cfg.ParallelExec = ctx.GlobalInt(ParallelExecFlag.Name)
+	}
 	if ctx.GlobalIsSet(CacheNoPrefetchFlag.Name) {
 		cfg.NoPrefetch = ctx.GlobalBool(CacheNoPrefetchFlag.Name)
 	}
-	// Read the value from the flag no matter if it's set or not.
-	cfg.Preimages = ctx.GlobalBool(CachePreimagesFlag.Name)
-	if cfg.NoPruning && !cfg.Preimages {
-		cfg.Preimages = true
-		log.Info("Enabling recording of key preimages since archive mode is used")
+	if ctx.GlobalIsSet(CacheNoTriePrefetchFlag.Name) {
+		cfg.NoTriePrefetch = ctx.GlobalBool(CacheNoTriePrefetchFlag.Name)
+	}
+	if ctx.GlobalIsSet(CachePreimagesFlag.Name) {
+		log.Warn("The flag --cache.preimages is deprecated and will be removed in the future, preimages are always recorded")
 	}
 	if ctx.GlobalIsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.GlobalUint64(TxLookupLimitFlag.Name)
@@ -1568,10 +1668,16 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 
 	if ctx.GlobalIsSet(EWASMInterpreterFlag.Name) {
 		cfg.EWASMInterpreter = ctx.GlobalString(EWASMInterpreterFlag.Name)
+		if _, ok := vm.LookupInterpreter(cfg.EWASMInterpreter); !ok {
+			Fatalf("Unknown interpreter --%s: %q", EWASMInterpreterFlag.Name, cfg.EWASMInterpreter)
+		}
 	}
 
 	if ctx.GlobalIsSet(EVMInterpreterFlag.Name) {
 		cfg.EVMInterpreter = ctx.GlobalString(EVMInterpreterFlag.Name)
+		if _, ok := vm.LookupInterpreter(cfg.EVMInterpreter); !ok {
+			Fatalf("Unknown interpreter --%s: %q", EVMInterpreterFlag.Name, cfg.EVMInterpreter)
+		}
 	}
 	if ctx.GlobalIsSet(RPCGlobalGasCapFlag.Name) {
 		cfg.RPCGasCap = ctx.GlobalUint64(RPCGlobalGasCapFlag.Name)
@@ -1842,15 +1948,15 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readOnly bool) (chain *core.B
 	cache := &core.CacheConfig{
 		TrieCleanLimit:      eth.DefaultConfig.TrieCleanCache,
 		TrieCleanNoPrefetch: ctx.GlobalBool(CacheNoPrefetchFlag.Name),
+		TrieNoNodePrefetch:  ctx.GlobalBool(CacheNoTriePrefetchFlag.Name),
 		TrieDirtyLimit:      eth.DefaultConfig.TrieDirtyCache,
 		TrieDirtyDisabled:   ctx.GlobalString(GCModeFlag.Name) == "archive",
 		TrieTimeLimit:       eth.DefaultConfig.TrieTimeout,
 		SnapshotLimit:       eth.DefaultConfig.SnapshotCache,
-		Preimages:           ctx.GlobalBool(CachePreimagesFlag.Name),
-	}
-	if cache.TrieDirtyDisabled && !cache.Preimages {
-		cache.Preimages = true
-		log.Info("Enabling recording of key preimages since archive mode is used")
+		StateDiffs:          ctx.GlobalBool(StateDiffsFlag.Name),
+		Witnesses:           ctx.GlobalBool(WitnessesFlag.Name),
+		ChainEvents:         ctx.GlobalBool(ChainEventsFlag.Name),
+		ParallelExecution:   ctx.GlobalInt(ParallelExecFlag.Name),
 	}
 	if !ctx.GlobalIsSet(SnapshotFlag.Name) {
 		cache.SnapshotLimit = 0 // Disabled
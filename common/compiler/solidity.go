@@ -27,7 +27,8 @@ type solcOutput struct {
 		Bin, SrcMap, Abi, Devdoc, Userdoc, Metadata string
 		Hashes                                      map[string]string
 	}
-	Version string
+	SourceList []string `json:"sourceList"`
+	Version    string
 }
 
 func (s *Solidity) makeArgs() []string {
@@ -153,6 +154,7 @@ func ParseCombinedJSON(combinedJSON []byte, source string, languageVersion strin
 				CompilerOptions: compilerOptions,
 				SrcMap:          info.SrcMap,
 				SrcMapRuntime:   info.SrcMapRuntime,
+				SourceList:      output.SourceList,
 				AbiDefinition:   abi,
 				UserDoc:         userdoc,
 				DeveloperDoc:    devdoc,
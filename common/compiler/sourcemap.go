@@ -0,0 +1,351 @@
+
+
+package compiler
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceMapEntry is a decompressed element of a solc source mapping, describing
+// the source range a single instruction was generated from.
+type SourceMapEntry struct {
+	Start         int  // Byte offset of the range in the source file
+	Length        int  // Length of the range in bytes
+	File          int  // Index of the file in the source list, -1 if generated
+	Jump          byte // 'i' into a function, 'o' out of a function, '-' otherwise
+	ModifierDepth int  // Depth of the modifier the instruction is in
+}
+
+// ParseSourceMap decompresses a solc source mapping (s:l:f:j:m entries separated
+// by semicolons, empty fields repeating the previous entry's value).
+func ParseSourceMap(srcmap string) ([]SourceMapEntry, error) {
+	if srcmap == "" {
+		return nil, nil
+	}
+	var (
+		items   = strings.Split(srcmap, ";")
+		entries = make([]SourceMapEntry, len(items))
+		last    = SourceMapEntry{File: -1, Jump: '-'}
+	)
+	for i, item := range items {
+		fields := strings.Split(item, ":")
+		if len(fields) > 5 {
+			return nil, fmt.Errorf("source map entry %d: too many fields", i)
+		}
+		for j, field := range fields {
+			if field == "" {
+				continue
+			}
+			if j == 3 {
+				if len(field) != 1 || !strings.Contains("io-", field) {
+					return nil, fmt.Errorf("source map entry %d: invalid jump %q", i, field)
+				}
+				last.Jump = field[0]
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("source map entry %d: invalid field %q", i, field)
+			}
+			switch j {
+			case 0:
+				last.Start = n
+			case 1:
+				last.Length = n
+			case 2:
+				last.File = n
+			case 4:
+				last.ModifierDepth = n
+			}
+		}
+		entries[i] = last
+	}
+	return entries, nil
+}
+
+// SourceFile is a source file referenced by a source mapping.
+type SourceFile struct {
+	Name    string
+	Content string
+}
+
+// SourceLocation is the position in the sources an instruction was generated from.
+type SourceLocation struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Function string `json:"function,omitempty"` // Enclosing function, qualified with the contract name
+	Jump     string `json:"jump,omitempty"`     // "in" or "out" for jumps into or out of functions
+}
+
+func (l *SourceLocation) String() string {
+	s := fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+	if l.Function != "" {
+		s += " (" + l.Function + ")"
+	}
+	return s
+}
+
+// SourceMapper maps the program counters of a contract's bytecode to the source
+// locations the instructions were generated from.
+type SourceMapper struct {
+	entries []SourceMapEntry
+	indexes map[uint64]int // Instruction index of every program counter
+	files   []*indexedSource
+}
+
+// NewSourceMapper creates a source mapper for the given bytecode, using its solc
+// source mapping and the files of the source list in the same order.
+func NewSourceMapper(code []byte, srcmap string, sources []SourceFile) (*SourceMapper, error) {
+	entries, err := ParseSourceMap(srcmap)
+	if err != nil {
+		return nil, err
+	}
+	m := &SourceMapper{
+		entries: entries,
+		indexes: make(map[uint64]int),
+		files:   make([]*indexedSource, len(sources)),
+	}
+	for pc, index := uint64(0), 0; pc < uint64(len(code)); index++ {
+		m.indexes[pc] = index
+		// Skip the immediate data of the push instructions
+		if op := code[pc]; op >= 0x60 && op <= 0x7f {
+			pc += uint64(op - 0x5f)
+		}
+		pc++
+	}
+	for i, source := range sources {
+		m.files[i] = indexSource(source)
+	}
+	return m, nil
+}
+
+// Locate returns the source location of the instruction at the given program
+// counter, or nil if it doesn't map to any source.
+func (m *SourceMapper) Locate(pc uint64) *SourceLocation {
+	index, ok := m.indexes[pc]
+	if !ok || index >= len(m.entries) {
+		return nil
+	}
+	entry := m.entries[index]
+	if entry.File < 0 || entry.File >= len(m.files) {
+		return nil
+	}
+	file := m.files[entry.File]
+	if entry.Start > len(file.content) {
+		return nil
+	}
+	line := sort.SearchInts(file.lines, entry.Start+1)
+	loc := &SourceLocation{
+		File:     file.name,
+		Line:     line,
+		Column:   entry.Start - file.lines[line-1] + 1,
+		Function: file.scope(entry.Start),
+	}
+	switch entry.Jump {
+	case 'i':
+		loc.Jump = "in"
+	case 'o':
+		loc.Jump = "out"
+	}
+	return loc
+}
+
+// SourceMapper creates a source mapper for the runtime code of the contract, with
+// the sources looked up as by SourceFiles.
+func (c *Contract) SourceMapper(sources map[string]string) (*SourceMapper, error) {
+	if c.Info.SrcMapRuntime == "" {
+		return nil, errors.New("no runtime source mapping")
+	}
+	code, err := DecodeLinkedCode(c.RuntimeCode)
+	if err != nil {
+		return nil, err
+	}
+	files, err := c.SourceFiles(sources)
+	if err != nil {
+		return nil, err
+	}
+	return NewSourceMapper(code, c.Info.SrcMapRuntime, files)
+}
+
+// SourceFiles returns the files of the contract's source list. The sources are
+// looked up by their name in the given map. If no sources are given, they are
+// read from the file system instead, or taken from the compiled source for code
+// compiled from a string.
+func (c *Contract) SourceFiles(sources map[string]string) ([]SourceFile, error) {
+	files := make([]SourceFile, len(c.Info.SourceList))
+	for i, name := range c.Info.SourceList {
+		content, ok := sources[name]
+		switch {
+		case ok:
+		case sources != nil:
+			return nil, fmt.Errorf("missing source %q", name)
+		case name == "<stdin>":
+			content = c.Info.Source
+		default:
+			blob, err := ioutil.ReadFile(name)
+			if err != nil {
+				return nil, err
+			}
+			content = string(blob)
+		}
+		files[i] = SourceFile{Name: name, Content: content}
+	}
+	return files, nil
+}
+
+// DecodeLinkedCode decodes hex encoded bytecode, replacing the placeholders of
+// unlinked libraries with zero addresses.
+func DecodeLinkedCode(code string) ([]byte, error) {
+	code = strings.TrimPrefix(code, "0x")
+	for {
+		start := strings.Index(code, "__")
+		if start < 0 {
+			break
+		}
+		if start+40 > len(code) {
+			return nil, errors.New("truncated library placeholder")
+		}
+		code = code[:start] + strings.Repeat("0", 40) + code[start+40:]
+	}
+	return hex.DecodeString(code)
+}
+
+// scopeDecl matches the declarations of the contracts and functions of a source.
+var scopeDecl = regexp.MustCompile(`\b(?:(contract|library|interface)\s+([A-Za-z_$][\w$]*)|(function|modifier)\s+([A-Za-z_$][\w$]*)|(function)\s*\(|(constructor|fallback|receive)\s*\()`)
+
+// sourceScope is the byte range of a contract or function declaration.
+type sourceScope struct {
+	start, end int
+	name       string
+	contract   bool
+}
+
+// indexedSource is a source file prepared for looking up positions.
+type indexedSource struct {
+	name    string
+	content string
+	lines   []int // Byte offsets of the line starts
+	scopes  []sourceScope
+}
+
+// indexSource computes the line starts and the declaration scopes of a source.
+// The scopes are found by matching the declarations and their braces, with the
+// comments and string literals blanked out.
+func indexSource(source SourceFile) *indexedSource {
+	idx := &indexedSource{name: source.Name, content: source.Content, lines: []int{0}}
+	for i := 0; i < len(source.Content); i++ {
+		if source.Content[i] == '\n' {
+			idx.lines = append(idx.lines, i+1)
+		}
+	}
+	code := blankComments(source.Content)
+	for _, match := range scopeDecl.FindAllStringSubmatchIndex(code, -1) {
+		scope := sourceScope{start: match[0]}
+		switch {
+		case match[2] >= 0:
+			scope.name, scope.contract = code[match[4]:match[5]], true
+		case match[6] >= 0:
+			scope.name = code[match[8]:match[9]]
+		case match[10] >= 0:
+			scope.name = "fallback"
+		default:
+			scope.name = code[match[12]:match[13]]
+		}
+		// Find the body of the declaration, skipping the ones without
+		body := strings.IndexAny(code[match[1]:], "{;")
+		if body < 0 || code[match[1]+body] == ';' {
+			continue
+		}
+		scope.end = matchBrace(code, match[1]+body)
+		idx.scopes = append(idx.scopes, scope)
+	}
+	return idx
+}
+
+// scope returns the name of the innermost function containing the offset,
+// qualified with its contract's name.
+func (s *indexedSource) scope(offset int) string {
+	var contract, function *sourceScope
+	for i := range s.scopes {
+		scope := &s.scopes[i]
+		if offset < scope.start || offset > scope.end {
+			continue
+		}
+		if scope.contract {
+			contract = scope
+		} else if function == nil || scope.start > function.start {
+			function = scope
+		}
+	}
+	switch {
+	case function == nil && contract == nil:
+		return ""
+	case function == nil:
+		return contract.name
+	case contract == nil:
+		return function.name
+	}
+	return contract.name + "." + function.name
+}
+
+// matchBrace returns the offset of the brace closing the one at the given offset,
+// or the end of the code if it's unbalanced.
+func matchBrace(code string, open int) int {
+	depth := 0
+	for i := open; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(code)
+}
+
+// blankComments replaces the comments and string literals of a source with spaces,
+// keeping the byte offsets and line breaks intact.
+func blankComments(source string) string {
+	code := []byte(source)
+	for i := 0; i < len(code); i++ {
+		switch {
+		case code[i] == '/' && i+1 < len(code) && code[i+1] == '/':
+			for ; i < len(code) && code[i] != '\n'; i++ {
+				code[i] = ' '
+			}
+		case code[i] == '/' && i+1 < len(code) && code[i+1] == '*':
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				end = len(code)
+			} else {
+				end += i + 4
+			}
+			for ; i < end; i++ {
+				if code[i] != '\n' {
+					code[i] = ' '
+				}
+			}
+			i--
+		case code[i] == '"' || code[i] == '\'':
+			quote := code[i]
+			for i++; i < len(code) && code[i] != quote && code[i] != '\n'; i++ {
+				if code[i] == '\\' && i+1 < len(code) {
+					code[i] = ' '
+					i++
+				}
+				code[i] = ' '
+			}
+		}
+	}
+	return string(code)
+}
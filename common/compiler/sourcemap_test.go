@@ -0,0 +1,96 @@
+
+
+package compiler
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const sourceMapTestSource = `pragma solidity >0.0.0;
+
+// function commented(uint a) {}
+contract Counter {
+    uint count;
+
+    function add(uint a) public {
+        count += a; // "}" in a comment
+    }
+
+    function check() public view {
+        require(count < 10, "too {large");
+    }
+}
+`
+
+func TestParseSourceMap(t *testing.T) {
+	entries, err := ParseSourceMap("1:2:0:-;:3;4::1:i;;::-1:o:1")
+	if err != nil {
+		t.Fatalf("failed to parse source map: %v", err)
+	}
+	want := []SourceMapEntry{
+		{Start: 1, Length: 2, File: 0, Jump: '-'},
+		{Start: 1, Length: 3, File: 0, Jump: '-'},
+		{Start: 4, Length: 3, File: 1, Jump: 'i'},
+		{Start: 4, Length: 3, File: 1, Jump: 'i'},
+		{Start: 4, Length: 3, File: -1, Jump: 'o', ModifierDepth: 1},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("entries mismatch:\nhave %+v\nwant %+v", entries, want)
+	}
+	for _, srcmap := range []string{"1:2:0:x", "a:1", "1:2:3:-:4:5"} {
+		if _, err := ParseSourceMap(srcmap); err == nil {
+			t.Errorf("invalid source map %q accepted", srcmap)
+		}
+	}
+}
+
+func TestSourceMapper(t *testing.T) {
+	var (
+		add   = strings.Index(sourceMapTestSource, "count += a")
+		check = strings.Index(sourceMapTestSource, "require")
+		decl  = strings.Index(sourceMapTestSource, "uint count")
+	)
+	// PUSH1 0x01, PUSH2 0x0203, ADD, JUMP, STOP
+	code := []byte{0x60, 0x01, 0x61, 0x02, 0x03, 0x01, 0x56, 0x00}
+	srcmap := strings.Join([]string{
+		strconv.Itoa(add) + ":10:0:-",
+		strconv.Itoa(check) + ":33",
+		strconv.Itoa(decl) + ":10",
+		":::i",
+		"0:0:-1:-",
+	}, ";")
+	mapper, err := NewSourceMapper(code, srcmap, []SourceFile{{Name: "Counter.sol", Content: sourceMapTestSource}})
+	if err != nil {
+		t.Fatalf("failed to create source mapper: %v", err)
+	}
+	tests := []struct {
+		pc   uint64
+		want *SourceLocation
+	}{
+		{0, &SourceLocation{File: "Counter.sol", Line: 8, Column: 9, Function: "Counter.add"}},
+		{2, &SourceLocation{File: "Counter.sol", Line: 12, Column: 9, Function: "Counter.check"}},
+		{5, &SourceLocation{File: "Counter.sol", Line: 5, Column: 5, Function: "Counter"}},
+		{6, &SourceLocation{File: "Counter.sol", Line: 5, Column: 5, Function: "Counter", Jump: "in"}},
+		{7, nil}, // generated code
+		{1, nil}, // push data
+		{8, nil}, // out of bounds
+	}
+	for _, tt := range tests {
+		if have := mapper.Locate(tt.pc); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("pc %d: location mismatch: have %v, want %v", tt.pc, have, tt.want)
+		}
+	}
+}
+
+func TestDecodeLinkedCode(t *testing.T) {
+	code, err := DecodeLinkedCode("0x73__$1234567890abcdef1234567890abcdef12$__ff")
+	if err != nil {
+		t.Fatalf("failed to decode code: %v", err)
+	}
+	if want := append(append([]byte{0x73}, make([]byte, 20)...), 0xff); !reflect.DeepEqual(code, want) {
+		t.Errorf("code mismatch: have %x, want %x", code, want)
+	}
+}
@@ -54,10 +54,16 @@ var unmarshalBytesTests = []unmarshalTest{
 	{input: `"0x0"`, wantErr: wrapTypeError(ErrOddLength, bytesT)},
 	{input: `"0xxx"`, wantErr: wrapTypeError(ErrSyntax, bytesT)},
 	{input: `"0x01zz01"`, wantErr: wrapTypeError(ErrSyntax, bytesT)},
+	{input: `"ff"`, wantErr: wrapTypeError(ErrMissingPrefix, bytesT)},
+	{input: `"f"`, wantErr: wrapTypeError(ErrMissingPrefix, bytesT)},
+	{input: `"0f01"`, wantErr: wrapTypeError(ErrMissingPrefix, bytesT)},
+	{input: `"fy01"`, wantErr: wrapTypeError(ErrMissingPrefix, bytesT)},
 
 	// valid encoding
 	{input: `""`, want: referenceBytes("")},
 	{input: `"0x"`, want: referenceBytes("")},
+	{input: `"fx01"`, want: referenceBytes("01")},
+	{input: `"FX01"`, want: referenceBytes("01")},
 	{input: `"0x02"`, want: referenceBytes("02")},
 	{input: `"0X02"`, want: referenceBytes("02")},
 	{input: `"0xffffffffff"`, want: referenceBytes("ffffffffff")},
@@ -337,9 +343,17 @@ func TestUnmarshalFixedUnprefixedText(t *testing.T) {
 		// check that output is not modified for partially correct input
 		{input: "444444gg", wantErr: ErrSyntax, want: []byte{0, 0, 0, 0}},
 		{input: "0x444444gg", wantErr: ErrSyntax, want: []byte{0, 0, 0, 0}},
+		// unprefixed inputs starting with a prefix character are kept whole
+		{input: "f", wantErr: ErrOddLength},
+		{input: "ff", wantErr: errors.New("hex string has length 2, want 8 for x")},
+		{input: "fx01", wantErr: errors.New("hex string has length 2, want 8 for x")},
 		// valid inputs
 		{input: "44444444", want: []byte{0x44, 0x44, 0x44, 0x44}},
 		{input: "0x44444444", want: []byte{0x44, 0x44, 0x44, 0x44}},
+		{input: "fx44444444", want: []byte{0x44, 0x44, 0x44, 0x44}},
+		{input: "ffffffff", want: []byte{0xff, 0xff, 0xff, 0xff}},
+		{input: "0fffffff", want: []byte{0x0f, 0xff, 0xff, 0xff}},
+		{input: "f0f0f0f0", want: []byte{0xf0, 0xf0, 0xf0, 0xf0}},
 	}
 
 	for _, test := range tests {
@@ -20,6 +20,8 @@ package asm
 import (
 	"encoding/hex"
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/core/vm"
 )
@@ -134,3 +136,92 @@ func Disassemble(script []byte) ([]string, error) {
 	}
 	return instrs, nil
 }
+
+// DisassembleSource disassembles EVM code into assembly source which compiles back
+// to the same code. The jump destinations targeted by a push immediately followed
+// by a jump are labelled, and the bytes not forming valid instructions, such as a
+// truncated push at the end of the code, are written as raw data.
+func DisassembleSource(code []byte) string {
+	type instruction struct {
+		pc    uint64
+		op    vm.OpCode
+		arg   []byte
+		valid bool
+	}
+	// Split the code into instructions, the way jump destinations are analysed
+	var instrs []instruction
+	for pc := uint64(0); pc < uint64(len(code)); {
+		op := vm.OpCode(code[pc])
+		instr := instruction{pc: pc, op: op, valid: op.IsPush() || isOpcode(op.String())}
+		if op.IsPush() {
+			end := pc + 1 + uint64(op-vm.PUSH1) + 1
+			if end > uint64(len(code)) {
+				// Keep the truncated push as data, along with its opcode
+				instr.arg, instr.valid = code[pc:], false
+				instrs = append(instrs, instr)
+				break
+			}
+			instr.arg = code[pc+1 : end]
+		}
+		instrs = append(instrs, instr)
+		pc += 1 + uint64(len(instr.arg))
+	}
+	// Label the jump destinations targeted by push and jump pairs
+	dests := make(map[uint64]bool)
+	for _, instr := range instrs {
+		if instr.valid && instr.op == vm.JUMPDEST {
+			dests[instr.pc] = true
+		}
+	}
+	var (
+		labels = make(map[uint64]string) // Labels of the jump destinations by position
+		refs   = make(map[int]string)    // Labels pushed by the instructions by index
+	)
+	for i, instr := range instrs {
+		if !instr.valid || !instr.op.IsPush() || i+1 == len(instrs) || (instrs[i+1].op != vm.JUMP && instrs[i+1].op != vm.JUMPI) {
+			continue
+		}
+		if dest := new(big.Int).SetBytes(instr.arg); dest.IsUint64() && dests[dest.Uint64()] {
+			labels[dest.Uint64()] = fmt.Sprintf("label_%x", dest.Uint64())
+			refs[i] = labels[dest.Uint64()]
+		}
+	}
+	// Write the instructions, gathering consecutive invalid ones into data
+	var (
+		source strings.Builder
+		data   []byte
+	)
+	flush := func() {
+		for len(data) > 0 {
+			n := len(data)
+			if n > 32 {
+				n = 32
+			}
+			fmt.Fprintf(&source, "\t%%data 0x%x\n", data[:n])
+			data = data[n:]
+		}
+	}
+	for i, instr := range instrs {
+		if !instr.valid {
+			if instr.arg != nil {
+				data = append(data, instr.arg...)
+			} else {
+				data = append(data, byte(instr.op))
+			}
+			continue
+		}
+		flush()
+		switch {
+		case instr.op == vm.JUMPDEST && labels[instr.pc] != "":
+			fmt.Fprintf(&source, "%s:\n", labels[instr.pc])
+		case refs[i] != "":
+			fmt.Fprintf(&source, "\t%v @%s\n", instr.op, refs[i])
+		case instr.op.IsPush():
+			fmt.Fprintf(&source, "\t%v 0x%x\n", instr.op, instr.arg)
+		default:
+			fmt.Fprintf(&source, "\t%v\n", instr.op)
+		}
+	}
+	flush()
+	return source.String()
+}
@@ -17,6 +17,7 @@
 package asm
 
 import (
+	"strings"
 	"testing"
 
 	"encoding/hex"
@@ -72,3 +73,37 @@ func TestInstructionIteratorEmpty(t *testing.T) {
 		t.Errorf("Expected 0, but got %v instead.", cnt)
 	}
 }
+
+// Tests that disassembled code compiles back to the same code, with the jump
+// destinations labelled.
+func TestDisassembleSource(t *testing.T) {
+	tests := []struct {
+		code   string
+		labels []string
+	}{
+		// PUSH1 4, JUMP, INVALID, JUMPDEST, PUSH2 0x0004, PUSH1 1, JUMPI, STOP
+		{"600456fe5b61000460015700", []string{"label_4:", "PUSH1 @label_4", "PUSH2 0x0004"}},
+		// Undefined opcodes and a truncated push
+		{"0c5b21600a5b62ff", []string{"JUMPDEST"}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		code, _ := hex.DecodeString(test.code)
+		source := DisassembleSource(code)
+		for _, label := range test.labels {
+			if !strings.Contains(source, label) {
+				t.Errorf("code %s: source missing %q:\n%s", test.code, label, source)
+			}
+		}
+		c := NewCompiler(false)
+		c.Feed(Lex([]byte(source), false))
+		output, errs := c.Compile()
+		if len(errs) != 0 {
+			t.Errorf("code %s: compile error: %v\nsource:\n%s", test.code, errs, source)
+			continue
+		}
+		if output != test.code {
+			t.Errorf("code %s: round trip mismatch: have %s\nsource:\n%s", test.code, output, source)
+		}
+	}
+}
@@ -17,75 +17,323 @@
 package asm
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/vm"
 )
 
+// maxExpansionDepth is the maximum nesting of macro expansions, includes and
+// constant definitions, guarding against infinite recursion.
+const maxExpansionDepth = 64
+
 // Compiler contains information about the parsed source
-// and holds the tokens for the program.
+// and holds the lines of the program.
+//
+// Besides instructions and label definitions, the source
+// may contain the following directives:
+//
+//	%define NAME value     defines a constant, referenced as $NAME
+//	%macro NAME param...   defines a macro, invoked as "NAME arg...",
+//	  ...                  its body referencing the parameters as
+//	%end                   $param and its labels being local to it
+//	%include "file"        includes the source of another file
+//	%data [NAME] value...  emits raw data, labelled @NAME and with
+//	                       its size defined as the constant $NAME_size
 type Compiler struct {
-	tokens []token
-	binary []interface{}
+	lines []line // Preprocessed lines of the program
 
-	labels map[string]int
+	constants map[string]token  // Defined constants by name
+	macros    map[string]*macro // Defined macros by name
+	labels    map[string]int    // Positions of the labels, once laid out
 
-	pc, pos int
+	file       string   // Path of the source being fed
+	including  []string // Paths of the files being included, to detect cycles
+	expansions int      // Number of macro expansions, to name their local labels
+	errors     []error  // Errors found while preprocessing
 
 	debug bool
 }
 
+// line is a source line, without its line start and end tokens.
+type line struct {
+	tokens []token
+	file   string // Path of the file the line is from, empty for the fed source
+}
+
+// macro is the definition of a macro.
+type macro struct {
+	params []string
+	body   []line
+}
+
+// segmentKind are the different pieces the code is compiled to.
+type segmentKind int
+
+const (
+	opSegment    segmentKind = iota // an opcode
+	pushSegment                     // a push of a value or of the position of a label
+	dataSegment                     // raw data
+	labelSegment                    // the definition of a label at the current position
+)
+
+// segment is a piece of the compiled code.
+type segment struct {
+	kind  segmentKind
+	op    vm.OpCode
+	data  []byte // Pushed value or raw data
+	label string // Label defined or pushed
+	width int    // Width of the push
+	fixed bool   // Whether the width of a label push is explicit
+
+	src  token // Token the segment was compiled from, for reporting errors
+	file string
+}
+
 // newCompiler returns a new allocated compiler.
 func NewCompiler(debug bool) *Compiler {
 	return &Compiler{
-		labels: make(map[string]int),
-		debug:  debug,
+		constants: make(map[string]token),
+		macros:    make(map[string]*macro),
+		labels:    make(map[string]int),
+		debug:     debug,
 	}
 }
 
+// SetFile sets the path of the source fed to the compiler, relative
+// to which the paths of the files it includes are resolved.
+func (c *Compiler) SetFile(path string) {
+	c.file = path
+}
+
 // Feed feeds tokens in to ch and are interpreted by
 // the compiler.
 //
 // feed is the first pass in the compile stage as it
-// collects the used labels in the program and keeps a
-// program counter which is used to determine the locations
-// of the jump dests. The labels can than be used in the
-// second stage to push labels and determine the right
-// position.
+// preprocesses the program: the constants and macros are
+// collected, the macro invocations expanded and the included
+// files fed in place. The remaining lines are compiled in the
+// second stage, once the positions of all labels are known.
 func (c *Compiler) Feed(ch <-chan token) {
-	var prev token
-	for i := range ch {
-		switch i.typ {
-		case number:
-			num := math.MustParseBig256(i.text).Bytes()
-			if len(num) == 0 {
-				num = []byte{0}
+	c.feed(ch, c.file, 0)
+}
+
+// feed preprocesses the source of a file.
+func (c *Compiler) feed(ch <-chan token, file string, depth int) {
+	if file != "" {
+		c.including = append(c.including, filepath.Clean(file))
+		defer func() { c.including = c.including[:len(c.including)-1] }()
+	}
+	var (
+		name string // Name of the macro being defined, empty if invalid
+		def  *macro // Macro being defined, if any
+		decl line   // Line declaring the macro being defined
+	)
+	for _, l := range splitLines(ch, file) {
+		first := l.tokens[0]
+		switch {
+		case def != nil && isDirective(first, "end"):
+			if len(l.tokens) > 1 {
+				c.fail(compileErr(l.file, l.tokens[1], l.tokens[1].text, lineEnd.String()))
 			}
-			c.pc += len(num)
-		case stringValue:
-			c.pc += len(i.text) - 2
-		case element:
-			c.pc++
-		case labelDef:
-			c.labels[i.text] = c.pc
-			c.pc++
-		case label:
-			c.pc += 4
-			if prev.typ == element && isJump(prev.text) {
-				c.pc++
+			if name != "" {
+				c.macros[name] = def
 			}
+			def = nil
+		case def != nil && isDirective(first, "macro"):
+			c.fail(compileErr(l.file, first, "nested macro definition", "%end"))
+		case def != nil:
+			def.body = append(def.body, l)
+		case isDirective(first, "macro"):
+			decl = l
+			name, def = c.defineMacro(l)
+		default:
+			c.process(l, depth)
 		}
+	}
+	if def != nil {
+		c.fail(lineErr(decl, decl.tokens[0], "unterminated macro definition"))
+	}
+}
 
-		c.tokens = append(c.tokens, i)
-		prev = i
+// splitLines reads all the tokens of a source and splits them into its non-empty lines.
+func splitLines(ch <-chan token, file string) []line {
+	var (
+		lines []line
+		cur   []token
+	)
+	for t := range ch {
+		switch t.typ {
+		case lineStart:
+			cur = nil
+		case lineEnd, eof:
+			if len(cur) > 0 {
+				lines = append(lines, line{tokens: cur, file: file})
+			}
+			cur = nil
+		default:
+			cur = append(cur, t)
+		}
 	}
-	if c.debug {
-		fmt.Fprintln(os.Stderr, "found", len(c.labels), "labels")
+	return lines
+}
+
+// process preprocesses a line outside of macro definitions, recording the
+// constant it defines or expanding it if it's an include or macro invocation.
+func (c *Compiler) process(l line, depth int) {
+	first := l.tokens[0]
+	switch {
+	case first.typ == directive:
+		switch first.text {
+		case "define":
+			if len(l.tokens) != 3 || l.tokens[1].typ != element || !isValue(l.tokens[2]) {
+				c.fail(lineErr(l, first, "invalid constant definition, expected %%define NAME value"))
+				return
+			}
+			c.define(l, l.tokens[1], l.tokens[2])
+		case "include":
+			if len(l.tokens) != 2 || l.tokens[1].typ != stringValue {
+				c.fail(lineErr(l, first, "invalid include, expected %%include \"file\""))
+				return
+			}
+			c.include(l, unquote(l.tokens[1].text), depth)
+		case "data":
+			c.lines = append(c.lines, l)
+		case "end":
+			c.fail(lineErr(l, first, "%%end outside of a macro definition"))
+		default:
+			c.fail(lineErr(l, first, "unknown directive %%%s", first.text))
+		}
+	case first.typ == element && c.macros[first.text] != nil:
+		c.expand(l, c.macros[first.text], depth)
+	default:
+		c.lines = append(c.lines, l)
+	}
+}
+
+// define defines a constant.
+func (c *Compiler) define(l line, name, value token) {
+	if _, ok := c.constants[name.text]; ok {
+		c.fail(lineErr(l, name, "constant %s already defined", name.text))
+		return
 	}
+	c.constants[name.text] = value
+}
+
+// include feeds the source of an included file, its path being relative to the
+// file including it.
+func (c *Compiler) include(l line, path string, depth int) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(l.file), path)
+	}
+	for _, file := range c.including {
+		if file == path {
+			c.fail(lineErr(l, l.tokens[0], "include cycle through %s", path))
+			return
+		}
+	}
+	if depth >= maxExpansionDepth {
+		c.fail(lineErr(l, l.tokens[0], "includes nested too deeply"))
+		return
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.fail(lineErr(l, l.tokens[0], "%v", err))
+		return
+	}
+	c.feed(Lex(src, c.debug), path, depth+1)
+}
+
+// defineMacro starts the definition of a macro. The returned name is empty if
+// the definition is invalid, in which case its body is still consumed.
+func (c *Compiler) defineMacro(l line) (string, *macro) {
+	def := new(macro)
+	if len(l.tokens) < 2 || l.tokens[1].typ != element {
+		c.fail(lineErr(l, l.tokens[0], "invalid macro definition, expected %%macro NAME param..."))
+		return "", def
+	}
+	name := l.tokens[1].text
+	if _, ok := pushWidth(name); ok || isJump(name) || isOpcode(name) {
+		c.fail(lineErr(l, l.tokens[1], "macro %s shadows an instruction", name))
+		return "", def
+	}
+	if _, ok := c.macros[name]; ok {
+		c.fail(lineErr(l, l.tokens[1], "macro %s already defined", name))
+		return "", def
+	}
+	for _, param := range l.tokens[2:] {
+		if param.typ != element {
+			c.fail(compileErr(l.file, param, param.text, "parameter name"))
+			return "", def
+		}
+		def.params = append(def.params, param.text)
+	}
+	return name, def
+}
+
+// expand expands the invocation of a macro, substituting its arguments for the
+// references to its parameters and renaming the labels defined in its body, so
+// that they're local to the expansion.
+func (c *Compiler) expand(call line, def *macro, depth int) {
+	name, args := call.tokens[0], call.tokens[1:]
+	if len(args) != len(def.params) {
+		c.fail(lineErr(call, name, "macro %s takes %d arguments, got %d", name.text, len(def.params), len(args)))
+		return
+	}
+	for _, arg := range args {
+		if !isValue(arg) {
+			c.fail(compileErr(call.file, arg, arg.text, "number, string, label or reference"))
+			return
+		}
+	}
+	if depth >= maxExpansionDepth {
+		c.fail(lineErr(call, name, "macro %s expanded too deeply", name.text))
+		return
+	}
+	c.expansions++
+	id := c.expansions
+
+	locals := make(map[string]bool)
+	for _, l := range def.body {
+		for _, t := range l.tokens {
+			if t.typ == labelDef {
+				locals[t.text] = true
+			}
+		}
+	}
+	for _, l := range def.body {
+		expanded := line{tokens: make([]token, len(l.tokens)), file: l.file}
+		for i, t := range l.tokens {
+			switch {
+			case t.typ == reference:
+				for j, param := range def.params {
+					if param == t.text {
+						lineno := t.lineno
+						t = args[j]
+						t.lineno = lineno
+						break
+					}
+				}
+			case (t.typ == label || t.typ == labelDef) && locals[t.text]:
+				t.text = fmt.Sprintf("%s#%d", t.text, id)
+			}
+			expanded.tokens[i] = t
+		}
+		c.process(expanded, depth+1)
+	}
+}
+
+// fail records an error found while preprocessing.
+func (c *Compiler) fail(err error) {
+	c.errors = append(c.errors, err)
 }
 
 // Compile compiles the current tokens and returns a
@@ -93,157 +341,313 @@ func (c *Compiler) Feed(ch <-chan token) {
 // and an error if it failed.
 //
 // compile is the second stage in the compile phase
-// which compiles the tokens to EVM instructions.
+// which compiles the lines to EVM instructions and
+// lays them out, selecting the narrowest push for
+// every label reference.
 func (c *Compiler) Compile() (string, []error) {
-	var errors []error
-	// continue looping over the tokens until
-	// the stack has been exhausted.
-	for c.pos < len(c.tokens) {
-		if err := c.compileLine(); err != nil {
-			errors = append(errors, err)
+	// Define the sizes of the data sections first, so they can be referenced anywhere
+	for _, l := range c.lines {
+		if !isDirective(l.tokens[0], "data") || len(l.tokens) < 2 || l.tokens[1].typ != element {
+			continue
+		}
+		name := l.tokens[1]
+		if data, err := c.data(l, l.tokens[2:]); err == nil {
+			size := token{typ: number, lineno: name.lineno, text: strconv.Itoa(len(data))}
+			c.define(l, token{typ: element, lineno: name.lineno, text: name.text + "_size"}, size)
 		}
 	}
+	errors := append([]error(nil), c.errors...)
 
-	// turn the binary to hex
-	var bin string
-	for _, v := range c.binary {
-		switch v := v.(type) {
-		case vm.OpCode:
-			bin += fmt.Sprintf("%x", []byte{byte(v)})
-		case []byte:
-			bin += fmt.Sprintf("%x", v)
+	var segments []segment
+	for _, l := range c.lines {
+		segs, err := c.compileLine(l)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		segments = append(segments, segs...)
+	}
+	// Make sure all labels are defined exactly once before laying them out
+	defined := make(map[string]bool)
+	for _, s := range segments {
+		if s.kind == labelSegment {
+			if defined[s.label] {
+				errors = append(errors, segmentErr(s, "label %s already defined", s.label))
+			}
+			defined[s.label] = true
+		}
+	}
+	for _, s := range segments {
+		if s.kind == pushSegment && s.label != "" && !defined[s.label] {
+			errors = append(errors, segmentErr(s, "undefined label @%s", s.label))
+		}
+	}
+	if len(errors) > 0 {
+		return "", errors
+	}
+	c.layout(segments)
+	if c.debug {
+		fmt.Fprintln(os.Stderr, "found", len(c.labels), "labels")
+	}
+	// Assemble the laid out segments
+	var bin []byte
+	for _, s := range segments {
+		switch s.kind {
+		case opSegment:
+			bin = append(bin, byte(s.op))
+		case pushSegment:
+			value := s.data
+			if s.label != "" {
+				value = big.NewInt(int64(c.labels[s.label])).Bytes()
+				if len(value) > s.width {
+					errors = append(errors, segmentErr(s, "position %d of label @%s exceeds PUSH%d", c.labels[s.label], s.label, s.width))
+					continue
+				}
+				value = common.LeftPadBytes(value, s.width)
+			}
+			bin = append(bin, byte(vm.PUSH1)+byte(s.width-1))
+			bin = append(bin, value...)
+		case dataSegment:
+			bin = append(bin, s.data...)
 		}
 	}
-	return bin, errors
+	if len(errors) > 0 {
+		return "", errors
+	}
+	return hex.EncodeToString(bin), nil
 }
 
-// next returns the next token and increments the
-// position.
-func (c *Compiler) next() token {
-	token := c.tokens[c.pos]
-	c.pos++
-	return token
+// layout assigns the positions of the labels, widening the automatically sized
+// label pushes until the positions of their labels fit. As pushes only ever get
+// wider, this terminates once all positions fit.
+func (c *Compiler) layout(segments []segment) {
+	for {
+		c.labels = make(map[string]int)
+		pc := 0
+		for _, s := range segments {
+			switch s.kind {
+			case opSegment:
+				pc++
+			case pushSegment:
+				pc += 1 + s.width
+			case dataSegment:
+				pc += len(s.data)
+			case labelSegment:
+				c.labels[s.label] = pc
+			}
+		}
+		var widened bool
+		for i := range segments {
+			s := &segments[i]
+			if s.kind != pushSegment || s.label == "" || s.fixed {
+				continue
+			}
+			if width := len(big.NewInt(int64(c.labels[s.label])).Bytes()); width > s.width {
+				s.width, widened = width, true
+			}
+		}
+		if !widened {
+			return
+		}
+	}
 }
 
 // compileLine compiles a single line instruction e.g.
 // "push 1", "jump @label".
-func (c *Compiler) compileLine() error {
-	n := c.next()
-	if n.typ != lineStart {
-		return compileErr(n, n.typ.String(), lineStart.String())
-	}
-
-	lvalue := c.next()
-	switch lvalue.typ {
-	case eof:
-		return nil
+func (c *Compiler) compileLine(l line) ([]segment, error) {
+	first := l.tokens[0]
+	switch first.typ {
 	case element:
-		if err := c.compileElement(lvalue); err != nil {
-			return err
-		}
+		return c.compileElement(l)
 	case labelDef:
-		c.compileLabel()
-	case lineEnd:
-		return nil
+		if len(l.tokens) > 1 {
+			return nil, compileErr(l.file, l.tokens[1], l.tokens[1].text, lineEnd.String())
+		}
+		return []segment{
+			{kind: labelSegment, label: first.text, src: first, file: l.file},
+			{kind: opSegment, op: vm.JUMPDEST, src: first, file: l.file},
+		}, nil
+	case directive:
+		// Only data directives are left after preprocessing
+		var segments []segment
+		args := l.tokens[1:]
+		if len(args) > 0 && args[0].typ == element {
+			segments = append(segments, segment{kind: labelSegment, label: args[0].text, src: args[0], file: l.file})
+			args = args[1:]
+		}
+		data, err := c.data(l, args)
+		if err != nil {
+			return nil, err
+		}
+		return append(segments, segment{kind: dataSegment, data: data, src: first, file: l.file}), nil
 	default:
-		return compileErr(lvalue, lvalue.text, fmt.Sprintf("%v or %v", labelDef, element))
-	}
-
-	if n := c.next(); n.typ != lineEnd {
-		return compileErr(n, n.text, lineEnd.String())
-	}
-
-	return nil
-}
-
-// compileNumber compiles the number to bytes
-func (c *Compiler) compileNumber(element token) (int, error) {
-	num := math.MustParseBig256(element.text).Bytes()
-	if len(num) == 0 {
-		num = []byte{0}
+		return nil, compileErr(l.file, first, first.text, fmt.Sprintf("%v or %v", labelDef, element))
 	}
-	c.pushBin(num)
-	return len(num), nil
 }
 
 // compileElement compiles the element (push & label or both)
 // to a binary representation and may error if incorrect statements
 // where fed.
-func (c *Compiler) compileElement(element token) error {
-	// check for a jump. jumps must be read and compiled
-	// from right to left.
-	if isJump(element.text) {
-		rvalue := c.next()
-		switch rvalue.typ {
-		case number:
-			// TODO figure out how to return the error properly
-			c.compileNumber(rvalue)
-		case stringValue:
-			// strings are quoted, remove them.
-			c.pushBin(rvalue.text[1 : len(rvalue.text)-2])
-		case label:
-			c.pushBin(vm.PUSH4)
-			pos := big.NewInt(int64(c.labels[rvalue.text])).Bytes()
-			pos = append(make([]byte, 4-len(pos)), pos...)
-			c.pushBin(pos)
-		case lineEnd:
-			c.pos--
-		default:
-			return compileErr(rvalue, rvalue.text, "number, string or label")
+func (c *Compiler) compileElement(l line) ([]segment, error) {
+	element, args := l.tokens[0], l.tokens[1:]
+	name := strings.ToUpper(element.text)
+
+	// handle pushes, either of an explicit width or
+	// of the narrowest one fitting the value.
+	if width, ok := pushWidth(name); ok {
+		if len(args) != 1 {
+			return nil, argsErr(l, element, args)
 		}
-		// push the operation
-		c.pushBin(toBinary(element.text))
-		return nil
-	} else if isPush(element.text) {
-		// handle pushes. pushes are read from left to right.
-		var value []byte
-
-		rvalue := c.next()
-		switch rvalue.typ {
-		case number:
-			value = math.MustParseBig256(rvalue.text).Bytes()
-			if len(value) == 0 {
-				value = []byte{0}
+		s, err := c.compilePush(l, args[0], width)
+		if err != nil {
+			return nil, err
+		}
+		return []segment{s}, nil
+	}
+	if isJump(name) {
+		// jumps may push their destination first.
+		var segments []segment
+		switch len(args) {
+		case 0:
+		case 1:
+			s, err := c.compilePush(l, args[0], 0)
+			if err != nil {
+				return nil, err
 			}
-		case stringValue:
-			value = []byte(rvalue.text[1 : len(rvalue.text)-1])
-		case label:
-			value = big.NewInt(int64(c.labels[rvalue.text])).Bytes()
-			value = append(make([]byte, 4-len(value)), value...)
+			segments = append(segments, s)
 		default:
-			return compileErr(rvalue, rvalue.text, "number, string or label")
+			return nil, compileErr(l.file, args[1], args[1].text, lineEnd.String())
 		}
+		return append(segments, segment{kind: opSegment, op: toBinary(name), src: element, file: l.file}), nil
+	}
+	if !isOpcode(name) {
+		return nil, lineErr(l, element, "unknown instruction %s", element.text)
+	}
+	if len(args) > 0 {
+		return nil, compileErr(l.file, args[0], args[0].text, lineEnd.String())
+	}
+	return []segment{{kind: opSegment, op: toBinary(name), src: element, file: l.file}}, nil
+}
 
-		if len(value) > 32 {
-			return fmt.Errorf("%d type error: unsupported string or number with size > 32", rvalue.lineno)
+// compilePush compiles the push of a value or label with the given width, or
+// the narrowest one fitting it if zero.
+func (c *Compiler) compilePush(l line, arg token, width int) (segment, error) {
+	value, err := c.resolve(l, arg)
+	if err != nil {
+		return segment{}, err
+	}
+	s := segment{kind: pushSegment, width: width, src: arg, file: l.file}
+	switch value.typ {
+	case label:
+		s.label, s.fixed = value.text, width > 0
+		if s.width == 0 {
+			s.width = 1
 		}
-
-		c.pushBin(vm.OpCode(int(vm.PUSH1) - 1 + len(value)))
-		c.pushBin(value)
-	} else {
-		c.pushBin(toBinary(element.text))
+		return s, nil
+	case number:
+		if s.data, err = parseNumber(l, value); err != nil {
+			return segment{}, err
+		}
+	case stringValue:
+		s.data = []byte(unquote(value.text))
+		if len(s.data) == 0 {
+			s.data = []byte{0}
+		}
+	default:
+		return segment{}, compileErr(l.file, arg, arg.text, "number, string, label or reference")
 	}
+	if len(s.data) > 32 {
+		return segment{}, fmt.Errorf("%s type error: unsupported string or number with size > 32", position(l.file, arg.lineno))
+	}
+	switch {
+	case s.width == 0:
+		s.width = len(s.data)
+	case len(s.data) > s.width:
+		return segment{}, lineErr(l, arg, "value %s exceeds PUSH%d", arg.text, s.width)
+	default:
+		s.data = common.LeftPadBytes(s.data, s.width)
+	}
+	return s, nil
+}
 
-	return nil
+// data compiles the values of a data directive to raw bytes. Hexadecimal numbers
+// are taken literally, keeping their leading zeros.
+func (c *Compiler) data(l line, args []token) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, lineErr(l, l.tokens[0], "missing data")
+	}
+	var data []byte
+	for _, arg := range args {
+		value, err := c.resolve(l, arg)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case value.typ == stringValue:
+			data = append(data, unquote(value.text)...)
+		case value.typ == number && (strings.HasPrefix(value.text, "0x") || strings.HasPrefix(value.text, "0X")):
+			digits := value.text[2:]
+			if len(digits)%2 == 1 {
+				digits = "0" + digits
+			}
+			b, err := hex.DecodeString(digits)
+			if err != nil {
+				return nil, lineErr(l, arg, "invalid number %s", value.text)
+			}
+			data = append(data, b...)
+		case value.typ == number:
+			b, err := parseNumber(l, value)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, b...)
+		default:
+			return nil, compileErr(l.file, arg, arg.text, "number, string or reference")
+		}
+	}
+	return data, nil
 }
 
-// compileLabel pushes a jumpdest to the binary slice.
-func (c *Compiler) compileLabel() {
-	c.pushBin(vm.JUMPDEST)
+// resolve returns the value of a constant reference, or the token itself if it
+// isn't a reference.
+func (c *Compiler) resolve(l line, t token) (token, error) {
+	for depth := 0; t.typ == reference; depth++ {
+		value, ok := c.constants[t.text]
+		if !ok {
+			return t, lineErr(l, t, "undefined constant $%s", t.text)
+		}
+		if depth >= maxExpansionDepth {
+			return t, lineErr(l, t, "constant $%s defined recursively", t.text)
+		}
+		value.lineno = t.lineno
+		t = value
+	}
+	return t, nil
 }
 
-// pushBin pushes the value v to the binary stack.
-func (c *Compiler) pushBin(v interface{}) {
-	if c.debug {
-		fmt.Printf("%d: %v\n", len(c.binary), v)
+// parseNumber parses a number to its big endian bytes, at least one byte long.
+func parseNumber(l line, t token) ([]byte, error) {
+	num, ok := math.ParseBig256(t.text)
+	if !ok {
+		return nil, lineErr(l, t, "invalid number %s", t.text)
+	}
+	if b := num.Bytes(); len(b) > 0 {
+		return b, nil
 	}
-	c.binary = append(c.binary, v)
+	return []byte{0}, nil
 }
 
-// isPush returns whether the string op is either any of
-// push(N).
-func isPush(op string) bool {
-	return strings.ToUpper(op) == "PUSH"
+// pushWidth returns whether the string op is either push or
+// any of push(N), along with N, or zero for push.
+func pushWidth(op string) (int, bool) {
+	op = strings.ToUpper(op)
+	if op == "PUSH" {
+		return 0, true
+	}
+	if code := vm.StringToOp(op); code.IsPush() && code.String() == op {
+		return int(code-vm.PUSH1) + 1, true
+	}
+	return 0, false
 }
 
 // isJump returns whether the string op is jump(i)
@@ -251,26 +655,75 @@ func isJump(op string) bool {
 	return strings.ToUpper(op) == "JUMPI" || strings.ToUpper(op) == "JUMP"
 }
 
+// isOpcode returns whether the string op names an opcode.
+func isOpcode(op string) bool {
+	op = strings.ToUpper(op)
+	return vm.StringToOp(op).String() == op
+}
+
+// isDirective returns whether the token is the given directive.
+func isDirective(t token, name string) bool {
+	return t.typ == directive && t.text == name
+}
+
+// isValue returns whether the token can be used as a value.
+func isValue(t token) bool {
+	return t.typ == number || t.typ == stringValue || t.typ == label || t.typ == reference
+}
+
 // toBinary converts text to a vm.OpCode
 func toBinary(text string) vm.OpCode {
 	return vm.StringToOp(strings.ToUpper(text))
 }
 
+// unquote removes the quotes around a lexed string.
+func unquote(text string) string {
+	return text[1 : len(text)-1]
+}
+
+// position formats the position of a line, omitting the file of the fed source.
+func position(file string, lineno int) string {
+	if file == "" {
+		return strconv.Itoa(lineno)
+	}
+	return fmt.Sprintf("%s:%d", file, lineno)
+}
+
 type compileError struct {
 	got  string
 	want string
 
+	file   string
 	lineno int
 }
 
 func (err compileError) Error() string {
-	return fmt.Sprintf("%d syntax error: unexpected %v, expected %v", err.lineno, err.got, err.want)
+	return fmt.Sprintf("%s syntax error: unexpected %v, expected %v", position(err.file, err.lineno), err.got, err.want)
 }
 
-func compileErr(c token, got, want string) error {
+func compileErr(file string, c token, got, want string) error {
 	return compileError{
 		got:    got,
 		want:   want,
+		file:   file,
 		lineno: c.lineno,
 	}
 }
+
+// argsErr reports a push without exactly one argument.
+func argsErr(l line, element token, args []token) error {
+	if len(args) == 0 {
+		return compileErr(l.file, element, lineEnd.String(), "number, string, label or reference")
+	}
+	return compileErr(l.file, args[1], args[1].text, lineEnd.String())
+}
+
+// lineErr creates an error at the position of a token of a line.
+func lineErr(l line, t token, format string, args ...interface{}) error {
+	return fmt.Errorf("%s error: %s", position(l.file, t.lineno), fmt.Sprintf(format, args...))
+}
+
+// segmentErr creates an error at the position a segment was compiled from.
+func segmentErr(s segment, format string, args ...interface{}) error {
+	return fmt.Errorf("%s error: %s", position(s.file, s.src.lineno), fmt.Sprintf(format, args...))
+}
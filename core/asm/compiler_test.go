@@ -3,6 +3,10 @@
 package asm
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -16,14 +20,14 @@ func TestCompiler(t *testing.T) {
 	label:
 	PUSH @label
 `,
-			output: "5a5b6300000001",
+			output: "5a5b6001",
 		},
 		{
 			input: `
 	PUSH @label
 	label:
 `,
-			output: "63000000055b",
+			output: "60025b",
 		},
 		{
 			input: `
@@ -31,14 +35,72 @@ func TestCompiler(t *testing.T) {
 	JUMP
 	label:
 `,
-			output: "6300000006565b",
+			output: "6003565b",
 		},
 		{
 			input: `
 	JUMP @label
 	label:
 `,
-			output: "6300000006565b",
+			output: "6003565b",
+		},
+		{
+			input: `
+	PUSH4 @label
+	label:
+`,
+			output: "63000000055b",
+		},
+		{
+			input: `
+	PUSH2 1
+	push32 0x01
+`,
+			output: "6100017f0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{
+			input: `
+	PUSH $SIZE
+	%define SIZE $WORD
+	%define WORD 0x20
+`,
+			output: "6020",
+		},
+		{
+			input: `
+	%macro countdown n
+		PUSH $n
+		loop:
+		PUSH 1
+		SWAP1
+		SUB
+		DUP1
+		JUMPI @loop
+	%end
+	countdown 3
+	countdown 2
+`,
+			output: "60035b60019003806002576002" + "5b600190038060" + "0d57",
+		},
+		{
+			input: `
+	PUSH $table_size
+	PUSH @table
+	PUSH 0
+	CODECOPY
+	STOP
+	%data table 0x0001 "ab"
+	%data 0xfe
+`,
+			output: "600460086000390000016162fe",
+		},
+		{
+			input: `
+	PUSH @end
+	%data ` + "0x" + strings.Repeat("00", 256) + `
+	end:
+`,
+			output: "610103" + strings.Repeat("00", 256) + "5b",
 		},
 	}
 	for _, test := range tests {
@@ -55,3 +117,74 @@ func TestCompiler(t *testing.T) {
 		}
 	}
 }
+
+func compile(input, file string) (string, []error) {
+	c := NewCompiler(false)
+	c.SetFile(file)
+	c.Feed(Lex([]byte(input), false))
+	return c.Compile()
+}
+
+func TestCompilerErrors(t *testing.T) {
+	tests := []struct {
+		input, err string
+	}{
+		{"PUSH @missing\n", "0 error: undefined label @missing"},
+		{"PUSH $missing\n", "0 error: undefined constant $missing"},
+		{"a:\na:\n", "1 error: label a already defined"},
+		{"PUSH1 0x0100\n", "0 error: value 0x0100 exceeds PUSH1"},
+		{"FOO\n", "0 error: unknown instruction FOO"},
+		{"GAS 1\n", "0 syntax error: unexpected 1, expected end of line"},
+		{"%macro m a\n%end\nm\n", "2 error: macro m takes 1 arguments, got 0"},
+		{"%macro m\nm\n%end\nm\n", "1 error: macro m expanded too deeply"},
+		{"%macro add\n%end\n", "0 error: macro add shadows an instruction"},
+		{"%macro m\nGAS\n", "0 error: unterminated macro definition"},
+		{"%end\n", "0 error: %end outside of a macro definition"},
+		{"%foo\n", "0 error: unknown directive %foo"},
+		{"%data\n", "0 error: missing data"},
+	}
+	for _, test := range tests {
+		_, errs := compile(test.input, "")
+		if len(errs) == 0 {
+			t.Errorf("input %q: expected error %q", test.input, test.err)
+			continue
+		}
+		if errs[0].Error() != test.err {
+			t.Errorf("input %q: error mismatch: have %q, want %q", test.input, errs[0], test.err)
+		}
+	}
+}
+
+func TestCompilerInclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asm-include")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"main.easm":       "%include \"lib/store.easm\"\nstore $ANSWER 0\n",
+		"lib/store.easm":  "%include \"consts.easm\"\n%macro store value slot\nPUSH $value\nPUSH $slot\nSSTORE\n%end\n",
+		"lib/consts.easm": "%define ANSWER 42\n",
+		"cycle.easm":      "GAS\n%include \"cycle.easm\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	main := filepath.Join(dir, "main.easm")
+	output, errs := compile(files["main.easm"], main)
+	if len(errs) != 0 {
+		t.Fatalf("compile error: %v", errs)
+	}
+	if output != "602a600055" {
+		t.Errorf("incorrect output: have %s, want 602a600055", output)
+	}
+	cycle := filepath.Join(dir, "cycle.easm")
+	if _, errs := compile(files["cycle.easm"], cycle); len(errs) != 1 || !strings.Contains(errs[0].Error(), "include cycle") {
+		t.Errorf("include cycle not detected: %v", errs)
+	}
+}
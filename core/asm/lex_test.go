@@ -68,6 +68,18 @@ func TestLexer(t *testing.T) {
 			input:  "@label123",
 			tokens: []token{{typ: lineStart}, {typ: label, text: "label123"}, {typ: eof}},
 		},
+		{
+			input:  "%define SIZE 32",
+			tokens: []token{{typ: lineStart}, {typ: directive, text: "define"}, {typ: element, text: "SIZE"}, {typ: number, text: "32"}, {typ: eof}},
+		},
+		{
+			input:  "PUSH $SIZE",
+			tokens: []token{{typ: lineStart}, {typ: element, text: "PUSH"}, {typ: reference, text: "SIZE"}, {typ: eof}},
+		},
+		{
+			input:  "PUSH 1, 2",
+			tokens: []token{{typ: lineStart}, {typ: element, text: "PUSH"}, {typ: number, text: "1"}, {typ: invalidStatement, text: ","}, {typ: number, text: "2"}, {typ: eof}},
+		},
 	}
 
 	for _, test := range tests {
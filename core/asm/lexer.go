@@ -52,6 +52,8 @@ const (
 	labelDef                          // label definition is emitted when a new label is found
 	number                            // number is emitted when a number is found
 	stringValue                       // stringValue is emitted when a string has been found
+	directive                         // directive is emitted when a directive (e.g. %define) is found
+	reference                         // reference is emitted when a constant or macro parameter is referenced
 
 	Numbers            = "1234567890"                                           // characters representing any decimal number
 	HexadecimalNumbers = Numbers + "aAbBcCdDeEfF"                               // characters representing any hexadecimal
@@ -76,6 +78,8 @@ var stringtokenTypes = []string{
 	labelDef:         "label definition",
 	number:           "number",
 	stringValue:      "string",
+	directive:        "directive",
+	reference:        "reference",
 }
 
 // lexer is the basic construct for parsing
@@ -215,8 +219,16 @@ func lexLine(l *lexer) stateFn {
 			return lexLabel
 		case r == '"':
 			return lexInsideString
-		default:
+		case r == '%':
+			l.ignore()
+			return lexDirective
+		case r == '$':
+			l.ignore()
+			return lexReference
+		case r == 0:
 			return nil
+		default:
+			l.emit(invalidStatement)
 		}
 	}
 }
@@ -241,6 +253,27 @@ func lexLabel(l *lexer) stateFn {
 	return lexLine
 }
 
+// lexDirective parses the name of the current directive
+// and emits it without its leading percent sign.
+func lexDirective(l *lexer) stateFn {
+	l.acceptRun(Alpha + "_" + Numbers)
+
+	l.emit(directive)
+
+	return lexLine
+}
+
+// lexReference parses the name of the current constant or
+// macro parameter reference and emits it without its leading
+// dollar sign.
+func lexReference(l *lexer) stateFn {
+	l.acceptRun(Alpha + "_" + Numbers)
+
+	l.emit(reference)
+
+	return lexLine
+}
+
 // lexInsideString lexes the inside of a string until
 // the state function finds the closing quote.
 // It returns the lex text state function.
@@ -0,0 +1,69 @@
+
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// Validation steps a bad block can fail, as persisted along with it.
+const (
+	BadBlockVerify      = "verify"      // Header or body verification
+	BadBlockBlacklisted = "blacklisted" // Block hash explicitly banned
+	BadBlockProcess     = "process"     // Transaction execution
+	BadBlockState       = "state"       // Post-state validation (gas, bloom, receipts, root)
+)
+
+// BadBlock retrieves a bad block rejected by the chain, or nil if it's unknown.
+func (bc *BlockChain) BadBlock(hash common.Hash) *rawdb.BadBlock {
+	return rawdb.ReadBadBlock(bc.db, hash)
+}
+
+// BadBlockPrestate re-executes a bad block on top of the state of its parent and
+// returns the part of the parent state it accessed, i.e. the pre-state needed to
+// replay the block in isolation. The block is expected to fail, execution errors
+// are ignored and only stop the recording at the failing transaction.
+func (bc *BlockChain) BadBlockPrestate(bad *rawdb.BadBlock) (GenesisAlloc, error) {
+	if bad.ParentRoot == (common.Hash{}) {
+		return nil, errors.New("parent of bad block unknown")
+	}
+	statedb, err := state.New(bad.ParentRoot, bc.stateCache, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parent state unavailable: %v", err)
+	}
+	statedb.RecordAccesses()
+	NewStateProcessor(bc.chainConfig, bc, bc.engine).Process(bad.Block, statedb, vm.Config{})
+
+	// Gather the accessed accounts from a pristine copy of the parent state
+	parent, err := state.New(bad.ParentRoot, bc.stateCache, nil)
+	if err != nil {
+		return nil, err
+	}
+	alloc := make(GenesisAlloc)
+	for addr, slots := range statedb.Accesses() {
+		if !parent.Exist(addr) {
+			continue
+		}
+		account := GenesisAccount{
+			Balance: parent.GetBalance(addr),
+			Nonce:   parent.GetNonce(addr),
+			Code:    parent.GetCode(addr),
+		}
+		for _, slot := range slots {
+			if value := parent.GetState(addr, slot); value != (common.Hash{}) {
+				if account.Storage == nil {
+					account.Storage = make(map[common.Hash]common.Hash)
+				}
+				account.Storage[slot] = value
+			}
+		}
+		alloc[addr] = account
+	}
+	return alloc, nil
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that rejected blocks are persisted with the receipts executed before
+// the failure and the failing step, and that their pre-state can be gathered.
+func TestBadBlockPersistence(t *testing.T) {
+	gspec, blocks := makeWitnessTestChain(params.TestChainConfig, 5)
+
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, err := NewBlockChain(db, nil, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	if n, err := chain.InsertChain(blocks[:4]); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Import a block with an invalid state root, failing post-state validation
+	header := blocks[4].Header()
+	header.Root = common.Hash{0xba, 0xd}
+	badState := types.NewBlockWithHeader(header).WithBody(blocks[4].Transactions(), nil)
+	if _, err := chain.InsertChain(types.Blocks{badState}); err == nil {
+		t.Fatalf("bad state root accepted")
+	}
+	// Import a block replaying a transaction, failing execution
+	txs := blocks[4].Transactions()
+	badProcess := types.NewBlock(blocks[4].Header(), types.Transactions{txs[0], txs[0]}, nil, nil, trie.NewStackTrie(nil))
+	if _, err := chain.InsertChain(types.Blocks{badProcess}); err == nil {
+		t.Fatalf("replayed transaction accepted")
+	}
+	chain.Stop()
+
+	// Restart the chain and ensure the bad blocks are remembered
+	chain, err = NewBlockChain(db, nil, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to recreate tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if bads := chain.BadBlocks(); len(bads) != 2 {
+		t.Fatalf("bad block count mismatch: have %d, want %d", len(bads), 2)
+	}
+	for _, test := range []struct {
+		block    *types.Block
+		stage    string
+		receipts int
+	}{
+		{badState, BadBlockState, 2},
+		{badProcess, BadBlockProcess, 1},
+	} {
+		bad := chain.BadBlock(test.block.Hash())
+		if bad == nil {
+			t.Fatalf("bad block %x not persisted", test.block.Hash())
+		}
+		if bad.Stage != test.stage || bad.Error == "" {
+			t.Errorf("bad block %x: failure mismatch: have %s %q, want %s", test.block.Hash(), bad.Stage, bad.Error, test.stage)
+		}
+		if len(bad.Receipts) != test.receipts {
+			t.Errorf("bad block %x: receipt count mismatch: have %d, want %d", test.block.Hash(), len(bad.Receipts), test.receipts)
+		}
+		if bad.ParentRoot != blocks[3].Root() {
+			t.Errorf("bad block %x: parent root mismatch: have %x, want %x", test.block.Hash(), bad.ParentRoot, blocks[3].Root())
+		}
+	}
+	// Gather the pre-state of the bad block and check it matches the parent state
+	alloc, err := chain.BadBlockPrestate(chain.BadBlock(badState.Hash()))
+	if err != nil {
+		t.Fatalf("failed to gather pre-state: %v", err)
+	}
+	parent, _ := chain.StateAt(blocks[3].Root())
+
+	sender, _ := types.Sender(types.HomesteadSigner{}, txs[0])
+	contract := *txs[0].To()
+	for _, addr := range []common.Address{sender, contract, common.BytesToAddress([]byte{0xbb, 0xbb})} {
+		account, ok := alloc[addr]
+		if !ok {
+			t.Fatalf("accessed account %x missing from pre-state", addr)
+		}
+		if account.Balance.Cmp(parent.GetBalance(addr)) != 0 || account.Nonce != parent.GetNonce(addr) || len(account.Code) != len(parent.GetCode(addr)) {
+			t.Errorf("account %x mismatch", addr)
+		}
+	}
+	// The contract clears the slot written two blocks before
+	slot := common.BigToHash(blocks[2].Number())
+	if value := alloc[contract].Storage[slot]; value == (common.Hash{}) || value != parent.GetState(contract, slot) {
+		t.Errorf("accessed slot %x mismatch: have %x, want %x", slot, value, parent.GetState(contract, slot))
+	}
+}
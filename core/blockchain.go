@@ -72,10 +72,10 @@ const (
 	bodyCacheLimit      = 256
 	blockCacheLimit     = 256
 	receiptsCacheLimit  = 32
+	stateDiffCacheLimit = 256
 	txLookupCacheLimit  = 1024
 	maxFutureBlocks     = 256
 	maxTimeFutureBlocks = 30
-	badBlockLimit       = 10
 	TriesInMemory       = 128
 
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
@@ -111,11 +111,16 @@ type CacheConfig struct {
 	TrieCleanJournal    string        // Disk journal for saving clean cache entries.
 	TrieCleanRejournal  time.Duration // Time interval to dump clean cache to disk periodically
 	TrieCleanNoPrefetch bool          // Whether to disable heuristic state prefetching for followup blocks
+	TrieNoNodePrefetch  bool          // Whether to disable concurrent trie node prefetching during block processing
 	TrieDirtyLimit      int           // Memory limit (MB) at which to start flushing dirty trie nodes to disk
 	TrieDirtyDisabled   bool          // Whether to disable trie write caching and GC altogether (archive node)
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
-	Preimages           bool          // Whether to store preimage of trie key to the disk
+	StateDiffs          bool          // Whether to store per-block state diffs for historical state queries
+	StateDiffRewind     uint64        // Maximum number of blocks historical state queries may rewind (0 = default)
+	Witnesses           bool          // Whether to generate and store execution witnesses of imported blocks
+	ChainEvents         bool          // Whether to persist the log of canonical chain changes
+	ParallelExecution   int           // Number of workers executing block transactions speculatively (0 = sequential)
 
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
@@ -167,6 +172,7 @@ type BlockChain struct {
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
 	blockProcFeed event.Feed
+	chainLogFeed  event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -175,10 +181,14 @@ type BlockChain struct {
 	currentBlock     atomic.Value // Current head of the block chain
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
+	eventHead *rawdb.ChainEventHead // Position of the chain event log (nil if disabled or new)
+	eventSent uint64                // Last chain event log cursor announced to subscribers
+
 	stateCache    state.Database // State database to reuse between imports (contains state cache)
 	bodyCache     *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache  *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	receiptsCache *lru.Cache     // Cache for the most recent receipts per block
+	diffCache     *lru.Cache     // Cache for the most recently accessed state diffs
 	blockCache    *lru.Cache     // Cache for the most recent entire blocks
 	txLookupCache *lru.Cache     // Cache for the most recent transaction lookup data.
 	futureBlocks  *lru.Cache     // future blocks are blocks added for later processing
@@ -194,12 +204,20 @@ type BlockChain struct {
 	processor  Processor  // Block transaction processor interface
 	vmConfig   vm.Config
 
-	badBlocks          *lru.Cache                     // Bad block cache
 	shouldPreserve     func(*types.Block) bool        // Function used to determine whether should preserve the given block.
 	terminateInsert    func(common.Hash, uint64) bool // Testing hook used to terminate ancient receipt chain insertion.
 	writeLegacyJournal bool                           // Testing flag used to flush the snapshot journal in legacy format.
 }
 
+// newStateDatabase creates a state database storing the state in the trie format
+// selected by the chain config.
+func newStateDatabase(db fafdb.Database, config *trie.Config, chainConfig *params.ChainConfig) state.Database {
+	if chainConfig != nil && chainConfig.BinaryTrie {
+		return state.NewBinaryDatabaseWithConfig(db, config)
+	}
+	return state.NewDatabaseWithConfig(db, config)
+}
+
 // NewBlockChain returns a fully initialised block chain using information
 // available in the database. It initialises the default Ethereum Validator and
 // Processor.
@@ -207,39 +225,51 @@ func NewBlockChain(db fafdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if cacheConfig == nil {
 		cacheConfig = defaultCacheConfig
 	}
+	if err := vm.CheckPrecompiles(chainConfig); err != nil {
+		return nil, err
+	}
+	if chainConfig.BinaryTrie && cacheConfig.SnapshotLimit > 0 {
+		log.Warn("Snapshots are not supported with binary tries, disabling")
+		config := *cacheConfig
+		config.SnapshotLimit = 0
+		cacheConfig = &config
+	}
 	bodyCache, _ := lru.New(bodyCacheLimit)
 	bodyRLPCache, _ := lru.New(bodyCacheLimit)
 	receiptsCache, _ := lru.New(receiptsCacheLimit)
+	diffCache, _ := lru.New(stateDiffCacheLimit)
 	blockCache, _ := lru.New(blockCacheLimit)
 	txLookupCache, _ := lru.New(txLookupCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
-	badBlocks, _ := lru.New(badBlockLimit)
 
 	bc := &BlockChain{
 		chainConfig: chainConfig,
 		cacheConfig: cacheConfig,
 		db:          db,
 		triegc:      prque.New(nil),
-		stateCache: state.NewDatabaseWithConfig(db, &trie.Config{
-			Cache:     cacheConfig.TrieCleanLimit,
-			Journal:   cacheConfig.TrieCleanJournal,
-			Preimages: cacheConfig.Preimages,
-		}),
+		stateCache: newStateDatabase(db, &trie.Config{
+			Cache:   cacheConfig.TrieCleanLimit,
+			Journal: cacheConfig.TrieCleanJournal,
+		}, chainConfig),
 		quit:           make(chan struct{}),
 		shouldPreserve: shouldPreserve,
 		bodyCache:      bodyCache,
 		bodyRLPCache:   bodyRLPCache,
 		receiptsCache:  receiptsCache,
+		diffCache:      diffCache,
 		blockCache:     blockCache,
 		txLookupCache:  txLookupCache,
 		futureBlocks:   futureBlocks,
 		engine:         engine,
 		vmConfig:       vmConfig,
-		badBlocks:      badBlocks,
 	}
 	bc.validator = NewBlockValidator(chainConfig, bc, engine)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
-	bc.processor = NewStateProcessor(chainConfig, bc, engine)
+	if cacheConfig.ParallelExecution > 1 {
+		bc.processor = NewParallelStateProcessor(chainConfig, bc, engine, cacheConfig.ParallelExecution)
+	} else {
+		bc.processor = NewStateProcessor(chainConfig, bc, engine)
+	}
 
 	var err error
 	bc.hc, err = NewHeaderChain(db, chainConfig, engine, bc.insertStopped)
@@ -255,6 +285,12 @@ func NewBlockChain(db fafdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bc.currentBlock.Store(nilBlock)
 	bc.currentFastBlock.Store(nilBlock)
 
+	if cacheConfig.ChainEvents {
+		if bc.eventHead = rawdb.ReadChainEventHead(db); bc.eventHead != nil {
+			bc.eventSent = bc.eventHead.Cursor
+		}
+	}
+
 	// Initialize the chain with ancient data if it isn't empty.
 	var txIndexBlock uint64
 
@@ -460,6 +496,15 @@ func (bc *BlockChain) loadLastState() error {
 	if pivot := rawdb.ReadLastPivotNumber(bc.db); pivot != nil {
 		log.Info("Loaded last fast-sync pivot marker", "number", *pivot)
 	}
+	// Catch up the chain event log with any head change not yet logged
+	if bc.cacheConfig.ChainEvents {
+		batch := bc.db.NewBatch()
+		events := bc.appendChainEvents(batch, currentBlock.Header())
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to update chain event log", "err", err)
+		}
+		bc.setChainEventHead(events)
+	}
 	return nil
 }
 
@@ -584,6 +629,15 @@ func (bc *BlockChain) SetHeadBeyondRoot(head uint64, root common.Hash) (uint64,
 			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
 		}
+		// State diffs and witnesses are never migrated into the ancient store
+		rawdb.DeleteStateDiff(db, hash, num)
+		rawdb.DeleteBlockWitness(db, hash, num)
+
+		// Log the removal of the block whilst its header is still available
+		if bc.cacheConfig.ChainEvents {
+			bc.revertChainEvent(db, hash, num)
+		}
+
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
 	// If SetHead was only called as a chain reparation method, try to skip
@@ -602,6 +656,7 @@ func (bc *BlockChain) SetHeadBeyondRoot(head uint64, root common.Hash) (uint64,
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
 	bc.receiptsCache.Purge()
+	bc.diffCache.Purge()
 	bc.blockCache.Purge()
 	bc.txLookupCache.Purge()
 	bc.futureBlocks.Purge()
@@ -624,6 +679,16 @@ func (bc *BlockChain) FastSyncCommitHead(hash common.Hash) error {
 	bc.chainmu.Lock()
 	bc.currentBlock.Store(block)
 	headBlockGauge.Update(int64(block.NumberU64()))
+
+	// The fast synced blocks were never processed, don't announce them
+	if bc.cacheConfig.ChainEvents {
+		batch := bc.db.NewBatch()
+		events := bc.skipChainEvents(batch, block.Header())
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to update chain event log", "err", err)
+		}
+		bc.setChainEventHead(events)
+	}
 	bc.chainmu.Unlock()
 
 	// Destroy any existing state snapshot and regenerate it in the background
@@ -773,10 +838,18 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 		rawdb.WriteHeadHeaderHash(batch, block.Hash())
 		rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	}
+	// Log the canonical chain change atomically with the head update
+	var events *rawdb.ChainEventHead
+	if bc.cacheConfig.ChainEvents {
+		events = bc.appendChainEvents(batch, block.Header())
+	}
 	// Flush the whole batch into the disk, exit the node if failed
 	if err := batch.Write(); err != nil {
 		log.Crit("Failed to update chain indexes and markers", "err", err)
 	}
+	if events != nil {
+		bc.setChainEventHead(events)
+	}
 	// Update all in-memory chain markers in the last step
 	if updateHeads {
 		bc.hc.SetCurrentHeader(block.Header())
@@ -1104,6 +1177,7 @@ func (bc *BlockChain) truncateAncient(head uint64) error {
 	bc.bodyCache.Purge()
 	bc.bodyRLPCache.Purge()
 	bc.receiptsCache.Purge()
+	bc.diffCache.Purge()
 	bc.blockCache.Purge()
 	bc.txLookupCache.Purge()
 	bc.futureBlocks.Purge()
@@ -1512,6 +1586,13 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, state.Preimages())
+	if bc.cacheConfig.StateDiffs {
+		diff, err := state.Diff(bc.chainConfig.IsEIP158(block.Number()))
+		if err != nil {
+			return NonStatTy, err
+		}
+		rawdb.WriteStateDiff(blockBatch, block.Hash(), block.NumberU64(), diff)
+	}
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
@@ -1699,9 +1780,17 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 	senderCacher.recoverFromBlocks(types.MakeSigner(bc.chainConfig, chain[0].Number()), chain)
 
 	var (
-		stats     = insertStats{startTime: mclock.Now()}
-		lastCanon *types.Block
+		stats       = insertStats{startTime: mclock.Now()}
+		lastCanon   *types.Block
+		activeState *state.StateDB
 	)
+	// An early return on a bad block or other error may skip stopping the trie
+	// prefetcher of the state being processed, make sure it's always terminated
+	defer func() {
+		if activeState != nil {
+			activeState.StopPrefetcher()
+		}
+	}()
 	// Fire a single chain head event if we've progressed the chain
 	defer func() {
 		if lastCanon != nil && bc.CurrentBlock().Hash() == lastCanon.Hash() {
@@ -1790,7 +1879,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 	case err != nil:
 		bc.futureBlocks.Remove(block.Hash())
 		stats.ignored += len(it.chain)
-		bc.reportBlock(block, nil, err)
+		bc.reportBlock(block, nil, BadBlockVerify, err)
 		return it.index, err
 	}
 	// No validation errors for the first block (or chain prefix skipped)
@@ -1802,7 +1891,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		}
 		// If the header is a banned one, straight out abort
 		if BadHashes[block.Hash()] {
-			bc.reportBlock(block, nil, ErrBlacklistedHash)
+			bc.reportBlock(block, nil, BadBlockBlacklisted, ErrBlacklistedHash)
 			return it.index, ErrBlacklistedHash
 		}
 		// If the block is known (in the middle of the chain), it's a special case for
@@ -1850,10 +1939,31 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		if parent == nil {
 			parent = bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
 		}
-		statedb, err := state.New(parent.Root, bc.stateCache, bc.snaps)
+		// If witnesses are requested, record everything the execution accesses.
+		// Recording needs all the transactions executed on the same state, so
+		// fall back to the sequential processor.
+		var (
+			stateCache = bc.stateCache
+			processor  = bc.processor
+			recorder   *witnessRecorder
+		)
+		if bc.cacheConfig.Witnesses {
+			recorder = newWitnessRecorder(bc)
+			stateCache = state.NewRecordingDatabase(bc.stateCache, recorder.state)
+			processor = NewStateProcessor(bc.chainConfig, recorder, bc.engine)
+		}
+		statedb, err := state.New(parent.Root, stateCache, bc.snaps)
 		if err != nil {
 			return it.index, err
 		}
+		if recorder != nil {
+			statedb.RecordAccesses()
+		}
+		// Load the trie paths touched by the transactions in the background
+		if !bc.cacheConfig.TrieNoNodePrefetch {
+			statedb.StartPrefetcher("chain")
+			activeState = statedb
+		}
 		// If we have a followup block, run that against the current state to pre-cache
 		// transactions and probabilistically some of the account/storage trie nodes.
 		var followupInterrupt uint32
@@ -1872,9 +1982,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		}
 		// Process block using the parent state as reference point
 		substart := time.Now()
-		receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		receipts, logs, usedGas, err := processor.Process(block, statedb, bc.vmConfig)
 		if err != nil {
-			bc.reportBlock(block, receipts, err)
+			bc.reportBlock(block, receipts, BadBlockProcess, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
 			return it.index, err
 		}
@@ -1895,7 +2005,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 		// Validate the state using the default validator
 		substart = time.Now()
 		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
-			bc.reportBlock(block, receipts, err)
+			bc.reportBlock(block, receipts, BadBlockState, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
 			return it.index, err
 		}
@@ -1907,10 +2017,21 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals bool) (int, er
 
 		blockValidationTimer.Update(time.Since(substart) - (statedb.AccountHashes + statedb.StorageHashes - triehash))
 
+		// Assemble and store the witness before the state is committed
+		if recorder != nil {
+			witness, err := recorder.witness(stateCache, parent, statedb)
+			if err != nil {
+				atomic.StoreUint32(&followupInterrupt, 1)
+				return it.index, err
+			}
+			rawdb.WriteBlockWitness(bc.db, block.Hash(), block.NumberU64(), witness)
+		}
+
 		// Write the block to the chain and get the status.
 		substart = time.Now()
 		status, err := bc.writeBlockWithState(block, receipts, logs, statedb, false)
 		atomic.StoreUint32(&followupInterrupt, 1)
+		statedb.StopPrefetcher()
 		if err != nil {
 			return it.index, err
 		}
@@ -2366,24 +2487,26 @@ func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 
 // BadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
 func (bc *BlockChain) BadBlocks() []*types.Block {
-	blocks := make([]*types.Block, 0, bc.badBlocks.Len())
-	for _, hash := range bc.badBlocks.Keys() {
-		if blk, exist := bc.badBlocks.Peek(hash); exist {
-			block := blk.(*types.Block)
-			blocks = append(blocks, block)
-		}
+	var blocks []*types.Block
+	for _, bad := range rawdb.ReadAllBadBlocks(bc.db) {
+		blocks = append(blocks, bad.Block)
 	}
 	return blocks
 }
 
-// addBadBlock adds a bad block to the bad-block LRU cache
-func (bc *BlockChain) addBadBlock(block *types.Block) {
-	bc.badBlocks.Add(block.Hash(), block)
-}
-
-// reportBlock logs a bad block error.
-func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
-	bc.addBadBlock(block)
+// reportBlock persists a bad block along with the receipts computed before the
+// failure and logs the error.
+func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, stage string, err error) {
+	bad := &rawdb.BadBlock{
+		Block:    block,
+		Receipts: receipts,
+		Stage:    stage,
+		Error:    err.Error(),
+	}
+	if parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil {
+		bad.ParentRoot = parent.Root
+	}
+	rawdb.WriteBadBlock(bc.db, bad)
 
 	var receiptString string
 	for i, receipt := range receipts {
@@ -2397,11 +2520,12 @@ Chain config: %v
 
 Number: %v
 Hash: 0x%x
+Stage: %v
 %v
 
 Error: %v
 ##############################
-`, bc.chainConfig, block.Number(), block.Hash(), receiptString, err))
+`, bc.chainConfig, block.Number(), block.Hash(), stage, receiptString, err))
 }
 
 // InsertHeaderChain attempts to insert the given header chain in to the local
@@ -0,0 +1,168 @@
+
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/fafdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ChainLogEntry is an entry of the chain event log, announcing a block joining
+// the canonical chain or being reverted from it.
+type ChainLogEntry struct {
+	Cursor   uint64         // Position of the entry in the log, strictly increasing
+	Reverted bool           // Whether the block left the canonical chain
+	Hash     common.Hash    // Hash of the block
+	Number   uint64         // Number of the block
+	Receipts types.Receipts // Receipts of the block, nil if no longer available
+}
+
+// appendChainEvents writes into batch the chain event log entries leading from
+// the last logged head block to a new canonical head: first the reverts of the
+// blocks leaving the canonical chain, newest first, then the applies of the ones
+// joining it, oldest first. The new position of the log is returned, it should
+// be activated via setChainEventHead once the batch is written.
+//
+// Note, this function assumes that the `mu` mutex is held!
+func (bc *BlockChain) appendChainEvents(batch fafdb.KeyValueWriter, head *types.Header) *rawdb.ChainEventHead {
+	// If the log is new, start it from the current head without replaying history
+	if bc.eventHead == nil {
+		next := &rawdb.ChainEventHead{Hash: head.Hash(), Number: head.Number.Uint64()}
+		rawdb.WriteChainEventHead(batch, next)
+		return next
+	}
+	if bc.eventHead.Hash == head.Hash() {
+		return bc.eventHead
+	}
+	// Gather the blocks leaving and joining the canonical chain
+	var reverted, applied []*types.Header
+	if head.ParentHash == bc.eventHead.Hash {
+		applied = append(applied, head)
+	} else {
+		var ancestor *types.Header
+		old := bc.GetHeader(bc.eventHead.Hash, bc.eventHead.Number)
+		if old != nil {
+			ancestor = rawdb.FindCommonAncestor(bc.db, old, head)
+		}
+		if ancestor == nil {
+			log.Error("Chain event log head unavailable, skipping events", "number", bc.eventHead.Number, "hash", bc.eventHead.Hash)
+			return bc.skipChainEvents(batch, head)
+		}
+		for h := old; h != nil && h.Hash() != ancestor.Hash(); h = bc.GetHeader(h.ParentHash, h.Number.Uint64()-1) {
+			reverted = append(reverted, h)
+		}
+		for h := head; h != nil && h.Hash() != ancestor.Hash(); h = bc.GetHeader(h.ParentHash, h.Number.Uint64()-1) {
+			applied = append(applied, h)
+		}
+		for i, j := 0, len(applied)-1; i < j; i, j = i+1, j-1 {
+			applied[i], applied[j] = applied[j], applied[i]
+		}
+	}
+	next := &rawdb.ChainEventHead{Cursor: bc.eventHead.Cursor, Hash: head.Hash(), Number: head.Number.Uint64()}
+	for _, header := range reverted {
+		next.Cursor++
+		rawdb.WriteChainEvent(batch, next.Cursor, &rawdb.ChainEventEntry{Reverted: true, Hash: header.Hash(), Number: header.Number.Uint64()})
+	}
+	for _, header := range applied {
+		next.Cursor++
+		rawdb.WriteChainEvent(batch, next.Cursor, &rawdb.ChainEventEntry{Hash: header.Hash(), Number: header.Number.Uint64()})
+	}
+	rawdb.WriteChainEventHead(batch, next)
+	return next
+}
+
+// revertChainEvent writes into batch the revert entry of a block being deleted
+// from the head of the chain, if it's the last logged head block. The position
+// of the log is updated right away, since the block data is gone after the
+// batch is written, but subscribers are not notified.
+//
+// Note, this function assumes that the `mu` mutex is held!
+func (bc *BlockChain) revertChainEvent(batch fafdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if bc.eventHead == nil || bc.eventHead.Hash != hash || number == 0 {
+		return
+	}
+	header := bc.GetHeader(hash, number)
+	if header == nil {
+		return
+	}
+	next := &rawdb.ChainEventHead{Cursor: bc.eventHead.Cursor + 1, Hash: header.ParentHash, Number: number - 1}
+	rawdb.WriteChainEvent(batch, next.Cursor, &rawdb.ChainEventEntry{Reverted: true, Hash: hash, Number: number})
+	rawdb.WriteChainEventHead(batch, next)
+	bc.eventHead = next
+}
+
+// skipChainEvents moves the head of the chain event log to a new head block
+// without logging the changes leading to it, e.g. after fast sync.
+//
+// Note, this function assumes that the `mu` mutex is held!
+func (bc *BlockChain) skipChainEvents(batch fafdb.KeyValueWriter, head *types.Header) *rawdb.ChainEventHead {
+	next := &rawdb.ChainEventHead{Hash: head.Hash(), Number: head.Number.Uint64()}
+	if bc.eventHead != nil {
+		next.Cursor = bc.eventHead.Cursor
+	}
+	rawdb.WriteChainEventHead(batch, next)
+	return next
+}
+
+// setChainEventHead activates a new position of the chain event log after its
+// entries were written, notifying subscribers of the new entries.
+//
+// Note, this function assumes that the `mu` mutex is held!
+func (bc *BlockChain) setChainEventHead(head *rawdb.ChainEventHead) {
+	bc.eventHead = head
+	if head.Cursor != bc.eventSent {
+		bc.eventSent = head.Cursor
+		bc.chainLogFeed.Send(ChainLogEvent{Cursor: head.Cursor})
+	}
+}
+
+// ChainLogCursor returns the cursor of the last entry in the chain event log,
+// or 0 if the log is empty.
+func (bc *BlockChain) ChainLogCursor() uint64 {
+	if head := rawdb.ReadChainEventHead(bc.db); head != nil {
+		return head.Cursor
+	}
+	return 0
+}
+
+// ChainLog retrieves at most limit entries of the chain event log, starting
+// with the one following the given cursor. Reverted blocks have the logs in
+// their receipts flagged as removed.
+func (bc *BlockChain) ChainLog(cursor uint64, limit int) []*ChainLogEntry {
+	head := bc.ChainLogCursor()
+
+	var entries []*ChainLogEntry
+	for cursor < head && len(entries) < limit {
+		cursor++
+		entry := rawdb.ReadChainEvent(bc.db, cursor)
+		if entry == nil {
+			log.Error("Chain event log entry missing", "cursor", cursor)
+			break
+		}
+		receipts := rawdb.ReadReceipts(bc.db, entry.Hash, entry.Number, bc.chainConfig)
+		if entry.Reverted {
+			for _, receipt := range receipts {
+				for _, log := range receipt.Logs {
+					log.Removed = true
+				}
+			}
+		}
+		entries = append(entries, &ChainLogEntry{
+			Cursor:   cursor,
+			Reverted: entry.Reverted,
+			Hash:     entry.Hash,
+			Number:   entry.Number,
+			Receipts: receipts,
+		})
+	}
+	return entries
+}
+
+// SubscribeChainLogEvent registers a subscription of ChainLogEvent.
+func (bc *BlockChain) SubscribeChainLogEvent(ch chan<- ChainLogEvent) event.Subscription {
+	return bc.scope.Track(bc.chainLogFeed.Subscribe(ch))
+}
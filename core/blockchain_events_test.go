@@ -0,0 +1,122 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// checkChainLog retrieves the chain event log entries following cursor and
+// compares them to the expected block hashes, reverted ones flagged.
+func checkChainLog(t *testing.T, chain *BlockChain, cursor uint64, reverted []bool, hashes []common.Hash) {
+	t.Helper()
+
+	entries := chain.ChainLog(cursor, 1024)
+	if len(entries) != len(hashes) {
+		t.Fatalf("entry count mismatch: have %d, want %d", len(entries), len(hashes))
+	}
+	for i, entry := range entries {
+		if entry.Cursor != cursor+uint64(i)+1 {
+			t.Errorf("entry %d: cursor mismatch: have %d, want %d", i, entry.Cursor, cursor+uint64(i)+1)
+		}
+		if entry.Reverted != reverted[i] || entry.Hash != hashes[i] {
+			t.Errorf("entry %d: mismatch: have %x (reverted %v), want %x (reverted %v)", i, entry.Hash, entry.Reverted, hashes[i], reverted[i])
+		}
+	}
+	if have := chain.ChainLogCursor(); have != cursor+uint64(len(hashes)) {
+		t.Errorf("log cursor mismatch: have %d, want %d", have, cursor+uint64(len(hashes)))
+	}
+}
+
+// Tests that the chain event log records the changes of the canonical chain in
+// order, reverting blocks before applying their replacements, and that it's
+// resumed after a restart.
+func TestChainEventLog(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr    = crypto.PubkeyToAddress(key.PublicKey)
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{addr: {Balance: big.NewInt(10000000000000)}}}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+		engine  = ethash.NewFaker()
+		config  = &CacheConfig{TrieCleanLimit: 256, TrieDirtyLimit: 256, TrieTimeLimit: 5 * 60 * 1e9, ChainEvents: true}
+	)
+	chain, err := NewBlockChain(db, config, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	events := make(chan ChainLogEvent, 16)
+	sub := chain.SubscribeChainLogEvent(events)
+	defer sub.Unsubscribe()
+
+	// Extend the chain with a few blocks, the second one creating a log
+	blocks, _ := GenerateChain(gspec.Config, genesis, engine, db, 3, func(i int, gen *BlockGen) {
+		if i == 1 {
+			tx, err := types.SignTx(types.NewContractCreation(gen.TxNonce(addr), new(big.Int), 1000000, new(big.Int), logCode), signer, key)
+			if err != nil {
+				t.Fatalf("failed to create tx: %v", err)
+			}
+			gen.AddTx(tx)
+		}
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	checkChainLog(t, chain, 0, []bool{false, false, false}, []common.Hash{blocks[0].Hash(), blocks[1].Hash(), blocks[2].Hash()})
+
+	if entry := chain.ChainLog(1, 1)[0]; len(entry.Receipts) != 1 || len(entry.Receipts[0].Logs) != 1 || entry.Receipts[0].Logs[0].Removed {
+		t.Fatalf("applied block receipts mismatch: %v", entry.Receipts)
+	}
+	select {
+	case ev := <-events:
+		if ev.Cursor == 0 || ev.Cursor > 3 {
+			t.Fatalf("event cursor mismatch: have %d", ev.Cursor)
+		}
+	default:
+		t.Fatalf("no chain log event delivered")
+	}
+	// Reorg to a heavier fork, reverting all the blocks first
+	forks, _ := GenerateChain(gspec.Config, genesis, engine, db, 4, func(i int, gen *BlockGen) {
+		gen.OffsetTime(-9)
+	})
+	if _, err := chain.InsertChain(forks); err != nil {
+		t.Fatalf("failed to insert forked chain: %v", err)
+	}
+	checkChainLog(t, chain, 3,
+		[]bool{true, true, true, false, false, false, false},
+		[]common.Hash{blocks[2].Hash(), blocks[1].Hash(), blocks[0].Hash(), forks[0].Hash(), forks[1].Hash(), forks[2].Hash(), forks[3].Hash()},
+	)
+	if entry := chain.ChainLog(4, 1)[0]; len(entry.Receipts) != 1 || len(entry.Receipts[0].Logs) != 1 || !entry.Receipts[0].Logs[0].Removed {
+		t.Fatalf("reverted block receipts mismatch: %v", entry.Receipts)
+	}
+	// Rewind the chain, reverting the deleted blocks
+	if err := chain.SetHead(2); err != nil {
+		t.Fatalf("failed to rewind chain: %v", err)
+	}
+	checkChainLog(t, chain, 10, []bool{true, true}, []common.Hash{forks[3].Hash(), forks[2].Hash()})
+	chain.Stop()
+
+	// Restart the chain and ensure the log is continued
+	chain, err = NewBlockChain(db, config, gspec.Config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to recreate tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if cursor := chain.ChainLogCursor(); cursor != 12 {
+		t.Fatalf("restarted log cursor mismatch: have %d, want %d", cursor, 12)
+	}
+	extension, _ := GenerateChain(gspec.Config, forks[1], engine, db, 1, nil)
+	if _, err := chain.InsertChain(extension); err != nil {
+		t.Fatalf("failed to extend chain: %v", err)
+	}
+	checkChainLog(t, chain, 12, []bool{false}, []common.Hash{extension[0].Hash()})
+}
@@ -137,12 +137,12 @@ func testBlockChainImport(chain types.Blocks, blockchain *BlockChain) error {
 		}
 		receipts, _, usedGas, err := blockchain.processor.Process(block, statedb, vm.Config{})
 		if err != nil {
-			blockchain.reportBlock(block, receipts, err)
+			blockchain.reportBlock(block, receipts, BadBlockProcess, err)
 			return err
 		}
 		err = blockchain.validator.ValidateState(block, statedb, receipts, usedGas)
 		if err != nil {
-			blockchain.reportBlock(block, receipts, err)
+			blockchain.reportBlock(block, receipts, BadBlockState, err)
 			return err
 		}
 		blockchain.chainmu.Lock()
@@ -3016,3 +3016,62 @@ func TestInitThenFailCreateContract(t *testing.T) {
 		}
 	}
 }
+
+// Tests that a chain configured at genesis to store its state in binary tries
+// can be generated, imported and its state accessed.
+func TestBinaryTrieChain(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		store   = common.BytesToAddress([]byte("store"))
+		config  = *params.TestChainConfig
+		engine  = ethash.NewFaker()
+		db      = rawdb.NewMemoryDatabase()
+	)
+	config.BinaryTrie = true
+
+	gspec := &Genesis{
+		Config: &config,
+		Alloc: GenesisAlloc{
+			address: {Balance: big.NewInt(1000000000)},
+			// Stores the call value at the slot of the block number
+			store: {Code: []byte{byte(vm.CALLVALUE), byte(vm.NUMBER), byte(vm.SSTORE)}, Balance: common.Big0},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	hexary := *gspec
+	hexary.Config = params.TestChainConfig
+	if root := hexary.ToBlock(nil).Root(); root == genesis.Root() {
+		t.Fatalf("binary genesis root matches the hexary one: %x", root)
+	}
+	blocks, _ := GenerateChain(&config, genesis, engine, db, 8, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), store, big.NewInt(int64(i+1)), 50000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		b.AddTx(tx)
+	})
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open head state: %v", err)
+	}
+	for i := range blocks {
+		slot := common.BigToHash(big.NewInt(int64(i + 1)))
+		if have, want := statedb.GetState(store, slot), common.BigToHash(big.NewInt(int64(i+1))); have != want {
+			t.Fatalf("slot %d mismatch: have %x, want %x", i+1, have, want)
+		}
+	}
+	if have, want := statedb.GetBalance(store), big.NewInt(36); have.Cmp(want) != 0 {
+		t.Fatalf("balance mismatch: have %v, want %v", have, want)
+	}
+}
@@ -10,6 +10,14 @@ import (
 // NewTxsEvent is posted when a batch of transactions enter the transaction pool.
 type NewTxsEvent struct{ Txs []*types.Transaction }
 
+// DropTxsEvent is posted when a batch of transactions leave the transaction pool
+// for a reason other than being included in the chain.
+type DropTxsEvent struct {
+	Txs         []*types.Transaction
+	Reason      TxDropReason
+	Replacement *types.Transaction // Transaction superseding the dropped one, set if Reason is TxDropReplaced
+}
+
 // NewMinedBlockEvent is posted when a block has been imported.
 type NewMinedBlockEvent struct{ Block *types.Block }
 
@@ -27,3 +35,7 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// ChainLogEvent is posted when new entries are appended to the chain event log,
+// carrying the cursor of the last one.
+type ChainLogEvent struct{ Cursor uint64 }
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"encoding/json"
 	"math/big"
 	"reflect"
 	"testing"
@@ -162,3 +163,56 @@ func TestSetupGenesis(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the state trie format can't be switched on an existing chain, as no
+// rewind can convert the stored state.
+func TestSetupGenesisBinaryTrie(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		config := *params.AllEthashProtocolChanges
+		config.BinaryTrie = binary
+		genesis := &Genesis{Config: &config}
+
+		db := rawdb.NewMemoryDatabase()
+		genesis.MustCommit(db)
+
+		switched := config
+		switched.BinaryTrie = !binary
+		if _, _, err := SetupGenesisBlock(db, &Genesis{Config: &switched}); err == nil {
+			t.Errorf("binary %v: switching the trie format accepted", binary)
+		}
+		if stored := rawdb.ReadChainConfig(db, genesis.ToBlock(nil).Hash()); stored.BinaryTrie != binary {
+			t.Errorf("binary %v: stored config overwritten", binary)
+		}
+		if _, _, err := SetupGenesisBlock(db, genesis); err != nil {
+			t.Errorf("binary %v: unchanged config rejected: %v", binary, err)
+		}
+	}
+}
+
+// Tests that genesis storage keys and values are parsed with either hex prefix,
+// or none, and that marshalled allocations load back.
+func TestGenesisStorageJSON(t *testing.T) {
+	addr := common.Address{0x01}
+	for _, prefix := range []string{"", "0x", "fx"} {
+		input := `{"0x0100000000000000000000000000000000000000": {"balance": "0x1", "storage": {"` + prefix + `01": "` + prefix + `f0ff"}}}`
+
+		var alloc GenesisAlloc
+		if err := json.Unmarshal([]byte(input), &alloc); err != nil {
+			t.Fatalf("prefix %q: failed to unmarshal allocation: %v", prefix, err)
+		}
+		if have := alloc[addr].Storage[common.BytesToHash([]byte{0x01})]; have != common.BytesToHash([]byte{0xf0, 0xff}) {
+			t.Errorf("prefix %q: storage mismatch: have %x", prefix, have)
+		}
+		blob, err := json.Marshal(alloc)
+		if err != nil {
+			t.Fatalf("prefix %q: failed to marshal allocation: %v", prefix, err)
+		}
+		var loaded GenesisAlloc
+		if err := json.Unmarshal(blob, &loaded); err != nil {
+			t.Fatalf("prefix %q: failed to reload allocation: %v", prefix, err)
+		}
+		if !reflect.DeepEqual(loaded, alloc) {
+			t.Errorf("prefix %q: reloaded allocation mismatch: have %v, want %v", prefix, loaded, alloc)
+		}
+	}
+}
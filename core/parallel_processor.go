@@ -0,0 +1,497 @@
+
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	speculativeTxMeter  = metrics.NewRegisteredMeter("chain/parallel/speculative", nil)
+	reexecutedTxMeter   = metrics.NewRegisteredMeter("chain/parallel/reexecuted", nil)
+	parallelSpeedupHist = metrics.NewRegisteredHistogram("chain/parallel/hitrate", nil, metrics.NewExpDecaySample(1028, 0.015))
+)
+
+// ParallelStateProcessor is a Processor executing the transactions of a block
+// optimistically in parallel. Every transaction is run speculatively on its own
+// view of the pre-block state while recording the state it accessed. The results
+// are then merged in block order: a transaction which read anything written by
+// a preceding one is discarded and re-executed on the merged state.
+//
+// The resulting state, receipts and logs are identical to the ones produced by
+// the sequential StateProcessor.
+//
+// ParallelStateProcessor implements Processor.
+type ParallelStateProcessor struct {
+	config  *params.ChainConfig // Chain configuration options
+	bc      ProcessorChain      // Canonical block chain
+	engine  consensus.Engine    // Consensus engine used for block rewards
+	workers int                 // Number of goroutines executing transactions speculatively
+
+	sequential *StateProcessor // Fallback processor for blocks not worth parallelising
+}
+
+// NewParallelStateProcessor initialises a new ParallelStateProcessor.
+func NewParallelStateProcessor(config *params.ChainConfig, bc ProcessorChain, engine consensus.Engine, workers int) *ParallelStateProcessor {
+	return &ParallelStateProcessor{
+		config:     config,
+		bc:         bc,
+		engine:     engine,
+		workers:    workers,
+		sequential: NewStateProcessor(config, bc, engine),
+	}
+}
+
+// speculation is the result of executing a transaction on the pre-block state.
+type speculation struct {
+	msg    types.Message
+	result *ExecutionResult
+	err    error
+
+	state  *state.StateDB  // Private state view the transaction was executed on
+	access *accessRecorder // State accessed during the execution
+	done   chan struct{}   // Closed when the speculative execution finished
+}
+
+// Process processes the state changes according to the Ethereum rules by running
+// the transaction messages using the statedb and applying any rewards to both
+// the processor (coinbase) and any included uncles.
+//
+// Process returns the receipts and logs accumulated during the process and
+// returns the amount of gas that was used in the process. If any of the
+// transactions failed to execute due to insufficient gas it will return an error,
+// along with the receipts of the transactions executed before the failing one.
+func (p *ParallelStateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	// Tracers expect the transactions to be executed once and in order
+	txs := block.Transactions()
+	if p.workers < 2 || len(txs) < 2 || cfg.Debug {
+		return p.sequential.Process(block, statedb, cfg)
+	}
+	var (
+		receipts types.Receipts
+		usedGas  = new(uint64)
+		header   = block.Header()
+		allLogs  []*types.Log
+		gp       = new(GasPool).AddGas(block.GasLimit())
+		signer   = types.MakeSigner(p.config, header.Number)
+	)
+	// Mutate the block and state according to any hard-fork specs
+	if p.config.DAOForkSupport && p.config.DAOForkBlock != nil && p.config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+	// Start executing all the transactions speculatively on the pre-block state
+	var (
+		base      = statedb.Copy()
+		specs     = make([]*speculation, len(txs))
+		tasks     = make(chan int, len(txs))
+		interrupt = new(uint32)
+		pend      sync.WaitGroup
+	)
+	for i := range txs {
+		specs[i] = &speculation{done: make(chan struct{})}
+		tasks <- i
+	}
+	close(tasks)
+
+	workers := p.workers
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	for w := 0; w < workers; w++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+
+			blockContext := NewEVMBlockContext(header, p.bc, nil)
+			for i := range tasks {
+				if atomic.LoadUint32(interrupt) == 0 {
+					p.speculate(specs[i], base, block, i, signer, blockContext, cfg)
+				}
+				close(specs[i].done)
+			}
+		}()
+	}
+	defer func() {
+		atomic.StoreUint32(interrupt, 1)
+		pend.Wait()
+	}()
+	// Merge the speculative results in block order, re-executing on conflicts
+	var (
+		blockContext = NewEVMBlockContext(header, p.bc, nil)
+		written      = newAccessWrites()
+		speculated   int
+	)
+	for i, tx := range txs {
+		spec := specs[i]
+		<-spec.done
+
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+
+		var (
+			receipt *types.Receipt
+			access  *accessRecorder
+		)
+		if spec.err == nil && gp.Gas() >= spec.msg.Gas() && !written.conflicts(spec.access, spec.state) {
+			if err := gp.SubGas(spec.result.UsedGas); err != nil {
+				return receipts, allLogs, *usedGas, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			mergeSpeculation(statedb, tx.Hash(), spec)
+			receipt = finaliseTransaction(spec.msg, p.config, statedb, header, tx, spec.result, usedGas)
+			access = spec.access
+
+			speculated++
+			speculativeTxMeter.Mark(1)
+		} else {
+			msg, err := tx.AsMessage(signer)
+			if err != nil {
+				return receipts, allLogs, *usedGas, err
+			}
+			access = newAccessRecorder(statedb)
+			evm := vm.NewEVM(blockContext, NewEVMTxContext(msg), access, p.config, cfg)
+			prepareAccessList(msg, p.config, access, header, evm)
+
+			result, err := ApplyMessage(evm, msg, gp)
+			if err != nil {
+				return receipts, allLogs, *usedGas, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			receipt = finaliseTransaction(msg, p.config, statedb, header, tx, result, usedGas)
+			reexecutedTxMeter.Mark(1)
+		}
+		written.add(access, statedb)
+
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+	}
+	parallelSpeedupHist.Update(int64(speculated * 100 / len(txs)))
+
+	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
+	p.engine.Finalize(p.bc, header, statedb, txs, block.Uncles())
+
+	return receipts, allLogs, *usedGas, nil
+}
+
+// speculate executes a transaction on a private copy of the pre-block state,
+// recording all the state it accessed.
+func (p *ParallelStateProcessor) speculate(spec *speculation, base *state.StateDB, block *types.Block, index int, signer types.Signer, blockContext vm.BlockContext, cfg vm.Config) {
+	tx := block.Transactions()[index]
+
+	spec.msg, spec.err = tx.AsMessage(signer)
+	if spec.err != nil {
+		return
+	}
+	spec.state = base.Copy()
+	spec.state.Prepare(tx.Hash(), block.Hash(), index)
+	spec.access = newAccessRecorder(spec.state)
+
+	evm := vm.NewEVM(blockContext, NewEVMTxContext(spec.msg), spec.access, p.config, cfg)
+	prepareAccessList(spec.msg, p.config, spec.access, block.Header(), evm)
+
+	// Gas availability is checked against the block gas pool when merging
+	spec.result, spec.err = ApplyMessage(evm, spec.msg, new(GasPool).AddGas(spec.msg.Gas()))
+	if spec.err != nil {
+		return
+	}
+	spec.state.Finalise(p.config.IsEIP158(block.Number()))
+}
+
+// mergeSpeculation applies the state changes of a non-conflicting speculative
+// execution onto the merged state. Balance changes are merged as deltas, since
+// accounts only ever credited or debited without reading may have been modified
+// by preceding transactions. Everything else modified by the transaction is
+// overwritten.
+func mergeSpeculation(statedb *state.StateDB, hash common.Hash, spec *speculation) {
+	for addr, account := range spec.access.accounts {
+		post := spec.state
+		if !post.Exist(addr) {
+			if statedb.Exist(addr) {
+				statedb.Suicide(addr)
+			}
+			continue
+		}
+		if account.created || !statedb.Exist(addr) {
+			statedb.CreateAccount(addr)
+		}
+		if delta := new(big.Int).Sub(post.GetBalance(addr), account.balance); delta.Sign() > 0 {
+			statedb.AddBalance(addr, delta)
+		} else if delta.Sign() < 0 {
+			statedb.SubBalance(addr, delta.Neg(delta))
+		}
+		if account.nonce || account.created {
+			if nonce := post.GetNonce(addr); statedb.GetNonce(addr) != nonce {
+				statedb.SetNonce(addr, nonce)
+			}
+		}
+		if account.code || account.created {
+			if codeHash := post.GetCodeHash(addr); statedb.GetCodeHash(addr) != codeHash {
+				statedb.SetCode(addr, post.GetCode(addr))
+			}
+		}
+		for slot := range account.slots {
+			if value := post.GetState(addr, slot); statedb.GetState(addr, slot) != value {
+				statedb.SetState(addr, slot, value)
+			}
+		}
+	}
+	for _, log := range spec.state.GetLogs(hash) {
+		statedb.AddLog(log)
+	}
+	for hash, preimage := range spec.state.Preimages() {
+		statedb.AddPreimage(hash, preimage)
+	}
+}
+
+// accessKind is the kind of account data accessed by a transaction.
+type accessKind uint8
+
+const (
+	accessExist accessKind = iota
+	accessBalance
+	accessNonce
+	accessCode
+	accessStorage
+)
+
+// accessKey identifies a piece of state accessed by a transaction.
+type accessKey struct {
+	addr common.Address
+	kind accessKind
+	slot common.Hash // Only set for storage accesses
+}
+
+// accessedAccount tracks the modifications made to an account by a transaction.
+type accessedAccount struct {
+	exist   bool     // Whether the account existed before the transaction
+	balance *big.Int // Balance of the account before the transaction
+	created bool     // Whether the account was (re)created by the transaction
+
+	nonce bool                     // Whether the nonce was set
+	code  bool                     // Whether the code was set
+	slots map[common.Hash]struct{} // Storage slots written by the transaction
+}
+
+// accessRecorder is a vm.StateDB wrapping a state database and recording the
+// state read and modified by a single transaction.
+type accessRecorder struct {
+	*state.StateDB
+
+	reads    map[accessKey]struct{}              // State read by the transaction
+	iterated map[common.Address]struct{}         // Accounts whose whole storage was read
+	accounts map[common.Address]*accessedAccount // Accounts modified by the transaction
+}
+
+// newAccessRecorder wraps a state database to record the accesses made to it.
+func newAccessRecorder(statedb *state.StateDB) *accessRecorder {
+	return &accessRecorder{
+		StateDB:  statedb,
+		reads:    make(map[accessKey]struct{}),
+		iterated: make(map[common.Address]struct{}),
+		accounts: make(map[common.Address]*accessedAccount),
+	}
+}
+
+// read marks some account data as read by the transaction.
+func (r *accessRecorder) read(addr common.Address, kinds ...accessKind) {
+	for _, kind := range kinds {
+		r.reads[accessKey{addr: addr, kind: kind}] = struct{}{}
+	}
+}
+
+// modify retrieves the modification tracker of an account, snapshotting the
+// account state the first time it's modified by the transaction.
+func (r *accessRecorder) modify(addr common.Address) *accessedAccount {
+	account := r.accounts[addr]
+	if account == nil {
+		account = &accessedAccount{
+			exist:   r.StateDB.Exist(addr),
+			balance: new(big.Int).Set(r.StateDB.GetBalance(addr)),
+			slots:   make(map[common.Hash]struct{}),
+		}
+		r.accounts[addr] = account
+	}
+	return account
+}
+
+func (r *accessRecorder) CreateAccount(addr common.Address) {
+	r.read(addr, accessExist, accessBalance, accessNonce, accessCode)
+	r.modify(addr).created = true
+	r.StateDB.CreateAccount(addr)
+}
+
+func (r *accessRecorder) SubBalance(addr common.Address, amount *big.Int) {
+	r.modify(addr)
+	r.StateDB.SubBalance(addr, amount)
+}
+
+func (r *accessRecorder) AddBalance(addr common.Address, amount *big.Int) {
+	r.modify(addr)
+	r.StateDB.AddBalance(addr, amount)
+}
+
+func (r *accessRecorder) GetBalance(addr common.Address) *big.Int {
+	r.read(addr, accessBalance)
+	return r.StateDB.GetBalance(addr)
+}
+
+func (r *accessRecorder) GetNonce(addr common.Address) uint64 {
+	r.read(addr, accessNonce)
+	return r.StateDB.GetNonce(addr)
+}
+
+func (r *accessRecorder) SetNonce(addr common.Address, nonce uint64) {
+	r.modify(addr).nonce = true
+	r.StateDB.SetNonce(addr, nonce)
+}
+
+func (r *accessRecorder) GetCodeHash(addr common.Address) common.Hash {
+	r.read(addr, accessCode)
+	return r.StateDB.GetCodeHash(addr)
+}
+
+func (r *accessRecorder) GetCode(addr common.Address) []byte {
+	r.read(addr, accessCode)
+	return r.StateDB.GetCode(addr)
+}
+
+func (r *accessRecorder) SetCode(addr common.Address, code []byte) {
+	r.modify(addr).code = true
+	r.StateDB.SetCode(addr, code)
+}
+
+func (r *accessRecorder) GetCodeSize(addr common.Address) int {
+	r.read(addr, accessCode)
+	return r.StateDB.GetCodeSize(addr)
+}
+
+func (r *accessRecorder) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	r.reads[accessKey{addr: addr, kind: accessStorage, slot: key}] = struct{}{}
+	return r.StateDB.GetCommittedState(addr, key)
+}
+
+func (r *accessRecorder) GetState(addr common.Address, key common.Hash) common.Hash {
+	r.reads[accessKey{addr: addr, kind: accessStorage, slot: key}] = struct{}{}
+	return r.StateDB.GetState(addr, key)
+}
+
+func (r *accessRecorder) SetState(addr common.Address, key, value common.Hash) {
+	r.modify(addr).slots[key] = struct{}{}
+	r.StateDB.SetState(addr, key, value)
+}
+
+func (r *accessRecorder) Suicide(addr common.Address) bool {
+	r.read(addr, accessExist, accessBalance)
+	r.modify(addr)
+	return r.StateDB.Suicide(addr)
+}
+
+func (r *accessRecorder) Exist(addr common.Address) bool {
+	r.read(addr, accessExist)
+	return r.StateDB.Exist(addr)
+}
+
+func (r *accessRecorder) Empty(addr common.Address) bool {
+	r.read(addr, accessExist, accessBalance, accessNonce, accessCode)
+	return r.StateDB.Empty(addr)
+}
+
+func (r *accessRecorder) ForEachStorage(addr common.Address, cb func(key, value common.Hash) bool) error {
+	r.iterated[addr] = struct{}{}
+	return r.StateDB.ForEachStorage(addr, cb)
+}
+
+// accessWrites is the set of state modified by the already merged transactions
+// of a block.
+type accessWrites struct {
+	writes map[accessKey]struct{}
+	wiped  map[common.Address]struct{} // Accounts whose whole storage was reset
+	stored map[common.Address]struct{} // Accounts with any storage slot modified
+}
+
+// newAccessWrites creates an empty write set.
+func newAccessWrites() *accessWrites {
+	return &accessWrites{
+		writes: make(map[accessKey]struct{}),
+		wiped:  make(map[common.Address]struct{}),
+		stored: make(map[common.Address]struct{}),
+	}
+}
+
+// add extends the write set with the modifications of a transaction, given the
+// state right after its execution.
+func (w *accessWrites) add(access *accessRecorder, post *state.StateDB) {
+	for addr, account := range access.accounts {
+		// Account creations and deletions reset all the account data
+		if account.created || account.exist != post.Exist(addr) {
+			for _, kind := range []accessKind{accessExist, accessBalance, accessNonce, accessCode} {
+				w.writes[accessKey{addr: addr, kind: kind}] = struct{}{}
+			}
+			w.wiped[addr] = struct{}{}
+			continue
+		}
+		if post.GetBalance(addr).Cmp(account.balance) != 0 {
+			w.writes[accessKey{addr: addr, kind: accessBalance}] = struct{}{}
+		}
+		if account.nonce {
+			w.writes[accessKey{addr: addr, kind: accessNonce}] = struct{}{}
+		}
+		if account.code {
+			w.writes[accessKey{addr: addr, kind: accessCode}] = struct{}{}
+		}
+		for slot := range account.slots {
+			w.writes[accessKey{addr: addr, kind: accessStorage, slot: slot}] = struct{}{}
+			w.stored[addr] = struct{}{}
+		}
+	}
+}
+
+// conflicts reports whether a speculatively executed transaction read any state
+// modified by the already merged transactions, given its speculative post state.
+func (w *accessWrites) conflicts(access *accessRecorder, post *state.StateDB) bool {
+	for key := range access.reads {
+		if _, ok := w.writes[key]; ok {
+			return true
+		}
+		if key.kind == accessStorage {
+			if _, ok := w.wiped[key.addr]; ok {
+				return true
+			}
+		}
+	}
+	for addr := range access.iterated {
+		if _, ok := w.stored[addr]; ok {
+			return true
+		}
+		if _, ok := w.wiped[addr]; ok {
+			return true
+		}
+	}
+	// Modifying an account recreated or deleted by a preceding transaction would
+	// otherwise be merged against stale account data
+	for addr, account := range access.accounts {
+		if _, ok := w.wiped[addr]; ok {
+			return true
+		}
+		// Creating or deleting an account (e.g. touching an empty one) depends
+		// on the entire account state, even if it was not explicitly read
+		if account.exist == post.Exist(addr) {
+			continue
+		}
+		for _, kind := range []accessKind{accessExist, accessBalance, accessNonce, accessCode} {
+			if _, ok := w.writes[accessKey{addr: addr, kind: kind}]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
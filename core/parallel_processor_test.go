@@ -0,0 +1,145 @@
+package core
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that the parallel state processor produces the exact same state, receipts
+// and logs as the sequential one, even if the transactions in a block conflict.
+func TestParallelStateProcessor(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+		funds  = big.NewInt(1000000000)
+
+		keys  []*ecdsa.PrivateKey
+		addrs []common.Address
+		alloc = make(GenesisAlloc)
+
+		// Contract incrementing slot 0 and logging the new value
+		counter = common.BytesToAddress([]byte{0xcc, 0xcc})
+		// Contract self-destructing to its caller
+		destruct = common.BytesToAddress([]byte{0xdd, 0xdd})
+		// Contract reading the balance of the first account into slot 0
+		oracle = common.BytesToAddress([]byte{0xee, 0xee})
+		// Account without any balance, nonce or code
+		empty = common.BytesToAddress([]byte{0xff, 0xff})
+	)
+	for i := 0; i < 8; i++ {
+		key, _ := crypto.GenerateKey()
+		keys = append(keys, key)
+		addrs = append(addrs, crypto.PubkeyToAddress(key.PublicKey))
+		alloc[addrs[i]] = GenesisAccount{Balance: funds}
+	}
+	alloc[counter] = GenesisAccount{
+		Code: []byte{
+			byte(vm.PUSH1), 0x00, byte(vm.SLOAD), byte(vm.PUSH1), 0x01, byte(vm.ADD),
+			byte(vm.DUP1), byte(vm.PUSH1), 0x00, byte(vm.SSTORE),
+			byte(vm.PUSH1), 0x00, byte(vm.MSTORE), byte(vm.PUSH1), 0x20, byte(vm.PUSH1), 0x00, byte(vm.LOG0),
+		},
+		Balance: big.NewInt(0),
+	}
+	alloc[destruct] = GenesisAccount{
+		Code:    []byte{byte(vm.CALLER), byte(vm.SELFDESTRUCT)},
+		Balance: big.NewInt(1000),
+	}
+	alloc[oracle] = GenesisAccount{
+		Code:    append(append([]byte{byte(vm.PUSH20)}, addrs[0].Bytes()...), byte(vm.BALANCE), byte(vm.PUSH1), 0x00, byte(vm.SSTORE)),
+		Balance: big.NewInt(0),
+	}
+	alloc[empty] = GenesisAccount{Balance: big.NewInt(0)}
+
+	gspec := &Genesis{Config: params.TestChainConfig, Alloc: alloc}
+	genesis := gspec.MustCommit(db)
+	signer := types.NewEIP155Signer(params.TestChainConfig.ChainID)
+
+	blocks, receipts := GenerateChain(params.TestChainConfig, genesis, engine, db, 4, func(i int, b *BlockGen) {
+		b.SetCoinbase(addrs[7])
+
+		send := func(from int, to common.Address, value int64, gas uint64) {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addrs[from]), to, big.NewInt(value), gas, big.NewInt(1), nil), signer, keys[from])
+			b.AddTx(tx)
+		}
+		switch i {
+		case 0:
+			// Independent transfers, multiple transactions from the same sender
+			send(0, common.Address{0x01}, 1000, 21000)
+			send(1, common.Address{0x02}, 1000, 21000)
+			send(2, common.Address{0x03}, 1000, 21000)
+			send(0, common.Address{0x04}, 1000, 21000)
+			send(0, common.Address{0x01}, 1000, 21000)
+		case 1:
+			// Contended storage slot and logs
+			send(0, counter, 0, 100000)
+			send(1, counter, 0, 100000)
+			send(2, common.Address{0x05}, 1000, 21000)
+			send(3, counter, 0, 100000)
+		case 2:
+			// Funds received from a preceding transaction, balance reads and
+			// payments to the coinbase
+			send(0, addrs[4], 500000000, 21000)
+			send(4, addrs[5], 900000000, 21000)
+			send(1, oracle, 0, 100000)
+			send(2, addrs[0], 1000, 21000)
+			send(3, oracle, 0, 100000)
+			send(6, addrs[7], 1000, 21000)
+			send(7, common.Address{0x06}, 1000, 21000)
+		case 3:
+			// Account deletions, both self-destructs and touched empty accounts
+			send(0, empty, 0, 21000)
+			send(1, empty, 0, 21000)
+			send(2, destruct, 0, 100000)
+			send(3, destruct, 0, 100000)
+			send(5, destruct, 1000, 100000)
+			send(6, common.Address{0x07}, 1000, 21000)
+		}
+	})
+	// Import the chain with parallel execution enabled, validating the state roots
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	config := *defaultCacheConfig
+	config.ParallelExecution = 4
+
+	chain, err := NewBlockChain(diskdb, &config, params.TestChainConfig, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, ok := chain.processor.(*ParallelStateProcessor); !ok {
+		t.Fatalf("processor mismatch: have %T, want %T", chain.processor, &ParallelStateProcessor{})
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Cross check the receipts against the sequentially generated ones
+	for i, block := range blocks {
+		have := chain.GetReceiptsByHash(block.Hash())
+		if len(have) != len(receipts[i]) {
+			t.Fatalf("block %d: receipt count mismatch: have %d, want %d", i, len(have), len(receipts[i]))
+		}
+		for j, want := range receipts[i] {
+			haveBlob, _ := rlp.EncodeToBytes(have[j])
+			wantBlob, _ := rlp.EncodeToBytes(want)
+			if !bytes.Equal(haveBlob, wantBlob) {
+				t.Errorf("block %d, tx %d: receipt mismatch: have %x, want %x", i, j, haveBlob, wantBlob)
+			}
+			if have[j].GasUsed != want.GasUsed || have[j].ContractAddress != want.ContractAddress {
+				t.Errorf("block %d, tx %d: receipt fields mismatch: have %+v, want %+v", i, j, have[j], want)
+			}
+		}
+	}
+}
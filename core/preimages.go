@@ -0,0 +1,110 @@
+
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// InsertPreimages inserts the preimages of all the account addresses and storage
+// slot keys in the genesis allocation into the preimage store.
+func (g *Genesis) InsertPreimages(store *trie.PreimageStore) {
+	for addr, account := range g.Alloc {
+		store.Insert(addr.Bytes())
+		for key := range account.Storage {
+			store.Insert(key.Bytes())
+		}
+	}
+}
+
+// BackfillPreimages re-executes the blocks in the range [from, to] on top of the
+// state of block from-1, inserting the preimages of all the account addresses
+// and storage slot keys accessed into the preimage store of the chain. As every
+// key in the state was written by some block or the genesis allocation, running
+// it from block 1 up to the head completes the set of preimages.
+//
+// Only the state of the first parent is needed, the subsequent ones are kept in
+// memory. The number of preimages found missing is returned.
+func (bc *BlockChain) BackfillPreimages(from, to uint64) (int, error) {
+	if from == 0 {
+		return 0, errors.New("genesis block cannot be re-executed")
+	}
+	parent := bc.GetHeaderByNumber(from - 1)
+	if parent == nil {
+		return 0, fmt.Errorf("block #%d not found", from-1)
+	}
+	var (
+		db        = newStateDatabase(bc.db, &trie.Config{Cache: 16}, bc.chainConfig)
+		store     = bc.stateCache.TrieDB().Preimages()
+		processor = NewStateProcessor(bc.chainConfig, bc, bc.engine)
+		root      = parent.Root
+
+		missing int
+		start   = time.Now()
+		logged  = time.Now()
+	)
+	statedb, err := state.New(root, db, nil)
+	if err != nil {
+		return 0, fmt.Errorf("state of block #%d unavailable: %v", from-1, err)
+	}
+	insert := func(key []byte) {
+		if !store.Has(crypto.Keccak256Hash(key)) {
+			store.Insert(key)
+			missing++
+		}
+	}
+	for number := from; number <= to; number++ {
+		block := bc.GetBlockByNumber(number)
+		if block == nil {
+			return missing, fmt.Errorf("block #%d not found", number)
+		}
+		statedb.RecordAccesses()
+
+		receipts, _, usedGas, err := processor.Process(block, statedb, vm.Config{})
+		if err != nil {
+			return missing, fmt.Errorf("failed to process block #%d: %v", number, err)
+		}
+		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+			return missing, fmt.Errorf("failed to validate block #%d: %v", number, err)
+		}
+		for addr, slots := range statedb.Accesses() {
+			insert(addr.Bytes())
+			for _, slot := range slots {
+				insert(slot.Bytes())
+			}
+		}
+		// Move on to the post state, only ever keeping the latest one in memory
+		next, err := statedb.Commit(bc.chainConfig.IsEIP158(block.Number()))
+		if err != nil {
+			return missing, err
+		}
+		db.TrieDB().Reference(next, common.Hash{})
+		db.TrieDB().Dereference(root)
+		root = next
+
+		if statedb, err = state.New(root, db, nil); err != nil {
+			return missing, err
+		}
+		if time.Since(logged) > 8*time.Second {
+			if err := store.Commit(); err != nil {
+				return missing, err
+			}
+			log.Info("Backfilling preimages", "number", number, "missing", missing, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := store.Commit(); err != nil {
+		return missing, err
+	}
+	log.Info("Backfilled preimages", "blocks", to-from+1, "missing", missing, "elapsed", common.PrettyDuration(time.Since(start)))
+	return missing, nil
+}
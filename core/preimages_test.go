@@ -0,0 +1,86 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the preimages of the keys written by blocks imported by a node that
+// didn't record them can be backfilled by re-executing the blocks.
+func TestBackfillPreimages(t *testing.T) {
+	gspec, blocks := makeWitnessTestChain(params.TestChainConfig, 8)
+
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, &CacheConfig{TrieDirtyDisabled: true}, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Drop all the preimages recorded during import, as an old node would've
+	it := diskdb.NewIterator([]byte("secure-key-"), nil)
+	for it.Next() {
+		diskdb.Delete(it.Key())
+	}
+	it.Release()
+
+	// The keys of the accounts and slots created by the blocks are unknown
+	var keys [][]byte
+	for i := range blocks {
+		keys = append(keys, common.BytesToAddress([]byte{byte(i + 1)}).Bytes())
+		keys = append(keys, common.BigToHash(big.NewInt(int64(i+1))).Bytes())
+	}
+	store := chain.StateCache().TrieDB().Preimages()
+	for _, key := range keys {
+		if store.Has(crypto.Keccak256Hash(key)) {
+			t.Fatalf("preimage of %x not dropped", key)
+		}
+	}
+	// Backfill the second half first, then the entire chain
+	first, err := chain.BackfillPreimages(5, 8)
+	if err != nil {
+		t.Fatalf("failed to backfill preimages: %v", err)
+	}
+	if first == 0 {
+		t.Fatalf("no missing preimages found")
+	}
+	second, err := chain.BackfillPreimages(1, 8)
+	if err != nil {
+		t.Fatalf("failed to backfill preimages: %v", err)
+	}
+	if second == 0 {
+		t.Fatalf("no missing preimages found in the first half")
+	}
+	if missing, err := chain.BackfillPreimages(1, 8); err != nil || missing != 0 {
+		t.Fatalf("repeated backfill mismatch: missing %d, err %v", missing, err)
+	}
+	for _, key := range keys {
+		if preimage := rawdb.ReadPreimage(diskdb, crypto.Keccak256Hash(key)); preimage == nil {
+			t.Errorf("preimage of %x not backfilled", key)
+		}
+	}
+	// Dumps resolve all the addresses
+	statedb, _ := chain.State()
+	for addr, account := range statedb.RawDump(true, false, false).Accounts {
+		if account.SecureKey != nil {
+			t.Errorf("account %x dumped without address", account.SecureKey)
+		}
+		for key := range account.Storage {
+			if key.Big().Uint64() > uint64(len(blocks)) {
+				t.Errorf("slot of %x dumped without key: %x", addr, key)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -674,6 +675,8 @@ func WriteAncientBlock(db fafdb.AncientWriter, block *types.Block, receipts type
 // DeleteBlock removes all block data associated with a hash.
 func DeleteBlock(db fafdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteReceipts(db, hash, number)
+	DeleteStateDiff(db, hash, number)
+	DeleteBlockWitness(db, hash, number)
 	DeleteHeader(db, hash, number)
 	DeleteBody(db, hash, number)
 	DeleteTd(db, hash, number)
@@ -714,3 +717,186 @@ func FindCommonAncestor(db fafdb.Reader, a, b *types.Header) *types.Header {
 	}
 	return a
 }
+
+// ChainEventEntry is an entry of the chain event log, recording a block either
+// becoming part of the canonical chain or being reverted from it.
+type ChainEventEntry struct {
+	Reverted bool
+	Hash     common.Hash
+	Number   uint64
+}
+
+// ChainEventHead is the position of the chain event log: the cursor of its last
+// entry and the canonical head block the entries lead up to.
+type ChainEventHead struct {
+	Cursor uint64
+	Hash   common.Hash
+	Number uint64
+}
+
+// ReadChainEventHead retrieves the position of the chain event log, or nil if
+// the log was never written.
+func ReadChainEventHead(db fafdb.KeyValueReader) *ChainEventHead {
+	data, _ := db.Get(chainEventHeadKey)
+	if len(data) == 0 {
+		return nil
+	}
+	head := new(ChainEventHead)
+	if err := rlp.DecodeBytes(data, head); err != nil {
+		log.Error("Invalid chain event log head", "err", err)
+		return nil
+	}
+	return head
+}
+
+// WriteChainEventHead stores the position of the chain event log.
+func WriteChainEventHead(db fafdb.KeyValueWriter, head *ChainEventHead) {
+	data, err := rlp.EncodeToBytes(head)
+	if err != nil {
+		log.Crit("Failed to encode chain event log head", "err", err)
+	}
+	if err := db.Put(chainEventHeadKey, data); err != nil {
+		log.Crit("Failed to store chain event log head", "err", err)
+	}
+}
+
+// ReadChainEvent retrieves the chain event log entry with the given cursor.
+func ReadChainEvent(db fafdb.KeyValueReader, cursor uint64) *ChainEventEntry {
+	data, _ := db.Get(chainEventKey(cursor))
+	if len(data) == 0 {
+		return nil
+	}
+	entry := new(ChainEventEntry)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		log.Error("Invalid chain event log entry", "cursor", cursor, "err", err)
+		return nil
+	}
+	return entry
+}
+
+// WriteChainEvent stores a chain event log entry under the given cursor.
+func WriteChainEvent(db fafdb.KeyValueWriter, cursor uint64, entry *ChainEventEntry) {
+	data, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		log.Crit("Failed to encode chain event log entry", "err", err)
+	}
+	if err := db.Put(chainEventKey(cursor), data); err != nil {
+		log.Crit("Failed to store chain event log entry", "err", err)
+	}
+}
+
+// badBlockToKeep is the maximum number of bad blocks persisted, the ones with
+// the lowest numbers are dropped first.
+const badBlockToKeep = 10
+
+// BadBlock is a block rejected by the local node, along with the details needed
+// to diagnose the failure.
+type BadBlock struct {
+	Block      *types.Block   // Block that failed validation
+	Receipts   types.Receipts // Receipts of the transactions executed before the failure, consensus fields only
+	ParentRoot common.Hash    // State root of the parent block, empty if the parent is unknown
+	Stage      string         // Validation step the block failed
+	Error      string         // Error the block was rejected with
+}
+
+// storedBadBlock is the storage representation of a bad block.
+type storedBadBlock struct {
+	Header     *types.Header
+	Body       *types.Body
+	Receipts   []*types.ReceiptForStorage
+	ParentRoot common.Hash
+	Stage      string
+	Error      string
+}
+
+// readStoredBadBlocks retrieves the list of stored bad blocks.
+func readStoredBadBlocks(db fafdb.KeyValueReader) []*storedBadBlock {
+	data, _ := db.Get(badBlockKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var stored []*storedBadBlock
+	if err := rlp.DecodeBytes(data, &stored); err != nil {
+		log.Error("Invalid bad block list", "err", err)
+		return nil
+	}
+	return stored
+}
+
+// decode converts a stored bad block into its usable form.
+func (stored *storedBadBlock) decode() *BadBlock {
+	receipts := make(types.Receipts, len(stored.Receipts))
+	for i, receipt := range stored.Receipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	return &BadBlock{
+		Block:      types.NewBlockWithHeader(stored.Header).WithBody(stored.Body.Transactions, stored.Body.Uncles),
+		Receipts:   receipts,
+		ParentRoot: stored.ParentRoot,
+		Stage:      stored.Stage,
+		Error:      stored.Error,
+	}
+}
+
+// ReadBadBlock retrieves the bad block with the given hash, or nil if it's not
+// among the stored ones.
+func ReadBadBlock(db fafdb.KeyValueReader, hash common.Hash) *BadBlock {
+	for _, stored := range readStoredBadBlocks(db) {
+		if stored.Header.Hash() == hash {
+			return stored.decode()
+		}
+	}
+	return nil
+}
+
+// ReadAllBadBlocks retrieves all the stored bad blocks, highest number first.
+func ReadAllBadBlocks(db fafdb.KeyValueReader) []*BadBlock {
+	var bad []*BadBlock
+	for _, stored := range readStoredBadBlocks(db) {
+		bad = append(bad, stored.decode())
+	}
+	return bad
+}
+
+// WriteBadBlock stores a bad block, keeping only the badBlockToKeep ones with
+// the highest numbers. Blocks already stored are ignored.
+func WriteBadBlock(db fafdb.KeyValueStore, bad *BadBlock) {
+	stored := readStoredBadBlocks(db)
+	for _, old := range stored {
+		if old.Header.Hash() == bad.Block.Hash() {
+			return
+		}
+	}
+	receipts := make([]*types.ReceiptForStorage, len(bad.Receipts))
+	for i, receipt := range bad.Receipts {
+		receipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	stored = append(stored, &storedBadBlock{
+		Header:     bad.Block.Header(),
+		Body:       bad.Block.Body(),
+		Receipts:   receipts,
+		ParentRoot: bad.ParentRoot,
+		Stage:      bad.Stage,
+		Error:      bad.Error,
+	})
+	sort.SliceStable(stored, func(i, j int) bool {
+		return stored[i].Header.Number.Uint64() > stored[j].Header.Number.Uint64()
+	})
+	if len(stored) > badBlockToKeep {
+		stored = stored[:badBlockToKeep]
+	}
+	data, err := rlp.EncodeToBytes(stored)
+	if err != nil {
+		log.Crit("Failed to encode bad blocks", "err", err)
+	}
+	if err := db.Put(badBlockKey, data); err != nil {
+		log.Crit("Failed to store bad blocks", "err", err)
+	}
+}
+
+// DeleteBadBlocks removes all the stored bad blocks.
+func DeleteBadBlocks(db fafdb.KeyValueWriter) {
+	if err := db.Delete(badBlockKey); err != nil {
+		log.Crit("Failed to delete bad blocks", "err", err)
+	}
+}
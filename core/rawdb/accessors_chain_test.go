@@ -443,3 +443,54 @@ func TestCanonicalHashIteration(t *testing.T) {
 		}
 	}
 }
+
+// Tests that bad blocks are stored highest number first, deduplicated and
+// limited to the most recent ones.
+func TestBadBlockStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+	if bad := ReadAllBadBlocks(db); len(bad) != 0 {
+		t.Fatalf("non existent bad blocks returned: %v", bad)
+	}
+	// Store a bad block with a receipt and check it's retrievable
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Extra: []byte("bad block")})
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		Logs:              []*types.Log{{Address: common.BytesToAddress([]byte{0x11}), Data: []byte{0x01}}},
+	}
+	WriteBadBlock(db, &BadBlock{
+		Block:      block,
+		Receipts:   types.Receipts{receipt},
+		ParentRoot: common.Hash{0x01},
+		Stage:      "state",
+		Error:      "invalid merkle root",
+	})
+	bad := ReadBadBlock(db, block.Hash())
+	if bad == nil {
+		t.Fatalf("stored bad block not found")
+	}
+	if bad.Block.Hash() != block.Hash() || bad.ParentRoot != (common.Hash{0x01}) || bad.Stage != "state" || bad.Error != "invalid merkle root" {
+		t.Fatalf("bad block mismatch: have %x %x %s %q", bad.Block.Hash(), bad.ParentRoot, bad.Stage, bad.Error)
+	}
+	if len(bad.Receipts) != 1 || bad.Receipts[0].CumulativeGasUsed != 21000 || len(bad.Receipts[0].Logs) != 1 {
+		t.Fatalf("bad block receipts mismatch: %v", bad.Receipts)
+	}
+	// Store more blocks than retained, including a duplicate
+	WriteBadBlock(db, &BadBlock{Block: block})
+	for i := 2; i <= 2*badBlockToKeep; i++ {
+		WriteBadBlock(db, &BadBlock{Block: types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))})})
+	}
+	all := ReadAllBadBlocks(db)
+	if len(all) != badBlockToKeep {
+		t.Fatalf("retained bad block count mismatch: have %d, want %d", len(all), badBlockToKeep)
+	}
+	for i, bad := range all {
+		if want := uint64(2*badBlockToKeep - i); bad.Block.NumberU64() != want {
+			t.Errorf("bad block %d: number mismatch: have %d, want %d", i, bad.Block.NumberU64(), want)
+		}
+	}
+	DeleteBadBlocks(db)
+	if bad := ReadAllBadBlocks(db); len(bad) != 0 {
+		t.Fatalf("deleted bad blocks returned: %v", bad)
+	}
+}
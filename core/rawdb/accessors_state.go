@@ -18,8 +18,10 @@ package rawdb
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/fafdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // ReadPreimage retrieves a single preimage of the provided hash.
@@ -28,6 +30,12 @@ func ReadPreimage(db fafdb.KeyValueReader, hash common.Hash) []byte {
 	return data
 }
 
+// HasPreimage checks if the preimage of the provided hash is present.
+func HasPreimage(db fafdb.KeyValueReader, hash common.Hash) bool {
+	ok, _ := db.Has(preimageKey(hash))
+	return ok
+}
+
 // WritePreimages writes the provided set of preimages to the database.
 func WritePreimages(db fafdb.KeyValueWriter, preimages map[common.Hash][]byte) {
 	for hash, preimage := range preimages {
@@ -94,3 +102,79 @@ func DeleteTrieNode(db fafdb.KeyValueWriter, hash common.Hash) {
 		log.Crit("Failed to delete trie node", "err", err)
 	}
 }
+
+// ReadStateDiffRLP retrieves the state diff of a block in RLP encoding.
+func ReadStateDiffRLP(db fafdb.KeyValueReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(stateDiffKey(number, hash))
+	return data
+}
+
+// ReadStateDiff retrieves the state modifications performed by a block.
+func ReadStateDiff(db fafdb.KeyValueReader, hash common.Hash, number uint64) *types.StateDiff {
+	data := ReadStateDiffRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	diff := new(types.StateDiff)
+	if err := rlp.DecodeBytes(data, diff); err != nil {
+		log.Error("Invalid state diff RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return diff
+}
+
+// WriteStateDiff stores the state modifications performed by a block.
+func WriteStateDiff(db fafdb.KeyValueWriter, hash common.Hash, number uint64, diff *types.StateDiff) {
+	data, err := rlp.EncodeToBytes(diff)
+	if err != nil {
+		log.Crit("Failed to RLP encode state diff", "err", err)
+	}
+	if err := db.Put(stateDiffKey(number, hash), data); err != nil {
+		log.Crit("Failed to store state diff", "err", err)
+	}
+}
+
+// DeleteStateDiff removes the state diff associated with a block.
+func DeleteStateDiff(db fafdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(stateDiffKey(number, hash)); err != nil {
+		log.Crit("Failed to delete state diff", "err", err)
+	}
+}
+
+// ReadBlockWitnessRLP retrieves the execution witness of a block in RLP encoding.
+func ReadBlockWitnessRLP(db fafdb.KeyValueReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(blockWitnessKey(number, hash))
+	return data
+}
+
+// ReadBlockWitness retrieves the execution witness of a block.
+func ReadBlockWitness(db fafdb.KeyValueReader, hash common.Hash, number uint64) *types.BlockWitness {
+	data := ReadBlockWitnessRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	witness := new(types.BlockWitness)
+	if err := rlp.DecodeBytes(data, witness); err != nil {
+		log.Error("Invalid block witness RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return witness
+}
+
+// WriteBlockWitness stores the execution witness of a block.
+func WriteBlockWitness(db fafdb.KeyValueWriter, hash common.Hash, number uint64, witness *types.BlockWitness) {
+	data, err := rlp.EncodeToBytes(witness)
+	if err != nil {
+		log.Crit("Failed to RLP encode block witness", "err", err)
+	}
+	if err := db.Put(blockWitnessKey(number, hash), data); err != nil {
+		log.Crit("Failed to store block witness", "err", err)
+	}
+}
+
+// DeleteBlockWitness removes the execution witness associated with a block.
+func DeleteBlockWitness(db fafdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(blockWitnessKey(number, hash)); err != nil {
+		log.Crit("Failed to delete block witness", "err", err)
+	}
+}
@@ -269,6 +269,9 @@ func InspectDatabase(db fafdb.Database) error {
 		headers         stat
 		bodies          stat
 		receipts        stat
+		stateDiffs      stat
+		witnesses       stat
+		chainEvents     stat
 		tds             stat
 		numHashPairings stat
 		hashNumPairings stat
@@ -313,6 +316,12 @@ func InspectDatabase(db fafdb.Database) error {
 			bodies.Add(size)
 		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
 			receipts.Add(size)
+		case bytes.HasPrefix(key, stateDiffPrefix) && len(key) == (len(stateDiffPrefix)+8+common.HashLength):
+			stateDiffs.Add(size)
+		case bytes.HasPrefix(key, blockWitnessPrefix) && len(key) == (len(blockWitnessPrefix)+8+common.HashLength):
+			witnesses.Add(size)
+		case bytes.HasPrefix(key, chainEventPrefix) && len(key) == (len(chainEventPrefix)+8):
+			chainEvents.Add(size)
 		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerTDSuffix):
 			tds.Add(size)
 		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
@@ -341,7 +350,7 @@ func InspectDatabase(db fafdb.Database) error {
 			bloomTrieNodes.Add(size)
 		default:
 			var accounted bool
-			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey} {
+			for _, meta := range [][]byte{databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey, chainEventHeadKey, badBlockKey} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
 					accounted = true
@@ -376,6 +385,9 @@ func InspectDatabase(db fafdb.Database) error {
 		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
 		{"Key-Value store", "Bodies", bodies.Size(), bodies.Count()},
 		{"Key-Value store", "Receipt lists", receipts.Size(), receipts.Count()},
+		{"Key-Value store", "State diffs", stateDiffs.Size(), stateDiffs.Count()},
+		{"Key-Value store", "Block witnesses", witnesses.Size(), witnesses.Count()},
+		{"Key-Value store", "Chain event log", chainEvents.Size(), chainEvents.Count()},
 		{"Key-Value store", "Difficulties", tds.Size(), tds.Count()},
 		{"Key-Value store", "Block number->hash", numHashPairings.Size(), numHashPairings.Count()},
 		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
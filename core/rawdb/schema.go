@@ -49,6 +49,13 @@ var (
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	fastTxLookupLimitKey = []byte("FastTransactionLookupLimit")
 
+	// badBlockKey tracks the list of bad blocks seen by the local node.
+	badBlockKey = []byte("InvalidBlock")
+
+	// chainEventHeadKey tracks the last entry of the chain event log and the head
+	// block it leads to.
+	chainEventHeadKey = []byte("LastChainEvent")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -57,6 +64,10 @@ var (
 
 	blockBodyPrefix     = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
 	blockReceiptsPrefix = []byte("r") // blockReceiptsPrefix + num (uint64 big endian) + hash -> block receipts
+	stateDiffPrefix     = []byte("d") // stateDiffPrefix + num (uint64 big endian) + hash -> block state diff
+	blockWitnessPrefix  = []byte("w") // blockWitnessPrefix + num (uint64 big endian) + hash -> block execution witness
+
+	chainEventPrefix = []byte("E") // chainEventPrefix + cursor (uint64 big endian) -> chain event log entry
 
 	txLookupPrefix        = []byte("l") // txLookupPrefix + hash -> transaction/receipt lookup metadata
 	bloomBitsPrefix       = []byte("B") // bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash -> bloom bits
@@ -151,6 +162,21 @@ func blockReceiptsKey(number uint64, hash common.Hash) []byte {
 	return append(append(blockReceiptsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// stateDiffKey = stateDiffPrefix + num (uint64 big endian) + hash
+func stateDiffKey(number uint64, hash common.Hash) []byte {
+	return append(append(stateDiffPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// blockWitnessKey = blockWitnessPrefix + num (uint64 big endian) + hash
+func blockWitnessKey(number uint64, hash common.Hash) []byte {
+	return append(append(blockWitnessPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// chainEventKey = chainEventPrefix + cursor (uint64 big endian)
+func chainEventKey(cursor uint64) []byte {
+	return append(chainEventPrefix, encodeBlockNumber(cursor)...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -121,19 +121,35 @@ func NewDatabaseWithConfig(db fafdb.Database, config *trie.Config) Database {
 	}
 }
 
+// NewBinaryDatabaseWithConfig creates a backing store for state, which stores
+// the accounts and storage slots in experimental binary Merkle tries instead of
+// hexary Merkle Patricia tries. The format of the state is fixed at genesis.
+func NewBinaryDatabaseWithConfig(db fafdb.Database, config *trie.Config) Database {
+	sdb := NewDatabaseWithConfig(db, config).(*cachingDB)
+	sdb.binary = true
+	return sdb
+}
+
 type cachingDB struct {
 	db            *trie.Database
 	codeSizeCache *lru.Cache
 	codeCache     *fastcache.Cache
+	binary        bool // Whether the state is stored in binary tries
 }
 
 // OpenTrie opens the main account trie at a specific root hash.
 func (db *cachingDB) OpenTrie(root common.Hash) (Trie, error) {
+	if db.binary {
+		return trie.NewBinary(root, db.db)
+	}
 	return trie.NewSecure(root, db.db)
 }
 
 // OpenStorageTrie opens the storage trie of an account.
 func (db *cachingDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
+	if db.binary {
+		return trie.NewBinary(root, db.db)
+	}
 	return trie.NewSecure(root, db.db)
 }
 
@@ -142,6 +158,8 @@ func (db *cachingDB) CopyTrie(t Trie) Trie {
 	switch t := t.(type) {
 	case *trie.SecureTrie:
 		return t.Copy()
+	case *trie.BinaryTrie:
+		return t.Copy()
 	default:
 		panic(fmt.Errorf("unknown trie type %T", t))
 	}
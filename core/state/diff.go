@@ -0,0 +1,150 @@
+
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Diff finalises all pending changes and computes the account and storage
+// modifications performed on top of the pre-state the StateDB was created
+// with. Both the pre and post values of the changed entries are retrieved from
+// the tries, so changes reverted within the block are not reported.
+//
+// Diff must be called before Commit, which resets the tracking of the modified
+// state objects.
+func (s *StateDB) Diff(deleteEmptyObjects bool) (*types.StateDiff, error) {
+	s.IntermediateRoot(deleteEmptyObjects)
+	if s.dbErr != nil {
+		return nil, fmt.Errorf("diff aborted due to earlier error: %v", s.dbErr)
+	}
+	pre, err := s.db.OpenTrie(s.originalRoot)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]common.Address, 0, len(s.stateObjectsDirty))
+	for addr := range s.stateObjectsDirty {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+
+	diff := new(types.StateDiff)
+	for _, addr := range addrs {
+		obj := s.stateObjects[addr]
+
+		// Retrieve the account before and after the block
+		prev, err := pre.TryGet(addr[:])
+		if err != nil {
+			return nil, err
+		}
+		var post []byte
+		if !obj.deleted {
+			if post, err = rlp.EncodeToBytes(obj); err != nil {
+				return nil, err
+			}
+		}
+		_, recreated := s.recreated[addr]
+
+		account := &types.AccountDiff{
+			Address: addr,
+			Prev:    prev,
+			Post:    post,
+			Wiped:   len(prev) > 0 && (obj.deleted || recreated),
+		}
+		if account.Storage, err = s.storageDiff(obj, prev, account.Wiped); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(prev, post) || len(account.Storage) > 0 {
+			diff.Accounts = append(diff.Accounts, account)
+		}
+	}
+	return diff, nil
+}
+
+// storageDiff computes the storage slot modifications of a single account,
+// given its RLP encoded pre-state and whether its original storage was wiped.
+func (s *StateDB) storageDiff(obj *stateObject, prev []byte, wiped bool) ([]*types.StorageDiff, error) {
+	// Open the original storage trie of the account, if there was any
+	var tr Trie
+	if len(prev) > 0 {
+		var data Account
+		if err := rlp.DecodeBytes(prev, &data); err != nil {
+			return nil, err
+		}
+		if data.Root != emptyRoot {
+			var err error
+			if tr, err = s.db.OpenStorageTrie(obj.addrHash, data.Root); err != nil {
+				return nil, err
+			}
+		}
+	}
+	slots := make(map[common.Hash]*types.StorageDiff)
+
+	// If the account was destructed, every original slot was cleared. Iterate the
+	// old storage to collect them, new writes are overlaid afterwards.
+	if wiped && tr != nil {
+		it := trie.NewIterator(tr.NodeIterator(nil))
+		for it.Next() {
+			value, err := decodeStorage(it.Value)
+			if err != nil {
+				return nil, err
+			}
+			hash := common.BytesToHash(it.Key)
+			slots[hash] = &types.StorageDiff{Hash: hash, Prev: value}
+		}
+		if it.Err != nil {
+			return nil, it.Err
+		}
+	}
+	// Overlay all the slots written by the live object
+	if !obj.deleted {
+		for key := range obj.updatedStorage {
+			hash := crypto.Keccak256Hash(key[:])
+
+			slot := slots[hash]
+			if slot == nil {
+				slot = &types.StorageDiff{Hash: hash}
+				if !wiped && tr != nil {
+					enc, err := tr.TryGet(key[:])
+					if err != nil {
+						return nil, err
+					}
+					if slot.Prev, err = decodeStorage(enc); err != nil {
+						return nil, err
+					}
+				}
+				slots[hash] = slot
+			}
+			slot.Post = obj.originStorage[key]
+		}
+	}
+	// Drop any unchanged slots and sort the rest for quick lookups
+	var diffs []*types.StorageDiff
+	for _, slot := range slots {
+		if slot.Prev != slot.Post {
+			diffs = append(diffs, slot)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return bytes.Compare(diffs[i].Hash[:], diffs[j].Hash[:]) < 0 })
+	return diffs, nil
+}
+
+// decodeStorage converts an RLP encoded storage trie value into a slot value.
+func decodeStorage(enc []byte) (common.Hash, error) {
+	if len(enc) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
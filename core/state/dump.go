@@ -124,7 +124,7 @@ func (s *StateDB) DumpToCollector(c DumpCollector, excludeCode, excludeStorage,
 			account.SecureKey = it.Key
 		}
 		addr := common.BytesToAddress(addrBytes)
-		obj := newObject(nil, addr, data)
+		obj := newObject(s, addr, data)
 		if !excludeCode {
 			account.Code = common.Bytes2Hex(obj.Code(s.db))
 		}
@@ -137,7 +137,12 @@ func (s *StateDB) DumpToCollector(c DumpCollector, excludeCode, excludeStorage,
 					log.Error("Failed to decode the value returned by iterator", "error", err)
 					continue
 				}
-				account.Storage[common.BytesToHash(s.trie.GetKey(storageIt.Key))] = common.Bytes2Hex(content)
+				// Fall back to the hashed slot key if the preimage is missing
+				key := s.trie.GetKey(storageIt.Key)
+				if key == nil {
+					key = storageIt.Key
+				}
+				account.Storage[common.BytesToHash(key)] = common.Bytes2Hex(content)
 			}
 		}
 		c.OnAccount(addr, account)
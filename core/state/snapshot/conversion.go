@@ -18,11 +18,14 @@ package snapshot
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/fafdb"
 	"github.com/ethereum/go-ethereum/fafdb/memorydb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -37,22 +40,61 @@ type trieKV struct {
 
 type (
 	// trieGeneratorFn is the interface of trie generation which can
-	// be implemented by different trie algorithm.
-	trieGeneratorFn func(in chan (trieKV), out chan (common.Hash))
+	// be implemented by different trie algorithm. If a database writer
+	// is specified, the generated trie nodes are committed into it.
+	trieGeneratorFn func(db fafdb.KeyValueWriter, in chan (trieKV), out chan (common.Hash))
 
 	// leafCallbackFn is the callback invoked at the leaves of the trie,
 	// returns the subtrie root with the specified subtrie identifier.
-	leafCallbackFn func(hash common.Hash, stat *generateStats) common.Hash
+	leafCallbackFn func(db fafdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error)
 )
 
 // GenerateAccountTrieRoot takes an account iterator and reproduces the root hash.
 func GenerateAccountTrieRoot(it AccountIterator) (common.Hash, error) {
-	return generateTrieRoot(it, common.Hash{}, stdGenerate, nil, &generateStats{start: time.Now()}, true)
+	return generateTrieRoot(nil, it, common.Hash{}, stackTrieGenerate, nil, &generateStats{start: time.Now()}, true)
 }
 
 // GenerateStorageTrieRoot takes a storage iterator and reproduces the root hash.
 func GenerateStorageTrieRoot(account common.Hash, it StorageIterator) (common.Hash, error) {
-	return generateTrieRoot(it, account, stdGenerate, nil, &generateStats{start: time.Now()}, true)
+	return generateTrieRoot(nil, it, account, stackTrieGenerate, nil, &generateStats{start: time.Now()}, true)
+}
+
+// GenerateTrie takes the whole snapshot tree as the input, traverses all the
+// accounts as well as the corresponding storages and regenerates the whole state
+// (account trie + all storage tries + contract codes) into the destination
+// database. The destination must be safe for concurrent writes.
+func GenerateTrie(snaptree *Tree, root common.Hash, src fafdb.Database, dst fafdb.KeyValueWriter) error {
+	acctIt, err := snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer acctIt.Release()
+
+	got, err := generateTrieRoot(dst, acctIt, common.Hash{}, stackTrieGenerate, func(dst fafdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error) {
+		// Migrate the code first, then all the storage trie nodes
+		if codeHash != emptyCode {
+			code := rawdb.ReadCode(src, codeHash)
+			if len(code) == 0 {
+				return common.Hash{}, errors.New("failed to read contract code")
+			}
+			rawdb.WriteCode(dst, codeHash, code)
+		}
+		storageIt, err := snaptree.StorageIterator(root, accountHash, common.Hash{})
+		if err != nil {
+			return common.Hash{}, err
+		}
+		defer storageIt.Release()
+
+		return generateTrieRoot(dst, storageIt, accountHash, stackTrieGenerate, nil, stat, false)
+	}, &generateStats{start: time.Now()}, true)
+
+	if err != nil {
+		return err
+	}
+	if got != root {
+		return fmt.Errorf("state root hash mismatch: got %x, want %x", got, root)
+	}
+	return nil
 }
 
 // VerifyState takes the whole snapshot tree as the input, traverses all the accounts
@@ -65,18 +107,14 @@ func VerifyState(snaptree *Tree, root common.Hash) error {
 	}
 	defer acctIt.Release()
 
-	got, err := generateTrieRoot(acctIt, common.Hash{}, stdGenerate, func(account common.Hash, stat *generateStats) common.Hash {
-		storageIt, err := snaptree.StorageIterator(root, account, common.Hash{})
+	got, err := generateTrieRoot(nil, acctIt, common.Hash{}, stackTrieGenerate, func(db fafdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error) {
+		storageIt, err := snaptree.StorageIterator(root, accountHash, common.Hash{})
 		if err != nil {
-			return common.Hash{}
+			return common.Hash{}, err
 		}
 		defer storageIt.Release()
 
-		hash, err := generateTrieRoot(storageIt, account, stdGenerate, nil, stat, false)
-		if err != nil {
-			return common.Hash{}
-		}
-		return hash
+		return generateTrieRoot(nil, storageIt, accountHash, stackTrieGenerate, nil, stat, false)
 	}, &generateStats{start: time.Now()}, true)
 
 	if err != nil {
@@ -150,7 +188,8 @@ func (stat *generateStats) reportDone() {
 // generateTrieRoot generates the trie hash based on the snapshot iterator.
 // It can be used for generating account trie, storage trie or even the
 // whole state which connects the accounts and the corresponding storages.
-func generateTrieRoot(it Iterator, account common.Hash, generatorFn trieGeneratorFn, leafCallback leafCallbackFn, stats *generateStats, report bool) (common.Hash, error) {
+// If a database writer is given, the generated trie nodes are committed.
+func generateTrieRoot(db fafdb.KeyValueWriter, it Iterator, account common.Hash, generatorFn trieGeneratorFn, leafCallback leafCallbackFn, stats *generateStats, report bool) (common.Hash, error) {
 	var (
 		in      = make(chan trieKV)         // chan to pass leaves
 		out     = make(chan common.Hash, 1) // chan to collect result
@@ -161,7 +200,7 @@ func generateTrieRoot(it Iterator, account common.Hash, generatorFn trieGenerato
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		generatorFn(in, out)
+		generatorFn(db, in, out)
 	}()
 
 	// Spin up a go-routine for progress logging
@@ -223,7 +262,11 @@ func generateTrieRoot(it Iterator, account common.Hash, generatorFn trieGenerato
 				}
 				// Apply the leaf callback. Normally the callback is used to traverse
 				// the storage trie and re-generate the subtrie root.
-				subroot := leafCallback(it.Hash(), stats)
+				subroot, err := leafCallback(db, it.Hash(), common.BytesToHash(account.CodeHash), stats)
+				if err != nil {
+					stop(false)
+					return common.Hash{}, err
+				}
 				if !bytes.Equal(account.Root, subroot.Bytes()) {
 					stop(false)
 					return common.Hash{}, fmt.Errorf("invalid subroot(%x), want %x, got %x", it.Hash(), account.Root, subroot)
@@ -266,10 +309,45 @@ func generateTrieRoot(it Iterator, account common.Hash, generatorFn trieGenerato
 
 // stdGenerate is a very basic hexary trie builder which uses the same Trie
 // as the rest of geth, with no enhancements or optimizations
-func stdGenerate(in chan (trieKV), out chan (common.Hash)) {
-	t, _ := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
+func stdGenerate(db fafdb.KeyValueWriter, in chan (trieKV), out chan (common.Hash)) {
+	var (
+		diskdb = memorydb.New()
+		triedb = trie.NewDatabase(diskdb)
+	)
+	t, _ := trie.New(common.Hash{}, triedb)
 	for leaf := range in {
 		t.TryUpdate(leaf.key[:], leaf.value)
 	}
-	out <- t.Hash()
+	if db == nil {
+		out <- t.Hash()
+		return
+	}
+	// Commit into a temporary database first, then move the nodes over
+	root, _ := t.Commit(nil)
+	triedb.Commit(root, false, nil)
+
+	it := diskdb.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		db.Put(it.Key(), it.Value())
+	}
+	out <- root
+}
+
+// stackTrieGenerate is a hexary trie builder which is built from bottom-up as
+// keys are added, committing the finished subtries into the database if one is
+// specified.
+func stackTrieGenerate(db fafdb.KeyValueWriter, in chan (trieKV), out chan (common.Hash)) {
+	t := trie.NewStackTrie(db)
+	for leaf := range in {
+		t.TryUpdate(leaf.key[:], leaf.value)
+	}
+	var root common.Hash
+	if db == nil {
+		root = t.Hash()
+	} else {
+		root, _ = t.Commit()
+	}
+	out <- root
 }
@@ -0,0 +1,151 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that the whole state trie can be regenerated from a snapshot into a
+// fresh database, with the stack trie and the standard trie producing the same
+// results.
+func TestGenerateTrie(t *testing.T) {
+	var (
+		diskdb = rawdb.NewMemoryDatabase()
+		triedb = trie.NewDatabase(diskdb)
+		code   = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+	)
+	rawdb.WriteCode(diskdb, crypto.Keccak256Hash(code), code)
+
+	stTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	for i := 0; i < 100; i++ {
+		stTrie.Update([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("val-%d", i)))
+	}
+	stRoot, _ := stTrie.Commit(nil)
+
+	accTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	for i := 0; i < 100; i++ {
+		acc := &Account{Balance: big.NewInt(int64(i)), Root: emptyRoot.Bytes(), CodeHash: emptyCode.Bytes()}
+		if i%10 == 0 {
+			acc.Root = stRoot.Bytes()
+		}
+		if i%20 == 0 {
+			acc.CodeHash = crypto.Keccak256(code)
+		}
+		val, _ := rlp.EncodeToBytes(acc)
+		accTrie.Update([]byte(fmt.Sprintf("acc-%d", i)), val)
+	}
+	root, _ := accTrie.Commit(func(path []byte, leaf []byte, parent common.Hash) error {
+		var acc Account
+		if err := rlp.DecodeBytes(leaf, &acc); err != nil {
+			return err
+		}
+		if root := common.BytesToHash(acc.Root); root != emptyRoot {
+			triedb.Reference(root, parent)
+		}
+		return nil
+	})
+	triedb.Commit(root, false, nil)
+
+	snap := generateSnapshot(diskdb, triedb, 16, root, nil)
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Snapshot generation failed")
+	}
+	snaps := &Tree{layers: map[common.Hash]snapshot{root: snap}}
+
+	if err := VerifyState(snaps, root); err != nil {
+		t.Fatalf("failed to verify state: %v", err)
+	}
+	// Regenerate the state into a clean database and ensure it's complete
+	dst := memorydb.New()
+	if err := GenerateTrie(snaps, root, diskdb, dst); err != nil {
+		t.Fatalf("failed to generate trie: %v", err)
+	}
+	it := dst.NewIterator(nil, nil)
+	for it.Next() {
+		blob, err := diskdb.Get(it.Key())
+		if err != nil {
+			t.Fatalf("unexpected entry %x: %v", it.Key(), err)
+		}
+		if !bytes.Equal(blob, it.Value()) {
+			t.Fatalf("entry %x mismatch: have %x, want %x", it.Key(), it.Value(), blob)
+		}
+	}
+	it.Release()
+
+	accIt := trie.NewIterator(mustNodeIterator(t, dst, root))
+	var accounts, slots int
+	for accIt.Next() {
+		var acc Account
+		if err := rlp.DecodeBytes(accIt.Value, &acc); err != nil {
+			t.Fatalf("failed to decode account: %v", err)
+		}
+		if codeHash := common.BytesToHash(acc.CodeHash); codeHash != emptyCode && rawdb.ReadCode(dst, codeHash) == nil {
+			t.Errorf("missing contract code %x", codeHash)
+		}
+		if root := common.BytesToHash(acc.Root); root != emptyRoot {
+			storageIt := trie.NewIterator(mustNodeIterator(t, dst, root))
+			for storageIt.Next() {
+				slots++
+			}
+			if storageIt.Err != nil {
+				t.Fatalf("failed to iterate storage trie: %v", storageIt.Err)
+			}
+		}
+		accounts++
+	}
+	if accIt.Err != nil {
+		t.Fatalf("failed to iterate account trie: %v", accIt.Err)
+	}
+	if accounts != 100 || slots != 1000 {
+		t.Errorf("state content mismatch: have %d accounts and %d slots, want 100 and 1000", accounts, slots)
+	}
+	// Cross check the stack trie generator against the standard trie
+	for _, generator := range []trieGeneratorFn{stdGenerate, stackTrieGenerate} {
+		accIt, _ := snaps.AccountIterator(root, common.Hash{})
+		hash, err := generateTrieRoot(nil, accIt, common.Hash{}, generator, nil, nil, false)
+		accIt.Release()
+
+		if err != nil {
+			t.Fatalf("failed to generate account trie root: %v", err)
+		}
+		if hash != root {
+			t.Errorf("account trie root mismatch: have %x, want %x", hash, root)
+		}
+	}
+}
+
+func mustNodeIterator(t *testing.T, db *memorydb.Database, root common.Hash) trie.NodeIterator {
+	tr, err := trie.New(root, trie.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to open trie %x: %v", root, err)
+	}
+	return tr.NodeIterator(nil)
+}
@@ -37,6 +37,7 @@ type generatorStats struct {
 	accounts uint64             // Number of accounts indexed
 	slots    uint64             // Number of storage slots indexed
 	storage  common.StorageSize // Account and storage slot size
+	missing  uint64             // Number of accounts and slots with unknown key preimages
 }
 
 // Log creates an contextual log with the given message and the context pulled
@@ -103,6 +104,21 @@ func generateSnapshot(diskdb fafdb.KeyValueStore, triedb *trie.Database, cache i
 	return base
 }
 
+// writePreimage adds the preimage of a hashed key to the batch if it's only known
+// by the in-memory preimage store, so it's persisted along with the snapshot. It
+// returns false if the preimage is unknown altogether.
+func writePreimage(batch fafdb.KeyValueWriter, diskdb fafdb.KeyValueReader, preimages *trie.PreimageStore, hash common.Hash) bool {
+	if rawdb.HasPreimage(diskdb, hash) {
+		return true
+	}
+	preimage := preimages.Preimage(hash)
+	if preimage == nil {
+		return false
+	}
+	rawdb.WritePreimages(batch, map[common.Hash][]byte{hash: preimage})
+	return true
+}
+
 // journalProgress persists the generator stats into the database to resume later.
 func journalProgress(db fafdb.KeyValueWriter, marker []byte, stats *generatorStats) {
 	// Write out the generator marker. Note it's a standalone disk layer generator
@@ -174,6 +190,10 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 	accIt := trie.NewIterator(accTrie.NodeIterator(accMarker))
 	batch := dl.diskdb.NewBatch()
 
+	// Persist the key preimages resolved while iterating the entire state and
+	// track the missing ones, so they can be backfilled
+	preimages := dl.triedb.Preimages()
+
 	// Iterate from the previous marker and continue generating the state snapshot
 	logged := time.Now()
 	for accIt.Next() {
@@ -196,6 +216,10 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 			rawdb.WriteAccountSnapshot(batch, accountHash, data)
 			stats.storage += common.StorageSize(1 + common.HashLength + len(data))
 			stats.accounts++
+
+			if !writePreimage(batch, dl.diskdb, preimages, accountHash) {
+				stats.missing++
+			}
 		}
 		// If we've exceeded our batch allowance or termination was requested, flush to disk
 		var abort chan *generatorStats
@@ -242,6 +266,10 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 				stats.storage += common.StorageSize(1 + 2*common.HashLength + len(storeIt.Value))
 				stats.slots++
 
+				if !writePreimage(batch, dl.diskdb, preimages, common.BytesToHash(storeIt.Key)) {
+					stats.missing++
+				}
+
 				// If we've exceeded our batch allowance or termination was requested, flush to disk
 				var abort chan *generatorStats
 				select {
@@ -298,6 +326,9 @@ func (dl *diskLayer) generate(stats *generatorStats) {
 	}
 	log.Info("Generated state snapshot", "accounts", stats.accounts, "slots", stats.slots,
 		"storage", stats.storage, "elapsed", common.PrettyDuration(time.Since(stats.start)))
+	if stats.missing > 0 {
+		log.Warn("State key preimages incomplete", "missing", stats.missing)
+	}
 
 	dl.lock.Lock()
 	dl.genMarker = nil
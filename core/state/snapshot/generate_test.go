@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/fafdb/memorydb"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
@@ -188,3 +190,53 @@ func TestGenerateCorruptStorageTrie(t *testing.T) {
 	snap.genAbort <- stop
 	<-stop
 }
+
+// Tests that snapshot generation persists the key preimages only known by the
+// in-memory preimage store of the trie database.
+func TestGeneratePreimages(t *testing.T) {
+	var (
+		diskdb = memorydb.New()
+		triedb = trie.NewDatabase(diskdb)
+	)
+	stTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	stTrie.Update([]byte("slot-1"), []byte{0x01})
+	stRoot, _ := stTrie.Commit(nil)
+
+	accTrie, _ := trie.NewSecure(common.Hash{}, triedb)
+	acc := &Account{Balance: big.NewInt(1), Root: stRoot.Bytes(), CodeHash: emptyCode.Bytes()}
+	val, _ := rlp.EncodeToBytes(acc)
+	accTrie.Update([]byte("acc-1"), val)
+
+	acc = &Account{Balance: big.NewInt(2), Root: emptyRoot.Bytes(), CodeHash: emptyCode.Bytes()}
+	val, _ = rlp.EncodeToBytes(acc)
+	accTrie.Update([]byte("acc-2"), val)
+	root, _ := accTrie.Commit(nil)
+	triedb.Commit(root, false, nil)
+
+	// Drop the persisted preimages, retaining some of them in memory only
+	it := diskdb.NewIterator([]byte("secure-key-"), nil)
+	for it.Next() {
+		diskdb.Delete(it.Key())
+	}
+	it.Release()
+	triedb.Preimages().Insert([]byte("acc-1"), []byte("slot-1"))
+
+	snap := generateSnapshot(diskdb, triedb, 16, root, nil)
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Snapshot generation failed")
+	}
+	for _, key := range []string{"acc-1", "slot-1"} {
+		if preimage := rawdb.ReadPreimage(diskdb, crypto.Keccak256Hash([]byte(key))); string(preimage) != key {
+			t.Errorf("preimage of %q not persisted: have %q", key, preimage)
+		}
+	}
+	if rawdb.HasPreimage(diskdb, crypto.Keccak256Hash([]byte("acc-2"))) {
+		t.Errorf("unknown preimage persisted")
+	}
+	// Signal abortion to the generator and wait for it to tear down
+	stop := make(chan *generatorStats)
+	snap.genAbort <- stop
+	<-stop
+}
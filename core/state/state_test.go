@@ -5,6 +5,7 @@ package state
 import (
 	"bytes"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -75,6 +76,47 @@ func TestDump(t *testing.T) {
 	}
 }
 
+// Tests that dumping accounts with storage resolves their storage tries through
+// the owning state, including when a prefetcher is running.
+func TestDumpStorage(t *testing.T) {
+	db := NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), nil)
+	sdb, _ := New(common.Hash{}, db, nil)
+
+	addr := toAddr([]byte{0x01})
+	sdb.SetBalance(addr, big.NewInt(7))
+	sdb.SetState(addr, common.Hash{0x01}, common.BytesToHash([]byte{0x2a}))
+	sdb.SetState(addr, common.Hash{0x02}, common.BytesToHash([]byte{0x01, 0x02}))
+	root, err := sdb.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	for _, prefetch := range []bool{false, true} {
+		state, _ := New(root, db, nil)
+		if prefetch {
+			state.StartPrefetcher("dump")
+		}
+		dump := state.RawDump(false, false, false)
+		account, ok := dump.Accounts[addr]
+		if !ok {
+			t.Fatalf("prefetch %v: account missing from dump", prefetch)
+		}
+		want := map[common.Hash]string{
+			{0x01}: "2a",
+			{0x02}: "0102",
+		}
+		if !reflect.DeepEqual(account.Storage, want) {
+			t.Errorf("prefetch %v: storage mismatch: have %v, want %v", prefetch, account.Storage, want)
+		}
+		if account.Balance != "7" {
+			t.Errorf("prefetch %v: balance mismatch: have %s, want 7", prefetch, account.Balance)
+		}
+		state.StopPrefetcher()
+	}
+}
+
 func TestNull(t *testing.T) {
 	s := newStateTest()
 	address := common.HexToAddress("0x823140710bf13990e4500136726d8b55")
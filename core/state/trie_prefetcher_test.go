@@ -0,0 +1,108 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+// filledStateDB creates a committed state with a few accounts and storage slots.
+func filledStateDB(t *testing.T) (Database, common.Hash) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(common.Hash{}, db, nil)
+
+	for i := byte(0); i < 16; i++ {
+		addr := common.BytesToAddress([]byte{i, 0xaf})
+		state.SetBalance(addr, big.NewInt(int64(i)+1))
+		state.SetCode(addr, []byte{i})
+		for j := int64(0); j < 32; j++ {
+			key := common.BigToHash(big.NewInt(j))
+			state.SetState(addr, key, common.BigToHash(big.NewInt(j+1)))
+		}
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	return db, root
+}
+
+// Tests that the prefetcher delivers the tries it was working on, and that both
+// active and inactive copies deliver the same content.
+func TestTriePrefetcherCopyAndClose(t *testing.T) {
+	db, root := filledStateDB(t)
+
+	prefetcher := newTriePrefetcher(db, root, "")
+	key := common.BytesToAddress([]byte{1, 0xaf}).Bytes()
+
+	prefetcher.prefetch(root, [][]byte{key})
+	prefetcher.prefetch(root, [][]byte{key})
+	a := prefetcher.trie(root)
+	prefetcher.prefetch(root, [][]byte{key})
+	b := prefetcher.trie(root)
+
+	cpy := prefetcher.copy()
+	cpy.prefetch(root, [][]byte{key})
+	c := cpy.trie(root)
+	prefetcher.close()
+
+	cpy2 := cpy.copy()
+	cpy2.prefetch(root, [][]byte{key})
+	d := cpy2.trie(root)
+	cpy.close()
+	cpy2.close()
+
+	for i, tr := range []Trie{a, b, c, d} {
+		if tr == nil {
+			t.Fatalf("trie %d: not delivered", i)
+		}
+		if hash := tr.Hash(); hash != root {
+			t.Errorf("trie %d: root mismatch: have %x, want %x", i, hash, root)
+		}
+	}
+	if tr := prefetcher.trie(common.Hash{1}); tr != nil {
+		t.Errorf("unknown trie delivered")
+	}
+}
+
+// Tests that state modifications hashed through prefetched tries result in the
+// same root as without any prefetching.
+func TestTriePrefetcherIntermediateRoot(t *testing.T) {
+	db, root := filledStateDB(t)
+
+	modify := func(state *StateDB) {
+		for i := byte(0); i < 16; i += 2 {
+			addr := common.BytesToAddress([]byte{i, 0xaf})
+			state.AddBalance(addr, big.NewInt(1))
+			state.SetState(addr, common.BigToHash(big.NewInt(int64(i))), common.Hash{})
+			state.SetState(addr, common.BigToHash(big.NewInt(100)), common.Hash{i + 1})
+		}
+		state.Suicide(common.BytesToAddress([]byte{3, 0xaf}))
+		state.SetBalance(common.BytesToAddress([]byte{0xff}), big.NewInt(1))
+		state.Finalise(true)
+	}
+	plain, _ := New(root, db, nil)
+	modify(plain)
+	want := plain.IntermediateRoot(true)
+
+	prefetched, _ := New(root, db, nil)
+	prefetched.prefetcher = newTriePrefetcher(db, root, "")
+	modify(prefetched)
+
+	// Read some of the storage through a copy while the prefetcher is running
+	cpy := prefetched.Copy()
+	if have := cpy.GetState(common.BytesToAddress([]byte{4, 0xaf}), common.BigToHash(big.NewInt(100))); have != (common.Hash{5}) {
+		t.Errorf("copied storage mismatch: have %x, want %x", have, common.Hash{5})
+	}
+	if have := prefetched.IntermediateRoot(true); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+	if prefetched.prefetcher != nil {
+		t.Errorf("prefetcher not stopped after hashing")
+	}
+	if have := cpy.IntermediateRoot(true); have != want {
+		t.Errorf("copy root mismatch: have %x, want %x", have, want)
+	}
+}
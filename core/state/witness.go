@@ -0,0 +1,341 @@
+
+
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// binaryCommitInterval is the number of leaves after which the binary tries
+// being built by ConvertToBinary are flushed to disk to bound memory usage.
+const binaryCommitInterval = 100000
+
+// stateAccesses tracks the accounts and storage slots loaded from (or written
+// into) the state tries, which together make up the witness needed to execute
+// a block statelessly.
+type stateAccesses struct {
+	accounts map[common.Address]struct{}
+	slots    map[common.Address]map[common.Hash]struct{}
+}
+
+func newStateAccesses() *stateAccesses {
+	return &stateAccesses{
+		accounts: make(map[common.Address]struct{}),
+		slots:    make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+func (a *stateAccesses) addAccount(addr common.Address) {
+	a.accounts[addr] = struct{}{}
+}
+
+func (a *stateAccesses) addSlot(addr common.Address, slot common.Hash) {
+	a.accounts[addr] = struct{}{}
+	if _, ok := a.slots[addr]; !ok {
+		a.slots[addr] = make(map[common.Hash]struct{})
+	}
+	a.slots[addr][slot] = struct{}{}
+}
+
+func (a *stateAccesses) copy() *stateAccesses {
+	cpy := newStateAccesses()
+	for addr := range a.accounts {
+		cpy.accounts[addr] = struct{}{}
+	}
+	for addr, slots := range a.slots {
+		cpy.slots[addr] = make(map[common.Hash]struct{}, len(slots))
+		for slot := range slots {
+			cpy.slots[addr][slot] = struct{}{}
+		}
+	}
+	return cpy
+}
+
+// RecordAccesses starts tracking all the accounts and storage slots that are
+// resolved from the underlying tries from now on. Entries already cached in
+// the state are not reported, so recording should be enabled on a fresh state.
+func (s *StateDB) RecordAccesses() {
+	s.accesses = newStateAccesses()
+}
+
+// Accesses returns the accounts and storage slots touched since RecordAccesses
+// was called, or nil if recording is disabled.
+func (s *StateDB) Accesses() map[common.Address][]common.Hash {
+	if s.accesses == nil {
+		return nil
+	}
+	accesses := make(map[common.Address][]common.Hash, len(s.accesses.accounts))
+	for addr := range s.accesses.accounts {
+		slots := make([]common.Hash, 0, len(s.accesses.slots[addr]))
+		for slot := range s.accesses.slots[addr] {
+			slots = append(slots, slot)
+		}
+		accesses[addr] = slots
+	}
+	return accesses
+}
+
+// WitnessRecorder collects the trie nodes and contract code resolved through a
+// recording state database, which together make up the state witness needed to
+// replay an execution without the full state database.
+type WitnessRecorder struct {
+	nodes *trie.Recorder
+	codes map[common.Hash][]byte
+	lock  sync.Mutex
+}
+
+// NewWitnessRecorder creates an empty state witness recorder.
+func NewWitnessRecorder() *WitnessRecorder {
+	return &WitnessRecorder{
+		nodes: trie.NewRecorder(),
+		codes: make(map[common.Hash][]byte),
+	}
+}
+
+func (r *WitnessRecorder) recordCode(hash common.Hash, code []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.codes[hash] = code
+}
+
+// Nodes returns the recorded trie nodes, sorted by hash.
+func (r *WitnessRecorder) Nodes() [][]byte {
+	return sortedByHash(r.nodes.Nodes())
+}
+
+// Codes returns the recorded contract code, sorted by hash.
+func (r *WitnessRecorder) Codes() [][]byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return sortedByHash(r.codes)
+}
+
+// sortedByHash flattens a hash->blob mapping into a list ordered by the hashes.
+func sortedByHash(blobs map[common.Hash][]byte) [][]byte {
+	hashes := make([]common.Hash, 0, len(blobs))
+	for hash := range blobs {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return bytes.Compare(hashes[i][:], hashes[j][:]) < 0 })
+
+	list := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		list[i] = blobs[hash]
+	}
+	return list
+}
+
+// recordableTrie is a state trie able to record the nodes it resolves.
+type recordableTrie interface {
+	SetRecorder(recorder *trie.Recorder)
+}
+
+// recordingDB is a state database view attaching a witness recorder to all the
+// tries opened through it and to all contract code retrievals.
+type recordingDB struct {
+	Database
+	recorder *WitnessRecorder
+}
+
+// NewRecordingDatabase wraps a state database, recording all the trie nodes and
+// contract code resolved through the returned view into recorder.
+func NewRecordingDatabase(db Database, recorder *WitnessRecorder) Database {
+	return &recordingDB{Database: db, recorder: recorder}
+}
+
+// OpenTrie opens the main account trie, attaching the recorder to it.
+func (db *recordingDB) OpenTrie(root common.Hash) (Trie, error) {
+	tr, err := db.Database.OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	if tr, ok := tr.(recordableTrie); ok {
+		tr.SetRecorder(db.recorder.nodes)
+	}
+	return tr, nil
+}
+
+// OpenStorageTrie opens the storage trie of an account, attaching the recorder
+// to it.
+func (db *recordingDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
+	tr, err := db.Database.OpenStorageTrie(addrHash, root)
+	if err != nil {
+		return nil, err
+	}
+	if tr, ok := tr.(recordableTrie); ok {
+		tr.SetRecorder(db.recorder.nodes)
+	}
+	return tr, nil
+}
+
+// ContractCode retrieves a particular contract's code, recording it.
+func (db *recordingDB) ContractCode(addrHash, codeHash common.Hash) ([]byte, error) {
+	code, err := db.Database.ContractCode(addrHash, codeHash)
+	if err == nil {
+		db.recorder.recordCode(codeHash, code)
+	}
+	return code, err
+}
+
+// ContractCodeSize retrieves a particular contracts code's size. The whole code
+// is recorded, since it's needed to answer the query without the database.
+func (db *recordingDB) ContractCodeSize(addrHash, codeHash common.Hash) (int, error) {
+	code, err := db.ContractCode(addrHash, codeHash)
+	return len(code), err
+}
+
+// ProveAccesses writes the merkle proofs of the given accounts and storage slots
+// in the state identified by root into proofDb. Proof nodes are keyed by their
+// hash, so nodes shared between proofs are only stored once and the contents of
+// proofDb make up the state witness for the accesses.
+func ProveAccesses(db Database, root common.Hash, accesses map[common.Address][]common.Hash, proofDb fafdb.KeyValueWriter) error {
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	for addr, slots := range accesses {
+		if err := tr.Prove(crypto.Keccak256(addr.Bytes()), 0, proofDb); err != nil {
+			return fmt.Errorf("failed to prove account %x: %v", addr, err)
+		}
+		if len(slots) == 0 {
+			continue
+		}
+		enc, err := tr.TryGet(addr.Bytes())
+		if err != nil {
+			return err
+		}
+		if len(enc) == 0 {
+			continue // Slots of a non-existent account need no proof
+		}
+		var data Account
+		if err := rlp.DecodeBytes(enc, &data); err != nil {
+			return fmt.Errorf("invalid account %x: %v", addr, err)
+		}
+		addrHash := crypto.Keccak256Hash(addr.Bytes())
+		storage, err := db.OpenStorageTrie(addrHash, data.Root)
+		if err != nil {
+			return err
+		}
+		for _, slot := range slots {
+			if err := storage.Prove(crypto.Keccak256(slot.Bytes()), 0, proofDb); err != nil {
+				return fmt.Errorf("failed to prove slot %x of %x: %v", slot, addr, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ConvertToBinary copies the Merkle Patricia state identified by root from src
+// into binary tries in dst, which must have been created with
+// NewBinaryDatabaseWithConfig. Contract code missing from the dst database is
+// copied over as well. The binary state root is returned.
+//
+// The hashed keys of the source tries are inserted as is, so the preimages of
+// the source state remain valid for the converted one.
+func ConvertToBinary(src Database, root common.Hash, dst Database) (common.Hash, error) {
+	accTrie, err := src.OpenTrie(root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tr, err := dst.OpenTrie(emptyRoot)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	binTrie, ok := tr.(*trie.BinaryTrie)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("destination database is not binary")
+	}
+	var (
+		accounts, slots int
+		start           = time.Now()
+		logged          = time.Now()
+	)
+	it := trie.NewIterator(accTrie.NodeIterator(nil))
+	for it.Next() {
+		var data Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return common.Hash{}, fmt.Errorf("invalid account %x: %v", it.Key, err)
+		}
+		// Convert the storage trie of the account, if any
+		if data.Root != emptyRoot {
+			srcStorage, err := src.OpenStorageTrie(common.BytesToHash(it.Key), data.Root)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			dstStorage, err := dst.OpenStorageTrie(common.BytesToHash(it.Key), emptyRoot)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			binStorage := dstStorage.(*trie.BinaryTrie)
+
+			var count int
+			storageIt := trie.NewIterator(srcStorage.NodeIterator(nil))
+			for storageIt.Next() {
+				if err := binStorage.TryUpdateHashed(storageIt.Key, storageIt.Value); err != nil {
+					return common.Hash{}, err
+				}
+				if count++; count%binaryCommitInterval == 0 {
+					if _, err := binStorage.Commit(nil); err != nil {
+						return common.Hash{}, err
+					}
+				}
+			}
+			if storageIt.Err != nil {
+				return common.Hash{}, storageIt.Err
+			}
+			if data.Root, err = binStorage.Commit(nil); err != nil {
+				return common.Hash{}, err
+			}
+			slots += count
+		}
+		// Copy the contract code if it's not yet in the destination database
+		if codeHash := common.BytesToHash(data.CodeHash); codeHash != common.BytesToHash(emptyCodeHash) {
+			if len(rawdb.ReadCode(dst.TrieDB().DiskDB(), codeHash)) == 0 {
+				code, err := src.ContractCode(common.BytesToHash(it.Key), codeHash)
+				if err != nil {
+					return common.Hash{}, err
+				}
+				rawdb.WriteCode(dst.TrieDB().DiskDB(), codeHash, code)
+			}
+		}
+		enc, err := rlp.EncodeToBytes(&data)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if err := binTrie.TryUpdateHashed(it.Key, enc); err != nil {
+			return common.Hash{}, err
+		}
+		if accounts++; accounts%binaryCommitInterval == 0 {
+			if _, err := binTrie.Commit(nil); err != nil {
+				return common.Hash{}, err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Converting state to binary trie", "accounts", accounts, "slots", slots, "at", common.BytesToHash(it.Key), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if it.Err != nil {
+		return common.Hash{}, it.Err
+	}
+	binRoot, err := binTrie.Commit(nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Converted state to binary trie", "root", binRoot, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+	return binRoot, nil
+}
@@ -0,0 +1,162 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// testStorageSlot returns the j-th storage slot of the i-th account populated by
+// makeTestState.
+func testStorageSlot(i, j byte) common.Hash {
+	return crypto.Keccak256Hash([]byte{i, i, i, i, i, j, j})
+}
+
+// Tests that converting a state into binary tries retains all its content and
+// results in the same root as building the binary state directly.
+func TestConvertToBinary(t *testing.T) {
+	src, root, accounts := makeTestState()
+
+	dst := NewBinaryDatabaseWithConfig(rawdb.NewMemoryDatabase(), nil)
+	binRoot, err := ConvertToBinary(src, root, dst)
+	if err != nil {
+		t.Fatalf("failed to convert state: %v", err)
+	}
+	if binRoot == root {
+		t.Fatalf("binary root matches the hexary one")
+	}
+	state, err := New(binRoot, dst, nil)
+	if err != nil {
+		t.Fatalf("failed to open binary state: %v", err)
+	}
+	for i, acc := range accounts {
+		if balance := state.GetBalance(acc.address); balance.Cmp(acc.balance) != 0 {
+			t.Errorf("account %d: balance mismatch: have %v, want %v", i, balance, acc.balance)
+		}
+		if nonce := state.GetNonce(acc.address); nonce != acc.nonce {
+			t.Errorf("account %d: nonce mismatch: have %v, want %v", i, nonce, acc.nonce)
+		}
+		if code := state.GetCode(acc.address); !bytes.Equal(code, acc.code) {
+			t.Errorf("account %d: code mismatch: have %x, want %x", i, code, acc.code)
+		}
+		if i%5 == 0 {
+			for j := byte(0); j < 5; j++ {
+				slot := testStorageSlot(byte(i), j)
+				if have := state.GetState(acc.address, slot); have != slot {
+					t.Errorf("account %d: slot %d mismatch: have %x, want %x", i, j, have, slot)
+				}
+			}
+		}
+	}
+	// Build the same state directly in binary tries and compare the roots
+	direct, _ := New(common.Hash{}, NewBinaryDatabaseWithConfig(rawdb.NewMemoryDatabase(), nil), nil)
+	for i, acc := range accounts {
+		direct.SetBalance(acc.address, acc.balance)
+		direct.SetNonce(acc.address, acc.nonce)
+		if acc.code != nil {
+			direct.SetCode(acc.address, acc.code)
+		}
+		if i%5 == 0 {
+			for j := byte(0); j < 5; j++ {
+				direct.SetState(acc.address, testStorageSlot(byte(i), j), testStorageSlot(byte(i), j))
+			}
+		}
+	}
+	if have, _ := direct.Commit(false); have != binRoot {
+		t.Fatalf("directly built binary root mismatch: have %x, want %x", have, binRoot)
+	}
+	// Modify the converted state and ensure it can be reopened
+	state.SetBalance(accounts[1].address, big.NewInt(1))
+	state.SetState(accounts[5].address, testStorageSlot(5, 0), common.Hash{})
+
+	newRoot, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit binary state: %v", err)
+	}
+	state, err = New(newRoot, dst, nil)
+	if err != nil {
+		t.Fatalf("failed to reopen binary state: %v", err)
+	}
+	if balance := state.GetBalance(accounts[1].address); balance.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("modified balance mismatch: have %v, want 1", balance)
+	}
+	if have := state.GetState(accounts[5].address, testStorageSlot(5, 0)); have != (common.Hash{}) {
+		t.Errorf("deleted slot mismatch: have %x, want empty", have)
+	}
+}
+
+// Tests that the recorded state accesses can be proven in both the hexary and
+// the binary trie formats.
+func TestProveAccesses(t *testing.T) {
+	db, root, accounts := makeTestState()
+
+	binDb := NewBinaryDatabaseWithConfig(rawdb.NewMemoryDatabase(), nil)
+	binRoot, err := ConvertToBinary(db, root, binDb)
+	if err != nil {
+		t.Fatalf("failed to convert state: %v", err)
+	}
+	verifiers := []func(common.Hash, []byte, *memorydb.Database) ([]byte, error){
+		func(root common.Hash, key []byte, proof *memorydb.Database) ([]byte, error) {
+			return trie.VerifyProof(root, key, proof)
+		},
+		func(root common.Hash, key []byte, proof *memorydb.Database) ([]byte, error) {
+			return trie.VerifyBinaryProof(root, key, proof)
+		},
+	}
+	missing := common.BytesToAddress([]byte("missing"))
+	for i, db := range []Database{db, binDb} {
+		root := []common.Hash{root, binRoot}[i]
+		verify := verifiers[i]
+
+		state, _ := New(root, db, nil)
+		state.RecordAccesses()
+		state.GetBalance(accounts[1].address)
+		state.GetState(accounts[5].address, testStorageSlot(5, 1))
+		state.GetState(accounts[5].address, common.Hash{0x01})
+		state.GetBalance(missing)
+
+		accesses := state.Accesses()
+		if len(accesses) != 3 {
+			t.Fatalf("format %d: accessed account count mismatch: have %d, want 3", i, len(accesses))
+		}
+		if len(accesses[accounts[5].address]) != 2 {
+			t.Fatalf("format %d: accessed slot count mismatch: have %d, want 2", i, len(accesses[accounts[5].address]))
+		}
+		proof := memorydb.New()
+		if err := ProveAccesses(db, root, accesses, proof); err != nil {
+			t.Fatalf("format %d: failed to prove accesses: %v", i, err)
+		}
+		if enc, err := verify(root, crypto.Keccak256(missing.Bytes()), proof); err != nil || enc != nil {
+			t.Fatalf("format %d: failed to prove missing account: %x, %v", i, enc, err)
+		}
+		enc, err := verify(root, crypto.Keccak256(accounts[5].address.Bytes()), proof)
+		if err != nil {
+			t.Fatalf("format %d: failed to verify account proof: %v", i, err)
+		}
+		var data Account
+		if err := rlp.DecodeBytes(enc, &data); err != nil {
+			t.Fatalf("format %d: failed to decode proven account: %v", i, err)
+		}
+		if data.Nonce != accounts[5].nonce {
+			t.Fatalf("format %d: proven nonce mismatch: have %d, want %d", i, data.Nonce, accounts[5].nonce)
+		}
+		slot := testStorageSlot(5, 1)
+		enc, err = verify(data.Root, crypto.Keccak256(slot.Bytes()), proof)
+		if err != nil {
+			t.Fatalf("format %d: failed to verify slot proof: %v", i, err)
+		}
+		if _, content, _, _ := rlp.Split(enc); common.BytesToHash(content) != slot {
+			t.Fatalf("format %d: proven slot mismatch: have %x, want %x", i, content, slot)
+		}
+		if enc, err := verify(data.Root, crypto.Keccak256(common.Hash{0x01}.Bytes()), proof); err != nil || enc != nil {
+			t.Fatalf("format %d: failed to prove missing slot: %x, %v", i, enc, err)
+		}
+	}
+}
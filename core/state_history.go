@@ -0,0 +1,262 @@
+
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// defaultStateDiffRewind is the maximum number of blocks historical state queries
+// may rewind the head state, if not configured otherwise.
+const defaultStateDiffRewind = uint64(8192)
+
+var (
+	// errStateDiffsDisabled is returned if historical state is requested from a
+	// chain not configured to persist per-block state diffs.
+	errStateDiffsDisabled = errors.New("state diffs disabled")
+
+	// errNonCanonicalState is returned if historical state is requested for a
+	// block not part of the canonical chain.
+	errNonCanonicalState = errors.New("historical state only available for canonical blocks")
+
+	// errStateDiffsUnavailable is returned if the state diffs needed to rewind
+	// the head state are missing, because the blocks were processed before diffs
+	// were enabled, or were fast synced without execution.
+	errStateDiffsUnavailable = errors.New("state diffs unavailable (block predates enabling diffs or was fast synced)")
+
+	// errStateRewindTooDeep is returned if historical state is requested for a
+	// block further behind the head than the state diffs may be rewound.
+	errStateRewindTooDeep = errors.New("historical state too far behind head")
+
+	// emptyCodeHash is the known hash of the empty EVM bytecode.
+	emptyCodeHash = crypto.Keccak256Hash(nil)
+)
+
+// HistoricalState provides read access to the post-state of an old canonical
+// block on nodes which already pruned its state trie. Values are reconstructed
+// from the persisted state diffs of the blocks following the requested one: the
+// first diff modifying an item holds its historical value, while items never
+// modified since are read from the current head state.
+//
+// Similarly to state.StateDB, database errors are memoized and can be retrieved
+// via Error.
+type HistoricalState struct {
+	bc     *BlockChain
+	number uint64         // Number of the block whose post-state is reconstructed
+	hash   common.Hash    // Hash of the block whose post-state is reconstructed
+	head   *types.Header  // Head block the diffs are rewound from
+	state  *state.StateDB // Head state the diffs are rewound from
+	err    error          // First error encountered while reconstructing the state
+}
+
+// HistoricalState creates a reader for the post-state of the given canonical
+// block, reconstructed from the persisted state diffs. Blocks further behind the
+// head than the configured rewind limit are refused.
+func (bc *BlockChain) HistoricalState(header *types.Header) (*HistoricalState, error) {
+	if !bc.cacheConfig.StateDiffs {
+		return nil, errStateDiffsDisabled
+	}
+	head := bc.CurrentBlock().Header()
+	number := header.Number.Uint64()
+	if number > head.Number.Uint64() || rawdb.ReadCanonicalHash(bc.db, number) != header.Hash() {
+		return nil, errNonCanonicalState
+	}
+	// Every query walks the diffs back from the head, bound the work done
+	limit := bc.cacheConfig.StateDiffRewind
+	if limit == 0 {
+		limit = defaultStateDiffRewind
+	}
+	if depth := head.Number.Uint64() - number; depth > limit {
+		return nil, fmt.Errorf("%w: %d blocks, limit %d", errStateRewindTooDeep, depth, limit)
+	}
+	// Fail early if the state can't be rewound to the requested block at all
+	if number < head.Number.Uint64() {
+		next := rawdb.ReadCanonicalHash(bc.db, number+1)
+		if bc.stateDiff(next, number+1) == nil {
+			return nil, fmt.Errorf("%w: block #%d", errStateDiffsUnavailable, number+1)
+		}
+	}
+	statedb, err := bc.StateAt(head.Root)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoricalState{
+		bc:     bc,
+		number: number,
+		hash:   header.Hash(),
+		head:   head,
+		state:  statedb,
+	}, nil
+}
+
+// stateDiff retrieves the state diff of a block from the cache or database.
+func (bc *BlockChain) stateDiff(hash common.Hash, number uint64) *types.StateDiff {
+	if cached, ok := bc.diffCache.Get(hash); ok {
+		return cached.(*types.StateDiff)
+	}
+	diff := rawdb.ReadStateDiff(bc.db, hash, number)
+	if diff == nil {
+		return nil
+	}
+	bc.diffCache.Add(hash, diff)
+	return diff
+}
+
+// setError remembers the first non-nil error it is called with.
+func (h *HistoricalState) setError(err error) {
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// Error returns the first error encountered while reconstructing the state.
+func (h *HistoricalState) Error() error {
+	if h.err != nil {
+		return h.err
+	}
+	return h.state.Error()
+}
+
+// rewind iterates the state diffs of the blocks following the requested one in
+// ascending order, until the callback reports the searched item as found. The
+// first block modifying an item holds its historical value as the pre-state.
+//
+// Blocks are resolved lazily through the canonical chain, checking that they
+// still link up to the head the reader was created with, in case of a reorg.
+func (h *HistoricalState) rewind(fn func(diff *types.StateDiff) bool) bool {
+	parent := h.hash
+	for number := h.number + 1; number <= h.head.Number.Uint64(); number++ {
+		header := h.bc.GetHeaderByNumber(number)
+		if header == nil || header.ParentHash != parent {
+			h.setError(errNonCanonicalState)
+			return false
+		}
+		hash := header.Hash()
+		if number == h.head.Number.Uint64() && hash != h.head.Hash() {
+			h.setError(errNonCanonicalState)
+			return false
+		}
+		diff := h.bc.stateDiff(hash, number)
+		if diff == nil {
+			h.setError(fmt.Errorf("%w: block #%d [%x…]", errStateDiffsUnavailable, number, hash[:4]))
+			return false
+		}
+		if fn(diff) {
+			return true
+		}
+		parent = hash
+	}
+	return false
+}
+
+// getAccount retrieves the historical account data of an address, or nil if
+// the account did not exist.
+func (h *HistoricalState) getAccount(addr common.Address) *state.Account {
+	var account *state.Account
+	found := h.rewind(func(diff *types.StateDiff) bool {
+		modified := diff.Account(addr)
+		if modified == nil {
+			return false
+		}
+		if len(modified.Prev) > 0 {
+			account = new(state.Account)
+			if err := rlp.DecodeBytes(modified.Prev, account); err != nil {
+				h.setError(err)
+				account = nil
+			}
+		}
+		return true
+	})
+	if h.err != nil {
+		return nil
+	}
+	// If the account was not modified since, it's the same as in the head state
+	if !found && h.state.Exist(addr) {
+		account = &state.Account{
+			Nonce:    h.state.GetNonce(addr),
+			Balance:  h.state.GetBalance(addr),
+			CodeHash: h.state.GetCodeHash(addr).Bytes(),
+		}
+	}
+	return account
+}
+
+// Exist reports whether the given account existed in the historical state.
+func (h *HistoricalState) Exist(addr common.Address) bool {
+	return h.getAccount(addr) != nil
+}
+
+// GetBalance retrieves the historical balance of an account.
+func (h *HistoricalState) GetBalance(addr common.Address) *big.Int {
+	if account := h.getAccount(addr); account != nil {
+		return account.Balance
+	}
+	return common.Big0
+}
+
+// GetNonce retrieves the historical nonce of an account.
+func (h *HistoricalState) GetNonce(addr common.Address) uint64 {
+	if account := h.getAccount(addr); account != nil {
+		return account.Nonce
+	}
+	return 0
+}
+
+// GetCodeHash retrieves the historical code hash of an account.
+func (h *HistoricalState) GetCodeHash(addr common.Address) common.Hash {
+	if account := h.getAccount(addr); account != nil {
+		return common.BytesToHash(account.CodeHash)
+	}
+	return common.Hash{}
+}
+
+// GetCode retrieves the historical code of an account. Contract code is never
+// pruned, so it's looked up directly by hash.
+func (h *HistoricalState) GetCode(addr common.Address) []byte {
+	hash := h.GetCodeHash(addr)
+	if hash == (common.Hash{}) || hash == emptyCodeHash {
+		return nil
+	}
+	code, err := h.bc.stateCache.ContractCode(crypto.Keccak256Hash(addr[:]), hash)
+	if err != nil {
+		h.setError(err)
+	}
+	return code
+}
+
+// GetState retrieves the historical value of a storage slot of an account.
+func (h *HistoricalState) GetState(addr common.Address, key common.Hash) common.Hash {
+	var (
+		hash  = crypto.Keccak256Hash(key[:])
+		value common.Hash
+	)
+	found := h.rewind(func(diff *types.StateDiff) bool {
+		modified := diff.Account(addr)
+		if modified == nil {
+			return false
+		}
+		slot := modified.Slot(hash)
+		if slot == nil {
+			// Wiped and created accounts list all their original slots
+			return modified.Wiped || len(modified.Prev) == 0
+		}
+		value = slot.Prev
+		return true
+	})
+	if h.err != nil {
+		return common.Hash{}
+	}
+	if !found {
+		return h.state.GetState(addr, key)
+	}
+	return value
+}
@@ -0,0 +1,252 @@
+
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the historical state reconstructed from the persisted state diffs
+// matches the archived state of every block, including storage modifications
+// and contract self-destructs.
+func TestHistoricalState(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		funds   = big.NewInt(1000000000)
+
+		// Factory deploying its calldata as init code via CREATE2 with a zero salt
+		factory = common.BytesToAddress([]byte{0xff, 0xff})
+		bb      = common.BytesToAddress([]byte{0xbb, 0xbb})
+
+		gspec = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc: GenesisAlloc{
+				address: {Balance: funds},
+				factory: {
+					Code: []byte{
+						byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00, byte(vm.CALLDATACOPY),
+						byte(vm.PUSH1), 0x00, byte(vm.CALLDATASIZE), byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x00,
+						byte(vm.CREATE2), byte(vm.STOP),
+					},
+					Balance: big.NewInt(0),
+				},
+			},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.HomesteadSigner{}
+
+		// Contract storing calldata[32:64] into slot calldata[0:32], or
+		// self-destructing if called without any calldata. The init code sets
+		// slot 1 to 1 before returning the runtime code.
+		code = []byte{
+			byte(vm.CALLDATASIZE), byte(vm.ISZERO), byte(vm.PUSH1), 0x0d, byte(vm.JUMPI),
+			byte(vm.PUSH1), 0x20, byte(vm.CALLDATALOAD),
+			byte(vm.PUSH1), 0x00, byte(vm.CALLDATALOAD),
+			byte(vm.SSTORE), byte(vm.STOP),
+			byte(vm.JUMPDEST), byte(vm.CALLER), byte(vm.SELFDESTRUCT),
+		}
+		initcode = append([]byte{
+			byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x01, byte(vm.SSTORE),
+			byte(vm.PUSH1), byte(len(code)), byte(vm.PUSH1), 0x11, byte(vm.PUSH1), 0x00, byte(vm.CODECOPY),
+			byte(vm.PUSH1), byte(len(code)), byte(vm.PUSH1), 0x00, byte(vm.RETURN),
+		}, code...)
+		aa = crypto.CreateAddress2(factory, common.Hash{}, crypto.Keccak256(initcode))
+	)
+	store := func(slot, value int64) []byte {
+		return append(common.BigToHash(big.NewInt(slot)).Bytes(), common.BigToHash(big.NewInt(value)).Bytes()...)
+	}
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 6, func(i int, b *BlockGen) {
+		b.SetCoinbase(common.Address{1})
+
+		var txs []*types.Transaction
+		switch i {
+		case 0:
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), bb, big.NewInt(1000), 21000, big.NewInt(1), nil))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+1, factory, big.NewInt(0), 200000, big.NewInt(1), initcode))
+		case 1:
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), aa, big.NewInt(0), 100000, big.NewInt(1), store(2, 2)))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+1, aa, big.NewInt(0), 100000, big.NewInt(1), store(3, 7)))
+		case 2:
+			// Modify a slot and revert it within the same block
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), aa, big.NewInt(0), 100000, big.NewInt(1), store(2, 9)))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+1, aa, big.NewInt(0), 100000, big.NewInt(1), store(2, 2)))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+2, aa, big.NewInt(0), 100000, big.NewInt(1), store(1, 5)))
+		case 3:
+			// Destruct and resurrect the contract within the same block
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), aa, big.NewInt(0), 100000, big.NewInt(1), nil))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+1, factory, big.NewInt(0), 200000, big.NewInt(1), initcode))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+2, aa, big.NewInt(0), 100000, big.NewInt(1), store(4, 4)))
+		case 4:
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), bb, big.NewInt(1000), 21000, big.NewInt(1), nil))
+			txs = append(txs, types.NewTransaction(b.TxNonce(address)+1, aa, big.NewInt(0), 100000, big.NewInt(1), store(3, 3)))
+		case 5:
+			txs = append(txs, types.NewTransaction(b.TxNonce(address), aa, big.NewInt(0), 100000, big.NewInt(1), nil))
+		}
+		for _, tx := range txs {
+			signed, _ := types.SignTx(tx, signer, key)
+			b.AddTx(signed)
+		}
+	})
+	// Import the chain into an archive node persisting state diffs
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	config := *defaultCacheConfig
+	config.TrieDirtyDisabled = true
+	config.StateDiffs = true
+
+	chain, err := NewBlockChain(diskdb, &config, params.TestChainConfig, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Cross check the reconstructed state against the archived one
+	accounts := []common.Address{address, factory, aa, bb, {1}}
+	var slots []common.Hash
+	for i := int64(1); i <= 4; i++ {
+		slots = append(slots, common.BigToHash(big.NewInt(i)))
+	}
+
+	for number := uint64(0); number <= chain.CurrentBlock().NumberU64(); number++ {
+		header := chain.GetHeaderByNumber(number)
+
+		historical, err := chain.HistoricalState(header)
+		if err != nil {
+			t.Fatalf("block %d: failed to create historical state: %v", number, err)
+		}
+		archive, err := chain.StateAt(header.Root)
+		if err != nil {
+			t.Fatalf("block %d: failed to open archive state: %v", number, err)
+		}
+		for _, addr := range accounts {
+			if have, want := historical.Exist(addr), archive.Exist(addr); have != want {
+				t.Errorf("block %d, account %x: existence mismatch: have %v, want %v", number, addr, have, want)
+			}
+			if have, want := historical.GetBalance(addr), archive.GetBalance(addr); have.Cmp(want) != 0 {
+				t.Errorf("block %d, account %x: balance mismatch: have %v, want %v", number, addr, have, want)
+			}
+			if have, want := historical.GetNonce(addr), archive.GetNonce(addr); have != want {
+				t.Errorf("block %d, account %x: nonce mismatch: have %v, want %v", number, addr, have, want)
+			}
+			if have, want := historical.GetCode(addr), archive.GetCode(addr); !bytes.Equal(have, want) {
+				t.Errorf("block %d, account %x: code mismatch: have %x, want %x", number, addr, have, want)
+			}
+			for _, slot := range slots {
+				if have, want := historical.GetState(addr, slot), archive.GetState(addr, slot); have != want {
+					t.Errorf("block %d, account %x, slot %x: value mismatch: have %x, want %x", number, addr, slot, have, want)
+				}
+			}
+		}
+		if err := historical.Error(); err != nil {
+			t.Fatalf("block %d: historical state error: %v", number, err)
+		}
+	}
+	// Ensure missing diffs are reported instead of returning bogus values
+	rawdb.DeleteStateDiff(diskdb, blocks[2].Hash(), blocks[2].NumberU64())
+	chain.diffCache.Purge()
+
+	historical, err := chain.HistoricalState(blocks[0].Header())
+	if err != nil {
+		t.Fatalf("failed to create historical state: %v", err)
+	}
+	historical.GetBalance(bb)
+	if err := historical.Error(); !errors.Is(err, errStateDiffsUnavailable) {
+		t.Fatalf("missing state diff error mismatch: have %v, want %v", err, errStateDiffsUnavailable)
+	}
+	// Items modified before the missing diff are resolved without reaching it
+	historical, _ = chain.HistoricalState(blocks[0].Header())
+	if have, want := historical.GetNonce(address), blocks[0].Transactions().Len(); have != uint64(want) {
+		t.Errorf("nonce mismatch: have %d, want %d", have, want)
+	}
+	if err := historical.Error(); err != nil {
+		t.Fatalf("historical state error: %v", err)
+	}
+	// States that can't be rewound to at all are refused upfront
+	if _, err := chain.HistoricalState(blocks[1].Header()); !errors.Is(err, errStateDiffsUnavailable) {
+		t.Fatalf("unavailable state error mismatch: have %v, want %v", err, errStateDiffsUnavailable)
+	}
+	// Blocks not on the canonical chain are refused
+	side := types.CopyHeader(blocks[3].Header())
+	side.Extra = []byte("side")
+	if _, err := chain.HistoricalState(side); err != errNonCanonicalState {
+		t.Fatalf("non-canonical state error mismatch: have %v, want %v", err, errNonCanonicalState)
+	}
+}
+
+// Tests that historical state is refused for blocks further behind the head than
+// the configured rewind limit.
+func TestHistoricalStateRewindLimit(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+
+		gspec   = &Genesis{Config: params.TestChainConfig, Alloc: GenesisAlloc{address: {Balance: big.NewInt(1000000000)}}}
+		genesis = gspec.MustCommit(db)
+	)
+	// Each block funds a fresh account
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, engine, db, 5, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{byte(i + 1)}, big.NewInt(1000), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+		b.AddTx(tx)
+	})
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	config := *defaultCacheConfig
+	config.StateDiffs = true
+	config.StateDiffRewind = 2
+
+	chain, err := NewBlockChain(diskdb, &config, params.TestChainConfig, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Blocks within the limit are reconstructed
+	for i, block := range blocks[2:] {
+		historical, err := chain.HistoricalState(block.Header())
+		if err != nil {
+			t.Fatalf("block %d: failed to create historical state: %v", block.NumberU64(), err)
+		}
+		funded := common.Address{byte(i + 3)}
+		if have := historical.GetBalance(funded); have.Uint64() != 1000 {
+			t.Errorf("block %d: funded balance mismatch: have %v, want 1000", block.NumberU64(), have)
+		}
+		if have := historical.GetBalance(common.Address{byte(i + 4)}); have.Sign() != 0 {
+			t.Errorf("block %d: future balance mismatch: have %v, want 0", block.NumberU64(), have)
+		}
+		if err := historical.Error(); err != nil {
+			t.Fatalf("block %d: historical state error: %v", block.NumberU64(), err)
+		}
+	}
+	// Older blocks are refused without walking the diffs
+	for _, block := range append([]*types.Block{genesis}, blocks[:2]...) {
+		if _, err := chain.HistoricalState(block.Header()); !errors.Is(err, errStateRewindTooDeep) {
+			t.Errorf("block %d: error mismatch: have %v, want %v", block.NumberU64(), err, errStateRewindTooDeep)
+		}
+	}
+}
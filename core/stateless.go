@@ -0,0 +1,203 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errWitnessParentMismatch is returned if a block is executed statelessly with a
+// witness assembled for a different parent block.
+var errWitnessParentMismatch = errors.New("witness parent mismatch")
+
+// witnessRecorder is a ProcessorChain recording everything needed to execute a
+// block statelessly: the ancestor headers retrieved for the BLOCKHASH opcode,
+// and the trie nodes and contract code resolved through its state database.
+type witnessRecorder struct {
+	ProcessorChain
+
+	state   *state.WitnessRecorder
+	headers map[common.Hash]*types.Header
+	lock    sync.Mutex
+}
+
+func newWitnessRecorder(chain ProcessorChain) *witnessRecorder {
+	return &witnessRecorder{
+		ProcessorChain: chain,
+		state:          state.NewWitnessRecorder(),
+		headers:        make(map[common.Hash]*types.Header),
+	}
+}
+
+// GetHeader retrieves a block header from the wrapped chain, recording it.
+func (r *witnessRecorder) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := r.ProcessorChain.GetHeader(hash, number)
+	if header != nil {
+		r.lock.Lock()
+		r.headers[hash] = header
+		r.lock.Unlock()
+	}
+	return header
+}
+
+// witness assembles the witness of a block executed into statedb on top of the
+// given parent, through the recording state database db.
+func (r *witnessRecorder) witness(db state.Database, parent *types.Header, statedb *state.StateDB) (*types.BlockWitness, error) {
+	// With snapshots, state reads don't touch the tries, so prove all the state
+	// accessed through the recording database. The proofs themselves are not
+	// needed, the resolved nodes get recorded either way.
+	if err := state.ProveAccesses(db, parent.Root, statedb.Accesses(), memorydb.New()); err != nil {
+		return nil, err
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	headers := []*types.Header{parent}
+	for {
+		header, ok := r.headers[headers[len(headers)-1].ParentHash]
+		if !ok {
+			break
+		}
+		headers = append(headers, header)
+	}
+	return &types.BlockWitness{
+		Headers: headers,
+		Nodes:   r.state.Nodes(),
+		Codes:   r.state.Codes(),
+	}, nil
+}
+
+// BlockWitness retrieves the execution witness of a block. If witnesses were not
+// generated when the block was imported, the block is re-executed on top of its
+// parent state to assemble it, which requires the parent state to be available.
+func (bc *BlockChain) BlockWitness(block *types.Block) (*types.BlockWitness, error) {
+	if witness := rawdb.ReadBlockWitness(bc.db, block.Hash(), block.NumberU64()); witness != nil {
+		return witness, nil
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis block has no witness")
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	var (
+		recorder = newWitnessRecorder(bc)
+		db       = state.NewRecordingDatabase(bc.stateCache, recorder.state)
+	)
+	statedb, err := state.New(parent.Root, db, bc.snaps)
+	if err != nil {
+		return nil, err
+	}
+	statedb.RecordAccesses()
+
+	receipts, _, usedGas, err := NewStateProcessor(bc.chainConfig, recorder, bc.engine).Process(block, statedb, vm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return nil, err
+	}
+	return recorder.witness(db, parent, statedb)
+}
+
+// witnessChain is a ProcessorChain serving the ancestor headers contained in a
+// block witness.
+type witnessChain struct {
+	config   *params.ChainConfig
+	engine   consensus.Engine
+	parent   *types.Header
+	headers  map[common.Hash]*types.Header
+	numbered map[uint64]*types.Header
+}
+
+func newWitnessChain(config *params.ChainConfig, engine consensus.Engine, headers []*types.Header) *witnessChain {
+	chain := &witnessChain{
+		config:   config,
+		engine:   engine,
+		parent:   headers[0],
+		headers:  make(map[common.Hash]*types.Header, len(headers)),
+		numbered: make(map[uint64]*types.Header, len(headers)),
+	}
+	for _, header := range headers {
+		chain.headers[header.Hash()] = header
+		chain.numbered[header.Number.Uint64()] = header
+	}
+	return chain
+}
+
+func (c *witnessChain) Config() *params.ChainConfig  { return c.config }
+func (c *witnessChain) Engine() consensus.Engine     { return c.engine }
+func (c *witnessChain) CurrentHeader() *types.Header { return c.parent }
+
+func (c *witnessChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.headers[hash]; header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func (c *witnessChain) GetHeaderByNumber(number uint64) *types.Header {
+	return c.numbered[number]
+}
+
+func (c *witnessChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	return c.headers[hash]
+}
+
+// ExecuteStateless executes a block using nothing but its witness, validates the
+// gas used, receipts and post-state root against the block header, and returns
+// the post-state root. An error is returned if the witness is not linked to the
+// block, or if it's missing any state needed for the execution.
+//
+// The block header itself is not verified; the caller is expected to do that if
+// the block comes from an untrusted source.
+func ExecuteStateless(config *params.ChainConfig, engine consensus.Engine, block *types.Block, witness *types.BlockWitness, cfg vm.Config) (common.Hash, error) {
+	// Make sure the witness headers form the chain of ancestors of the block
+	parent := witness.Parent()
+	if parent == nil || parent.Hash() != block.ParentHash() || parent.Number.Uint64()+1 != block.NumberU64() {
+		return common.Hash{}, errWitnessParentMismatch
+	}
+	for i := 1; i < len(witness.Headers); i++ {
+		if witness.Headers[i].Hash() != witness.Headers[i-1].ParentHash {
+			return common.Hash{}, fmt.Errorf("witness header %d is not an ancestor", i)
+		}
+	}
+	// Assemble the parent state from the witness nodes and code
+	db := rawdb.NewMemoryDatabase()
+	for _, blob := range witness.Nodes {
+		rawdb.WriteTrieNode(db, crypto.Keccak256Hash(blob), blob)
+	}
+	for _, code := range witness.Codes {
+		rawdb.WriteCode(db, crypto.Keccak256Hash(code), code)
+	}
+	statedb, err := state.New(parent.Root, newStateDatabase(db, nil, config), nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("incomplete witness: %v", err)
+	}
+	// Execute the block and validate the results, reporting missing state first
+	// as it would also cause a root mismatch
+	chain := newWitnessChain(config, engine, witness.Headers)
+	receipts, _, usedGas, err := NewStateProcessor(config, chain, engine).Process(block, statedb, cfg)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	root := statedb.IntermediateRoot(config.IsEIP158(block.Number()))
+	if err := statedb.Error(); err != nil {
+		return common.Hash{}, fmt.Errorf("incomplete witness: %v", err)
+	}
+	if err := NewBlockValidator(config, nil, engine).ValidateState(block, statedb, receipts, usedGas); err != nil {
+		return root, err
+	}
+	return root, nil
+}
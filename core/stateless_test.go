@@ -0,0 +1,158 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// makeWitnessTestChain generates a chain exercising the parts of the state a
+// block witness needs to cover: storage reads, writes and deletions, ancestor
+// block hashes, code size queries and account creations.
+func makeWitnessTestChain(config *params.ChainConfig, n int) (*Genesis, []*types.Block) {
+	var (
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+
+		aa = common.BytesToAddress([]byte{0xaa, 0xaa})
+		bb = common.BytesToAddress([]byte{0xbb, 0xbb})
+	)
+	// Contract aa stores BLOCKHASH(n-3) at slot n, clears slot n-2 and queries
+	// the code size of bb
+	code := []byte{
+		byte(vm.PUSH1), 0x03, byte(vm.NUMBER), byte(vm.SUB), byte(vm.BLOCKHASH), byte(vm.NUMBER), byte(vm.SSTORE),
+		byte(vm.PUSH1), 0x00, byte(vm.PUSH1), 0x02, byte(vm.NUMBER), byte(vm.SUB), byte(vm.SSTORE),
+		byte(vm.PUSH20),
+	}
+	code = append(code, bb.Bytes()...)
+	code = append(code, byte(vm.EXTCODESIZE), byte(vm.POP))
+
+	gspec := &Genesis{
+		Config: config,
+		Alloc: GenesisAlloc{
+			address: {Balance: big.NewInt(1000000000)},
+			aa:      {Code: code, Balance: common.Big0},
+			bb:      {Code: []byte{byte(vm.STOP), byte(vm.STOP)}, Balance: common.Big0},
+		},
+	}
+	genesis := gspec.MustCommit(db)
+
+	// Generate the blocks one by one on top of a live chain to serve BLOCKHASH
+	chain, _ := NewBlockChain(db, &CacheConfig{TrieDirtyDisabled: true}, config, engine, vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	blocks := []*types.Block{genesis}
+	for i := 0; i < n; i++ {
+		generated, _ := GenerateChain(config, blocks[i], engine, db, 1, func(_ int, b *BlockGen) {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), aa, common.Big0, 100000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+			b.AddTxWithChain(chain, tx)
+			tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(address), common.BytesToAddress([]byte{byte(i + 1)}), big.NewInt(1), 21000, big.NewInt(1), nil), types.HomesteadSigner{}, key)
+			b.AddTxWithChain(chain, tx)
+		})
+		if _, err := chain.InsertChain(generated); err != nil {
+			panic(err)
+		}
+		blocks = append(blocks, generated[0])
+	}
+	return gspec, blocks[1:]
+}
+
+// Tests that the witnesses generated during block import are sufficient to
+// execute the blocks statelessly, both with hexary and binary state tries.
+func TestStatelessExecution(t *testing.T) {
+	binary := *params.TestChainConfig
+	binary.BinaryTrie = true
+
+	for _, config := range []*params.ChainConfig{params.TestChainConfig, &binary} {
+		gspec, blocks := makeWitnessTestChain(config, 8)
+
+		diskdb := rawdb.NewMemoryDatabase()
+		gspec.MustCommit(diskdb)
+
+		cacheConfig := *defaultCacheConfig
+		cacheConfig.Witnesses = true
+
+		chain, err := NewBlockChain(diskdb, &cacheConfig, config, ethash.NewFaker(), vm.Config{}, nil, nil)
+		if err != nil {
+			t.Fatalf("failed to create tester chain: %v", err)
+		}
+		if n, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+		}
+		chain.Stop()
+
+		for _, block := range blocks {
+			witness := rawdb.ReadBlockWitness(diskdb, block.Hash(), block.NumberU64())
+			if witness == nil {
+				t.Fatalf("binary %v, block %d: witness missing", config.BinaryTrie, block.NumberU64())
+			}
+			if block.NumberU64() > 3 && len(witness.Headers) < 2 {
+				t.Errorf("binary %v, block %d: ancestor headers missing", config.BinaryTrie, block.NumberU64())
+			}
+			root, err := ExecuteStateless(config, ethash.NewFaker(), block, witness, vm.Config{})
+			if err != nil {
+				t.Fatalf("binary %v, block %d: stateless execution failed: %v", config.BinaryTrie, block.NumberU64(), err)
+			}
+			if root != block.Root() {
+				t.Fatalf("binary %v, block %d: root mismatch: have %x, want %x", config.BinaryTrie, block.NumberU64(), root, block.Root())
+			}
+		}
+	}
+}
+
+// Tests that incomplete or mismatching witnesses are rejected.
+func TestStatelessExecutionInvalidWitness(t *testing.T) {
+	gspec, blocks := makeWitnessTestChain(params.TestChainConfig, 5)
+
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+
+	chain, err := NewBlockChain(diskdb, &CacheConfig{TrieDirtyDisabled: true}, params.TestChainConfig, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	// Witnesses weren't stored, so they need to be generated on demand
+	block := blocks[4]
+	witness, err := chain.BlockWitness(block)
+	if err != nil {
+		t.Fatalf("failed to generate witness: %v", err)
+	}
+	if _, err := ExecuteStateless(params.TestChainConfig, ethash.NewFaker(), block, witness, vm.Config{}); err != nil {
+		t.Fatalf("stateless execution failed: %v", err)
+	}
+	if _, err := ExecuteStateless(params.TestChainConfig, ethash.NewFaker(), blocks[3], witness, vm.Config{}); err != errWitnessParentMismatch {
+		t.Errorf("mismatching witness error mismatch: have %v, want %v", err, errWitnessParentMismatch)
+	}
+	incomplete := *witness
+	incomplete.Codes = nil
+	if _, err := ExecuteStateless(params.TestChainConfig, ethash.NewFaker(), block, &incomplete, vm.Config{}); err == nil {
+		t.Errorf("witness without code accepted")
+	}
+	incomplete = *witness
+	incomplete.Headers = incomplete.Headers[:1]
+	if _, err := ExecuteStateless(params.TestChainConfig, ethash.NewFaker(), block, &incomplete, vm.Config{}); err == nil {
+		t.Errorf("witness without ancestor headers accepted")
+	}
+	for i := range witness.Nodes {
+		incomplete = *witness
+		incomplete.Nodes = append(append([][]byte{}, witness.Nodes[:i]...), witness.Nodes[i+1:]...)
+		if _, err := ExecuteStateless(params.TestChainConfig, ethash.NewFaker(), block, &incomplete, vm.Config{}); err == nil {
+			t.Errorf("witness without node %d accepted", i)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// dumpedTx is a single entry in a transaction pool dump, tagging a transaction
+// with the last activity time of its sender so stale entries can be skipped on
+// restore.
+type dumpedTx struct {
+	Time uint64             // Unix timestamp of the sender's last pool activity
+	Tx   *types.Transaction // Transaction to restore into the pool
+}
+
+// txDump is a periodically regenerated snapshot of the transaction pool contents
+// with the aim of allowing remote transactions to survive node restarts. Unlike
+// the local journal it is not appended to on every insertion, rather rewritten
+// as a whole on every rejournal cycle and on shutdown.
+type txDump struct {
+	path string // Filesystem path to store the transactions at
+}
+
+// newTxDump creates a new transaction pool dump backed by the given file.
+func newTxDump(path string) *txDump {
+	return &txDump{
+		path: path,
+	}
+}
+
+// load parses a transaction pool dump from disk, injecting all entries not older
+// than maxAge into the specified pool.
+func (dump *txDump) load(add func([]*types.Transaction) []error, maxAge time.Duration) error {
+	// Skip the parsing if the dump file doesn't exist at all
+	if _, err := os.Stat(dump.path); os.IsNotExist(err) {
+		return nil
+	}
+	input, err := os.Open(dump.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	total, stale, dropped, err := importTxDump(input, add, maxAge)
+	log.Info("Loaded transaction pool dump", "transactions", total, "stale", stale, "dropped", dropped)
+	return err
+}
+
+// save atomically replaces the transaction pool dump with the given entries.
+func (dump *txDump) save(txs []*dumpedTx) error {
+	replacement, err := os.OpenFile(dump.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if err := exportTxDump(replacement, txs); err != nil {
+		replacement.Close()
+		return err
+	}
+	replacement.Close()
+
+	if err := os.Rename(dump.path+".new", dump.path); err != nil {
+		return err
+	}
+	log.Info("Regenerated transaction pool dump", "transactions", len(txs))
+	return nil
+}
+
+// exportTxDump writes the given entries into an RLP stream.
+func exportTxDump(w io.Writer, txs []*dumpedTx) error {
+	for _, tx := range txs {
+		if err := rlp.Encode(w, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importTxDump parses an RLP stream of dumped transactions, feeding them in
+// batches into the provided add method. Entries older than maxAge are skipped,
+// unless maxAge is zero. The number of parsed, stale and rejected transactions
+// is returned.
+func importTxDump(r io.Reader, add func([]*types.Transaction) []error, maxAge time.Duration) (total, stale, dropped int, err error) {
+	stream := rlp.NewStream(r, 0)
+
+	// Create a method to load a limited batch of transactions and bump the
+	// appropriate progress counters.
+	loadBatch := func(txs types.Transactions) {
+		for _, err := range add(txs) {
+			if err != nil {
+				log.Debug("Failed to add dumped transaction", "err", err)
+				dropped++
+			}
+		}
+	}
+	var (
+		batch  types.Transactions
+		cutoff = time.Now().Add(-maxAge)
+	)
+	for {
+		// Parse the next transaction and terminate on error
+		entry := new(dumpedTx)
+		if err = stream.Decode(entry); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			if batch.Len() > 0 {
+				loadBatch(batch)
+			}
+			break
+		}
+		total++
+
+		if maxAge > 0 && time.Unix(int64(entry.Time), 0).Before(cutoff) {
+			stale++
+			continue
+		}
+		if batch = append(batch, entry.Tx); batch.Len() > 1024 {
+			loadBatch(batch)
+			batch = batch[:0]
+		}
+	}
+	return total, stale, dropped, err
+}
@@ -264,20 +264,11 @@ func (l *txList) Overlaps(tx *types.Transaction) bool {
 // thresholds are also potentially updated.
 func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Transaction) {
 	// If there's an older better transaction, abort
-	old := l.txs.Get(tx.Nonce())
-	if old != nil {
-		// threshold = oldGP * (100 + priceBump) / 100
-		a := big.NewInt(100 + int64(priceBump))
-		a = a.Mul(a, old.GasPrice())
-		b := big.NewInt(100)
-		threshold := a.Div(a, b)
-		// Have to ensure that the new gas price is higher than the old gas
-		// price as well as checking the percentage threshold to ensure that
-		// this is accurate for low (Wei-level) gas price replacements
-		if old.GasPriceCmp(tx) >= 0 || tx.GasPriceIntCmp(threshold) < 0 {
-			return false, nil
-		}
+	if !l.Accepts(tx, priceBump) {
+		return false, nil
 	}
+	old := l.txs.Get(tx.Nonce())
+
 	// Otherwise overwrite the old transaction with the current one
 	l.txs.Put(tx)
 	if cost := tx.Cost(); l.costcap.Cmp(cost) < 0 {
@@ -289,6 +280,25 @@ func (l *txList) Add(tx *types.Transaction, priceBump uint64) (bool, *types.Tran
 	return true, old
 }
 
+// Accepts checks whether a transaction would be inserted into the list by Add,
+// that is either its nonce is free, or it bumps the gas price of the transaction
+// with the same nonce by at least the required percentage.
+func (l *txList) Accepts(tx *types.Transaction, priceBump uint64) bool {
+	old := l.txs.Get(tx.Nonce())
+	if old == nil {
+		return true
+	}
+	// threshold = oldGP * (100 + priceBump) / 100
+	a := big.NewInt(100 + int64(priceBump))
+	a = a.Mul(a, old.GasPrice())
+	b := big.NewInt(100)
+	threshold := a.Div(a, b)
+	// Have to ensure that the new gas price is higher than the old gas
+	// price as well as checking the percentage threshold to ensure that
+	// this is accurate for low (Wei-level) gas price replacements
+	return old.GasPriceCmp(tx) < 0 && tx.GasPriceIntCmp(threshold) >= 0
+}
+
 // Forward removes all transactions from the list with a nonce lower than the
 // provided threshold. Every removed transaction is returned for any post-removal
 // maintenance.
@@ -0,0 +1,207 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// ErrSenderNotAllowed is returned if the sender of a transaction is not on
+	// the configured sender allowlist of the pool.
+	ErrSenderNotAllowed = errors.New("sender not allowed")
+
+	// ErrRecipientNotAllowed is returned if the recipient of a transaction is not
+	// on the configured recipient allowlist of the pool.
+	ErrRecipientNotAllowed = errors.New("recipient not allowed")
+
+	// ErrDeployNotAllowed is returned if a contract creation is attempted by an
+	// account not permitted to deploy contracts.
+	ErrDeployNotAllowed = errors.New("contract deployment not allowed")
+
+	// ErrAccountRateLimited is returned if an account submitted more transactions
+	// than permitted within the configured rate limiting window.
+	ErrAccountRateLimited = errors.New("account rate limited")
+)
+
+// rejectedTxMeter counts the transactions refused by the admission policies.
+var rejectedTxMeter = metrics.NewRegisteredMeter("txpool/rejected", nil)
+
+// TxAdmissionPolicy is a pluggable rule deciding whether a transaction that
+// already passed the consensus and DoS validation may enter the pool. It is
+// applied to new local, remote and light client relayed transactions alike, but
+// not to the ones reinjected after a reorg or reloaded from the journal.
+//
+// Policies are invoked with the pool lock held, so they must not call back into
+// the transaction pool.
+type TxAdmissionPolicy interface {
+	// Admit returns nil if the transaction sent by from is accepted, or the
+	// reason of the rejection otherwise.
+	Admit(tx *types.Transaction, from common.Address, local bool) error
+}
+
+// TxAdmissionConfig are the configuration parameters of the built-in admission
+// policies of the transaction pool. The zero value admits every transaction.
+type TxAdmissionConfig struct {
+	Senders    []common.Address // Accounts allowed to send transactions (empty = all)
+	Recipients []common.Address // Accounts allowed to receive transactions (empty = all)
+	Deployers  []common.Address // Accounts allowed to create contracts (empty = all)
+	NoDeploy   bool             // Whether contract creation is disabled altogether
+
+	RateLimit  uint64        // Maximum number of transactions admitted per account within a window (0 = unlimited)
+	RateWindow time.Duration // Time window over which the per-account rate limit is enforced (0 = default)
+}
+
+// policies creates the admission policies enabled by the configuration.
+func (config *TxAdmissionConfig) policies() []TxAdmissionPolicy {
+	var policies []TxAdmissionPolicy
+	if len(config.Senders) > 0 {
+		policies = append(policies, &senderPolicy{allowed: newAddressSet(config.Senders)})
+	}
+	if len(config.Recipients) > 0 {
+		policies = append(policies, &recipientPolicy{allowed: newAddressSet(config.Recipients)})
+	}
+	if config.NoDeploy || len(config.Deployers) > 0 {
+		policies = append(policies, &deployPolicy{disabled: config.NoDeploy, allowed: newAddressSet(config.Deployers)})
+	}
+	if config.RateLimit > 0 && config.RateWindow > 0 {
+		policies = append(policies, newRatePolicy(config.RateLimit, config.RateWindow))
+	}
+	return policies
+}
+
+// newAddressSet converts a list of addresses into a set for quick lookups.
+func newAddressSet(addrs []common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+// senderPolicy only admits transactions sent by allowlisted accounts.
+type senderPolicy struct {
+	allowed map[common.Address]struct{}
+}
+
+// Admit implements TxAdmissionPolicy.
+func (p *senderPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	if _, ok := p.allowed[from]; !ok {
+		return ErrSenderNotAllowed
+	}
+	return nil
+}
+
+// recipientPolicy only admits transactions sent to allowlisted accounts. Contract
+// creations are left to the deployment policy.
+type recipientPolicy struct {
+	allowed map[common.Address]struct{}
+}
+
+// Admit implements TxAdmissionPolicy.
+func (p *recipientPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	if to := tx.To(); to != nil {
+		if _, ok := p.allowed[*to]; !ok {
+			return ErrRecipientNotAllowed
+		}
+	}
+	return nil
+}
+
+// deployPolicy restricts contract creations to allowlisted accounts, or disables
+// them altogether.
+type deployPolicy struct {
+	disabled bool
+	allowed  map[common.Address]struct{}
+}
+
+// Admit implements TxAdmissionPolicy.
+func (p *deployPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	if tx.To() != nil {
+		return nil
+	}
+	if p.disabled {
+		return ErrDeployNotAllowed
+	}
+	if _, ok := p.allowed[from]; !ok {
+		return ErrDeployNotAllowed
+	}
+	return nil
+}
+
+// rateWindow tracks the number of transactions admitted for a single account
+// within the current window.
+type rateWindow struct {
+	start time.Time
+	count uint64
+}
+
+// ratePolicy limits the number of transactions admitted per account within a
+// fixed time window.
+type ratePolicy struct {
+	limit   uint64
+	window  time.Duration
+	windows map[common.Address]*rateWindow
+	cleaned time.Time // Last time expired windows were purged
+
+	now  func() time.Time // Clock source, overridable in tests
+	lock sync.Mutex
+}
+
+// newRatePolicy creates a per-account rate limiting policy.
+func newRatePolicy(limit uint64, window time.Duration) *ratePolicy {
+	return &ratePolicy{
+		limit:   limit,
+		window:  window,
+		windows: make(map[common.Address]*rateWindow),
+		now:     time.Now,
+	}
+}
+
+// Admit implements TxAdmissionPolicy.
+func (p *ratePolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := p.now()
+
+	// Purge all expired windows every now and again to avoid leaking memory
+	if now.Sub(p.cleaned) > p.window {
+		for addr, w := range p.windows {
+			if now.Sub(w.start) > p.window {
+				delete(p.windows, addr)
+			}
+		}
+		p.cleaned = now
+	}
+	w := p.windows[from]
+	if w == nil || now.Sub(w.start) > p.window {
+		w = &rateWindow{start: now}
+		p.windows[from] = w
+	}
+	if w.count >= p.limit {
+		return ErrAccountRateLimited
+	}
+	w.count++
+	return nil
+}
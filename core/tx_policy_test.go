@@ -0,0 +1,238 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that the built-in admission policies reject transactions from unlisted
+// senders, to unlisted recipients and unauthorized contract deployments, for
+// local and remote transactions alike.
+func TestTransactionAdmissionAllowlists(t *testing.T) {
+	t.Parallel()
+
+	allowed, _ := crypto.GenerateKey()
+	denied, _ := crypto.GenerateKey()
+
+	recipient := common.BytesToAddress([]byte{1})
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.Admission = TxAdmissionConfig{
+		Senders:    []common.Address{crypto.PubkeyToAddress(allowed.PublicKey)},
+		Recipients: []common.Address{recipient},
+		NoDeploy:   true,
+	}
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(allowed.PublicKey), big.NewInt(1000000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(denied.PublicKey), big.NewInt(1000000000))
+
+	sign := func(nonce uint64, to *common.Address, key *ecdsa.PrivateKey) *types.Transaction {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(nonce, big.NewInt(0), 100000, big.NewInt(1), nil)
+		} else {
+			tx = types.NewTransaction(nonce, *to, big.NewInt(0), 100000, big.NewInt(1), nil)
+		}
+		signed, _ := types.SignTx(tx, types.HomesteadSigner{}, key)
+		return signed
+	}
+	if err := pool.AddLocal(sign(0, &recipient, allowed)); err != nil {
+		t.Fatalf("allowed transaction rejected: %v", err)
+	}
+	if err := pool.AddLocal(sign(0, &recipient, denied)); err != ErrSenderNotAllowed {
+		t.Fatalf("unlisted sender error mismatch: have %v, want %v", err, ErrSenderNotAllowed)
+	}
+	other := common.BytesToAddress([]byte{2})
+	if err := pool.addRemoteSync(sign(1, &other, allowed)); err != ErrRecipientNotAllowed {
+		t.Fatalf("unlisted recipient error mismatch: have %v, want %v", err, ErrRecipientNotAllowed)
+	}
+	if err := pool.addRemoteSync(sign(1, nil, allowed)); err != ErrDeployNotAllowed {
+		t.Fatalf("deployment error mismatch: have %v, want %v", err, ErrDeployNotAllowed)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the per-account rate limiter admits at most the configured number
+// of transactions within a window and resets once the window passes.
+func TestTransactionAdmissionRateLimit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Unix(1000, 0)
+		policy = newRatePolicy(2, time.Minute)
+		from   = common.BytesToAddress([]byte{1})
+		tx     = types.NewTransaction(0, from, big.NewInt(0), 21000, big.NewInt(1), nil)
+	)
+	policy.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if err := policy.Admit(tx, from, false); err != nil {
+			t.Fatalf("transaction %d: rejected within limit: %v", i, err)
+		}
+	}
+	if err := policy.Admit(tx, from, false); err != ErrAccountRateLimited {
+		t.Fatalf("over limit error mismatch: have %v, want %v", err, ErrAccountRateLimited)
+	}
+	if err := policy.Admit(tx, common.BytesToAddress([]byte{2}), false); err != nil {
+		t.Fatalf("unrelated account rejected: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if err := policy.Admit(tx, from, false); err != nil {
+		t.Fatalf("transaction rejected after window reset: %v", err)
+	}
+}
+
+// Tests that a rate limit configured without a window is enforced over the
+// default window instead of being silently dropped.
+func TestTransactionAdmissionRateLimitDefaultWindow(t *testing.T) {
+	t.Parallel()
+
+	config := testTxPoolConfig
+	config.Admission = TxAdmissionConfig{RateLimit: 2}
+	config = config.sanitize()
+
+	if config.Admission.RateWindow != DefaultTxPoolConfig.Admission.RateWindow {
+		t.Fatalf("rate window mismatch: have %v, want %v", config.Admission.RateWindow, DefaultTxPoolConfig.Admission.RateWindow)
+	}
+	policies := config.Admission.policies()
+	if len(policies) != 1 {
+		t.Fatalf("policy count mismatch: have %d, want 1", len(policies))
+	}
+	policy, ok := policies[0].(*ratePolicy)
+	if !ok {
+		t.Fatalf("policy type mismatch: have %T, want *ratePolicy", policies[0])
+	}
+	if policy.limit != 2 || policy.window != DefaultTxPoolConfig.Admission.RateWindow {
+		t.Errorf("rate policy mismatch: have %d per %v, want 2 per %v", policy.limit, policy.window, DefaultTxPoolConfig.Admission.RateWindow)
+	}
+}
+
+// reorgTestChain is a test blockchain serving a fixed set of blocks, to allow
+// simulating chain reorganisations.
+type reorgTestChain struct {
+	*testBlockChain
+	blocks map[common.Hash]*types.Block
+}
+
+func (bc *reorgTestChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	return bc.blocks[hash]
+}
+
+// errTestPolicyRejected is returned by the counting policy if armed to reject.
+var errTestPolicyRejected = errors.New("rejected by test policy")
+
+// countingPolicy is an admission policy counting the transactions it admitted,
+// or rejecting all of them if armed.
+type countingPolicy struct {
+	admitted int
+	reject   bool
+}
+
+// Admit implements TxAdmissionPolicy.
+func (p *countingPolicy) Admit(tx *types.Transaction, from common.Address, local bool) error {
+	if p.reject {
+		return errTestPolicyRejected
+	}
+	p.admitted++
+	return nil
+}
+
+// Tests that admission policies are only consulted for new inbound transactions
+// that would actually be pooled, and not for transactions discarded by the pool
+// itself or reinjected after a reorg.
+func TestTransactionAdmissionReorg(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &reorgTestChain{
+		testBlockChain: &testBlockChain{statedb, 1000000, new(event.Feed)},
+		blocks:         make(map[common.Hash]*types.Block),
+	}
+	pool := NewTxPool(testTxPoolConfig, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	policy := new(countingPolicy)
+	pool.AddPolicy(policy)
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	tx := transaction(0, 100000, key)
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	// Underpriced replacements must be discarded without reaching the policies
+	if err := pool.addRemoteSync(pricedTransaction(0, 100001, big.NewInt(1), key)); err != ErrReplaceUnderpriced {
+		t.Fatalf("replacement error mismatch: have %v, want %v", err, ErrReplaceUnderpriced)
+	}
+	if policy.admitted != 1 {
+		t.Fatalf("admitted transaction count mismatch: have %d, want %d", policy.admitted, 1)
+	}
+	// Include the transaction in a block, then reorg it out onto a longer chain
+	var (
+		genesis = types.NewBlock(&types.Header{GasLimit: 1000000}, nil, nil, nil, new(trie.Trie))
+		mined   = types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: genesis.Hash(), GasLimit: 1000000}, []*types.Transaction{tx}, nil, nil, new(trie.Trie))
+		side1   = types.NewBlock(&types.Header{Number: big.NewInt(1), ParentHash: genesis.Hash(), GasLimit: 1000000, Extra: []byte("side")}, nil, nil, nil, new(trie.Trie))
+		side2   = types.NewBlock(&types.Header{Number: big.NewInt(2), ParentHash: side1.Hash(), GasLimit: 1000000}, nil, nil, nil, new(trie.Trie))
+	)
+	for _, block := range []*types.Block{genesis, mined, side1, side2} {
+		blockchain.blocks[block.Hash()] = block
+	}
+	pool.mu.Lock()
+	pool.removeTx(tx.Hash(), true)
+	pool.mu.Unlock()
+
+	// Arm the policy to reject everything and ensure the reorged out transaction is
+	// still reinjected without being counted again
+	policy.reject = true
+
+	pool.mu.Lock()
+	pool.reset(mined.Header(), side2.Header())
+	pool.mu.Unlock()
+
+	if pool.Get(tx.Hash()) == nil {
+		t.Fatalf("reorged out transaction not reinjected")
+	}
+	if policy.admitted != 1 {
+		t.Fatalf("admitted transaction count mismatch: have %d, want %d", policy.admitted, 1)
+	}
+	// New inbound transactions are still subject to the policies
+	if err := pool.addRemoteSync(transaction(1, 100000, key)); err != errTestPolicyRejected {
+		t.Fatalf("new transaction error mismatch: have %v, want %v", err, errTestPolicyRejected)
+	}
+}
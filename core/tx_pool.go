@@ -3,7 +3,9 @@
 package core
 
 import (
+	"bytes"
 	"errors"
+	"io"
 	"math"
 	"math/big"
 	"sort"
@@ -108,6 +110,39 @@ const (
 	TxStatusIncluded
 )
 
+// TxDropReason is the cause of a transaction leaving the pool without being
+// included in the chain.
+type TxDropReason uint
+
+const (
+	TxDropReplaced    TxDropReason = iota // Superseded by a transaction with the same nonce
+	TxDropUnderpriced                     // Priced out of a full pool or below the minimum gas price
+	TxDropStale                           // Nonce already used by the current chain state
+	TxDropUnpayable                       // Insufficient balance or gas allowance
+	TxDropRateLimit                       // Exceeded the per-account or global pool limits
+	TxDropEvicted                         // Queued for longer than the allowed lifetime
+)
+
+// String implements fmt.Stringer, returning the reason code used by the APIs.
+func (r TxDropReason) String() string {
+	switch r {
+	case TxDropReplaced:
+		return "replaced"
+	case TxDropUnderpriced:
+		return "underpriced"
+	case TxDropStale:
+		return "stale"
+	case TxDropUnpayable:
+		return "unpayable"
+	case TxDropRateLimit:
+		return "ratelimit"
+	case TxDropEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
 // blockChain provides the state of blockchain and current gas limit to do
 // some pre checks in tx pool and event subscribers.
 type blockChain interface {
@@ -123,7 +158,11 @@ type TxPoolConfig struct {
 	Locals    []common.Address // Addresses that should be treated by default as local
 	NoLocals  bool             // Whether local transaction handling should be disabled
 	Journal   string           // Journal of local transactions to survive node restarts
-	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
+	Rejournal time.Duration    // Time interval to regenerate the local transaction journal and pool dump
+
+	Dump      string        // Disk dump of remote transactions to survive node restarts (empty = disabled)
+	DumpLimit uint64        // Maximum number of remote transactions to persist into the dump
+	DumpAge   time.Duration // Maximum age of dumped transactions to restore on startup
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -134,6 +173,11 @@ type TxPoolConfig struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	PrivateLifetime time.Duration // Maximum amount of time private transactions are kept unannounced
+	PrivateRelease  bool          // Whether expired private transactions are announced instead of dropped
+
+	Admission TxAdmissionConfig // Built-in admission policies applied to every inbound transaction
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -142,6 +186,9 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	DumpLimit: 4096,
+	DumpAge:   time.Hour,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -151,6 +198,12 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	GlobalQueue:  1024,
 
 	Lifetime: 3 * time.Hour,
+
+	PrivateLifetime: 10 * time.Minute,
+
+	Admission: TxAdmissionConfig{
+		RateWindow: time.Minute,
+	},
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -161,6 +214,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.DumpLimit < 1 {
+		log.Warn("Sanitizing invalid txpool dump limit", "provided", conf.DumpLimit, "updated", DefaultTxPoolConfig.DumpLimit)
+		conf.DumpLimit = DefaultTxPoolConfig.DumpLimit
+	}
+	if conf.DumpAge < 1 {
+		log.Warn("Sanitizing invalid txpool dump age", "provided", conf.DumpAge, "updated", DefaultTxPoolConfig.DumpAge)
+		conf.DumpAge = DefaultTxPoolConfig.DumpAge
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
 		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
@@ -189,6 +250,14 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
 		conf.Lifetime = DefaultTxPoolConfig.Lifetime
 	}
+	if conf.PrivateLifetime < 1 {
+		log.Warn("Sanitizing invalid txpool private lifetime", "provided", conf.PrivateLifetime, "updated", DefaultTxPoolConfig.PrivateLifetime)
+		conf.PrivateLifetime = DefaultTxPoolConfig.PrivateLifetime
+	}
+	if conf.Admission.RateLimit > 0 && conf.Admission.RateWindow < 1 {
+		log.Warn("Sanitizing invalid txpool rate limit window", "provided", conf.Admission.RateWindow, "updated", DefaultTxPoolConfig.Admission.RateWindow)
+		conf.Admission.RateWindow = DefaultTxPoolConfig.Admission.RateWindow
+	}
 	return conf
 }
 
@@ -205,9 +274,12 @@ type TxPool struct {
 	chain       blockChain
 	gasPrice    *big.Int
 	txFeed      event.Feed
+	privFeed    event.Feed
+	dropFeed    event.Feed
 	scope       event.SubscriptionScope
 	signer      types.Signer
 	mu          sync.RWMutex
+	policies    []TxAdmissionPolicy // Admission policies applied after the basic validation
 
 	istanbul bool // Fork indicator whether we are in the istanbul stage.
 
@@ -215,14 +287,18 @@ type TxPool struct {
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
 	currentMaxGas uint64         // Current gas limit for transaction caps
 
-	locals  *accountSet // Set of local transaction to exempt from eviction rules
-	journal *txJournal  // Journal of local transaction to back up to disk
+	locals   *accountSet // Set of local transaction to exempt from eviction rules
+	privates *txPrivates // Set of private transactions to exempt from network propagation
+	journal  *txJournal  // Journal of local transaction to back up to disk
+	dump     *txDump     // Dump of remote transactions to back up to disk
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
 	beats   map[common.Address]time.Time // Last heartbeat from each known account
+	senders []common.Address             // Accounts with pending or queued transactions, sorted
 	all     *txLookup                    // All transactions to allow lookups
 	priced  *txPricedList                // All transactions sorted by price
+	drops   []DropTxsEvent               // Drop events accumulated under the pool lock
 
 	chainHeadCh     chan ChainHeadEvent
 	chainHeadSub    event.Subscription
@@ -254,6 +330,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		queue:           make(map[common.Address]*txList),
 		beats:           make(map[common.Address]time.Time),
 		all:             newTxLookup(),
+		privates:        newTxPrivates(),
 		chainHeadCh:     make(chan ChainHeadEvent, chainHeadChanSize),
 		reqResetCh:      make(chan *txpoolResetRequest),
 		reqPromoteCh:    make(chan *accountSet),
@@ -267,6 +344,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
+	pool.policies = config.Admission.policies()
 	pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, chain.CurrentBlock().Header())
 
@@ -278,13 +356,25 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain block
 	if !config.NoLocals && config.Journal != "" {
 		pool.journal = newTxJournal(config.Journal)
 
-		if err := pool.journal.load(pool.AddLocals); err != nil {
+		// Journaled transactions were admitted before, don't apply the policies again
+		load := func(txs []*types.Transaction) []error {
+			return pool.addTxs(txs, true, true, false)
+		}
+		if err := pool.journal.load(load); err != nil {
 			log.Warn("Failed to load transaction journal", "err", err)
 		}
 		if err := pool.journal.rotate(pool.local()); err != nil {
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If remote transaction persistence is enabled, restore the previous dump
+	if config.Dump != "" {
+		pool.dump = newTxDump(config.Dump)
+
+		if err := pool.dump.load(pool.AddRemotes, config.DumpAge); err != nil {
+			log.Warn("Failed to load transaction pool dump", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeChainHeadEvent(pool.chainHeadCh)
@@ -354,9 +444,14 @@ func (pool *TxPool) loop() {
 						pool.removeTx(tx.Hash(), true)
 					}
 					queuedEvictionMeter.Mark(int64(len(list)))
+					pool.queueDropEvent(TxDropEvicted, nil, list...)
 				}
 			}
 			pool.mu.Unlock()
+			pool.sendDropEvents()
+
+			// Drop or release any expired private transactions
+			pool.expirePrivates()
 
 		// Handle local transaction journal rotation
 		case <-journal.C:
@@ -367,6 +462,15 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+			if pool.dump != nil {
+				pool.mu.RLock()
+				remotes := pool.remote(int(pool.config.DumpLimit))
+				pool.mu.RUnlock()
+
+				if err := pool.dump.save(remotes); err != nil {
+					log.Warn("Failed to regenerate tx pool dump", "err", err)
+				}
+			}
 		}
 	}
 }
@@ -383,15 +487,127 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.dump != nil {
+		pool.mu.RLock()
+		remotes := pool.remote(int(pool.config.DumpLimit))
+		pool.mu.RUnlock()
+
+		if err := pool.dump.save(remotes); err != nil {
+			log.Warn("Failed to save tx pool dump", "err", err)
+		}
+	}
 	log.Info("Transaction pool stopped")
 }
 
 // SubscribeNewTxsEvent registers a subscription of NewTxsEvent and
-// starts sending event to the given channel.
+// starts sending event to the given channel. Private transactions are
+// never delivered through this subscription.
 func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscription {
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribePrivateTxsEvent registers a subscription of NewTxsEvent carrying
+// only the private transactions, meant for local block production.
+func (pool *TxPool) SubscribePrivateTxsEvent(ch chan<- NewTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.privFeed.Subscribe(ch))
+}
+
+// sendTxsEvent announces a batch of newly executable transactions, delivering
+// the private ones only to the private feed.
+func (pool *TxPool) sendTxsEvent(txs types.Transactions) {
+	var public, private types.Transactions
+	for _, tx := range txs {
+		if pool.privates.contains(tx.Hash()) {
+			private = append(private, tx)
+		} else {
+			public = append(public, tx)
+		}
+	}
+	if len(public) > 0 {
+		pool.txFeed.Send(NewTxsEvent{public})
+	}
+	if len(private) > 0 {
+		pool.privFeed.Send(NewTxsEvent{private})
+	}
+}
+
+// expirePrivates clears the private marker of all transactions kept unannounced
+// longer than the configured lifetime, either dropping them from the pool or
+// releasing them to the network.
+func (pool *TxPool) expirePrivates() {
+	expired := pool.privates.expire(time.Now())
+	if len(expired) == 0 {
+		return
+	}
+	var released []*types.Transaction
+
+	pool.mu.Lock()
+	for _, hash := range expired {
+		tx := pool.all.Get(hash)
+		if tx == nil {
+			continue // Already included or dropped
+		}
+		if !pool.config.PrivateRelease {
+			pool.removeTx(hash, true)
+			pool.queueDropEvent(TxDropEvicted, nil, tx)
+			continue
+		}
+		// Executable transactions need explicit announcement, queued ones will
+		// be announced naturally on promotion
+		from, _ := types.Sender(pool.signer, tx) // already validated
+		if list := pool.pending[from]; list != nil && list.txs.Get(tx.Nonce()) == tx {
+			released = append(released, tx)
+		}
+		pool.journalTx(from, tx)
+	}
+	pool.mu.Unlock()
+	pool.sendDropEvents()
+
+	log.Debug("Expired private transactions", "count", len(expired), "released", len(released))
+	if len(released) > 0 {
+		pool.sendTxsEvent(released)
+	}
+}
+
+// AddPolicy registers an additional admission policy, applied to all new inbound
+// transactions afterwards. Transactions already pooled are not affected.
+func (pool *TxPool) AddPolicy(policy TxAdmissionPolicy) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.policies = append(pool.policies, policy)
+}
+
+// SubscribeDropTxsEvent registers a subscription of DropTxsEvent and
+// starts sending event to the given channel.
+func (pool *TxPool) SubscribeDropTxsEvent(ch chan<- DropTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.dropFeed.Subscribe(ch))
+}
+
+// queueDropEvent records a batch of transactions leaving the pool, to be sent to
+// the subscribers once the pool lock is released.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) queueDropEvent(reason TxDropReason, replacement *types.Transaction, txs ...*types.Transaction) {
+	if len(txs) == 0 {
+		return
+	}
+	pool.drops = append(pool.drops, DropTxsEvent{Txs: txs, Reason: reason, Replacement: replacement})
+}
+
+// sendDropEvents notifies the subscribers of all the drop events accumulated
+// since the last call. It must be called without holding the pool lock.
+func (pool *TxPool) sendDropEvents() {
+	pool.mu.Lock()
+	drops := pool.drops
+	pool.drops = nil
+	pool.mu.Unlock()
+
+	for _, ev := range drops {
+		pool.dropFeed.Send(ev)
+	}
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -403,13 +619,17 @@ func (pool *TxPool) GasPrice() *big.Int {
 // SetGasPrice updates the minimum price required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
+	defer pool.sendDropEvents()
+
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
 	pool.gasPrice = price
-	for _, tx := range pool.priced.Cap(price, pool.locals) {
+	drops := pool.priced.Cap(price, pool.locals)
+	for _, tx := range drops {
 		pool.removeTx(tx.Hash(), false)
 	}
+	pool.queueDropEvent(TxDropUnderpriced, nil, drops...)
 	log.Info("Transaction pool price threshold updated", "price", price)
 }
 
@@ -462,6 +682,86 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool, returning the
+// pending as well as queued transactions of this address, sorted by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var pending types.Transactions
+	if list, ok := pool.pending[addr]; ok {
+		pending = list.Flatten()
+	}
+	var queued types.Transactions
+	if list, ok := pool.queue[addr]; ok {
+		queued = list.Flatten()
+	}
+	return pending, queued
+}
+
+// ContentPage retrieves the data content of the transaction pool for at most
+// limit accounts, ordered by address and starting right after the cursor, which
+// starts from the first account if nil. The cursor of the following page is also
+// returned, nil if there's none.
+func (pool *TxPool) ContentPage(cursor *common.Address, limit int) (map[common.Address]types.Transactions, map[common.Address]types.Transactions, *common.Address) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(pool.senders), func(i int) bool {
+			return bytes.Compare(pool.senders[i][:], cursor[:]) > 0
+		})
+	}
+	pending := make(map[common.Address]types.Transactions)
+	queued := make(map[common.Address]types.Transactions)
+	for i := start; i < len(pool.senders); i++ {
+		if i-start == limit {
+			next := pool.senders[i-1]
+			return pending, queued, &next
+		}
+		addr := pool.senders[i]
+		if list := pool.pending[addr]; list != nil && !list.Empty() {
+			pending[addr] = list.Flatten()
+		}
+		if list := pool.queue[addr]; list != nil && !list.Empty() {
+			queued[addr] = list.Flatten()
+		}
+	}
+	return pending, queued, nil
+}
+
+// indexSender adds an account to the sorted index of senders, if not yet present.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) indexSender(addr common.Address) {
+	i := sort.Search(len(pool.senders), func(i int) bool {
+		return bytes.Compare(pool.senders[i][:], addr[:]) >= 0
+	})
+	if i < len(pool.senders) && pool.senders[i] == addr {
+		return
+	}
+	pool.senders = append(pool.senders, common.Address{})
+	copy(pool.senders[i+1:], pool.senders[i:])
+	pool.senders[i] = addr
+}
+
+// unindexSender removes an account from the sorted index of senders, unless it
+// still has pending or queued transactions.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) unindexSender(addr common.Address) {
+	if pool.pending[addr] != nil || pool.queue[addr] != nil {
+		return
+	}
+	i := sort.Search(len(pool.senders), func(i int) bool {
+		return bytes.Compare(pool.senders[i][:], addr[:]) >= 0
+	})
+	if i < len(pool.senders) && pool.senders[i] == addr {
+		pool.senders = append(pool.senders[:i], pool.senders[i+1:]...)
+	}
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
@@ -491,15 +791,81 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr := range pool.locals.accounts {
 		if pending := pool.pending[addr]; pending != nil {
-			txs[addr] = append(txs[addr], pending.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(pending.Flatten())...)
 		}
 		if queued := pool.queue[addr]; queued != nil {
-			txs[addr] = append(txs[addr], queued.Flatten()...)
+			txs[addr] = append(txs[addr], pool.public(queued.Flatten())...)
 		}
 	}
 	return txs
 }
 
+// public filters out all the private transactions from the given list, which
+// must not leave the node in any form, including disk backups.
+func (pool *TxPool) public(txs types.Transactions) types.Transactions {
+	filtered := txs[:0]
+	for _, tx := range txs {
+		if !pool.privates.contains(tx.Hash()) {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
+// remote retrieves the currently known non-local transactions, tagged with the
+// last activity time of their senders and capped at limit entries (zero means
+// no cap). Executable transactions are preferred over queued ones, and recently
+// active accounts over stale ones. Nonce ordering within an account is always
+// preserved, so any truncated account only loses its highest nonces.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxPool) remote(limit int) []*dumpedTx {
+	var dumped []*dumpedTx
+
+	for _, lists := range []map[common.Address]*txList{pool.pending, pool.queue} {
+		// Order the accounts by their last activity to retain the most relevant ones
+		addresses := make(addressesByHeartbeat, 0, len(lists))
+		for addr := range lists {
+			if !pool.locals.contains(addr) {
+				addresses = append(addresses, addressByHeartbeat{addr, pool.beats[addr]})
+			}
+		}
+		sort.Sort(sort.Reverse(addresses))
+
+		for _, addr := range addresses {
+			seen := uint64(addr.heartbeat.Unix())
+			if addr.heartbeat.IsZero() {
+				seen = uint64(time.Now().Unix())
+			}
+			for _, tx := range pool.public(lists[addr.address].Flatten()) {
+				if limit > 0 && len(dumped) >= limit {
+					return dumped
+				}
+				dumped = append(dumped, &dumpedTx{Time: seen, Tx: tx})
+			}
+		}
+	}
+	return dumped
+}
+
+// Export writes all non-local transactions currently in the pool into the given
+// writer, in the same format used by the on-disk pool dump.
+func (pool *TxPool) Export(w io.Writer) (int, error) {
+	pool.mu.RLock()
+	remotes := pool.remote(0)
+	pool.mu.RUnlock()
+
+	return len(remotes), exportTxDump(w, remotes)
+}
+
+// Import reads a previously exported set of transactions from the given reader
+// and injects them into the pool as remote transactions, subject to the usual
+// validation rules. The number of parsed and rejected transactions is returned.
+func (pool *TxPool) Import(r io.Reader) (int, int, error) {
+	total, _, dropped, err := importTxDump(r, pool.AddRemotesSync, 0)
+	return total, dropped, err
+}
+
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
@@ -553,7 +919,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 // If a newly added transaction is marked as local, its sending account will be
 // whitelisted, preventing any associated transaction from being dropped out of the pool
 // due to pricing constraints.
-func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err error) {
+func (pool *TxPool) add(tx *types.Transaction, local, admit bool) (replaced bool, err error) {
 	// If the transaction is already known, discard it
 	hash := tx.Hash()
 	if pool.all.Get(hash) != nil {
@@ -567,24 +933,51 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
-	// If the transaction pool is full, discard underpriced transactions
-	if uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue {
-		// If the new transaction is underpriced, don't accept it
-		if !local && pool.priced.Underpriced(tx, pool.locals) {
-			log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
-			underpricedTxMeter.Mark(1)
-			return false, ErrUnderpriced
+	// If the transaction pool is full and the new transaction is underpriced, don't accept it
+	full := uint64(pool.all.Count()) >= pool.config.GlobalSlots+pool.config.GlobalQueue
+	if full && !local && pool.priced.Underpriced(tx, pool.locals) {
+		log.Trace("Discarding underpriced transaction", "hash", hash, "price", tx.GasPrice())
+		underpricedTxMeter.Mark(1)
+		return false, ErrUnderpriced
+	}
+	// If the transaction would replace a better one, discard it
+	from, _ := types.Sender(pool.signer, tx) // already validated
+	list := pool.pending[from]
+	if list == nil || !list.Overlaps(tx) {
+		list = pool.queue[from]
+	}
+	if list != nil && !list.Accepts(tx, pool.config.PriceBump) {
+		log.Trace("Discarding underpriced replacement", "hash", hash, "price", tx.GasPrice())
+		if list == pool.pending[from] {
+			pendingDiscardMeter.Mark(1)
+		} else {
+			queuedDiscardMeter.Mark(1)
 		}
-		// New transaction is better than our worse ones, make room for it
+		return false, ErrReplaceUnderpriced
+	}
+	// Ensure new inbound transactions are accepted by all the admission policies.
+	// Reinjected and reloaded ones were already admitted once, and none of the
+	// checks above may reject the transaction after a stateful policy counted it.
+	if admit {
+		for _, policy := range pool.policies {
+			if err := policy.Admit(tx, from, local); err != nil {
+				log.Trace("Discarding rejected transaction", "hash", hash, "err", err)
+				rejectedTxMeter.Mark(1)
+				return false, err
+			}
+		}
+	}
+	// New transaction is better than our worse ones, make room for it
+	if full {
 		drop := pool.priced.Discard(pool.all.Slots()-int(pool.config.GlobalSlots+pool.config.GlobalQueue)+numSlots(tx), pool.locals)
 		for _, tx := range drop {
 			log.Trace("Discarding freshly underpriced transaction", "hash", tx.Hash(), "price", tx.GasPrice())
 			underpricedTxMeter.Mark(1)
 			pool.removeTx(tx.Hash(), false)
 		}
+		pool.queueDropEvent(TxDropUnderpriced, nil, drop...)
 	}
 	// Try to replace an existing transaction in the pending pool
-	from, _ := types.Sender(pool.signer, tx) // already validated
 	if list := pool.pending[from]; list != nil && list.Overlaps(tx) {
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
@@ -597,6 +990,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 			pool.all.Remove(old.Hash())
 			pool.priced.Removed(1)
 			pendingReplaceMeter.Mark(1)
+			pool.queueDropEvent(TxDropReplaced, tx, old)
 		}
 		pool.all.Add(tx)
 		pool.priced.Put(tx)
@@ -637,6 +1031,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 	from, _ := types.Sender(pool.signer, tx) // already validated
 	if pool.queue[from] == nil {
 		pool.queue[from] = newTxList(false)
+		pool.indexSender(from)
 	}
 	inserted, old := pool.queue[from].Add(tx, pool.config.PriceBump)
 	if !inserted {
@@ -649,6 +1044,7 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		queuedReplaceMeter.Mark(1)
+		pool.queueDropEvent(TxDropReplaced, tx, old)
 	} else {
 		// Nothing was replaced, bump the queued counter
 		queuedGauge.Inc(1)
@@ -667,8 +1063,8 @@ func (pool *TxPool) enqueueTx(hash common.Hash, tx *types.Transaction) (bool, er
 // journalTx adds the specified transaction to the local disk journal if it is
 // deemed to have been sent from a local account.
 func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
-	// Only journal if it's enabled and the transaction is local
-	if pool.journal == nil || !pool.locals.contains(from) {
+	// Only journal if it's enabled and the transaction is local and public
+	if pool.journal == nil || !pool.locals.contains(from) || pool.privates.contains(tx.Hash()) {
 		return
 	}
 	if err := pool.journal.insert(tx); err != nil {
@@ -684,6 +1080,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 	// Try to insert the transaction into the pending queue
 	if pool.pending[addr] == nil {
 		pool.pending[addr] = newTxList(true)
+		pool.indexSender(addr)
 	}
 	list := pool.pending[addr]
 
@@ -693,6 +1090,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(hash)
 		pool.priced.Removed(1)
 		pendingDiscardMeter.Mark(1)
+		pool.queueDropEvent(TxDropReplaced, list.txs.Get(tx.Nonce()), tx)
 		return false
 	}
 	// Otherwise discard any previous transaction and mark this
@@ -700,6 +1098,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 		pool.all.Remove(old.Hash())
 		pool.priced.Removed(1)
 		pendingReplaceMeter.Mark(1)
+		pool.queueDropEvent(TxDropReplaced, tx, old)
 	} else {
 		// Nothing was replaced, bump the pending counter
 		pendingGauge.Inc(1)
@@ -723,7 +1122,7 @@ func (pool *TxPool) promoteTx(addr common.Address, hash common.Hash, tx *types.T
 // This method is used to add transactions from the RPC API and performs synchronous pool
 // reorganization and event propagation.
 func (pool *TxPool) AddLocals(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, !pool.config.NoLocals, true)
+	return pool.addTxs(txs, !pool.config.NoLocals, true, true)
 }
 
 // AddLocal enqueues a single local transaction into the pool if it is valid. This is
@@ -733,18 +1132,40 @@ func (pool *TxPool) AddLocal(tx *types.Transaction) error {
 	return errs[0]
 }
 
+// AddPrivate enqueues a single local transaction into the pool if it is valid,
+// marking it private: the transaction is eligible for inclusion by the local
+// miner, but is never announced to the network until it expires.
+func (pool *TxPool) AddPrivate(tx *types.Transaction) error {
+	// Mark the transaction before insertion so no announcement can race ahead
+	hash := tx.Hash()
+	known := pool.privates.contains(hash)
+	pool.privates.add(hash, time.Now().Add(pool.config.PrivateLifetime))
+
+	errs := pool.addTxs([]*types.Transaction{tx}, !pool.config.NoLocals, true, true)
+	if errs[0] != nil && !known {
+		pool.privates.remove(hash)
+	}
+	return errs[0]
+}
+
+// IsPrivate returns whether the transaction with the given hash was submitted
+// privately and must not be propagated to the network.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	return pool.privates.contains(hash)
+}
+
 // AddRemotes enqueues a batch of transactions into the pool if they are valid. If the
 // senders are not among the locally tracked ones, full pricing constraints will apply.
 //
 // This method is used to add transactions from the p2p network and does not wait for pool
 // reorganization and internal event propagation.
 func (pool *TxPool) AddRemotes(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, false)
+	return pool.addTxs(txs, false, false, true)
 }
 
 // This is like AddRemotes, but waits for pool reorganization. Tests use this method.
 func (pool *TxPool) AddRemotesSync(txs []*types.Transaction) []error {
-	return pool.addTxs(txs, false, true)
+	return pool.addTxs(txs, false, true, true)
 }
 
 // This is like AddRemotes with a single transaction, but waits for pool reorganization. Tests use this method.
@@ -762,8 +1183,9 @@ func (pool *TxPool) AddRemote(tx *types.Transaction) error {
 	return errs[0]
 }
 
-// addTxs attempts to queue a batch of transactions if they are valid.
-func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
+// addTxs attempts to queue a batch of transactions if they are valid. The admission
+// policies are only consulted if admit is set, i.e. for new inbound transactions.
+func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync, admit bool) []error {
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
 		errs = make([]error, len(txs))
@@ -794,8 +1216,9 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 
 	// Process all the new transaction and merge any errors into the original slice
 	pool.mu.Lock()
-	newErrs, dirtyAddrs := pool.addTxsLocked(news, local)
+	newErrs, dirtyAddrs := pool.addTxsLocked(news, local, admit)
 	pool.mu.Unlock()
+	pool.sendDropEvents()
 
 	var nilSlot = 0
 	for _, err := range newErrs {
@@ -815,11 +1238,11 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local, sync bool) []error {
 
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
-func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local bool) ([]error, *accountSet) {
+func (pool *TxPool) addTxsLocked(txs []*types.Transaction, local, admit bool) ([]error, *accountSet) {
 	dirty := newAccountSet(pool.signer)
 	errs := make([]error, len(txs))
 	for i, tx := range txs {
-		replaced, err := pool.add(tx, local)
+		replaced, err := pool.add(tx, local, admit)
 		errs[i] = err
 		if err == nil && !replaced {
 			dirty.addTx(tx)
@@ -887,6 +1310,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 			// If no more pending transactions are left, remove the list
 			if pending.Empty() {
 				delete(pool.pending, addr)
+				pool.unindexSender(addr)
 			}
 			// Postpone any invalidated transactions
 			for _, tx := range invalids {
@@ -908,6 +1332,7 @@ func (pool *TxPool) removeTx(hash common.Hash, outofbound bool) {
 		if future.Empty() {
 			delete(pool.queue, addr)
 			delete(pool.beats, addr)
+			pool.unindexSender(addr)
 		}
 	}
 }
@@ -1062,6 +1487,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		pool.pendingNonces.set(addr, highestPending.Nonce()+1)
 	}
 	pool.mu.Unlock()
+	pool.sendDropEvents()
 
 	// Notify subsystems for newly added transactions
 	for _, tx := range promoted {
@@ -1076,7 +1502,7 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		for _, set := range events {
 			txs = append(txs, set.Flatten()...)
 		}
-		pool.txFeed.Send(NewTxsEvent{txs})
+		pool.sendTxsEvent(txs)
 	}
 }
 
@@ -1161,7 +1587,7 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	// Inject any transactions discarded due to reorgs
 	log.Debug("Reinjecting stale transactions", "count", len(reinject))
 	senderCacher.recover(pool.signer, reinject)
-	pool.addTxsLocked(reinject, false)
+	pool.addTxsLocked(reinject, false, false)
 
 	// Update all fork indicator by next pending block number.
 	next := new(big.Int).Add(newHead.Number, big.NewInt(1))
@@ -1188,6 +1614,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 			pool.all.Remove(hash)
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
+		pool.queueDropEvent(TxDropStale, nil, forwards...)
 		// Drop all transactions that are too costly (low balance or out of gas)
 		drops, _ := list.Filter(pool.currentState.GetBalance(addr), pool.currentMaxGas)
 		for _, tx := range drops {
@@ -1196,6 +1623,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		}
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
+		pool.queueDropEvent(TxDropUnpayable, nil, drops...)
 
 		// Gather all executable transactions and promote them
 		readies := list.Ready(pool.pendingNonces.get(addr))
@@ -1218,6 +1646,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 				log.Trace("Removed cap-exceeding queued transaction", "hash", hash)
 			}
 			queuedRateLimitMeter.Mark(int64(len(caps)))
+			pool.queueDropEvent(TxDropRateLimit, nil, caps...)
 		}
 		// Mark all the items dropped as removed
 		pool.priced.Removed(len(forwards) + len(drops) + len(caps))
@@ -1229,6 +1658,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) []*types.Trans
 		if list.Empty() {
 			delete(pool.queue, addr)
 			delete(pool.beats, addr)
+			pool.unindexSender(addr)
 		}
 	}
 	return promoted
@@ -1283,6 +1713,7 @@ func (pool *TxPool) truncatePending() {
 						log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 					}
 					pool.priced.Removed(len(caps))
+					pool.queueDropEvent(TxDropRateLimit, nil, caps...)
 					pendingGauge.Dec(int64(len(caps)))
 					if pool.locals.contains(offenders[i]) {
 						localGauge.Dec(int64(len(caps)))
@@ -1310,6 +1741,7 @@ func (pool *TxPool) truncatePending() {
 					log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 				}
 				pool.priced.Removed(len(caps))
+				pool.queueDropEvent(TxDropRateLimit, nil, caps...)
 				pendingGauge.Dec(int64(len(caps)))
 				if pool.locals.contains(addr) {
 					localGauge.Dec(int64(len(caps)))
@@ -1349,17 +1781,20 @@ func (pool *TxPool) truncateQueue() {
 
 		// Drop all transactions if they are less than the overflow
 		if size := uint64(list.Len()); size <= drop {
-			for _, tx := range list.Flatten() {
+			txs := list.Flatten()
+			for _, tx := range txs {
 				pool.removeTx(tx.Hash(), true)
 			}
 			drop -= size
 			queuedRateLimitMeter.Mark(int64(size))
+			pool.queueDropEvent(TxDropRateLimit, nil, txs...)
 			continue
 		}
 		// Otherwise drop only last few transactions
 		txs := list.Flatten()
 		for i := len(txs) - 1; i >= 0 && drop > 0; i-- {
 			pool.removeTx(txs[i].Hash(), true)
+			pool.queueDropEvent(TxDropRateLimit, nil, txs[i])
 			drop--
 			queuedRateLimitMeter.Mark(1)
 		}
@@ -1390,6 +1825,8 @@ func (pool *TxPool) demoteUnexecutables() {
 		}
 		pool.priced.Removed(len(olds) + len(drops))
 		pendingNofundsMeter.Mark(int64(len(drops)))
+		pool.queueDropEvent(TxDropStale, nil, olds...)
+		pool.queueDropEvent(TxDropUnpayable, nil, drops...)
 
 		for _, tx := range invalids {
 			hash := tx.Hash()
@@ -1415,6 +1852,7 @@ func (pool *TxPool) demoteUnexecutables() {
 		// Delete the entire pending entry if it became empty.
 		if list.Empty() {
 			delete(pool.pending, addr)
+			pool.unindexSender(addr)
 		}
 	}
 }
@@ -3,6 +3,7 @@
 package core
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -10,6 +11,8 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -110,6 +113,25 @@ func validateTxPoolInternals(pool *TxPool) error {
 			return fmt.Errorf("pending nonce mismatch: have %v, want %v", nonce, last+1)
 		}
 	}
+	// Ensure the sender index is sorted and tracks exactly the known accounts
+	senders := make(map[common.Address]struct{})
+	for addr := range pool.pending {
+		senders[addr] = struct{}{}
+	}
+	for addr := range pool.queue {
+		senders[addr] = struct{}{}
+	}
+	if len(pool.senders) != len(senders) {
+		return fmt.Errorf("sender index size mismatch: have %d, want %d", len(pool.senders), len(senders))
+	}
+	for i, addr := range pool.senders {
+		if _, ok := senders[addr]; !ok {
+			return fmt.Errorf("sender index contains unknown account %x", addr)
+		}
+		if i > 0 && bytes.Compare(pool.senders[i-1][:], addr[:]) >= 0 {
+			return fmt.Errorf("sender index unsorted at %d: %x >= %x", i, pool.senders[i-1], addr)
+		}
+	}
 	return nil
 }
 
@@ -343,14 +365,14 @@ func TestTransactionChainFork(t *testing.T) {
 	resetState()
 
 	tx := transaction(0, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	pool.removeTx(tx.Hash(), true)
 
 	// reset the pool's internal state
 	resetState()
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 }
@@ -377,10 +399,10 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	tx3, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(100), 1000000, big.NewInt(1), nil), signer, key)
 
 	// Add the first two transaction, ensure higher priced stays only
-	if replace, err := pool.add(tx1, false); err != nil || replace {
+	if replace, err := pool.add(tx1, false, true); err != nil || replace {
 		t.Errorf("first transaction insert failed (%v) or reported replacement (%v)", err, replace)
 	}
-	if replace, err := pool.add(tx2, false); err != nil || !replace {
+	if replace, err := pool.add(tx2, false, true); err != nil || !replace {
 		t.Errorf("second transaction insert failed (%v) or not reported replacement (%v)", err, replace)
 	}
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
@@ -392,7 +414,7 @@ func TestTransactionDoubleNonce(t *testing.T) {
 	}
 
 	// Add the third transaction and ensure it's not saved (smaller price)
-	pool.add(tx3, false)
+	pool.add(tx3, false, true)
 	<-pool.requestPromoteExecutables(newAccountSet(signer, addr))
 	if pool.pending[addr].Len() != 1 {
 		t.Error("expected 1 pending transactions, got", pool.pending[addr].Len())
@@ -415,7 +437,7 @@ func TestTransactionMissingNonce(t *testing.T) {
 	addr := crypto.PubkeyToAddress(key.PublicKey)
 	pool.currentState.AddBalance(addr, big.NewInt(100000000000000))
 	tx := transaction(1, 100000, key)
-	if _, err := pool.add(tx, false); err != nil {
+	if _, err := pool.add(tx, false, true); err != nil {
 		t.Error("didn't expect error", err)
 	}
 	if len(pool.pending) != 0 {
@@ -1835,6 +1857,235 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// Tests that remote transactions are persisted into the pool dump on shutdown and
+// restored on startup, subject to the dump size cap and the usual validation.
+func TestTransactionDumping(t *testing.T) {
+	t.Parallel()
+
+	// Create a temporary file for the dump
+	file, err := ioutil.TempFile("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary dump: %v", err)
+	}
+	dump := file.Name()
+	defer os.Remove(dump)
+
+	// Clean up the temporary file, we only need the path for now
+	file.Close()
+	os.Remove(dump)
+
+	// Create the original pool to inject transaction into the dump
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.Dump = dump
+	config.DumpLimit = 4
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+	local, _ := crypto.GenerateKey()
+	remote, _ := crypto.GenerateKey()
+
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000000))
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	// Add a local, three pending and two queued remote transactions
+	if err := pool.AddLocal(pricedTransaction(0, 100000, big.NewInt(1), local)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	for _, nonce := range []uint64{0, 1, 2, 4, 5} {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(1), remote)); err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", nonce, err)
+		}
+	}
+	pending, queued := pool.Stats()
+	if pending != 4 || queued != 2 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 4, 2)
+	}
+	// Terminate the old pool, bump the remote nonce, create a new pool and ensure
+	// only the capped, still valid remote transactions survive
+	pool.Stop()
+	statedb.SetNonce(crypto.PubkeyToAddress(remote.PublicKey), 1)
+	blockchain = &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	<-pool.requestPromoteExecutables(newAccountSet(pool.signer))
+	pending, queued = pool.Stats()
+	if pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that exporting the remote transactions of a pool and importing them into
+// a fresh one reproduces the same pool content.
+func TestTransactionExportImport(t *testing.T) {
+	t.Parallel()
+
+	source, key := setupTxPool()
+	defer source.Stop()
+
+	source.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	for _, nonce := range []uint64{0, 1, 3} {
+		if err := source.addRemoteSync(transaction(nonce, 100000, key)); err != nil {
+			t.Fatalf("failed to add remote transaction %d: %v", nonce, err)
+		}
+	}
+	var blob bytes.Buffer
+	if n, err := source.Export(&blob); err != nil || n != 3 {
+		t.Fatalf("export mismatch: have %d/%v, want %d/nil", n, err, 3)
+	}
+	sink, _ := setupTxPool()
+	defer sink.Stop()
+
+	sink.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+	total, dropped, err := sink.Import(&blob)
+	if err != nil {
+		t.Fatalf("failed to import transactions: %v", err)
+	}
+	if total != 3 || dropped != 0 {
+		t.Fatalf("import mismatch: have %d/%d, want %d/%d", total, dropped, 3, 0)
+	}
+	if pending, queued := sink.Stats(); pending != 2 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want %d/%d", pending, queued, 2, 1)
+	}
+	if err := validateTxPoolInternals(sink); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that transactions leaving the pool are reported with the correct reason.
+func TestTransactionDropEvents(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	pool.currentState.AddBalance(account, big.NewInt(1000000000))
+
+	drops := make(chan DropTxsEvent, 16)
+	sub := pool.SubscribeDropTxsEvent(drops)
+	defer sub.Unsubscribe()
+
+	// Replace a pending transaction and ensure the replacement is reported
+	original := pricedTransaction(0, 100000, big.NewInt(1), key)
+	if err := pool.addRemoteSync(original); err != nil {
+		t.Fatalf("failed to add original transaction: %v", err)
+	}
+	replacement := pricedTransaction(0, 100000, big.NewInt(2), key)
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to add replacement transaction: %v", err)
+	}
+	select {
+	case ev := <-drops:
+		if ev.Reason != TxDropReplaced || len(ev.Txs) != 1 || ev.Txs[0].Hash() != original.Hash() {
+			t.Fatalf("replacement event mismatch: have %v %v, want %v %v", ev.Reason, ev.Txs, TxDropReplaced, original.Hash())
+		}
+		if ev.Replacement == nil || ev.Replacement.Hash() != replacement.Hash() {
+			t.Fatalf("replacement mismatch: have %v, want %x", ev.Replacement, replacement.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("replacement event not fired")
+	}
+	// Bump the account nonce and ensure the stale transaction is reported
+	pool.currentState.SetNonce(account, 1)
+	<-pool.requestReset(nil, nil)
+
+	select {
+	case ev := <-drops:
+		if ev.Reason != TxDropStale || len(ev.Txs) != 1 || ev.Txs[0].Hash() != replacement.Hash() {
+			t.Fatalf("stale event mismatch: have %v %v, want %v %v", ev.Reason, ev.Txs, TxDropStale, replacement.Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("stale event not fired")
+	}
+}
+
+// Tests that private transactions are pooled for local inclusion, excluded from
+// the local journal and either dropped or released once they expire.
+func TestTransactionPrivate(t *testing.T)        { testTransactionPrivate(t, false) }
+func TestTransactionPrivateRelease(t *testing.T) { testTransactionPrivate(t, true) }
+
+func testTransactionPrivate(t *testing.T, release bool) {
+	t.Parallel()
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.PrivateRelease = release
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+
+	key, _ := crypto.GenerateKey()
+	pool.currentState.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1000000000))
+
+	events := make(chan NewTxsEvent, 4)
+	sub := pool.SubscribeNewTxsEvent(events)
+	defer sub.Unsubscribe()
+
+	privEvents := make(chan NewTxsEvent, 4)
+	privSub := pool.SubscribePrivateTxsEvent(privEvents)
+	defer privSub.Unsubscribe()
+
+	tx := transaction(0, 100000, key)
+	if err := pool.AddPrivate(tx); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if !pool.IsPrivate(tx.Hash()) {
+		t.Fatalf("transaction not marked private")
+	}
+	if pending, _ := pool.Stats(); pending != 1 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+	}
+	for addr, txs := range pool.local() {
+		if len(txs) != 0 {
+			t.Fatalf("private transaction exposed to the journal: %x: %v", addr, txs)
+		}
+	}
+	if err := validateEvents(events, 0); err != nil {
+		t.Fatalf("private transaction announced publicly: %v", err)
+	}
+	if err := validateEvents(privEvents, 1); err != nil {
+		t.Fatalf("private event firing failed: %v", err)
+	}
+	// Expire the private transaction and check that it's dropped or released
+	pool.privates.add(tx.Hash(), time.Now().Add(-time.Second))
+	pool.expirePrivates()
+
+	if pool.IsPrivate(tx.Hash()) {
+		t.Fatalf("expired transaction still marked private")
+	}
+	if release {
+		if pending, _ := pool.Stats(); pending != 1 {
+			t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 1)
+		}
+		if err := validateEvents(events, 1); err != nil {
+			t.Fatalf("release event firing failed: %v", err)
+		}
+		if err := validateEvents(privEvents, 0); err != nil {
+			t.Fatalf("released transaction announced privately: %v", err)
+		}
+	} else {
+		if pending, _ := pool.Stats(); pending != 0 {
+			t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 0)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // TestTransactionStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestTransactionStatusCheck(t *testing.T) {
@@ -1891,6 +2142,99 @@ func TestTransactionStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that the pool content can be paged through by sender account, and that
+// the sender index tracks accounts as they come and go.
+func TestTransactionContentPage(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupTxPool()
+	defer pool.Stop()
+
+	// Create a batch of funded accounts sorted by address
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		pool.currentState.AddBalance(crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := crypto.PubkeyToAddress(keys[i].PublicKey), crypto.PubkeyToAddress(keys[j].PublicKey)
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	addrs := make([]common.Address, len(keys))
+	for i, key := range keys {
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	// Give some accounts pending, some queued and some both kinds of transactions
+	txs := types.Transactions{
+		transaction(0, 100000, keys[0]),
+		transaction(0, 100000, keys[1]),
+		transaction(2, 100000, keys[1]),
+		transaction(0, 100000, keys[2]),
+		transaction(1, 100000, keys[3]),
+		transaction(1, 100000, keys[4]),
+	}
+	for i, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Page through the pool two accounts at a time
+	var (
+		cursor  *common.Address
+		pending = make(map[common.Address]types.Transactions)
+		queued  = make(map[common.Address]types.Transactions)
+		pages   int
+	)
+	for {
+		p, q, next := pool.ContentPage(cursor, 2)
+		for addr, list := range p {
+			pending[addr] = list
+		}
+		for addr, list := range q {
+			queued[addr] = list
+		}
+		if pages++; next == nil {
+			break
+		}
+		if want := addrs[2*pages-1]; *next != want {
+			t.Fatalf("page %d: next cursor mismatch: have %x, want %x", pages, *next, want)
+		}
+		cursor = next
+	}
+	if pages != 3 {
+		t.Fatalf("page count mismatch: have %d, want %d", pages, 3)
+	}
+	allPending, allQueued := pool.Content()
+	if !reflect.DeepEqual(pending, allPending) {
+		t.Errorf("paged pending content mismatch: have %v, want %v", pending, allPending)
+	}
+	if !reflect.DeepEqual(queued, allQueued) {
+		t.Errorf("paged queued content mismatch: have %v, want %v", queued, allQueued)
+	}
+	// A page exactly covering the remaining accounts has no next cursor
+	if p, q, next := pool.ContentPage(&addrs[1], 3); next != nil || len(p) != 1 || len(q) != 2 {
+		t.Errorf("last full page mismatch: %d pending, %d queued, next %v", len(p), len(q), next)
+	}
+	// A cursor after the last account yields an empty page
+	if p, q, next := pool.ContentPage(&addrs[4], 2); next != nil || len(p)+len(q) != 0 {
+		t.Errorf("trailing page mismatch: %d pending, %d queued, next %v", len(p), len(q), next)
+	}
+	// Remove the only transaction of an account and ensure it's dropped from the index
+	pool.mu.Lock()
+	pool.removeTx(txs[3].Hash(), true)
+	pool.mu.Unlock()
+
+	if p, q, next := pool.ContentPage(&addrs[1], 1); len(p) != 0 || len(q) != 1 || q[addrs[3]] == nil || next == nil || *next != addrs[3] {
+		t.Errorf("page after removal mismatch: pending %v, queued %v, next %v", p, q, next)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the transaction slots consumption is computed correctly
 func TestTransactionSlotCount(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,81 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// txPrivates is the set of privately submitted transactions, which are kept in
+// the pool for local block production but must never be announced to the network.
+//
+// Similarly to txLookup, the set is protected by its own lock so the networking
+// layer can filter transactions without acquiring the pool mutex.
+type txPrivates struct {
+	expiry map[common.Hash]time.Time // Deadline after which a private transaction expires
+	lock   sync.RWMutex
+}
+
+// newTxPrivates returns a new, empty private transaction set.
+func newTxPrivates() *txPrivates {
+	return &txPrivates{
+		expiry: make(map[common.Hash]time.Time),
+	}
+}
+
+// add marks a transaction as private until the given deadline.
+func (p *txPrivates) add(hash common.Hash, deadline time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.expiry[hash] = deadline
+}
+
+// remove clears the private marker of a transaction.
+func (p *txPrivates) remove(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.expiry, hash)
+}
+
+// contains checks whether a transaction is marked private.
+func (p *txPrivates) contains(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	_, ok := p.expiry[hash]
+	return ok
+}
+
+// expire removes and returns all the transactions whose deadline passed.
+func (p *txPrivates) expire(now time.Time) []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var expired []common.Hash
+	for hash, deadline := range p.expiry {
+		if now.After(deadline) {
+			expired = append(expired, hash)
+			delete(p.expiry, hash)
+		}
+	}
+	return expired
+}
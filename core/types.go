@@ -3,6 +3,7 @@
 package core
 
 import (
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -35,3 +36,13 @@ type Processor interface {
 	// the processor (coinbase) and any included uncles.
 	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error)
 }
+
+// ProcessorChain is the chain access needed by the processors to execute blocks:
+// the ancestor headers served to the BLOCKHASH opcode and the chain configuration
+// and consensus engine used to finalize the blocks.
+type ProcessorChain interface {
+	consensus.ChainHeaderReader
+
+	// Engine retrieves the chain's consensus engine.
+	Engine() consensus.Engine
+}
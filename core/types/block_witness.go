@@ -0,0 +1,20 @@
+package types
+
+// BlockWitness contains everything needed to execute a block without access to
+// the chain and state databases: the headers of the parent and of the ancestors
+// accessed through the BLOCKHASH opcode, along with the trie nodes and contract
+// code accessed on the parent state during execution.
+type BlockWitness struct {
+	Headers []*Header // Parent header first, followed by its ancestors in order
+	Nodes   [][]byte  // RLP encoded state trie nodes, sorted by hash
+	Codes   [][]byte  // Contract code, sorted by hash
+}
+
+// Parent returns the header of the parent block the witness is for, or nil if
+// the witness is malformed.
+func (w *BlockWitness) Parent() *Header {
+	if len(w.Headers) == 0 {
+		return nil
+	}
+	return w.Headers[0]
+}
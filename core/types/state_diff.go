@@ -0,0 +1,57 @@
+
+
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateDiff is the set of state modifications performed by a single block,
+// holding the values of every changed account and storage slot before and
+// after the block was applied.
+type StateDiff struct {
+	Accounts []*AccountDiff // Modified accounts, sorted by address
+}
+
+// AccountDiff is the modification of a single account within a block.
+type AccountDiff struct {
+	Address common.Address
+	Prev    []byte         // RLP encoded account before the block (empty if nonexistent)
+	Post    []byte         // RLP encoded account after the block (empty if deleted)
+	Wiped   bool           // Whether the original storage was cleared by a self-destruct
+	Storage []*StorageDiff // Modified storage slots, sorted by slot hash
+}
+
+// StorageDiff is the modification of a single storage slot within a block.
+type StorageDiff struct {
+	Hash common.Hash // Hash of the storage slot key
+	Prev common.Hash // Value of the slot before the block
+	Post common.Hash // Value of the slot after the block
+}
+
+// Account retrieves the modification of an account from the diff, or nil if
+// the account was not changed by the block.
+func (d *StateDiff) Account(addr common.Address) *AccountDiff {
+	i := sort.Search(len(d.Accounts), func(i int) bool {
+		return bytes.Compare(d.Accounts[i].Address[:], addr[:]) >= 0
+	})
+	if i < len(d.Accounts) && d.Accounts[i].Address == addr {
+		return d.Accounts[i]
+	}
+	return nil
+}
+
+// Slot retrieves the modification of a storage slot from the account diff, or
+// nil if the slot was not changed by the block.
+func (d *AccountDiff) Slot(hash common.Hash) *StorageDiff {
+	i := sort.Search(len(d.Storage), func(i int) bool {
+		return bytes.Compare(d.Storage[i].Hash[:], hash[:]) >= 0
+	})
+	if i < len(d.Storage) && d.Storage[i].Hash == hash {
+		return d.Storage[i]
+	}
+	return nil
+}
@@ -231,6 +231,34 @@ func BenchmarkPrecompiledIdentity(bench *testing.B) {
 	benchmarkPrecompiled("04", t, bench)
 }
 
+// Tests that the precompile address lists of each fork contain exactly the
+// addresses of the fork's precompiles.
+func TestPrecompiledAddresses(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []common.Address
+		contracts map[common.Address]PrecompiledContract
+		count     int
+	}{
+		{"homestead", PrecompiledAddressesHomestead, PrecompiledContractsHomestead, 4},
+		{"byzantium", PrecompiledAddressesByzantium, PrecompiledContractsByzantium, 8},
+		{"istanbul", PrecompiledAddressesIstanbul, PrecompiledContractsIstanbul, 9},
+		{"yolov2", PrecompiledAddressesYoloV2, PrecompiledContractsYoloV2, 18},
+	}
+	for _, tt := range tests {
+		if len(tt.addresses) != tt.count {
+			t.Errorf("%s: address count mismatch: have %d, want %d", tt.name, len(tt.addresses), tt.count)
+		}
+		seen := make(map[common.Address]bool)
+		for _, addr := range tt.addresses {
+			if _, ok := tt.contracts[addr]; !ok || seen[addr] {
+				t.Errorf("%s: unexpected address %x", tt.name, addr)
+			}
+			seen[addr] = true
+		}
+	}
+}
+
 // Tests the sample inputs from the ModExp EIP 198.
 func TestPrecompiledModExp(t *testing.T)      { testJson("modexp", "05", t) }
 func BenchmarkPrecompiledModExp(b *testing.B) { benchJson("modexp", "05", b) }
@@ -4,6 +4,7 @@ package vm
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync/atomic"
 	"time"
@@ -31,31 +32,11 @@ type (
 // ActivePrecompiles returns the addresses of the precompiles enabled with the current
 // configuration
 func (evm *EVM) ActivePrecompiles() []common.Address {
-	switch {
-	case evm.chainRules.IsYoloV2:
-		return PrecompiledAddressesYoloV2
-	case evm.chainRules.IsIstanbul:
-		return PrecompiledAddressesIstanbul
-	case evm.chainRules.IsByzantium:
-		return PrecompiledAddressesByzantium
-	default:
-		return PrecompiledAddressesHomestead
-	}
+	return evm.precompileAddrs
 }
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
-	var precompiles map[common.Address]PrecompiledContract
-	switch {
-	case evm.chainRules.IsYoloV2:
-		precompiles = PrecompiledContractsYoloV2
-	case evm.chainRules.IsIstanbul:
-		precompiles = PrecompiledContractsIstanbul
-	case evm.chainRules.IsByzantium:
-		precompiles = PrecompiledContractsByzantium
-	default:
-		precompiles = PrecompiledContractsHomestead
-	}
-	p, ok := precompiles[addr]
+	p, ok := evm.precompiles[addr]
 	return p, ok
 }
 
@@ -121,11 +102,18 @@ type EVM struct {
 	StateDB StateDB
 	// Depth is the current call stack
 	depth int
+	// readOnly is set while executing within a static call, shared by all the
+	// interpreters so it holds across calls switching between them
+	readOnly bool
 
 	// chainConfig contains information about the current chain
 	chainConfig *params.ChainConfig
 	// chain rules contains the chain rules for the current epoch
 	chainRules params.Rules
+	// precompiles contains the precompiled contracts active in the current block,
+	// including the custom ones enabled by the chain configuration
+	precompiles     map[common.Address]PrecompiledContract
+	precompileAddrs []common.Address
 	// virtual machine configuration options used to initialise the
 	// evm.
 	vmConfig Config
@@ -154,27 +142,26 @@ func NewEVM(blockCtx BlockContext, txCtx TxContext, statedb StateDB, chainConfig
 		chainRules:   chainConfig.Rules(blockCtx.BlockNumber),
 		interpreters: make([]Interpreter, 0, 1),
 	}
+	evm.precompiles, evm.precompileAddrs = activePrecompiles(chainConfig, evm.chainRules, blockCtx.BlockNumber)
 
 	if chainConfig.IsEWASM(blockCtx.BlockNumber) {
-		// to be implemented by EVM-C and Wagon PRs.
-		// if vmConfig.EWASMInterpreter != "" {
-		//  extIntOpts := strings.Split(vmConfig.EWASMInterpreter, ":")
-		//  path := extIntOpts[0]
-		//  options := []string{}
-		//  if len(extIntOpts) > 1 {
-		//    options = extIntOpts[1..]
-		//  }
-		//  evm.interpreters = append(evm.interpreters, NewEVMVCInterpreter(evm, vmConfig, options))
-		// } else {
-		// 	evm.interpreters = append(evm.interpreters, NewEWASMInterpreter(evm, vmConfig))
-		// }
-		panic("No supported ewasm interpreter yet.")
-	}
-
-	// vmConfig.EVMInterpreter will be used by EVM-C, it won't be checked here
-	// as we always want to have the built-in EVM as the failover option.
+		name := vmConfig.EWASMInterpreter
+		if name == "" {
+			name = "wasm"
+		}
+		constructor, ok := LookupInterpreter(name)
+		if !ok {
+			panic(fmt.Sprintf("unknown ewasm interpreter %q", name))
+		}
+		evm.interpreters = append(evm.interpreters, constructor(evm, vmConfig))
+	}
+	// vmConfig.EVMInterpreter is only used if registered, as we always want to
+	// have the built-in EVM as the failover option.
+	if constructor, ok := LookupInterpreter(vmConfig.EVMInterpreter); ok && vmConfig.EVMInterpreter != "" {
+		evm.interpreters = append(evm.interpreters, constructor(evm, vmConfig))
+	}
 	evm.interpreters = append(evm.interpreters, NewEVMInterpreter(evm, vmConfig))
-	evm.interpreter = evm.interpreters[0]
+	evm.interpreter = evm.interpreters[len(evm.interpreters)-1]
 
 	return evm
 }
@@ -243,7 +230,8 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 
 	if isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas)
+		ctx := &PrecompileContext{Caller: caller.Address(), Address: addr, Value: value}
+		ret, gas, err = evm.runPrecompile(p, ctx, input, gas)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
@@ -301,7 +289,8 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas)
+		ctx := &PrecompileContext{Caller: caller.Address(), Address: caller.Address(), Value: value}
+		ret, gas, err = evm.runPrecompile(p, ctx, input, gas)
 	} else {
 		addrCopy := addr
 		// Initialise a new contract and set the code that is to be used by the EVM.
@@ -337,7 +326,11 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas)
+		ctx := &PrecompileContext{Caller: caller.Address(), Address: caller.Address(), Value: new(big.Int)}
+		if parent, ok := caller.(*Contract); ok {
+			ctx.Caller, ctx.Value = parent.CallerAddress, parent.Value()
+		}
+		ret, gas, err = evm.runPrecompile(p, ctx, input, gas)
 	} else {
 		addrCopy := addr
 		// Initialise a new contract and make initialise the delegate values
@@ -381,7 +374,8 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	evm.StateDB.AddBalance(addr, big0)
 
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas)
+		ctx := &PrecompileContext{Caller: caller.Address(), Address: addr, Value: new(big.Int), ReadOnly: true}
+		ret, gas, err = evm.runPrecompile(p, ctx, input, gas)
 	} else {
 		// At this point, we use a copy of address. If we don't, the go compiler will
 		// leak the 'contract' to the outer scope, and make allocation for 'contract'
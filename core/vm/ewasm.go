@@ -0,0 +1,480 @@
+
+
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/wasm"
+	"github.com/ethereum/go-ethereum/params"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// eeiModule is the name of the module contracts import the Ethereum Environment
+// Interface functions from.
+const eeiModule = "ethereum"
+
+// errFinish is returned by the finish host function to end the execution of a
+// contract successfully.
+var errFinish = errors.New("finish")
+
+// ewasmModules caches the decoded modules of contracts by code hash.
+var ewasmModules, _ = lru.New(256)
+
+func init() {
+	RegisterInterpreter("wasm", func(evm *EVM, cfg Config) Interpreter {
+		return NewEWASMInterpreter(evm, cfg)
+	})
+}
+
+// EWASMInterpreter runs contracts compiled to WebAssembly, recognized by the
+// magic prefix of their code. Contracts export their entry point as "main" and
+// their memory as "memory", and interact with the chain through the functions of
+// the Ethereum Environment Interface imported from the "ethereum" module.
+//
+// The execution isn't reported to tracers, which only understand EVM opcodes.
+type EWASMInterpreter struct {
+	evm *EVM
+	cfg Config
+
+	returnData []byte // Last call's return data for subsequent reuse
+}
+
+// NewEWASMInterpreter returns a new instance of the WebAssembly interpreter.
+func NewEWASMInterpreter(evm *EVM, cfg Config) *EWASMInterpreter {
+	return &EWASMInterpreter{
+		evm: evm,
+		cfg: cfg,
+	}
+}
+
+// CanRun implements Interpreter, accepting code carrying the wasm magic prefix.
+func (in *EWASMInterpreter) CanRun(code []byte) bool {
+	return wasm.IsModule(code)
+}
+
+// Run implements Interpreter, running the main function of the contract. As for
+// the EVM interpreter, any error returned should be considered a revert and
+// consume all gas operation, except for ErrExecutionReverted.
+func (in *EWASMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (ret []byte, err error) {
+	// Increment the call depth which is restricted to 1024
+	in.evm.depth++
+	defer func() { in.evm.depth-- }()
+
+	// Make sure the readOnly is only set if we aren't in readOnly yet.
+	if readOnly && !in.evm.readOnly {
+		in.evm.readOnly = true
+		defer func() { in.evm.readOnly = false }()
+	}
+	in.returnData = nil
+
+	module, err := in.module(contract)
+	if err != nil {
+		return nil, err
+	}
+	if pages := uint64(module.Memory.Min); !contract.UseGas(pages * params.EWASMMemoryPageGas) {
+		return nil, ErrOutOfGas
+	}
+	call := &eeiCall{in: in, contract: contract, input: input}
+	instance, err := wasm.Instantiate(module, call.imports(), wasm.Config{
+		UseGas:    call.useGas,
+		PageGas:   params.EWASMMemoryPageGas,
+		Interrupt: &in.evm.abort,
+	})
+	if err != nil {
+		return nil, err
+	}
+	switch _, err = instance.Call("main"); err {
+	case nil, errFinish:
+		return call.output, nil
+	case ErrExecutionReverted:
+		return call.output, err
+	default:
+		return nil, err
+	}
+}
+
+// module decodes the code of a contract, checking it has the exports required to
+// run it. Modules of deployed contracts are cached by code hash.
+func (in *EWASMInterpreter) module(contract *Contract) (*wasm.Module, error) {
+	if contract.CodeHash != (common.Hash{}) {
+		if module, ok := ewasmModules.Get(contract.CodeHash); ok {
+			return module.(*wasm.Module), nil
+		}
+	}
+	module, err := wasm.Decode(contract.Code)
+	if err != nil {
+		return nil, err
+	}
+	index, ok := module.Export("main", wasm.ExternalFunction)
+	if !ok {
+		return nil, fmt.Errorf("%w: missing main function", wasm.ErrInvalidModule)
+	}
+	if t, _ := module.FuncType(index); len(t.Params) > 0 || len(t.Results) > 0 {
+		return nil, fmt.Errorf("%w: invalid main function signature", wasm.ErrInvalidModule)
+	}
+	if _, ok := module.Export("memory", wasm.ExternalMemory); !ok {
+		return nil, fmt.Errorf("%w: missing memory export", wasm.ErrInvalidModule)
+	}
+	if module.Start != nil {
+		return nil, fmt.Errorf("%w: start function not allowed", wasm.ErrInvalidModule)
+	}
+	for _, imp := range module.Imports {
+		if imp.Module != eeiModule {
+			return nil, fmt.Errorf("%w: import from unknown module %q", wasm.ErrInvalidModule, imp.Module)
+		}
+	}
+	if contract.CodeHash != (common.Hash{}) {
+		ewasmModules.Add(contract.CodeHash, module)
+	}
+	return module, nil
+}
+
+// eeiCall is the execution of a contract, providing it the Ethereum Environment
+// Interface functions.
+type eeiCall struct {
+	in       *EWASMInterpreter
+	contract *Contract
+	input    []byte
+	output   []byte // Data returned with finish or revert
+}
+
+// useGas charges gas to the contract.
+func (c *eeiCall) useGas(gas uint64) error {
+	if !c.contract.UseGas(gas) {
+		return ErrOutOfGas
+	}
+	return nil
+}
+
+// eeiFunction is a function of the Ethereum Environment Interface.
+type eeiFunction struct {
+	params  []wasm.ValueType
+	results []wasm.ValueType
+	gas     uint64 // Static gas charged before running the function
+	run     func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error)
+}
+
+var (
+	eeiNone = []wasm.ValueType{}
+	eeiI32  = []wasm.ValueType{wasm.I32}
+	eeiI64  = []wasm.ValueType{wasm.I64}
+)
+
+func eeiParams(types ...wasm.ValueType) []wasm.ValueType {
+	return types
+}
+
+// eeiFunctions are the functions of the Ethereum Environment Interface, by name.
+var eeiFunctions = map[string]eeiFunction{
+	"useGas": {eeiI64, eeiNone, 0, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, c.useGas(args[0])
+	}},
+	"getGasLeft": {eeiNone, eeiI64, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.contract.Gas, nil
+	}},
+	"getAddress": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), c.contract.Address().Bytes())
+	}},
+	"getExternalBalance": {eeiParams(wasm.I32, wasm.I32), eeiNone, params.BalanceGasEIP1884, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		addr, err := mem.Read(uint32(args[0]), common.AddressLength)
+		if err != nil {
+			return 0, err
+		}
+		return 0, mem.Write(uint32(args[1]), eeiU128(c.in.evm.StateDB.GetBalance(common.BytesToAddress(addr))))
+	}},
+	"getBlockHash": {eeiParams(wasm.I64, wasm.I32), eeiI32, GasExtStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		var (
+			num   = args[0]
+			upper = c.in.evm.Context.BlockNumber.Uint64()
+			lower uint64
+		)
+		if upper > 256 {
+			lower = upper - 256
+		}
+		if num < lower || num >= upper {
+			return 1, nil
+		}
+		return 0, mem.Write(uint32(args[1]), c.in.evm.Context.GetHash(num).Bytes())
+	}},
+	"call": {eeiParams(wasm.I64, wasm.I32, wasm.I32, wasm.I32, wasm.I32), eeiI32, params.CallGasEIP150, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.call(mem, args[0], uint32(args[1]), &args[2], uint32(args[3]), uint32(args[4]))
+	}},
+	"callStatic": {eeiParams(wasm.I64, wasm.I32, wasm.I32, wasm.I32), eeiI32, params.CallGasEIP150, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.call(mem, args[0], uint32(args[1]), nil, uint32(args[2]), uint32(args[3]))
+	}},
+	"getCallDataSize": {eeiNone, eeiI32, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return uint64(len(c.input)), nil
+	}},
+	"callDataCopy": {eeiParams(wasm.I32, wasm.I32, wasm.I32), eeiNone, GasFastestStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, c.copy(mem, uint32(args[0]), c.input, args[1], args[2])
+	}},
+	"getCaller": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), c.contract.Caller().Bytes())
+	}},
+	"getCallValue": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), eeiU128(c.contract.Value()))
+	}},
+	"getTxOrigin": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), c.in.evm.Origin.Bytes())
+	}},
+	"getTxGasPrice": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), eeiU128(c.in.evm.GasPrice))
+	}},
+	"getBlockCoinbase": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), c.in.evm.Context.Coinbase.Bytes())
+	}},
+	"getBlockDifficulty": {eeiI32, eeiNone, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, mem.Write(uint32(args[0]), eeiLittleEndian(c.in.evm.Context.Difficulty, 32))
+	}},
+	"getBlockGasLimit": {eeiNone, eeiI64, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.in.evm.Context.GasLimit, nil
+	}},
+	"getBlockNumber": {eeiNone, eeiI64, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.in.evm.Context.BlockNumber.Uint64(), nil
+	}},
+	"getBlockTimestamp": {eeiNone, eeiI64, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return c.in.evm.Context.Time.Uint64(), nil
+	}},
+	"getCodeSize": {eeiNone, eeiI32, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return uint64(len(c.contract.Code)), nil
+	}},
+	"codeCopy": {eeiParams(wasm.I32, wasm.I32, wasm.I32), eeiNone, GasFastestStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return 0, c.copy(mem, uint32(args[0]), c.contract.Code, args[1], args[2])
+	}},
+	"getReturnDataSize": {eeiNone, eeiI32, GasQuickStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		return uint64(len(c.in.returnData)), nil
+	}},
+	"returnDataCopy": {eeiParams(wasm.I32, wasm.I32, wasm.I32), eeiNone, GasFastestStep, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		if args[1]+args[2] > uint64(len(c.in.returnData)) {
+			return 0, ErrReturnDataOutOfBounds
+		}
+		return 0, c.copy(mem, uint32(args[0]), c.in.returnData, args[1], args[2])
+	}},
+	"storageLoad": {eeiParams(wasm.I32, wasm.I32), eeiNone, params.SloadGasEIP2200, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		key, err := mem.Read(uint32(args[0]), common.HashLength)
+		if err != nil {
+			return 0, err
+		}
+		value := c.in.evm.StateDB.GetState(c.contract.Address(), common.BytesToHash(key))
+		return 0, mem.Write(uint32(args[1]), value.Bytes())
+	}},
+	"storageStore": {eeiParams(wasm.I32, wasm.I32), eeiNone, 0, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		if c.in.evm.readOnly {
+			return 0, ErrWriteProtection
+		}
+		key, err := mem.Read(uint32(args[0]), common.HashLength)
+		if err != nil {
+			return 0, err
+		}
+		value, err := mem.Read(uint32(args[1]), common.HashLength)
+		if err != nil {
+			return 0, err
+		}
+		if c.contract.Gas <= params.SstoreSentryGasEIP2200 {
+			return 0, ErrOutOfGas
+		}
+		var (
+			addr = c.contract.Address()
+			k, v = common.BytesToHash(key), common.BytesToHash(value)
+		)
+		if err := c.useGas(sstoreGasEIP2200(c.in.evm, addr, k, v)); err != nil {
+			return 0, err
+		}
+		c.in.evm.StateDB.SetState(addr, k, v)
+		return 0, nil
+	}},
+	"log": {eeiParams(wasm.I32, wasm.I32, wasm.I32, wasm.I32, wasm.I32, wasm.I32, wasm.I32), eeiNone, params.LogGas, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		if c.in.evm.readOnly {
+			return 0, ErrWriteProtection
+		}
+		count := args[2]
+		if count > 4 {
+			return 0, fmt.Errorf("invalid log topic count %d", count)
+		}
+		if err := c.useGas(count*params.LogTopicGas + args[1]*params.LogDataGas); err != nil {
+			return 0, err
+		}
+		data, err := mem.Read(uint32(args[0]), uint32(args[1]))
+		if err != nil {
+			return 0, err
+		}
+		topics := make([]common.Hash, count)
+		for i := range topics {
+			topic, err := mem.Read(uint32(args[3+i]), common.HashLength)
+			if err != nil {
+				return 0, err
+			}
+			topics[i] = common.BytesToHash(topic)
+		}
+		c.in.evm.StateDB.AddLog(&types.Log{
+			Address: c.contract.Address(),
+			Topics:  topics,
+			Data:    data,
+			// This is a non-consensus field, but assigned here because
+			// core/state doesn't know the current block number.
+			BlockNumber: c.in.evm.Context.BlockNumber.Uint64(),
+		})
+		return 0, nil
+	}},
+	"finish": {eeiParams(wasm.I32, wasm.I32), eeiNone, 0, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		output, err := mem.Read(uint32(args[0]), uint32(args[1]))
+		if err != nil {
+			return 0, err
+		}
+		c.output = output
+		return 0, errFinish
+	}},
+	"selfDestruct": {eeiI32, eeiNone, params.SelfdestructGasEIP150, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		if c.in.evm.readOnly {
+			return 0, ErrWriteProtection
+		}
+		raw, err := mem.Read(uint32(args[0]), common.AddressLength)
+		if err != nil {
+			return 0, err
+		}
+		var (
+			db          = c.in.evm.StateDB
+			self        = c.contract.Address()
+			beneficiary = common.BytesToAddress(raw)
+			balance     = db.GetBalance(self)
+		)
+		// Charge the account creation and refund like the SELFDESTRUCT opcode does
+		if db.Empty(beneficiary) && balance.Sign() != 0 {
+			if err := c.useGas(params.CreateBySelfdestructGas); err != nil {
+				return 0, err
+			}
+		}
+		if !db.HasSuicided(self) {
+			db.AddRefund(params.SelfdestructRefundGas)
+		}
+		db.AddBalance(beneficiary, balance)
+		db.Suicide(self)
+		return 0, errFinish
+	}},
+	"revert": {eeiParams(wasm.I32, wasm.I32), eeiNone, 0, func(c *eeiCall, mem *wasm.Instance, args []uint64) (uint64, error) {
+		output, err := mem.Read(uint32(args[0]), uint32(args[1]))
+		if err != nil {
+			return 0, err
+		}
+		c.output = output
+		return 0, ErrExecutionReverted
+	}},
+}
+
+// imports returns the host functions of the interface, bound to the call.
+func (c *eeiCall) imports() wasm.Imports {
+	funcs := make(map[string]*wasm.HostFunction, len(eeiFunctions))
+	for name, fn := range eeiFunctions {
+		fn := fn
+		funcs[name] = &wasm.HostFunction{
+			Type: wasm.FuncType{Params: fn.params, Results: fn.results},
+			Call: func(mem *wasm.Instance, args []uint64) (uint64, error) {
+				if err := c.useGas(fn.gas); err != nil {
+					return 0, err
+				}
+				return fn.run(c, mem, args)
+			},
+		}
+	}
+	return wasm.Imports{eeiModule: funcs}
+}
+
+// copy writes size bytes of data starting at dataOffset to the memory, zero
+// padding past the end of the data. The copy gas is charged and the destination
+// checked before the data is sliced, so huge sizes fail without allocating.
+func (c *eeiCall) copy(mem *wasm.Instance, memOffset uint32, data []byte, dataOffset, size uint64) error {
+	if err := c.useGas(toWordSize(size) * params.CopyGas); err != nil {
+		return err
+	}
+	if uint64(memOffset)+size > uint64(len(mem.Memory())) {
+		return wasm.ErrOutOfBounds
+	}
+	return mem.Write(memOffset, getData(data, dataOffset, size))
+}
+
+// call calls another contract, with the value at the given offset, or statically
+// if there's none, returning 0 on success, 1 on failure and 2 on revert.
+func (c *eeiCall) call(mem *wasm.Instance, gas uint64, addrOffset uint32, valueOffset *uint64, dataOffset, dataLength uint32) (uint64, error) {
+	raw, err := mem.Read(addrOffset, common.AddressLength)
+	if err != nil {
+		return 0, err
+	}
+	addr := common.BytesToAddress(raw)
+
+	value := new(big.Int)
+	if valueOffset != nil {
+		raw, err := mem.Read(uint32(*valueOffset), 16)
+		if err != nil {
+			return 0, err
+		}
+		for i, j := 0, len(raw)-1; i < j; i, j = i+1, j-1 {
+			raw[i], raw[j] = raw[j], raw[i]
+		}
+		value.SetBytes(raw)
+	}
+	input, err := mem.Read(dataOffset, dataLength)
+	if err != nil {
+		return 0, err
+	}
+	// Charge the value transfer and account creation like the CALL opcode does
+	var cost uint64
+	if value.Sign() != 0 {
+		if c.in.evm.readOnly {
+			return 0, ErrWriteProtection
+		}
+		cost += params.CallValueTransferGas
+		if c.in.evm.StateDB.Empty(addr) {
+			cost += params.CallNewAccountGas
+		}
+	}
+	if err := c.useGas(cost); err != nil {
+		return 0, err
+	}
+	// Pass at most all but one 64th of the remaining gas
+	if available := c.contract.Gas - c.contract.Gas/64; gas > available {
+		gas = available
+	}
+	c.contract.Gas -= gas
+	if value.Sign() != 0 {
+		gas += params.CallStipend
+	}
+	var (
+		ret      []byte
+		leftover uint64
+	)
+	if valueOffset != nil {
+		ret, leftover, err = c.in.evm.Call(c.contract, addr, input, gas, value)
+	} else {
+		ret, leftover, err = c.in.evm.StaticCall(c.contract, addr, input, gas)
+	}
+	c.contract.Gas += leftover
+	c.in.returnData = ret
+
+	switch err {
+	case nil:
+		return 0, nil
+	case ErrExecutionReverted:
+		return 2, nil
+	default:
+		return 1, nil
+	}
+}
+
+// eeiU128 encodes a value as the 128 bit little endian integer of the interface.
+func eeiU128(v *big.Int) []byte {
+	return eeiLittleEndian(v, 16)
+}
+
+// eeiLittleEndian encodes the low bytes of a value as a little endian integer.
+func eeiLittleEndian(v *big.Int, size int) []byte {
+	b := common.LeftPadBytes(v.Bytes(), 32)[32-size:]
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,186 @@
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm/wasm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// echoInterpreter is an interpreter returning the input of contracts whose code
+// starts with 0xec.
+type echoInterpreter struct{}
+
+func (in *echoInterpreter) Run(contract *Contract, input []byte, static bool) ([]byte, error) {
+	return input, nil
+}
+
+func (in *echoInterpreter) CanRun(code []byte) bool {
+	return len(code) > 0 && code[0] == 0xec
+}
+
+func init() {
+	RegisterInterpreter("testEcho", func(evm *EVM, cfg Config) Interpreter {
+		return new(echoInterpreter)
+	})
+}
+
+func newInterpreterTestEVM(config *params.ChainConfig, vmConfig Config) *EVM {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber: new(big.Int),
+	}
+	return NewEVM(vmctx, TxContext{}, statedb, config, vmConfig)
+}
+
+// Tests that registered interpreters are selected through the configuration and
+// run the code they accept, falling back to the built-in EVM.
+func TestInterpreterRegistry(t *testing.T) {
+	if _, ok := LookupInterpreter("testEcho:some:options"); !ok {
+		t.Fatalf("registered interpreter not found")
+	}
+	if _, ok := LookupInterpreter("missing"); ok {
+		t.Fatalf("unregistered interpreter found")
+	}
+	evm := newInterpreterTestEVM(params.TestChainConfig, Config{EVMInterpreter: "testEcho"})
+
+	echo := common.BytesToAddress([]byte("echo"))
+	evm.StateDB.SetCode(echo, []byte{0xec})
+	if ret, _, err := evm.Call(AccountRef(common.Address{}), echo, []byte("hello"), 100000, new(big.Int)); err != nil || !bytes.Equal(ret, []byte("hello")) {
+		t.Errorf("echo call mismatch: have %q, %v", ret, err)
+	}
+	code := common.BytesToAddress([]byte("code"))
+	evm.StateDB.SetCode(code, []byte{byte(PUSH1), 1, byte(PUSH1), 0, byte(MSTORE8), byte(PUSH1), 1, byte(PUSH1), 0, byte(RETURN)})
+	if ret, _, err := evm.Call(AccountRef(common.Address{}), code, nil, 100000, new(big.Int)); err != nil || !bytes.Equal(ret, []byte{1}) {
+		t.Errorf("evm call mismatch: have %x, %v", ret, err)
+	}
+}
+
+// Tests that WebAssembly contracts missing the required exports are rejected.
+func TestEWASMInvalidContracts(t *testing.T) {
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+	evm := newInterpreterTestEVM(&config, Config{})
+
+	tests := []struct {
+		name string
+		code string
+	}{
+		// (module)
+		{"empty", "0061736d01000000"},
+		// (module (func (export "main")))
+		{"no memory", "0061736d0100000001040160000003020100070801046d61696e00000a040102000b"},
+		// (module (memory (export "memory") 1) (func (export "main") (param i32)))
+		{"main signature", "0061736d0100000001050160017f00030201000503010001071102046d61696e0000066d656d6f727902000a040102000b"},
+	}
+	for _, tt := range tests {
+		addr := common.BytesToAddress([]byte(tt.name))
+		evm.StateDB.SetCode(addr, common.Hex2Bytes(tt.code))
+		_, _, err := evm.Call(AccountRef(common.Address{}), addr, nil, 100000, new(big.Int))
+		if !errors.Is(err, wasm.ErrInvalidModule) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, wasm.ErrInvalidModule)
+		}
+	}
+}
+
+// Tests that unknown ewasm interpreters are refused.
+func TestEWASMUnknownInterpreter(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("unknown interpreter accepted")
+		}
+	}()
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+	newInterpreterTestEVM(&config, Config{EWASMInterpreter: "missing:options"})
+}
+
+// Tests that copying huge amounts of data into the memory of a contract runs out
+// of gas before the data is allocated, and that copies beyond the memory fail.
+func TestEWASMCopyGas(t *testing.T) {
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+	evm := newInterpreterTestEVM(&config, Config{})
+
+	// The contracts import a copy function and call it from main with a memory
+	// offset of 0, a data offset of 0 and the given size, e.g. for callDataCopy:
+	//
+	// (module
+	//   (import "ethereum" "callDataCopy" (func (param i32 i32 i32)))
+	//   (memory (export "memory") 1)
+	//   (func (export "main") (call 0 (i32.const 0) (i32.const 0) (i32.const -1))))
+	tests := []struct {
+		name string
+		code string
+		err  error
+	}{
+		// callDataCopy of 0xffffffff bytes
+		{"calldata huge", "0061736d01000000010a0260037f7f7f0060000002190108657468657265756d0c63616c6c44617461436f70790000030201010503010001071102046d61696e0001066d656d6f72790200" +
+			"0a0c010a0041004100417f10000b", ErrOutOfGas},
+		// codeCopy of 0xffffffff bytes
+		{"code huge", "0061736d01000000010a0260037f7f7f0060000002150108657468657265756d08636f6465436f70790000030201010503010001071102046d61696e0001066d656d6f72790200" +
+			"0a0c010a0041004100417f10000b", ErrOutOfGas},
+		// callDataCopy of 0x20000 bytes, affordable but past the single page
+		{"calldata past memory", "0061736d01000000010a0260037f7f7f0060000002190108657468657265756d0c63616c6c44617461436f70790000030201010503010001071102046d61696e0001066d656d6f72790200" +
+			"0a0e010c00410041004180800810000b", wasm.ErrOutOfBounds},
+	}
+	for _, tt := range tests {
+		addr := common.BytesToAddress([]byte(tt.name))
+		evm.StateDB.SetCode(addr, common.Hex2Bytes(tt.code))
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		_, _, err := evm.Call(AccountRef(common.Address{}), addr, []byte{0x01}, 1000000, new(big.Int))
+		runtime.ReadMemStats(&after)
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.err)
+		}
+		if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16*1024*1024 {
+			t.Errorf("%s: allocated %d bytes", tt.name, allocated)
+		}
+	}
+}
+
+// Tests that ewasm contracts can destroy themselves, except within static calls.
+func TestEWASMSelfDestruct(t *testing.T) {
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+	evm := newInterpreterTestEVM(&config, Config{})
+
+	// (module
+	//   (import "ethereum" "selfDestruct" (func (param i32)))
+	//   (memory (export "memory") 1)
+	//   (func (export "main") (call 0 (i32.const 0))))
+	addr := common.BytesToAddress([]byte("destruct"))
+	evm.StateDB.SetCode(addr, common.Hex2Bytes("0061736d01000000"+
+		"01080260017f00600000"+ // types
+		"02190108657468657265756d0c73656c6644657374727563740000"+ // imports
+		"03020101"+ // functions
+		"0503010001"+ // memory
+		"071102046d61696e0001066d656d6f72790200"+ // exports
+		"0a08010600410010000b")) // code
+	evm.StateDB.AddBalance(addr, big.NewInt(100))
+
+	if _, _, err := evm.StaticCall(AccountRef(common.Address{}), addr, nil, 100000); err != ErrWriteProtection {
+		t.Fatalf("static self-destruct error mismatch: have %v, want %v", err, ErrWriteProtection)
+	}
+	if evm.StateDB.HasSuicided(addr) {
+		t.Fatalf("contract destroyed within a static call")
+	}
+	if _, _, err := evm.Call(AccountRef(common.Address{}), addr, nil, 100000, new(big.Int)); err != nil {
+		t.Fatalf("self-destruct failed: %v", err)
+	}
+	if !evm.StateDB.HasSuicided(addr) || evm.StateDB.GetBalance(common.Address{}).Uint64() != 100 {
+		t.Errorf("self-destruct mismatch: destroyed %v, beneficiary balance %v", evm.StateDB.HasSuicided(addr), evm.StateDB.GetBalance(common.Address{}))
+	}
+}
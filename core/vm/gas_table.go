@@ -181,24 +181,26 @@ func gasSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory, m
 		return 0, errors.New("not enough gas for reentrancy sentry")
 	}
 	// Gas sentry honoured, do the actual gas calculation based on the stored value
-	var (
-		y, x    = stack.Back(1), stack.Back(0)
-		current = evm.StateDB.GetState(contract.Address(), x.Bytes32())
-	)
-	value := common.Hash(y.Bytes32())
+	y, x := stack.Back(1), stack.Back(0)
+	return sstoreGasEIP2200(evm, contract.Address(), x.Bytes32(), y.Bytes32()), nil
+}
 
+// sstoreGasEIP2200 returns the net gas cost of storing a value in a storage slot
+// of an account, adjusting the refund counter as defined by EIP-2200.
+func sstoreGasEIP2200(evm *EVM, addr common.Address, key, value common.Hash) uint64 {
+	current := evm.StateDB.GetState(addr, key)
 	if current == value { // noop (1)
-		return params.SloadGasEIP2200, nil
+		return params.SloadGasEIP2200
 	}
-	original := evm.StateDB.GetCommittedState(contract.Address(), x.Bytes32())
+	original := evm.StateDB.GetCommittedState(addr, key)
 	if original == current {
 		if original == (common.Hash{}) { // create slot (2.1.1)
-			return params.SstoreSetGasEIP2200, nil
+			return params.SstoreSetGasEIP2200
 		}
 		if value == (common.Hash{}) { // delete slot (2.1.2b)
 			evm.StateDB.AddRefund(params.SstoreClearsScheduleRefundEIP2200)
 		}
-		return params.SstoreResetGasEIP2200, nil // write existing slot (2.1.2)
+		return params.SstoreResetGasEIP2200 // write existing slot (2.1.2)
 	}
 	if original != (common.Hash{}) {
 		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
@@ -214,7 +216,7 @@ func gasSStoreEIP2200(evm *EVM, contract *Contract, stack *Stack, mem *Memory, m
 			evm.StateDB.AddRefund(params.SstoreResetGasEIP2200 - params.SloadGasEIP2200)
 		}
 	}
-	return params.SloadGasEIP2200, nil // dirty update (2.2)
+	return params.SloadGasEIP2200 // dirty update (2.2)
 }
 
 func makeGasLog(n uint64) gasFunc {
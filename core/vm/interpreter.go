@@ -3,7 +3,10 @@
 package vm
 
 import (
+	"fmt"
 	"hash"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -20,8 +23,8 @@ type Config struct {
 
 	JumpTable [256]*operation // EVM instruction table, automatically populated if unset
 
-	EWASMInterpreter string // External EWASM interpreter options
-	EVMInterpreter   string // External EVM interpreter options
+	EWASMInterpreter string // Registered EWASM interpreter, as name[:options] (default = built-in "wasm")
+	EVMInterpreter   string // Registered EVM interpreter tried before the built-in one, as name[:options]
 
 	ExtraEips []int // Additional EIPS that are to be enabled
 }
@@ -30,6 +33,13 @@ type Config struct {
 // passed environment to query external sources for state information.
 // The Interpreter will run the byte code VM based on the passed
 // configuration.
+//
+// Interpreters are the plug-in point for alternative execution engines. The EVM
+// hands each contract to the first of its interpreters able to run the code, so
+// engines are registered by name with RegisterInterpreter and selected through
+// the EWASMInterpreter and EVMInterpreter options of the Config. Implementations
+// must increment the depth of the EVM while running, refuse state modifications
+// when called statically, and charge all gas to the passed contract.
 type Interpreter interface {
 	// Run loops and evaluates the contract's code with the given input data and returns
 	// the return byte-slice and an error if one occurred.
@@ -48,6 +58,40 @@ type Interpreter interface {
 	CanRun([]byte) bool
 }
 
+// InterpreterConstructor creates an interpreter for the given EVM.
+type InterpreterConstructor func(evm *EVM, cfg Config) Interpreter
+
+var (
+	interpreters    = make(map[string]InterpreterConstructor)
+	interpretersMux sync.RWMutex
+)
+
+// RegisterInterpreter makes an interpreter available by name to the EVMs. It is
+// meant to be called from the init function of the package implementing it, and
+// panics if the name is taken.
+func RegisterInterpreter(name string, constructor InterpreterConstructor) {
+	interpretersMux.Lock()
+	defer interpretersMux.Unlock()
+
+	if _, ok := interpreters[name]; ok {
+		panic(fmt.Sprintf("interpreter %q already registered", name))
+	}
+	interpreters[name] = constructor
+}
+
+// LookupInterpreter returns the constructor of the interpreter registered with
+// the given name, ignoring any options following a colon.
+func LookupInterpreter(name string) (InterpreterConstructor, bool) {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		name = name[:i]
+	}
+	interpretersMux.RLock()
+	defer interpretersMux.RUnlock()
+
+	constructor, ok := interpreters[name]
+	return constructor, ok
+}
+
 // callCtx contains the things that are per-call, such as stack and memory,
 // but not transients like pc and gas
 type callCtx struct {
@@ -73,7 +117,6 @@ type EVMInterpreter struct {
 	hasher    keccakState // Keccak256 hasher instance shared across opcodes
 	hasherBuf common.Hash // Keccak256 hasher result array shared aross opcodes
 
-	readOnly   bool   // Whether to throw on stateful modifications
 	returnData []byte // Last CALL's return data for subsequent reuse
 }
 
@@ -132,9 +175,9 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 
 	// Make sure the readOnly is only set if we aren't in readOnly yet.
 	// This makes also sure that the readOnly flag isn't removed for child calls.
-	if readOnly && !in.readOnly {
-		in.readOnly = true
-		defer func() { in.readOnly = false }()
+	if readOnly && !in.evm.readOnly {
+		in.evm.readOnly = true
+		defer func() { in.evm.readOnly = false }()
 	}
 
 	// Reset the previous call's return data. It's unimportant to preserve the old buffer
@@ -217,7 +260,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 			return nil, &ErrStackOverflow{stackLen: sLen, limit: operation.maxStack}
 		}
 		// If the operation is valid, enforce and write restrictions
-		if in.readOnly && in.evm.chainRules.IsByzantium {
+		if in.evm.readOnly && in.evm.chainRules.IsByzantium {
 			// If the interpreter is operating in readonly mode, make sure no
 			// state-modifying operation is performed. The 3rd stack item
 			// for a call operation is the value. Transferring value from one
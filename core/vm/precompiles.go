@@ -0,0 +1,215 @@
+
+
+package vm
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// errStatefulPrecompile is returned if a stateful precompiled contract is run
+// without the context of a call.
+var errStatefulPrecompile = errors.New("stateful precompile run without context")
+
+// PrecompileContext is the context of a call to a stateful precompiled contract.
+type PrecompileContext struct {
+	StateDB StateDB       // State the call runs on
+	Block   *BlockContext // Block the call is executed in
+
+	Caller   common.Address // Address of the caller
+	Address  common.Address // Address whose storage the call runs on, the caller's for CALLCODE and DELEGATECALL
+	Value    *big.Int       // Value transferred along with the call
+	ReadOnly bool           // Whether state modifications are disallowed
+}
+
+// StatefulPrecompiledContract is a native Go contract with access to the state
+// and the context of the call, e.g. to keep storage of its own.
+type StatefulPrecompiledContract interface {
+	RequiredGas(input []byte) uint64                          // RequiredGas calculates the contract gas use
+	Run(ctx *PrecompileContext, input []byte) ([]byte, error) // Run runs the precompiled contract in the given context
+}
+
+// statefulPrecompile adapts a stateful precompiled contract to the stateless
+// interface, so that it can be kept along the built in precompiled contracts.
+type statefulPrecompile struct {
+	contract StatefulPrecompiledContract
+}
+
+func (p *statefulPrecompile) RequiredGas(input []byte) uint64 {
+	return p.contract.RequiredGas(input)
+}
+
+func (p *statefulPrecompile) Run(input []byte) ([]byte, error) {
+	return nil, errStatefulPrecompile
+}
+
+var (
+	registry     = make(map[string]PrecompiledContract) // Custom precompiled contracts by name
+	registryLock sync.RWMutex
+)
+
+func init() {
+	RegisterPrecompile("ed25519Verify", &ed25519Verify{})
+}
+
+// RegisterPrecompile registers a custom precompiled contract by name, to be
+// enabled by chain configurations at the address and block they specify. It
+// panics if a precompiled contract was already registered with the name.
+func RegisterPrecompile(name string, p PrecompiledContract) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("precompile %q already registered", name))
+	}
+	registry[name] = p
+}
+
+// RegisterStatefulPrecompile registers a custom precompiled contract accessing
+// the state by name, like RegisterPrecompile.
+func RegisterStatefulPrecompile(name string, p StatefulPrecompiledContract) {
+	RegisterPrecompile(name, &statefulPrecompile{contract: p})
+}
+
+// CheckPrecompiles checks that the custom precompiled contracts enabled by the
+// chain configuration are registered, and that their addresses don't collide
+// with each other or with the built in precompiled contracts.
+func CheckPrecompiles(config *params.ChainConfig) error {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	names := make([]string, 0, len(config.Precompiles))
+	for name := range config.Precompiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	addrs := make(map[common.Address]string)
+	for _, name := range names {
+		p := config.Precompiles[name]
+		if p == nil {
+			return fmt.Errorf("precompile %q: missing configuration", name)
+		}
+		if _, ok := registry[name]; !ok {
+			return fmt.Errorf("precompile %q: not registered", name)
+		}
+		if _, ok := PrecompiledContractsYoloV2[p.Address]; ok {
+			return fmt.Errorf("precompile %q: address %x taken by a built in precompile", name, p.Address)
+		}
+		if other, ok := addrs[p.Address]; ok {
+			return fmt.Errorf("precompile %q: address %x taken by precompile %q", name, p.Address, other)
+		}
+		addrs[p.Address] = name
+	}
+	return nil
+}
+
+// activePrecompiles returns the precompiled contracts active at the given block,
+// the built in ones of the fork extended with the custom ones of the chain.
+func activePrecompiles(config *params.ChainConfig, rules params.Rules, num *big.Int) (map[common.Address]PrecompiledContract, []common.Address) {
+	var (
+		precompiles map[common.Address]PrecompiledContract
+		addrs       []common.Address
+	)
+	switch {
+	case rules.IsYoloV2:
+		precompiles, addrs = PrecompiledContractsYoloV2, PrecompiledAddressesYoloV2
+	case rules.IsIstanbul:
+		precompiles, addrs = PrecompiledContractsIstanbul, PrecompiledAddressesIstanbul
+	case rules.IsByzantium:
+		precompiles, addrs = PrecompiledContractsByzantium, PrecompiledAddressesByzantium
+	default:
+		precompiles, addrs = PrecompiledContractsHomestead, PrecompiledAddressesHomestead
+	}
+	if len(config.Precompiles) == 0 {
+		return precompiles, addrs
+	}
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	names := make([]string, 0, len(config.Precompiles))
+	for name := range config.Precompiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var custom map[common.Address]PrecompiledContract
+	for _, name := range names {
+		p := config.Precompiles[name]
+		contract, ok := registry[name]
+		if !ok || !config.IsPrecompileActive(name, num) {
+			continue
+		}
+		if custom == nil {
+			custom = make(map[common.Address]PrecompiledContract, len(precompiles)+len(config.Precompiles))
+			for addr, contract := range precompiles {
+				custom[addr] = contract
+			}
+			addrs = append([]common.Address{}, addrs...)
+		}
+		if _, ok := custom[p.Address]; !ok {
+			addrs = append(addrs, p.Address)
+		}
+		custom[p.Address] = contract
+	}
+	if custom == nil {
+		return precompiles, addrs
+	}
+	return custom, addrs
+}
+
+// runPrecompile runs a precompiled contract, providing stateful ones with the
+// context of the call.
+func (evm *EVM) runPrecompile(p PrecompiledContract, ctx *PrecompileContext, input []byte, gas uint64) (ret []byte, remainingGas uint64, err error) {
+	stateful, ok := p.(*statefulPrecompile)
+	if !ok {
+		return RunPrecompiledContract(p, input, gas)
+	}
+	gasCost := stateful.RequiredGas(input)
+	if gas < gasCost {
+		return nil, 0, ErrOutOfGas
+	}
+	gas -= gasCost
+
+	// Calls from within a static call can't modify the state either
+	ctx.ReadOnly = ctx.ReadOnly || evm.readOnly
+	ctx.StateDB, ctx.Block = evm.StateDB, &evm.Context
+	output, err := stateful.contract.Run(ctx, input)
+	return output, gas, err
+}
+
+// ed25519Verify implements Ed25519 signature verification as a native contract.
+// Its input is the 32 byte public key, followed by the 64 byte signature and the
+// signed message, and it returns 1 as a 32 byte word if the signature is valid,
+// 0 otherwise.
+type ed25519Verify struct{}
+
+func (c *ed25519Verify) RequiredGas(input []byte) uint64 {
+	var msg int
+	if len(input) > ed25519.PublicKeySize+ed25519.SignatureSize {
+		msg = len(input) - ed25519.PublicKeySize - ed25519.SignatureSize
+	}
+	return uint64(msg+31)/32*params.Ed25519VerifyPerWordGas + params.Ed25519VerifyBaseGas
+}
+
+func (c *ed25519Verify) Run(input []byte) ([]byte, error) {
+	if len(input) < ed25519.PublicKeySize+ed25519.SignatureSize {
+		return common.LeftPadBytes(nil, 32), nil
+	}
+	var (
+		key = input[:ed25519.PublicKeySize]
+		sig = input[ed25519.PublicKeySize : ed25519.PublicKeySize+ed25519.SignatureSize]
+		msg = input[ed25519.PublicKeySize+ed25519.SignatureSize:]
+	)
+	if ed25519.Verify(key, msg, sig) {
+		return common.LeftPadBytes([]byte{1}, 32), nil
+	}
+	return common.LeftPadBytes(nil, 32), nil
+}
@@ -0,0 +1,122 @@
+
+
+package vm
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// storePrecompile is a stateful precompiled contract storing its input in the
+// storage of the called address, and returning the previously stored value.
+type storePrecompile struct{}
+
+func (p *storePrecompile) RequiredGas(input []byte) uint64 {
+	return 100
+}
+
+func (p *storePrecompile) Run(ctx *PrecompileContext, input []byte) ([]byte, error) {
+	prev := ctx.StateDB.GetState(ctx.Address, common.Hash{})
+	if ctx.ReadOnly {
+		return nil, ErrWriteProtection
+	}
+	ctx.StateDB.SetState(ctx.Address, common.Hash{}, common.BytesToHash(input))
+	return prev[:], nil
+}
+
+func init() {
+	RegisterStatefulPrecompile("testStore", &storePrecompile{})
+}
+
+// Tests that custom precompiled contracts enabled in the chain configuration are
+// active from their activation block, and that stateful ones run on the state.
+func TestCustomPrecompiles(t *testing.T) {
+	var config params.ChainConfig
+	if err := json.Unmarshal([]byte(`{
+		"chainId": 1,
+		"byzantiumBlock": 0,
+		"precompiles": {
+			"ed25519Verify": {"address": "0x0000000000000000000000000000000000000100", "block": 0},
+			"testStore": {"address": "0x0000000000000000000000000000000000000101", "block": 10}
+		}
+	}`), &config); err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	if err := CheckPrecompiles(&config); err != nil {
+		t.Fatalf("config rejected: %v", err)
+	}
+	var (
+		verifier = common.BytesToAddress([]byte{0x01, 0x00})
+		store    = common.BytesToAddress([]byte{0x01, 0x01})
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	newEVM := func(number int64) *EVM {
+		vmctx := BlockContext{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			BlockNumber: big.NewInt(number),
+		}
+		return NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+	}
+	// Before the activation block, only the built in and the verifier are active
+	evm := newEVM(9)
+	if _, ok := evm.precompile(store); ok {
+		t.Errorf("precompile active before its activation block")
+	}
+	if have, want := len(evm.ActivePrecompiles()), len(PrecompiledAddressesByzantium)+1; have != want {
+		t.Errorf("active precompile count mismatch: have %d, want %d", have, want)
+	}
+	pub, priv, _ := ed25519.GenerateKey(bytes.NewReader(make([]byte, 32)))
+	msg := []byte("hello")
+	input := append(append(append([]byte{}, pub...), ed25519.Sign(priv, msg)...), msg...)
+
+	ret, gas, err := evm.Call(AccountRef(common.Address{}), verifier, input, 10000, new(big.Int))
+	if err != nil || !bytes.Equal(ret, common.LeftPadBytes([]byte{1}, 32)) {
+		t.Errorf("signature verification failed: %x, %v", ret, err)
+	}
+	if want := 10000 - params.Ed25519VerifyBaseGas - params.Ed25519VerifyPerWordGas; gas != want {
+		t.Errorf("verification gas mismatch: have %d, want %d", gas, want)
+	}
+	input[len(input)-1] ^= 0xff
+	if ret, _, _ := evm.Call(AccountRef(common.Address{}), verifier, input, 10000, new(big.Int)); !bytes.Equal(ret, make([]byte, 32)) {
+		t.Errorf("invalid signature accepted: %x", ret)
+	}
+	// From the activation block, the stateful one runs on the state
+	evm = newEVM(10)
+	if _, _, err := evm.Call(AccountRef(common.Address{}), store, []byte{0x42}, 10000, new(big.Int)); err != nil {
+		t.Fatalf("stateful call failed: %v", err)
+	}
+	if have := statedb.GetState(store, common.Hash{}); have != common.BytesToHash([]byte{0x42}) {
+		t.Errorf("stored value mismatch: have %x, want 42", have)
+	}
+	if _, _, err := evm.StaticCall(AccountRef(common.Address{}), store, []byte{0x43}, 10000); err != ErrWriteProtection {
+		t.Errorf("static call error mismatch: have %v, want %v", err, ErrWriteProtection)
+	}
+	if have := statedb.GetState(store, common.Hash{}); have != common.BytesToHash([]byte{0x42}) {
+		t.Errorf("stored value modified by static call: %x", have)
+	}
+}
+
+// Tests that chain configurations enabling unknown or colliding precompiled
+// contracts are rejected.
+func TestCheckPrecompiles(t *testing.T) {
+	tests := []map[string]*params.PrecompileConfig{
+		{"unknown": {Address: common.Address{0xff}}},
+		{"ed25519Verify": {Address: common.BytesToAddress([]byte{1})}},
+		{"ed25519Verify": {Address: common.Address{0xff}}, "testStore": {Address: common.Address{0xff}}},
+		{"ed25519Verify": nil},
+	}
+	for i, precompiles := range tests {
+		if err := CheckPrecompiles(&params.ChainConfig{Precompiles: precompiles}); err == nil {
+			t.Errorf("test %d: invalid configuration accepted", i)
+		}
+	}
+}
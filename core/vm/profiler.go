@@ -0,0 +1,308 @@
+
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OpcodeProfile is the gas and time spent executing an opcode.
+type OpcodeProfile struct {
+	Op    string        `json:"op"`
+	Count uint64        `json:"count"` // Number of times the opcode was executed
+	Gas   uint64        `json:"gas"`   // Gas used, excluding the calls made by the opcode
+	Time  time.Duration `json:"time"`  // Time spent in nanoseconds, excluding the calls made by the opcode
+}
+
+// ContractProfile is the gas and time spent executing the code of a contract.
+type ContractProfile struct {
+	Address common.Address `json:"address"`
+	Calls   uint64         `json:"calls"` // Number of call frames executing the code
+	Gas     uint64         `json:"gas"`   // Gas used, excluding the calls to other contracts
+	Time    time.Duration  `json:"time"`  // Time spent in nanoseconds, excluding the calls to other contracts
+}
+
+// StackProfile is the gas and time spent executing an opcode in a call path.
+type StackProfile struct {
+	Stack string        `json:"stack"` // Addresses of the call path and the opcode, separated by semicolons
+	Gas   uint64        `json:"gas"`
+	Time  time.Duration `json:"time"`
+}
+
+// Profile is the gas and time spent by an execution, aggregated per opcode, per
+// contract and per call path.
+type Profile struct {
+	Gas       uint64             `json:"gas"`       // Gas used by the execution
+	Time      time.Duration      `json:"time"`      // Duration of the execution in nanoseconds
+	Opcodes   []*OpcodeProfile   `json:"opcodes"`   // Opcodes by decreasing gas
+	Contracts []*ContractProfile `json:"contracts"` // Contracts by decreasing gas
+	Stacks    []*StackProfile    `json:"stacks"`    // Call paths sorted by their stack
+}
+
+// profileFrame is a call frame being profiled.
+type profileFrame struct {
+	path string         // Call path of the frame, in collapsed stack format
+	addr common.Address // Address of the executing code
+
+	op     OpCode    // Last executed opcode, pending the accounting of its gas and time
+	gas    uint64    // Gas available before the last opcode
+	cost   uint64    // Cost of the last opcode
+	start  time.Time // Time the last opcode started executing
+	failed bool      // Whether the frame failed, consuming all the gas it had
+
+	childGas  uint64        // Gas used by the calls made by the last opcode
+	childTime time.Duration // Time spent in the calls made by the last opcode
+
+	usedGas  uint64        // Gas used by the frame so far, including its calls
+	usedTime time.Duration // Time spent in the frame so far, including its calls
+}
+
+// Profiler is an EVM tracer aggregating the gas and time spent by an execution
+// per opcode, per contract and per call path. The gas of an opcode is computed
+// from the gas available before the next one in the same frame, so that it also
+// accounts for refunded call gas, with the gas used by the calls it makes taken
+// out and attributed to the called frames.
+type Profiler struct {
+	frames []*profileFrame
+
+	opcodes   map[OpCode]*OpcodeProfile
+	contracts map[common.Address]*ContractProfile
+	stacks    map[string]*StackProfile
+
+	gas  uint64        // Gas used by the execution, once finished
+	time time.Duration // Duration of the execution, once finished
+
+	interrupt uint32 // Atomic flag to signal execution interruption
+	reason    error  // Textual reason for the interruption
+}
+
+// NewProfiler creates a new EVM tracer profiling the gas and time spent executing.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		opcodes:   make(map[OpCode]*OpcodeProfile),
+		contracts: make(map[common.Address]*ContractProfile),
+		stacks:    make(map[string]*StackProfile),
+	}
+}
+
+// CaptureStart implements the Tracer interface to initialize the tracing operation.
+func (p *Profiler) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+// CaptureState implements the Tracer interface to trace a single step of VM execution.
+func (p *Profiler) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, rData []byte, contract *Contract, depth int, err error) error {
+	if atomic.LoadUint32(&p.interrupt) > 0 {
+		return nil
+	}
+	now := time.Now()
+
+	// Account the frames returned from, and the previous opcode of the current one
+	for len(p.frames) > depth {
+		p.leave(now)
+	}
+	if len(p.frames) < depth {
+		p.enter(contract)
+	} else {
+		frame := p.frames[len(p.frames)-1]
+		p.account(frame, frame.gas-gas, now)
+	}
+	frame := p.frames[len(p.frames)-1]
+	frame.op, frame.gas, frame.cost, frame.start = op, gas, cost, now
+
+	// An error before the execution of the opcode consumes all the gas of the frame
+	if err != nil {
+		frame.failed = true
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface to trace an execution fault
+// while running an opcode.
+func (p *Profiler) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, rStack *ReturnStack, contract *Contract, depth int, err error) error {
+	if len(p.frames) > 0 && err != ErrExecutionReverted {
+		p.frames[len(p.frames)-1].failed = true
+	}
+	return nil
+}
+
+// CaptureEnd is called after the call finishes to finalize the tracing.
+func (p *Profiler) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	now := time.Now()
+	for len(p.frames) > 0 {
+		p.leave(now)
+	}
+	p.gas, p.time = gasUsed, t
+	return nil
+}
+
+// enter starts profiling a new call frame executing the code of the contract.
+func (p *Profiler) enter(contract *Contract) {
+	addr := contract.Address()
+	if contract.CodeAddr != nil {
+		addr = *contract.CodeAddr
+	}
+	path := addr.Hex()
+	if len(p.frames) > 0 {
+		path = p.frames[len(p.frames)-1].path + ";" + path
+	}
+	p.frames = append(p.frames, &profileFrame{path: path, addr: addr})
+
+	stats, ok := p.contracts[addr]
+	if !ok {
+		stats = &ContractProfile{Address: addr}
+		p.contracts[addr] = stats
+	}
+	stats.Calls++
+}
+
+// leave finishes profiling the innermost call frame, accounting its last opcode
+// and its total gas and time to the calling opcode of its parent frame.
+func (p *Profiler) leave(now time.Time) {
+	frame := p.frames[len(p.frames)-1]
+	p.frames = p.frames[:len(p.frames)-1]
+
+	if frame.failed {
+		p.account(frame, frame.gas, now)
+	} else {
+		p.account(frame, frame.cost, now)
+	}
+	if len(p.frames) > 0 {
+		parent := p.frames[len(p.frames)-1]
+		parent.childGas += frame.usedGas
+		parent.childTime += frame.usedTime
+	}
+}
+
+// account attributes the gas used by the last opcode of a frame and the time
+// spent executing it, minus the gas and time of the calls it made.
+func (p *Profiler) account(frame *profileFrame, gas uint64, now time.Time) {
+	elapsed := now.Sub(frame.start)
+	frame.usedGas += gas
+	frame.usedTime += elapsed
+
+	if gas > frame.childGas {
+		gas -= frame.childGas
+	} else {
+		gas = 0
+	}
+	if elapsed > frame.childTime {
+		elapsed -= frame.childTime
+	} else {
+		elapsed = 0
+	}
+	frame.childGas, frame.childTime = 0, 0
+
+	opStats, ok := p.opcodes[frame.op]
+	if !ok {
+		opStats = &OpcodeProfile{Op: frame.op.String()}
+		p.opcodes[frame.op] = opStats
+	}
+	opStats.Count++
+	opStats.Gas += gas
+	opStats.Time += elapsed
+
+	contractStats := p.contracts[frame.addr]
+	contractStats.Gas += gas
+	contractStats.Time += elapsed
+
+	stack := frame.path + ";" + frame.op.String()
+	stackStats, ok := p.stacks[stack]
+	if !ok {
+		stackStats = &StackProfile{Stack: stack}
+		p.stacks[stack] = stackStats
+	}
+	stackStats.Gas += gas
+	stackStats.Time += elapsed
+}
+
+// Profile returns the profile of the execution traced.
+func (p *Profiler) Profile() *Profile {
+	profile := &Profile{
+		Gas:       p.gas,
+		Time:      p.time,
+		Opcodes:   make([]*OpcodeProfile, 0, len(p.opcodes)),
+		Contracts: make([]*ContractProfile, 0, len(p.contracts)),
+		Stacks:    make([]*StackProfile, 0, len(p.stacks)),
+	}
+	for _, stats := range p.opcodes {
+		entry := *stats
+		profile.Opcodes = append(profile.Opcodes, &entry)
+	}
+	sort.Slice(profile.Opcodes, func(i, j int) bool {
+		a, b := profile.Opcodes[i], profile.Opcodes[j]
+		if a.Gas != b.Gas {
+			return a.Gas > b.Gas
+		}
+		return a.Op < b.Op
+	})
+	for _, stats := range p.contracts {
+		entry := *stats
+		profile.Contracts = append(profile.Contracts, &entry)
+	}
+	sort.Slice(profile.Contracts, func(i, j int) bool {
+		a, b := profile.Contracts[i], profile.Contracts[j]
+		if a.Gas != b.Gas {
+			return a.Gas > b.Gas
+		}
+		return bytes.Compare(a.Address[:], b.Address[:]) < 0
+	})
+	for _, stats := range p.stacks {
+		entry := *stats
+		profile.Stacks = append(profile.Stacks, &entry)
+	}
+	sort.Slice(profile.Stacks, func(i, j int) bool {
+		return profile.Stacks[i].Stack < profile.Stacks[j].Stack
+	})
+	return profile
+}
+
+// Stop terminates the profiling, the result reporting the given error instead.
+func (p *Profiler) Stop(err error) {
+	p.reason = err
+	atomic.StoreUint32(&p.interrupt, 1)
+}
+
+// GetResult returns the JSON encoded profile of the execution, or the reason the
+// profiling was stopped.
+func (p *Profiler) GetResult() (json.RawMessage, error) {
+	if atomic.LoadUint32(&p.interrupt) > 0 {
+		return nil, p.reason
+	}
+	return json.Marshal(p.Profile())
+}
+
+// WriteProfile writes a formatted summary of the profile to the given writer.
+func WriteProfile(writer io.Writer, profile *Profile) {
+	fmt.Fprintf(writer, "Gas used: %d, execution time: %v\n\n", profile.Gas, profile.Time)
+
+	fmt.Fprintf(writer, "%-16s %10s %12s %14s\n", "OPCODE", "COUNT", "GAS", "TIME")
+	for _, op := range profile.Opcodes {
+		fmt.Fprintf(writer, "%-16s %10d %12d %14v\n", op.Op, op.Count, op.Gas, op.Time)
+	}
+	fmt.Fprintln(writer)
+
+	fmt.Fprintf(writer, "%-42s %10s %12s %14s\n", "CONTRACT", "CALLS", "GAS", "TIME")
+	for _, contract := range profile.Contracts {
+		fmt.Fprintf(writer, "%-42s %10d %12d %14v\n", contract.Address.Hex(), contract.Calls, contract.Gas, contract.Time)
+	}
+}
+
+// WriteCollapsedStacks writes the gas used per call path of the profile in the
+// collapsed stack format of flame graph tools, skipping the paths without gas.
+func WriteCollapsedStacks(writer io.Writer, profile *Profile) {
+	for _, stack := range profile.Stacks {
+		if stack.Gas > 0 {
+			fmt.Fprintf(writer, "%s %d\n", stack.Stack, stack.Gas)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the profiler attributes all the gas used to the executed opcodes,
+// contracts and call paths, with the gas of the calls taken out of the calling
+// opcodes, including calls failing with all their gas consumed.
+func TestProfiler(t *testing.T) {
+	var (
+		caller = common.HexToAddress("fx1000")
+		store  = common.HexToAddress("fx2000")
+		broken = common.HexToAddress("fx3000")
+	)
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(store, common.FromHex("fx600160005500")) // PUSH1 1, PUSH1 0, SSTORE, STOP
+	statedb.SetCode(broken, common.FromHex("fx600356"))      // PUSH1 3, JUMP (invalid destination)
+
+	// PUSH1 0, DUP1 x4, PUSH20 address, GAS, CALL, POP for both contracts, then STOP
+	var code []byte
+	for _, addr := range []common.Address{store, broken} {
+		code = append(code, common.FromHex("fx600080808080")...)
+		code = append(code, byte(PUSH20))
+		code = append(code, addr.Bytes()...)
+		code = append(code, byte(GAS), byte(CALL), byte(POP))
+	}
+	statedb.SetCode(caller, append(code, byte(STOP)))
+
+	profiler := NewProfiler()
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		BlockNumber: new(big.Int),
+	}
+	vmenv := NewEVM(vmctx, TxContext{}, statedb, params.AllEthashProtocolChanges, Config{Debug: true, Tracer: profiler})
+	if _, _, err := vmenv.Call(AccountRef(common.Address{}), caller, nil, 1000000, new(big.Int)); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	profile := profiler.Profile()
+	if profile.Gas == 0 {
+		t.Fatalf("no gas used")
+	}
+	var opGas, contractGas, stackGas uint64
+	for _, op := range profile.Opcodes {
+		opGas += op.Gas
+	}
+	for _, contract := range profile.Contracts {
+		contractGas += contract.Gas
+		if contract.Calls != 1 {
+			t.Errorf("contract %x: call count mismatch: have %d, want 1", contract.Address, contract.Calls)
+		}
+	}
+	stacks := make(map[string]uint64)
+	for _, stack := range profile.Stacks {
+		stackGas += stack.Gas
+		stacks[stack.Stack] = stack.Gas
+	}
+	if opGas != profile.Gas || contractGas != profile.Gas || stackGas != profile.Gas {
+		t.Errorf("gas mismatch: have %d per opcode, %d per contract, %d per call path, want %d", opGas, contractGas, stackGas, profile.Gas)
+	}
+	// The calls are only charged for themselves, the failing one consuming most of the gas
+	var (
+		storePath  = caller.Hex() + ";" + store.Hex()
+		brokenPath = caller.Hex() + ";" + broken.Hex()
+	)
+	if gas := stacks[storePath+";SSTORE"]; gas < params.SstoreSetGasEIP2200 {
+		t.Errorf("SSTORE gas too low: have %d, want at least %d", gas, params.SstoreSetGasEIP2200)
+	}
+	if gas := stacks[caller.Hex()+";CALL"]; gas > 2*params.CallNewAccountGas {
+		t.Errorf("CALL gas includes the called frames: %d", gas)
+	}
+	if gas := stacks[brokenPath+";JUMP"]; gas < profile.Gas/2 {
+		t.Errorf("failing JUMP gas too low: have %d, total %d", gas, profile.Gas)
+	}
+	if profile.Opcodes[0].Op != "JUMP" || profile.Contracts[0].Address != broken {
+		t.Errorf("ordering mismatch: top opcode %s, top contract %x", profile.Opcodes[0].Op, profile.Contracts[0].Address)
+	}
+	var out bytes.Buffer
+	WriteCollapsedStacks(&out, profile)
+	if want := storePath + ";SSTORE "; !strings.Contains(out.String(), want) {
+		t.Errorf("collapsed stacks missing %q:\n%s", want, out.String())
+	}
+	if strings.Contains(out.String(), ";STOP ") {
+		t.Errorf("collapsed stacks contain gasless paths:\n%s", out.String())
+	}
+	// Stopping the profiler reports the reason instead of the result
+	profiler.Stop(errors.New("timeout"))
+	if _, err := profiler.GetResult(); err == nil || err.Error() != "timeout" {
+		t.Errorf("interrupted result error mismatch: have %v, want timeout", err)
+	}
+}
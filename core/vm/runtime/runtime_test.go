@@ -110,6 +110,136 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// ewasmStore is a WebAssembly contract storing 42 at slot 1 and returning it, or
+// reverting with it if called with any input:
+//
+//	(module
+//	  (import "ethereum" "storageStore" (func $storageStore (param i32 i32)))
+//	  (import "ethereum" "finish" (func $finish (param i32 i32)))
+//	  (import "ethereum" "revert" (func $revert (param i32 i32)))
+//	  (import "ethereum" "getCallDataSize" (func $getCallDataSize (result i32)))
+//	  (memory (export "memory") 1)
+//	  (data (i32.const 0) "\00...\01" "\00...\2a") ;; 32 byte key and value
+//	  (func (export "main")
+//	    (if (call $getCallDataSize)
+//	      (then (call $revert (i32.const 32) (i32.const 32))))
+//	    (call $storageStore (i32.const 0) (i32.const 32))
+//	    (call $finish (i32.const 32) (i32.const 32))))
+var ewasmStore = common.Hex2Bytes("0061736d01000000" +
+	"010d0360027f7f006000017f600000" + // types
+	"02580408657468657265756d0c73746f7261676553746f7265000008657468657265756d0666696e697368" +
+	"000008657468657265756d06726576657274000008657468657265756d0f67657443616c6c4461746153697a650001" + // imports
+	"03020102" + // functions
+	"0503010001" + // memory
+	"071102046d61696e0004066d656d6f72790200" + // exports
+	"0a1b011900100304404120412010020b4100412010004120412010010b0b" + // code
+	"46010041000b40" + // data
+	"0000000000000000000000000000000000000000000000000000000000000001" +
+	"000000000000000000000000000000000000000000000000000000000000002a")
+
+func TestEWASM(t *testing.T) {
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+
+	ret, state, err := Execute(ewasmStore, nil, &Config{ChainConfig: &config})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if num := new(big.Int).SetBytes(ret); num.Cmp(big.NewInt(42)) != 0 {
+		t.Error("Expected 42, got", num)
+	}
+	address := common.BytesToAddress([]byte("contract"))
+	if value := state.GetState(address, common.BigToHash(big.NewInt(1))); value.Big().Cmp(big.NewInt(42)) != 0 {
+		t.Error("Expected 42 in storage, got", value.Big())
+	}
+	// Reverts return the data, but don't modify the state
+	ret, state, err = Execute(ewasmStore, []byte{1}, &Config{ChainConfig: &config})
+	if err != vm.ErrExecutionReverted {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrExecutionReverted)
+	}
+	if num := new(big.Int).SetBytes(ret); num.Cmp(big.NewInt(42)) != 0 {
+		t.Error("Expected 42, got", num)
+	}
+	if value := state.GetState(address, common.BigToHash(big.NewInt(1))); value != (common.Hash{}) {
+		t.Error("Expected empty storage, got", value.Big())
+	}
+	// Running out of gas while storing fails the execution
+	if _, _, err = Execute(ewasmStore, nil, &Config{ChainConfig: &config, GasLimit: params.EWASMMemoryPageGas + 10000}); err != vm.ErrOutOfGas {
+		t.Fatalf("error mismatch: have %v, want %v", err, vm.ErrOutOfGas)
+	}
+	// EVM contracts still run on the built-in interpreter
+	ret, _, err = Execute([]byte{
+		byte(vm.PUSH1), 10,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	}, nil, &Config{ChainConfig: &config})
+	if err != nil {
+		t.Fatal("didn't expect error", err)
+	}
+	if num := new(big.Int).SetBytes(ret); num.Cmp(big.NewInt(10)) != 0 {
+		t.Error("Expected 10, got", num)
+	}
+}
+
+// Tests that the static mode of a call holds when the call tree switches between
+// the EVM and the WebAssembly interpreter (EIP-214).
+func TestEWASMStaticCall(t *testing.T) {
+	config := *params.TestChainConfig
+	config.EWASMBlock = new(big.Int)
+
+	var (
+		ewasmAddr = common.BytesToAddress([]byte("ewasm"))
+		proxyAddr = common.BytesToAddress([]byte("proxy"))
+	)
+	// The proxy calls the ewasm store without value, returning the success flag
+	proxy := append([]byte{
+		byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+		byte(vm.PUSH20)}, ewasmAddr.Bytes()...)
+	proxy = append(proxy,
+		byte(vm.GAS), byte(vm.CALL),
+		byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	)
+	// The caller statically calls the proxy, returning the proxy's output
+	caller := append([]byte{
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0,
+		byte(vm.PUSH20)}, proxyAddr.Bytes()...)
+	caller = append(caller,
+		byte(vm.GAS), byte(vm.STATICCALL), byte(vm.POP),
+		byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	)
+	newState := func() *state.StateDB {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.SetCode(ewasmAddr, ewasmStore)
+		statedb.SetCode(proxyAddr, proxy)
+		return statedb
+	}
+	slot := common.BigToHash(big.NewInt(1))
+
+	// Called normally, the proxy lets the ewasm contract store
+	ret, statedb, err := Execute(proxy, nil, &Config{ChainConfig: &config, State: newState()})
+	if err != nil {
+		t.Fatalf("proxy call failed: %v", err)
+	}
+	if new(big.Int).SetBytes(ret).Uint64() != 1 || statedb.GetState(ewasmAddr, slot).Big().Uint64() != 42 {
+		t.Fatalf("proxy call mismatch: success %x, slot %x", ret, statedb.GetState(ewasmAddr, slot))
+	}
+	// Called from within a static call, the store must fail
+	ret, statedb, err = Execute(caller, nil, &Config{ChainConfig: &config, State: newState()})
+	if err != nil {
+		t.Fatalf("static call failed: %v", err)
+	}
+	if new(big.Int).SetBytes(ret).Sign() != 0 {
+		t.Errorf("ewasm store succeeded within a static call")
+	}
+	if value := statedb.GetState(ewasmAddr, slot); value != (common.Hash{}) {
+		t.Errorf("storage modified within a static call: %x", value)
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
@@ -0,0 +1,215 @@
+
+
+// Package srcmap maps EVM execution traces to the sources of the executed
+// contracts, using the source mappings produced by the Solidity compiler.
+package srcmap
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// contract is a known contract the executed code can be mapped to.
+type contract struct {
+	name   string
+	mapper *compiler.SourceMapper
+}
+
+// Mapper identifies the executed contracts and maps their instructions to the
+// sources. Contracts are identified by their runtime code with the immediate
+// data of the push instructions ignored, so that linked libraries and immutable
+// values don't prevent matching the deployed code against the compiler output.
+type Mapper struct {
+	contracts map[common.Hash]*contract
+}
+
+// NewMapper creates a mapper without any known contracts.
+func NewMapper() *Mapper {
+	return &Mapper{contracts: make(map[common.Hash]*contract)}
+}
+
+// FromCombinedJSON creates a mapper for all the contracts of a solc --combined-json
+// output. The sources are looked up in the given map, or read from the file system
+// if it's nil.
+func FromCombinedJSON(combinedJSON []byte, sources map[string]string) (*Mapper, error) {
+	contracts, err := compiler.ParseCombinedJSON(combinedJSON, "", "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	m := NewMapper()
+	for name, c := range contracts {
+		// Interfaces and abstract contracts don't have any code to map
+		if c.RuntimeCode == "0x" || c.Info.SrcMapRuntime == "" {
+			continue
+		}
+		if err := m.AddContract(name, c, sources); err != nil {
+			return nil, fmt.Errorf("contract %s: %v", name, err)
+		}
+	}
+	return m, nil
+}
+
+// AddContract adds a compiled contract to the known ones.
+func (m *Mapper) AddContract(name string, c *compiler.Contract, sources map[string]string) error {
+	code, err := compiler.DecodeLinkedCode(c.RuntimeCode)
+	if err != nil {
+		return err
+	}
+	mapper, err := c.SourceMapper(sources)
+	if err != nil {
+		return err
+	}
+	m.contracts[skeletonHash(code)] = &contract{name: name, mapper: mapper}
+	return nil
+}
+
+// Add adds a contract to the known ones, given its runtime code, the source
+// mapping of it and the files of the source list.
+func (m *Mapper) Add(name string, code []byte, srcmap string, sources []compiler.SourceFile) error {
+	mapper, err := compiler.NewSourceMapper(code, srcmap, sources)
+	if err != nil {
+		return err
+	}
+	m.contracts[skeletonHash(code)] = &contract{name: name, mapper: mapper}
+	return nil
+}
+
+// lookup returns the known contract with the given code, if any.
+func (m *Mapper) lookup(code []byte) *contract {
+	if len(code) == 0 {
+		return nil
+	}
+	return m.contracts[skeletonHash(code)]
+}
+
+// skeletonHash hashes the code with the immediate data of the push instructions
+// zeroed out.
+func skeletonHash(code []byte) common.Hash {
+	skeleton := make([]byte, len(code))
+	for pc := 0; pc < len(code); pc++ {
+		skeleton[pc] = code[pc]
+		if op := vm.OpCode(code[pc]); op.IsPush() {
+			pc += int(op - vm.PUSH1 + 1)
+		}
+	}
+	return crypto.Keccak256Hash(skeleton)
+}
+
+// Step is a structured log of an execution step, along with the source location
+// its instruction was generated from.
+type Step struct {
+	vm.StructLog
+	Contract string                   // Name of the executing contract, empty if unknown
+	Source   *compiler.SourceLocation // Location of the instruction, nil if unmapped
+}
+
+// Trace is an execution trace mapped to the sources.
+type Trace struct {
+	Steps   []*Step
+	Reverts []int // Indexes of the steps reverting or failing their call frame
+}
+
+// Tracer is a vm.StructLogger additionally recording the contract executing each
+// step, so that the trace can be mapped to the sources.
+type Tracer struct {
+	*vm.StructLogger
+
+	mapper    *Mapper
+	contracts []*contract // Known contract executing each step
+
+	lastCode     []byte    // Code of the last step, to avoid identifying it at every step
+	lastContract *contract // Known contract matching the last code
+}
+
+// NewTracer creates a tracer recording the steps as configured, mapping them to
+// the contracts known by the mapper.
+func NewTracer(mapper *Mapper, cfg *vm.LogConfig) *Tracer {
+	return &Tracer{
+		StructLogger: vm.NewStructLogger(cfg),
+		mapper:       mapper,
+	}
+}
+
+// CaptureState logs a new structured log message along with the executing contract.
+func (t *Tracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	if err := t.StructLogger.CaptureState(env, pc, op, gas, cost, memory, stack, rStack, rData, contract, depth, err); err != nil {
+		return err
+	}
+	if !sameSlice(contract.Code, t.lastCode) {
+		t.lastCode, t.lastContract = contract.Code, t.mapper.lookup(contract.Code)
+	}
+	t.contracts = append(t.contracts, t.lastContract)
+	return nil
+}
+
+// sameSlice reports whether two byte slices share the same backing array and length.
+func sameSlice(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(a) == 0 || &a[0] == &b[0]
+}
+
+// Trace maps the recorded steps to the sources.
+func (t *Tracer) Trace() *Trace {
+	logs := t.StructLogs()
+	trace := &Trace{Steps: make([]*Step, len(logs))}
+	for i, log := range logs {
+		step := &Step{StructLog: log}
+		if c := t.contracts[i]; c != nil {
+			step.Contract, step.Source = c.name, c.mapper.Locate(log.Pc)
+		}
+		trace.Steps[i] = step
+
+		// A step fails its frame if it reverts, or if it's the last one of the frame
+		// without halting it successfully
+		var failed bool
+		switch {
+		case log.Op == vm.REVERT || log.Err != nil:
+			failed = true
+		case i == len(logs)-1:
+			failed = t.Error() != nil
+		case logs[i+1].Depth < log.Depth:
+			failed = log.Op != vm.STOP && log.Op != vm.RETURN && log.Op != vm.SELFDESTRUCT
+		}
+		if failed {
+			trace.Reverts = append(trace.Reverts, i)
+		}
+	}
+	return trace
+}
+
+// WriteTrace writes a formatted trace mapped to the sources to the given writer.
+func WriteTrace(writer io.Writer, trace *Trace) {
+	for _, step := range trace.Steps {
+		fmt.Fprintf(writer, "%-16spc=%08d gas=%v cost=%v", step.Op, step.Pc, step.Gas, step.GasCost)
+		if step.Source != nil {
+			fmt.Fprintf(writer, " at %v", step.Source)
+		} else if step.Contract != "" {
+			fmt.Fprintf(writer, " in %s", step.Contract)
+		}
+		if step.Err != nil {
+			fmt.Fprintf(writer, " ERROR: %v", step.Err)
+		}
+		fmt.Fprintln(writer)
+	}
+	for _, index := range trace.Reverts {
+		step, outcome := trace.Steps[index], "Failed"
+		if step.Op == vm.REVERT {
+			outcome = "Reverted"
+		}
+		switch {
+		case step.Source != nil:
+			fmt.Fprintf(writer, "%s at %v, step %d (%v)\n", outcome, step.Source, index, step.Op)
+		case step.Contract != "":
+			fmt.Fprintf(writer, "%s in %s, step %d (%v, pc %d)\n", outcome, step.Contract, index, step.Op, step.Pc)
+		default:
+			fmt.Fprintf(writer, "%s at step %d (%v, pc %d)\n", outcome, index, step.Op, step.Pc)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+
+
+package srcmap
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+)
+
+const testSource = `contract Store {
+    uint x;
+
+    function set() public {
+        x = 1;
+        revert();
+    }
+}
+`
+
+// Tests that the steps of known contracts are mapped to their sources, even if
+// the deployed code differs in its push data, and that reverts are located.
+func TestTracer(t *testing.T) {
+	var (
+		store  = common.HexToAddress("fx1000")
+		caller = common.HexToAddress("fx2000")
+
+		assign  = strings.Index(testSource, "x = 1")
+		reverts = strings.Index(testSource, "revert()")
+	)
+	// Register the code storing 2, but deploy one storing 1
+	srcmap := strings.Join([]string{
+		strconv.Itoa(assign) + ":5:0:-", "", "",
+		strconv.Itoa(reverts) + ":8", "", "",
+	}, ";")
+	mapper := NewMapper()
+	code := common.FromHex("fx6002600055600080fd") // PUSH1 2, PUSH1 0, SSTORE, PUSH1 0, DUP1, REVERT
+	if err := mapper.Add("Store", code, srcmap, []compiler.SourceFile{{Name: "Store.sol", Content: testSource}}); err != nil {
+		t.Fatalf("failed to add contract: %v", err)
+	}
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetCode(store, common.FromHex("fx6001600055600080fd"))
+	// PUSH1 0, DUP1 x4, PUSH20 store, GAS, CALL, POP, STOP
+	statedb.SetCode(caller, append(append(common.FromHex("fx600080808080"+"73"), store.Bytes()...), common.FromHex("fx5af15000")...))
+
+	tracer := NewTracer(mapper, nil)
+	_, _, err := runtime.Call(caller, nil, &runtime.Config{
+		State:     statedb,
+		EVMConfig: vm.Config{Debug: true, Tracer: tracer},
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	trace := tracer.Trace()
+	if len(trace.Steps) != 16 {
+		t.Fatalf("step count mismatch: have %d, want 16", len(trace.Steps))
+	}
+	for i, step := range trace.Steps {
+		var want *compiler.SourceLocation
+		switch {
+		case step.Depth == 2 && step.Pc < 5:
+			want = &compiler.SourceLocation{File: "Store.sol", Line: 5, Column: 9, Function: "Store.set"}
+		case step.Depth == 2:
+			want = &compiler.SourceLocation{File: "Store.sol", Line: 6, Column: 9, Function: "Store.set"}
+		}
+		if (step.Source == nil) != (want == nil) || (want != nil && *step.Source != *want) {
+			t.Errorf("step %d (%v at depth %d): location mismatch: have %v, want %v", i, step.Op, step.Depth, step.Source, want)
+		}
+		if want != nil && step.Contract != "Store" {
+			t.Errorf("step %d: contract mismatch: have %q, want %q", i, step.Contract, "Store")
+		}
+	}
+	if len(trace.Reverts) != 1 || trace.Steps[trace.Reverts[0]].Op != vm.REVERT {
+		t.Fatalf("reverts mismatch: have %v", trace.Reverts)
+	}
+	var out bytes.Buffer
+	WriteTrace(&out, trace)
+	if want := "Reverted at Store.sol:6:9 (Store.set), step 13 (REVERT)\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("trace output mismatch: have\n%s\nwant suffix %q", out.String(), want)
+	}
+}
+
+// Tests that failing frames are reported even if they don't explicitly revert.
+func TestTracerFailures(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	target := common.HexToAddress("fx1000")
+	statedb.SetCode(target, common.FromHex("fx600356")) // PUSH1 3, JUMP (invalid destination)
+
+	tracer := NewTracer(NewMapper(), nil)
+	runtime.Call(target, nil, &runtime.Config{
+		State:     statedb,
+		EVMConfig: vm.Config{Debug: true, Tracer: tracer},
+	})
+	trace := tracer.Trace()
+	if len(trace.Reverts) != 1 || trace.Steps[trace.Reverts[0]].Op != vm.JUMP {
+		t.Fatalf("failures mismatch: have %v", trace.Reverts)
+	}
+}
@@ -0,0 +1,740 @@
+
+
+package wasm
+
+import (
+	"fmt"
+)
+
+// Instruction opcodes of the supported integer subset of WebAssembly 1.0.
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+	opDrop         = 0x1a
+	opSelect       = 0x1b
+	opLocalGet     = 0x20
+	opLocalSet     = 0x21
+	opLocalTee     = 0x22
+	opGlobalGet    = 0x23
+	opGlobalSet    = 0x24
+	opI32Load      = 0x28
+	opI64Load      = 0x29
+	opI32Load8S    = 0x2c
+	opI32Load8U    = 0x2d
+	opI32Load16S   = 0x2e
+	opI32Load16U   = 0x2f
+	opI64Load8S    = 0x30
+	opI64Load8U    = 0x31
+	opI64Load16S   = 0x32
+	opI64Load16U   = 0x33
+	opI64Load32S   = 0x34
+	opI64Load32U   = 0x35
+	opI32Store     = 0x36
+	opI64Store     = 0x37
+	opI32Store8    = 0x3a
+	opI32Store16   = 0x3b
+	opI64Store8    = 0x3c
+	opI64Store16   = 0x3d
+	opI64Store32   = 0x3e
+	opMemorySize   = 0x3f
+	opMemoryGrow   = 0x40
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+	opI32Eqz       = 0x45
+	opI32Eq        = 0x46
+	opI32Ne        = 0x47
+	opI32LtS       = 0x48
+	opI32LtU       = 0x49
+	opI32GtS       = 0x4a
+	opI32GtU       = 0x4b
+	opI32LeS       = 0x4c
+	opI32LeU       = 0x4d
+	opI32GeS       = 0x4e
+	opI32GeU       = 0x4f
+	opI64Eqz       = 0x50
+	opI64Eq        = 0x51
+	opI64Ne        = 0x52
+	opI64LtS       = 0x53
+	opI64LtU       = 0x54
+	opI64GtS       = 0x55
+	opI64GtU       = 0x56
+	opI64LeS       = 0x57
+	opI64LeU       = 0x58
+	opI64GeS       = 0x59
+	opI64GeU       = 0x5a
+	opI32Clz       = 0x67
+	opI32Ctz       = 0x68
+	opI32Popcnt    = 0x69
+	opI32Add       = 0x6a
+	opI32Sub       = 0x6b
+	opI32Mul       = 0x6c
+	opI32DivS      = 0x6d
+	opI32DivU      = 0x6e
+	opI32RemS      = 0x6f
+	opI32RemU      = 0x70
+	opI32And       = 0x71
+	opI32Or        = 0x72
+	opI32Xor       = 0x73
+	opI32Shl       = 0x74
+	opI32ShrS      = 0x75
+	opI32ShrU      = 0x76
+	opI32Rotl      = 0x77
+	opI32Rotr      = 0x78
+	opI64Clz       = 0x79
+	opI64Ctz       = 0x7a
+	opI64Popcnt    = 0x7b
+	opI64Add       = 0x7c
+	opI64Sub       = 0x7d
+	opI64Mul       = 0x7e
+	opI64DivS      = 0x7f
+	opI64DivU      = 0x80
+	opI64RemS      = 0x81
+	opI64RemU      = 0x82
+	opI64And       = 0x83
+	opI64Or        = 0x84
+	opI64Xor       = 0x85
+	opI64Shl       = 0x86
+	opI64ShrS      = 0x87
+	opI64ShrU      = 0x88
+	opI64Rotl      = 0x89
+	opI64Rotr      = 0x8a
+	opI32WrapI64   = 0xa7
+	opI64ExtendS   = 0xac
+	opI64ExtendU   = 0xad
+
+	// opMeter is an internal instruction charging the gas of a basic block. It's
+	// not a valid WebAssembly opcode, so it can't clash with decoded ones.
+	opMeter = 0xff
+)
+
+// Gas costs of the instructions, charged per basic block.
+const (
+	GasBase   uint64 = 1  // Cost of the instructions not listed below
+	GasMul    uint64 = 3  // Cost of multiplications
+	GasDiv    uint64 = 8  // Cost of divisions and remainders
+	GasMemory uint64 = 3  // Cost of memory loads and stores
+	GasCall   uint64 = 10 // Cost of direct and indirect calls
+	GasGrow   uint64 = 10 // Cost of memory growth, besides the cost of the new pages
+	GasZero   uint64 = 0  // Cost of structural instructions without effect
+)
+
+// opCost returns the gas cost of an instruction.
+func opCost(op byte) uint64 {
+	switch {
+	case op == opNop || op == opBlock || op == opLoop || op == opElse || op == opEnd || op == opMeter:
+		return GasZero
+	case op == opI32Mul || op == opI64Mul:
+		return GasMul
+	case op >= opI32DivS && op <= opI32RemU, op >= opI64DivS && op <= opI64RemU:
+		return GasDiv
+	case op >= opI32Load && op <= opI64Store32:
+		return GasMemory
+	case op == opCall || op == opCallIndirect:
+		return GasCall
+	case op == opMemoryGrow:
+		return GasGrow
+	default:
+		return GasBase
+	}
+}
+
+// instr is a compiled instruction, with its immediates decoded and the targets
+// of its branches resolved.
+type instr struct {
+	op     byte
+	imm    uint64   // Constant, index, memory offset, block arity, branch depth or gas cost
+	target int      // Index of the matching end of blocks, ifs and elses, start of the body of loops
+	alt    int      // Index the false branch of an if continues at
+	table  []uint32 // Branch depths of br_table, the default one last
+}
+
+// unknown is the type of values popped from an unreachable operand stack, which
+// matches any type.
+const unknown ValueType = 0
+
+// ctrlFrame is a control structure being validated.
+type ctrlFrame struct {
+	op          byte
+	results     []ValueType // Types of the values the structure leaves on the stack
+	height      int         // Height of the operand stack at the start of the structure
+	start       int         // Index of the instruction opening the structure
+	elseAt      int         // Index of the else instruction of an if, -1 if none
+	unreachable bool        // Whether the rest of the structure is unreachable
+}
+
+// labelTypes returns the types of the values a branch to the frame carries.
+func (f *ctrlFrame) labelTypes() []ValueType {
+	if f.op == opLoop {
+		return nil
+	}
+	return f.results
+}
+
+// compiler validates the body of a function and compiles it.
+type compiler struct {
+	m      *Module
+	locals []ValueType
+	r      *reader
+
+	code   []instr
+	vals   []ValueType
+	ctrls  []*ctrlFrame
+	meters []int // Indexes of the metering instructions
+}
+
+// compile validates and compiles the bodies of all the functions of the module.
+func (m *Module) compile() error {
+	m.compiled = make([][]instr, len(m.Functions))
+	for i, fn := range m.Functions {
+		t := m.Types[fn.Type]
+		c := &compiler{
+			m:      m,
+			locals: append(append([]ValueType{}, t.Params...), fn.Locals...),
+			r:      &reader{data: fn.Code},
+		}
+		code, err := c.compile(t.Results)
+		if err != nil {
+			return fmt.Errorf("function %d: %w", len(m.Imports)+i, err)
+		}
+		m.compiled[i] = code
+	}
+	return nil
+}
+
+func (c *compiler) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidModule, fmt.Sprintf(format, args...), c.r.pos)
+}
+
+func (c *compiler) push(t ValueType) {
+	c.vals = append(c.vals, t)
+}
+
+func (c *compiler) pop(want ValueType) (ValueType, error) {
+	frame := c.ctrls[len(c.ctrls)-1]
+	if len(c.vals) == frame.height {
+		if frame.unreachable {
+			return want, nil
+		}
+		return 0, c.errorf("operand stack underflow")
+	}
+	have := c.vals[len(c.vals)-1]
+	c.vals = c.vals[:len(c.vals)-1]
+	if have != want && have != unknown && want != unknown {
+		return 0, c.errorf("type mismatch: have %v, want %v", have, want)
+	}
+	if have == unknown {
+		return want, nil
+	}
+	return have, nil
+}
+
+func (c *compiler) popAll(types []ValueType) error {
+	for i := len(types) - 1; i >= 0; i-- {
+		if _, err := c.pop(types[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setUnreachable marks the rest of the current structure as unreachable, after
+// an unconditional branch.
+func (c *compiler) setUnreachable() {
+	frame := c.ctrls[len(c.ctrls)-1]
+	c.vals = c.vals[:frame.height]
+	frame.unreachable = true
+}
+
+// meter starts a new basic block, injecting the instruction charging its gas.
+func (c *compiler) meter() {
+	c.meters = append(c.meters, len(c.code))
+	c.code = append(c.code, instr{op: opMeter})
+}
+
+// label returns the frame targeted by a branch of the given depth.
+func (c *compiler) label(depth uint32) (*ctrlFrame, error) {
+	if int(depth) >= len(c.ctrls) {
+		return nil, c.errorf("unknown label %d", depth)
+	}
+	return c.ctrls[len(c.ctrls)-1-int(depth)], nil
+}
+
+func (c *compiler) blockType() ([]ValueType, error) {
+	b, err := c.r.byte()
+	if err != nil {
+		return nil, err
+	}
+	if b == 0x40 {
+		return nil, nil
+	}
+	c.r.pos--
+	t, err := c.r.valueType()
+	return []ValueType{t}, err
+}
+
+func (c *compiler) index() (uint32, error) {
+	return c.r.uvarint32()
+}
+
+func (c *compiler) compile(results []ValueType) ([]instr, error) {
+	c.ctrls = []*ctrlFrame{{op: opBlock, results: results, elseAt: -1}}
+	c.meter()
+
+	for len(c.ctrls) > 0 {
+		op, err := c.r.byte()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.instruction(op); err != nil {
+			return nil, err
+		}
+	}
+	if !c.r.done() {
+		return nil, c.errorf("trailing bytes after function end")
+	}
+	// Charge each basic block the cost of all its instructions
+	for i, at := range c.meters {
+		end := len(c.code)
+		if i+1 < len(c.meters) {
+			end = c.meters[i+1]
+		}
+		for _, in := range c.code[at+1 : end] {
+			c.code[at].imm += opCost(in.op)
+		}
+	}
+	return c.code, nil
+}
+
+// instruction validates and compiles a single instruction.
+func (c *compiler) instruction(op byte) error {
+	var (
+		in  = instr{op: op}
+		err error
+	)
+	switch {
+	case op == opUnreachable:
+		c.code = append(c.code, in)
+		c.setUnreachable()
+		return nil
+
+	case op == opNop:
+		return nil
+
+	case op == opBlock || op == opLoop || op == opIf:
+		types, err := c.blockType()
+		if err != nil {
+			return err
+		}
+		if op == opIf {
+			if _, err := c.pop(I32); err != nil {
+				return err
+			}
+		}
+		in.imm = uint64(len(types))
+		if op == opLoop {
+			in.imm = 0
+			in.target = len(c.code) + 1
+		}
+		c.ctrls = append(c.ctrls, &ctrlFrame{op: op, results: types, height: len(c.vals), start: len(c.code), elseAt: -1})
+		c.code = append(c.code, in)
+		if op != opBlock {
+			c.meter()
+		}
+		return nil
+
+	case op == opElse:
+		frame := c.ctrls[len(c.ctrls)-1]
+		if frame.op != opIf || frame.elseAt >= 0 {
+			return c.errorf("else outside if")
+		}
+		if err := c.popAll(frame.results); err != nil {
+			return err
+		}
+		if len(c.vals) != frame.height {
+			return c.errorf("operand stack height mismatch at else")
+		}
+		frame.elseAt, frame.unreachable = len(c.code), false
+		c.code = append(c.code, in)
+		c.meter()
+		return nil
+
+	case op == opEnd:
+		frame := c.ctrls[len(c.ctrls)-1]
+		if err := c.popAll(frame.results); err != nil {
+			return err
+		}
+		if len(c.vals) != frame.height {
+			return c.errorf("operand stack height mismatch at end")
+		}
+		if frame.op == opIf && frame.elseAt < 0 && len(frame.results) > 0 {
+			return c.errorf("if without else must not have results")
+		}
+		c.ctrls = c.ctrls[:len(c.ctrls)-1]
+		c.vals = append(c.vals, frame.results...)
+
+		end := len(c.code)
+		c.code = append(c.code, in)
+		if len(c.ctrls) == 0 {
+			return nil // end of the function
+		}
+		switch frame.op {
+		case opBlock:
+			c.code[frame.start].target = end
+		case opIf:
+			c.code[frame.start].target = end
+			c.code[frame.start].alt = end
+			if frame.elseAt >= 0 {
+				c.code[frame.start].alt = frame.elseAt + 1
+				c.code[frame.elseAt].target = end
+			}
+		}
+		c.meter()
+		return nil
+
+	case op == opBr || op == opBrIf:
+		depth, err := c.index()
+		if err != nil {
+			return err
+		}
+		frame, err := c.label(depth)
+		if err != nil {
+			return err
+		}
+		if op == opBrIf {
+			if _, err := c.pop(I32); err != nil {
+				return err
+			}
+		}
+		types := frame.labelTypes()
+		if err := c.popAll(types); err != nil {
+			return err
+		}
+		in.imm = uint64(depth)
+		c.code = append(c.code, in)
+		if op == opBr {
+			c.setUnreachable()
+		} else {
+			c.vals = append(c.vals, types...)
+			c.meter()
+		}
+		return nil
+
+	case op == opBrTable:
+		if err := c.r.vector(func() error {
+			depth, err := c.index()
+			in.table = append(in.table, depth)
+			return err
+		}); err != nil {
+			return err
+		}
+		depth, err := c.index()
+		if err != nil {
+			return err
+		}
+		in.table = append(in.table, depth)
+		if _, err := c.pop(I32); err != nil {
+			return err
+		}
+		def, err := c.label(depth)
+		if err != nil {
+			return err
+		}
+		for _, depth := range in.table {
+			frame, err := c.label(depth)
+			if err != nil {
+				return err
+			}
+			if len(frame.labelTypes()) != len(def.labelTypes()) || (len(def.labelTypes()) > 0 && frame.labelTypes()[0] != def.labelTypes()[0]) {
+				return c.errorf("br_table label type mismatch")
+			}
+		}
+		if err := c.popAll(def.labelTypes()); err != nil {
+			return err
+		}
+		c.code = append(c.code, in)
+		c.setUnreachable()
+		return nil
+
+	case op == opReturn:
+		if err := c.popAll(c.ctrls[0].results); err != nil {
+			return err
+		}
+		c.code = append(c.code, in)
+		c.setUnreachable()
+		return nil
+
+	case op == opCall:
+		if in.imm, err = c.callee(); err != nil {
+			return err
+		}
+		t, _ := c.m.FuncType(uint32(in.imm))
+		if err := c.popAll(t.Params); err != nil {
+			return err
+		}
+		c.vals = append(c.vals, t.Results...)
+
+	case op == opCallIndirect:
+		index, err := c.r.typeIndex(len(c.m.Types))
+		if err != nil {
+			return err
+		}
+		if reserved, err := c.r.byte(); err != nil || reserved != 0 {
+			return c.errorf("invalid call_indirect reserved byte")
+		}
+		if c.m.Table == nil {
+			return c.errorf("call_indirect without table")
+		}
+		if _, err := c.pop(I32); err != nil {
+			return err
+		}
+		t := c.m.Types[index]
+		if err := c.popAll(t.Params); err != nil {
+			return err
+		}
+		c.vals = append(c.vals, t.Results...)
+		in.imm = uint64(index)
+
+	case op == opDrop:
+		if _, err := c.pop(unknown); err != nil {
+			return err
+		}
+
+	case op == opSelect:
+		if _, err := c.pop(I32); err != nil {
+			return err
+		}
+		t, err := c.pop(unknown)
+		if err != nil {
+			return err
+		}
+		if t, err = c.pop(t); err != nil {
+			return err
+		}
+		c.push(t)
+
+	case op >= opLocalGet && op <= opLocalTee:
+		index, err := c.index()
+		if err != nil {
+			return err
+		}
+		if int(index) >= len(c.locals) {
+			return c.errorf("unknown local %d", index)
+		}
+		t := c.locals[index]
+		if op != opLocalGet {
+			if _, err := c.pop(t); err != nil {
+				return err
+			}
+		}
+		if op != opLocalSet {
+			c.push(t)
+		}
+		in.imm = uint64(index)
+
+	case op == opGlobalGet || op == opGlobalSet:
+		index, err := c.index()
+		if err != nil {
+			return err
+		}
+		if int(index) >= len(c.m.Globals) {
+			return c.errorf("unknown global %d", index)
+		}
+		global := c.m.Globals[index]
+		if op == opGlobalGet {
+			c.push(global.Type)
+		} else {
+			if !global.Mutable {
+				return c.errorf("global %d is immutable", index)
+			}
+			if _, err := c.pop(global.Type); err != nil {
+				return err
+			}
+		}
+		in.imm = uint64(index)
+
+	case op >= opI32Load && op <= opI64Store32:
+		size, t, store, ok := memoryAccess(op)
+		if !ok {
+			return c.errorf("floating point instruction 0x%x not supported", op)
+		}
+		if c.m.Memory == nil {
+			return c.errorf("memory access without memory")
+		}
+		align, err := c.index()
+		if err != nil {
+			return err
+		}
+		if 1<<align > size {
+			return c.errorf("alignment exceeds access size")
+		}
+		offset, err := c.index()
+		if err != nil {
+			return err
+		}
+		if store {
+			if _, err := c.pop(t); err != nil {
+				return err
+			}
+		}
+		if _, err := c.pop(I32); err != nil {
+			return err
+		}
+		if !store {
+			c.push(t)
+		}
+		in.imm = uint64(offset)
+
+	case op == opMemorySize || op == opMemoryGrow:
+		if reserved, err := c.r.byte(); err != nil || reserved != 0 {
+			return c.errorf("invalid memory reserved byte")
+		}
+		if c.m.Memory == nil {
+			return c.errorf("memory instruction without memory")
+		}
+		if op == opMemoryGrow {
+			if _, err := c.pop(I32); err != nil {
+				return err
+			}
+		}
+		c.push(I32)
+
+	case op == opI32Const:
+		v, err := c.r.varint(32)
+		if err != nil {
+			return err
+		}
+		in.imm = uint64(uint32(v))
+		c.push(I32)
+
+	case op == opI64Const:
+		v, err := c.r.varint(64)
+		if err != nil {
+			return err
+		}
+		in.imm = uint64(v)
+		c.push(I64)
+
+	default:
+		params, result, ok := numericType(op)
+		if !ok {
+			if isFloat(op) {
+				return c.errorf("floating point instruction 0x%x not supported", op)
+			}
+			return c.errorf("unknown instruction 0x%x", op)
+		}
+		if err := c.popAll(params); err != nil {
+			return err
+		}
+		c.push(result)
+	}
+	c.code = append(c.code, in)
+	return nil
+}
+
+// callee decodes the index of a called function.
+func (c *compiler) callee() (uint64, error) {
+	index, err := c.index()
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := c.m.FuncType(index); !ok {
+		return 0, c.errorf("unknown function %d", index)
+	}
+	return uint64(index), nil
+}
+
+// memoryAccess returns the size in bytes and the value type of a memory access
+// instruction, and whether it's a store.
+func memoryAccess(op byte) (size uint32, t ValueType, store bool, ok bool) {
+	switch op {
+	case opI32Load:
+		return 4, I32, false, true
+	case opI64Load:
+		return 8, I64, false, true
+	case opI32Load8S, opI32Load8U:
+		return 1, I32, false, true
+	case opI32Load16S, opI32Load16U:
+		return 2, I32, false, true
+	case opI64Load8S, opI64Load8U:
+		return 1, I64, false, true
+	case opI64Load16S, opI64Load16U:
+		return 2, I64, false, true
+	case opI64Load32S, opI64Load32U:
+		return 4, I64, false, true
+	case opI32Store:
+		return 4, I32, true, true
+	case opI64Store:
+		return 8, I64, true, true
+	case opI32Store8:
+		return 1, I32, true, true
+	case opI32Store16:
+		return 2, I32, true, true
+	case opI64Store8:
+		return 1, I64, true, true
+	case opI64Store16:
+		return 2, I64, true, true
+	case opI64Store32:
+		return 4, I64, true, true
+	}
+	return 0, 0, false, false
+}
+
+var (
+	unaryI32  = []ValueType{I32}
+	unaryI64  = []ValueType{I64}
+	binaryI32 = []ValueType{I32, I32}
+	binaryI64 = []ValueType{I64, I64}
+)
+
+// numericType returns the operand and result types of a numeric instruction.
+func numericType(op byte) ([]ValueType, ValueType, bool) {
+	switch {
+	case op == opI32Eqz:
+		return unaryI32, I32, true
+	case op >= opI32Eq && op <= opI32GeU:
+		return binaryI32, I32, true
+	case op == opI64Eqz:
+		return unaryI64, I32, true
+	case op >= opI64Eq && op <= opI64GeU:
+		return binaryI64, I32, true
+	case op >= opI32Clz && op <= opI32Popcnt:
+		return unaryI32, I32, true
+	case op >= opI32Add && op <= opI32Rotr:
+		return binaryI32, I32, true
+	case op >= opI64Clz && op <= opI64Popcnt:
+		return unaryI64, I64, true
+	case op >= opI64Add && op <= opI64Rotr:
+		return binaryI64, I64, true
+	case op == opI32WrapI64:
+		return unaryI64, I32, true
+	case op == opI64ExtendS || op == opI64ExtendU:
+		return unaryI32, I64, true
+	}
+	return nil, 0, false
+}
+
+// isFloat returns whether the opcode is a floating point instruction.
+func isFloat(op byte) bool {
+	switch {
+	case op == 0x2a || op == 0x2b || op == 0x38 || op == 0x39: // loads and stores
+		return true
+	case op == 0x43 || op == 0x44: // constants
+		return true
+	case op >= 0x5b && op <= 0x66: // comparisons
+		return true
+	case op >= 0x8b && op <= 0xbf: // arithmetic and conversions
+		return op != opI32WrapI64 && op != opI64ExtendS && op != opI64ExtendU
+	}
+	return false
+}
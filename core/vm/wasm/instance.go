@@ -0,0 +1,709 @@
+
+
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync/atomic"
+)
+
+// PageSize is the size of a WebAssembly memory page.
+const PageSize = 65536
+
+const (
+	maxCallDepth  = 1024    // Maximum depth of nested function calls
+	maxStackSize  = 1 << 20 // Maximum number of values on the operand stack, including locals
+	interruptRate = 1024    // Number of basic blocks executed between interruption checks
+)
+
+// Errors trapping the execution of a module.
+var (
+	ErrUnreachable        = errors.New("unreachable executed")
+	ErrOutOfBounds        = errors.New("out of bounds memory access")
+	ErrDivideByZero       = errors.New("integer divide by zero")
+	ErrIntegerOverflow    = errors.New("integer overflow")
+	ErrCallStackExhausted = errors.New("call stack exhausted")
+	ErrUndefinedElement   = errors.New("undefined table element")
+	ErrIndirectCallType   = errors.New("indirect call type mismatch")
+	ErrInterrupted        = errors.New("execution interrupted")
+	ErrMissingImport      = errors.New("missing import")
+	ErrExportNotFound     = errors.New("export not found")
+	ErrArgumentCount      = errors.New("argument count mismatch")
+	ErrSegmentOutOfBounds = errors.New("segment out of bounds")
+)
+
+// HostFunction is a function provided by the embedder to the modules importing it.
+// The arguments and the result of the function are passed as raw 64 bit values,
+// with 32 bit integers in the low bits.
+type HostFunction struct {
+	Type FuncType
+	Call func(in *Instance, args []uint64) (uint64, error)
+}
+
+// Imports are the host functions available for import, by module and name.
+type Imports map[string]map[string]*HostFunction
+
+// Config are the execution options of an instance.
+type Config struct {
+	UseGas    func(gas uint64) error // Charges gas for the execution, failing if exhausted, nil if unmetered
+	PageGas   uint64                 // Gas charged for each page the memory grows with
+	Interrupt *int32                 // Aborts the execution if set to non-zero, nil if not interruptible
+}
+
+// Instance is an instantiated module, with its own memory, table and globals.
+// It's not thread safe.
+type Instance struct {
+	module  *Module
+	config  Config
+	hosts   []*HostFunction // Host functions resolved for the imports
+	memory  []byte
+	table   []int64 // Function indexes of the table elements, -1 if undefined
+	globals []uint64
+
+	// Execution state, reused across calls
+	stack  []uint64
+	labels []label
+	frames []frame
+	blocks uint64 // Number of basic blocks executed, for interruption checks
+}
+
+// label is the target of branches to an entered control structure.
+type label struct {
+	cont   int  // Index of the instruction to continue at
+	height int  // Height of the operand stack when entering the structure
+	arity  int  // Number of values carried by branches
+	loop   bool // Whether branches re-enter the structure
+}
+
+// frame is the activation of a function call.
+type frame struct {
+	code   []instr
+	pc     int
+	locals int // Position of the locals, including the parameters, on the stack
+	labels int // Position of the label of the function body on the label stack
+	arity  int // Number of results of the function
+}
+
+// Instantiate creates an instance of a module, resolving its imports and
+// initializing its memory, table and globals. The start function, if any, is
+// run as part of the instantiation.
+func Instantiate(m *Module, imports Imports, config Config) (*Instance, error) {
+	in := &Instance{module: m, config: config}
+	for _, imp := range m.Imports {
+		host, ok := imports[imp.Module][imp.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.%s", ErrMissingImport, imp.Module, imp.Name)
+		}
+		if !host.Type.Equal(m.Types[imp.Type]) {
+			return nil, fmt.Errorf("%w: %s.%s: signature mismatch: have %v, want %v", ErrMissingImport, imp.Module, imp.Name, host.Type, m.Types[imp.Type])
+		}
+		in.hosts = append(in.hosts, host)
+	}
+	for _, global := range m.Globals {
+		in.globals = append(in.globals, global.Init)
+	}
+	if m.Table != nil {
+		in.table = make([]int64, m.Table.Min)
+		for i := range in.table {
+			in.table[i] = -1
+		}
+		for _, elem := range m.Elements {
+			if uint64(elem.Offset)+uint64(len(elem.Funcs)) > uint64(len(in.table)) {
+				return nil, fmt.Errorf("%w: element segment", ErrSegmentOutOfBounds)
+			}
+			for i, fn := range elem.Funcs {
+				in.table[int(elem.Offset)+i] = int64(fn)
+			}
+		}
+	}
+	if m.Memory != nil {
+		in.memory = make([]byte, uint64(m.Memory.Min)*PageSize)
+		for _, data := range m.Data {
+			if uint64(data.Offset)+uint64(len(data.Init)) > uint64(len(in.memory)) {
+				return nil, fmt.Errorf("%w: data segment", ErrSegmentOutOfBounds)
+			}
+			copy(in.memory[data.Offset:], data.Init)
+		}
+	}
+	if m.Start != nil {
+		if _, err := in.call(*m.Start, nil); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// Module returns the module the instance was created from.
+func (in *Instance) Module() *Module {
+	return in.module
+}
+
+// Memory returns the memory of the instance. It's invalidated when the memory
+// grows, so it shouldn't be retained across calls to the instance.
+func (in *Instance) Memory() []byte {
+	return in.memory
+}
+
+// Read returns a copy of a range of the memory.
+func (in *Instance) Read(offset, size uint32) ([]byte, error) {
+	if uint64(offset)+uint64(size) > uint64(len(in.memory)) {
+		return nil, ErrOutOfBounds
+	}
+	return append([]byte{}, in.memory[offset:offset+size]...), nil
+}
+
+// Write copies data to the memory at the given offset.
+func (in *Instance) Write(offset uint32, data []byte) error {
+	if uint64(offset)+uint64(len(data)) > uint64(len(in.memory)) {
+		return ErrOutOfBounds
+	}
+	copy(in.memory[offset:], data)
+	return nil
+}
+
+// Call runs the function exported with the given name, returning its result, if
+// it has one.
+func (in *Instance) Call(name string, args ...uint64) (uint64, error) {
+	index, ok := in.module.Export(name, ExternalFunction)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrExportNotFound, name)
+	}
+	return in.call(index, args)
+}
+
+// call runs a function with the given arguments.
+func (in *Instance) call(index uint32, args []uint64) (uint64, error) {
+	t, _ := in.module.FuncType(index)
+	if len(args) != len(t.Params) {
+		return 0, ErrArgumentCount
+	}
+	in.stack, in.labels, in.frames = in.stack[:0], in.labels[:0], in.frames[:0]
+	in.stack = append(in.stack, args...)
+	if err := in.invoke(index); err != nil {
+		return 0, err
+	}
+	if err := in.run(); err != nil {
+		return 0, err
+	}
+	if len(t.Results) == 0 {
+		return 0, nil
+	}
+	return in.stack[len(in.stack)-1], nil
+}
+
+// invoke calls a function with its arguments on the top of the stack, running
+// host functions and entering the frame of defined ones.
+func (in *Instance) invoke(index uint32) error {
+	t, _ := in.module.FuncType(index)
+	if int(index) < len(in.hosts) {
+		args := append([]uint64{}, in.stack[len(in.stack)-len(t.Params):]...)
+		in.stack = in.stack[:len(in.stack)-len(t.Params)]
+
+		ret, err := in.hosts[index].Call(in, args)
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(t.Results) == 0:
+		case t.Results[0] == I32:
+			in.stack = append(in.stack, uint64(uint32(ret)))
+		default:
+			in.stack = append(in.stack, ret)
+		}
+		return nil
+	}
+	if len(in.frames) >= maxCallDepth {
+		return ErrCallStackExhausted
+	}
+	fn := in.module.Functions[int(index)-len(in.hosts)]
+	if len(in.stack)+len(fn.Locals) > maxStackSize {
+		return ErrCallStackExhausted
+	}
+	locals := len(in.stack) - len(t.Params)
+	for range fn.Locals {
+		in.stack = append(in.stack, 0)
+	}
+	code := in.module.compiled[int(index)-len(in.hosts)]
+	in.frames = append(in.frames, frame{code: code, locals: locals, labels: len(in.labels), arity: len(t.Results)})
+	in.labels = append(in.labels, label{cont: len(code), height: len(in.stack), arity: len(t.Results)})
+	return nil
+}
+
+// ret returns from the current function, leaving its results on the stack.
+func (in *Instance) ret() {
+	f := in.frames[len(in.frames)-1]
+	copy(in.stack[f.locals:], in.stack[len(in.stack)-f.arity:])
+	in.stack = in.stack[:f.locals+f.arity]
+	in.labels = in.labels[:f.labels]
+	in.frames = in.frames[:len(in.frames)-1]
+}
+
+// branch continues the execution at the label of the given depth, returning the
+// index of the instruction to continue at, or -1 if returning from the function.
+func (in *Instance) branch(depth int) int {
+	f := &in.frames[len(in.frames)-1]
+	target := len(in.labels) - 1 - depth
+	if target == f.labels {
+		in.ret()
+		return -1
+	}
+	l := in.labels[target]
+	copy(in.stack[l.height:], in.stack[len(in.stack)-l.arity:])
+	in.stack = in.stack[:l.height+l.arity]
+	if l.loop {
+		in.labels = in.labels[:target+1]
+	} else {
+		in.labels = in.labels[:target]
+	}
+	return l.cont
+}
+
+func (in *Instance) pop() uint64 {
+	v := in.stack[len(in.stack)-1]
+	in.stack = in.stack[:len(in.stack)-1]
+	return v
+}
+
+func (in *Instance) push(v uint64) {
+	in.stack = append(in.stack, v)
+}
+
+func b2i(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// address computes the effective address of a memory access, checking it's in
+// the bounds of the memory.
+func (in *Instance) address(base uint64, offset uint64, size uint64) (uint64, error) {
+	addr := uint64(uint32(base)) + offset
+	if addr+size > uint64(len(in.memory)) {
+		return 0, ErrOutOfBounds
+	}
+	return addr, nil
+}
+
+// run executes the frames on the call stack until the outermost one returns.
+func (in *Instance) run() error {
+	for len(in.frames) > 0 {
+		f := &in.frames[len(in.frames)-1]
+		if f.pc >= len(f.code) {
+			in.ret()
+			continue
+		}
+		op := f.code[f.pc]
+		f.pc++
+
+		switch op.op {
+		case opMeter:
+			if in.config.UseGas != nil {
+				if err := in.config.UseGas(op.imm); err != nil {
+					return err
+				}
+			}
+			if in.blocks++; in.blocks%interruptRate == 0 && in.config.Interrupt != nil && atomic.LoadInt32(in.config.Interrupt) != 0 {
+				return ErrInterrupted
+			}
+
+		case opUnreachable:
+			return ErrUnreachable
+
+		case opBlock:
+			in.labels = append(in.labels, label{cont: op.target + 1, height: len(in.stack), arity: int(op.imm)})
+
+		case opLoop:
+			in.labels = append(in.labels, label{cont: op.target, height: len(in.stack), loop: true})
+
+		case opIf:
+			in.labels = append(in.labels, label{cont: op.target + 1, height: len(in.stack) - 1, arity: int(op.imm)})
+			if in.pop() == 0 {
+				f.pc = op.alt
+			}
+
+		case opElse:
+			f.pc = op.target
+
+		case opEnd:
+			if len(in.labels)-1 == f.labels {
+				in.ret()
+			} else {
+				in.labels = in.labels[:len(in.labels)-1]
+			}
+
+		case opBr:
+			if pc := in.branch(int(op.imm)); pc >= 0 {
+				f.pc = pc
+			}
+
+		case opBrIf:
+			if in.pop() != 0 {
+				if pc := in.branch(int(op.imm)); pc >= 0 {
+					f.pc = pc
+				}
+			}
+
+		case opBrTable:
+			index := uint32(in.pop())
+			depth := op.table[len(op.table)-1]
+			if int(index) < len(op.table)-1 {
+				depth = op.table[index]
+			}
+			if pc := in.branch(int(depth)); pc >= 0 {
+				f.pc = pc
+			}
+
+		case opReturn:
+			in.ret()
+
+		case opCall:
+			if err := in.invoke(uint32(op.imm)); err != nil {
+				return err
+			}
+
+		case opCallIndirect:
+			elem := uint32(in.pop())
+			if int(elem) >= len(in.table) || in.table[elem] < 0 {
+				return ErrUndefinedElement
+			}
+			index := uint32(in.table[elem])
+			if t, _ := in.module.FuncType(index); !t.Equal(in.module.Types[op.imm]) {
+				return ErrIndirectCallType
+			}
+			if err := in.invoke(index); err != nil {
+				return err
+			}
+
+		case opDrop:
+			in.pop()
+
+		case opSelect:
+			cond, b, a := in.pop(), in.pop(), in.pop()
+			if cond != 0 {
+				in.push(a)
+			} else {
+				in.push(b)
+			}
+
+		case opLocalGet:
+			in.push(in.stack[f.locals+int(op.imm)])
+		case opLocalSet:
+			in.stack[f.locals+int(op.imm)] = in.pop()
+		case opLocalTee:
+			in.stack[f.locals+int(op.imm)] = in.stack[len(in.stack)-1]
+		case opGlobalGet:
+			in.push(in.globals[op.imm])
+		case opGlobalSet:
+			in.globals[op.imm] = in.pop()
+
+		case opI32Load, opI64Load, opI32Load8S, opI32Load8U, opI32Load16S, opI32Load16U,
+			opI64Load8S, opI64Load8U, opI64Load16S, opI64Load16U, opI64Load32S, opI64Load32U:
+			if err := in.load(op); err != nil {
+				return err
+			}
+
+		case opI32Store, opI64Store, opI32Store8, opI32Store16, opI64Store8, opI64Store16, opI64Store32:
+			if err := in.store(op); err != nil {
+				return err
+			}
+
+		case opMemorySize:
+			in.push(uint64(len(in.memory) / PageSize))
+
+		case opMemoryGrow:
+			if err := in.grow(); err != nil {
+				return err
+			}
+
+		case opI32Const, opI64Const:
+			in.push(op.imm)
+
+		default:
+			if err := in.numeric(op.op); err != nil {
+				return err
+			}
+		}
+		// Abort if the stack overflowed, checked once the instruction executed
+		if len(in.stack) > maxStackSize {
+			return ErrCallStackExhausted
+		}
+	}
+	return nil
+}
+
+func (in *Instance) load(op instr) error {
+	size, _, _, _ := memoryAccess(op.op)
+	addr, err := in.address(in.pop(), op.imm, uint64(size))
+	if err != nil {
+		return err
+	}
+	mem := in.memory[addr:]
+	var v uint64
+	switch op.op {
+	case opI32Load:
+		v = uint64(binary.LittleEndian.Uint32(mem))
+	case opI64Load:
+		v = binary.LittleEndian.Uint64(mem)
+	case opI32Load8S:
+		v = uint64(uint32(int32(int8(mem[0]))))
+	case opI32Load8U, opI64Load8U:
+		v = uint64(mem[0])
+	case opI32Load16S:
+		v = uint64(uint32(int32(int16(binary.LittleEndian.Uint16(mem)))))
+	case opI32Load16U, opI64Load16U:
+		v = uint64(binary.LittleEndian.Uint16(mem))
+	case opI64Load8S:
+		v = uint64(int64(int8(mem[0])))
+	case opI64Load16S:
+		v = uint64(int64(int16(binary.LittleEndian.Uint16(mem))))
+	case opI64Load32S:
+		v = uint64(int64(int32(binary.LittleEndian.Uint32(mem))))
+	case opI64Load32U:
+		v = uint64(binary.LittleEndian.Uint32(mem))
+	}
+	in.push(v)
+	return nil
+}
+
+func (in *Instance) store(op instr) error {
+	size, _, _, _ := memoryAccess(op.op)
+	v := in.pop()
+	addr, err := in.address(in.pop(), op.imm, uint64(size))
+	if err != nil {
+		return err
+	}
+	mem := in.memory[addr:]
+	switch size {
+	case 1:
+		mem[0] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(mem, uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(mem, uint32(v))
+	case 8:
+		binary.LittleEndian.PutUint64(mem, v)
+	}
+	return nil
+}
+
+// grow grows the memory by the number of pages on the stack, pushing the old
+// size in pages, or -1 if the memory can't grow.
+func (in *Instance) grow() error {
+	var (
+		delta = uint64(uint32(in.pop()))
+		size  = uint64(len(in.memory) / PageSize)
+		limit = uint64(maxPages)
+	)
+	if max := in.module.Memory.Max; max != nil {
+		limit = uint64(*max)
+	}
+	if size+delta > limit {
+		in.push(0xffffffff)
+		return nil
+	}
+	if in.config.UseGas != nil && delta > 0 {
+		if err := in.config.UseGas(delta * in.config.PageGas); err != nil {
+			return err
+		}
+	}
+	in.memory = append(in.memory, make([]byte, delta*PageSize)...)
+	in.push(size)
+	return nil
+}
+
+// numeric executes a numeric instruction.
+func (in *Instance) numeric(op byte) error {
+	switch {
+	case op == opI32Eqz:
+		in.push(b2i(uint32(in.pop()) == 0))
+	case op == opI64Eqz:
+		in.push(b2i(in.pop() == 0))
+
+	case op >= opI32Eq && op <= opI32GeU:
+		b, a := uint32(in.pop()), uint32(in.pop())
+		var r bool
+		switch op {
+		case opI32Eq:
+			r = a == b
+		case opI32Ne:
+			r = a != b
+		case opI32LtS:
+			r = int32(a) < int32(b)
+		case opI32LtU:
+			r = a < b
+		case opI32GtS:
+			r = int32(a) > int32(b)
+		case opI32GtU:
+			r = a > b
+		case opI32LeS:
+			r = int32(a) <= int32(b)
+		case opI32LeU:
+			r = a <= b
+		case opI32GeS:
+			r = int32(a) >= int32(b)
+		case opI32GeU:
+			r = a >= b
+		}
+		in.push(b2i(r))
+
+	case op >= opI64Eq && op <= opI64GeU:
+		b, a := in.pop(), in.pop()
+		var r bool
+		switch op {
+		case opI64Eq:
+			r = a == b
+		case opI64Ne:
+			r = a != b
+		case opI64LtS:
+			r = int64(a) < int64(b)
+		case opI64LtU:
+			r = a < b
+		case opI64GtS:
+			r = int64(a) > int64(b)
+		case opI64GtU:
+			r = a > b
+		case opI64LeS:
+			r = int64(a) <= int64(b)
+		case opI64LeU:
+			r = a <= b
+		case opI64GeS:
+			r = int64(a) >= int64(b)
+		case opI64GeU:
+			r = a >= b
+		}
+		in.push(b2i(r))
+
+	case op == opI32Clz:
+		in.push(uint64(bits.LeadingZeros32(uint32(in.pop()))))
+	case op == opI32Ctz:
+		in.push(uint64(bits.TrailingZeros32(uint32(in.pop()))))
+	case op == opI32Popcnt:
+		in.push(uint64(bits.OnesCount32(uint32(in.pop()))))
+	case op == opI64Clz:
+		in.push(uint64(bits.LeadingZeros64(in.pop())))
+	case op == opI64Ctz:
+		in.push(uint64(bits.TrailingZeros64(in.pop())))
+	case op == opI64Popcnt:
+		in.push(uint64(bits.OnesCount64(in.pop())))
+
+	case op >= opI32Add && op <= opI32Rotr:
+		b, a := uint32(in.pop()), uint32(in.pop())
+		var r uint32
+		switch op {
+		case opI32Add:
+			r = a + b
+		case opI32Sub:
+			r = a - b
+		case opI32Mul:
+			r = a * b
+		case opI32DivS:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			if int32(a) == -1<<31 && int32(b) == -1 {
+				return ErrIntegerOverflow
+			}
+			r = uint32(int32(a) / int32(b))
+		case opI32DivU:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			r = a / b
+		case opI32RemS:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			if int32(b) != -1 {
+				r = uint32(int32(a) % int32(b))
+			}
+		case opI32RemU:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			r = a % b
+		case opI32And:
+			r = a & b
+		case opI32Or:
+			r = a | b
+		case opI32Xor:
+			r = a ^ b
+		case opI32Shl:
+			r = a << (b % 32)
+		case opI32ShrS:
+			r = uint32(int32(a) >> (b % 32))
+		case opI32ShrU:
+			r = a >> (b % 32)
+		case opI32Rotl:
+			r = bits.RotateLeft32(a, int(b%32))
+		case opI32Rotr:
+			r = bits.RotateLeft32(a, -int(b%32))
+		}
+		in.push(uint64(r))
+
+	case op >= opI64Add && op <= opI64Rotr:
+		b, a := in.pop(), in.pop()
+		var r uint64
+		switch op {
+		case opI64Add:
+			r = a + b
+		case opI64Sub:
+			r = a - b
+		case opI64Mul:
+			r = a * b
+		case opI64DivS:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			if int64(a) == -1<<63 && int64(b) == -1 {
+				return ErrIntegerOverflow
+			}
+			r = uint64(int64(a) / int64(b))
+		case opI64DivU:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			r = a / b
+		case opI64RemS:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			if int64(b) != -1 {
+				r = uint64(int64(a) % int64(b))
+			}
+		case opI64RemU:
+			if b == 0 {
+				return ErrDivideByZero
+			}
+			r = a % b
+		case opI64And:
+			r = a & b
+		case opI64Or:
+			r = a | b
+		case opI64Xor:
+			r = a ^ b
+		case opI64Shl:
+			r = a << (b % 64)
+		case opI64ShrS:
+			r = uint64(int64(a) >> (b % 64))
+		case opI64ShrU:
+			r = a >> (b % 64)
+		case opI64Rotl:
+			r = bits.RotateLeft64(a, int(b%64))
+		case opI64Rotr:
+			r = bits.RotateLeft64(a, -int(b%64))
+		}
+		in.push(r)
+
+	case op == opI32WrapI64:
+		in.push(uint64(uint32(in.pop())))
+	case op == opI64ExtendS:
+		in.push(uint64(int64(int32(in.pop()))))
+	case op == opI64ExtendU:
+		in.push(uint64(uint32(in.pop())))
+	}
+	return nil
+}
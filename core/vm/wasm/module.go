@@ -0,0 +1,747 @@
+
+
+// Package wasm implements a WebAssembly interpreter in pure Go, to run contracts
+// compiled to WebAssembly deterministically.
+//
+// Only the integer subset of the WebAssembly 1.0 (MVP) binary format is supported:
+// modules using floating point types or instructions are rejected, as they can't
+// be executed deterministically across platforms. Function bodies are compiled to
+// an internal representation with gas metering injected at the start of every
+// basic block, charging the cost of the whole block before executing it.
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Magic is the prefix of all WebAssembly binary modules, followed by Version.
+var Magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// Version is the only supported version of the WebAssembly binary format.
+const Version = 1
+
+// ErrInvalidModule is returned if a binary module is malformed or uses features
+// not supported by the interpreter.
+var ErrInvalidModule = errors.New("invalid wasm module")
+
+// ValueType is the type of a WebAssembly value.
+type ValueType byte
+
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+)
+
+// String implements fmt.Stringer.
+func (t ValueType) String() string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	default:
+		return fmt.Sprintf("type(0x%x)", byte(t))
+	}
+}
+
+// FuncType is the signature of a function.
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+// Equal returns whether two function signatures are identical.
+func (t FuncType) Equal(other FuncType) bool {
+	return bytes.Equal(valueBytes(t.Params), valueBytes(other.Params)) && bytes.Equal(valueBytes(t.Results), valueBytes(other.Results))
+}
+
+// String implements fmt.Stringer.
+func (t FuncType) String() string {
+	return fmt.Sprintf("%v -> %v", t.Params, t.Results)
+}
+
+func valueBytes(types []ValueType) []byte {
+	b := make([]byte, len(types))
+	for i, t := range types {
+		b[i] = byte(t)
+	}
+	return b
+}
+
+// External kinds of imports and exports.
+const (
+	ExternalFunction byte = 0x00
+	ExternalTable    byte = 0x01
+	ExternalMemory   byte = 0x02
+	ExternalGlobal   byte = 0x03
+)
+
+// Import is a function imported by a module. Importing tables, memories and
+// globals isn't supported.
+type Import struct {
+	Module string
+	Name   string
+	Type   uint32 // Index of the signature of the function
+}
+
+// Export is an entity exported by a module.
+type Export struct {
+	Name  string
+	Kind  byte   // External kind of the entity
+	Index uint32 // Index of the entity in its index space
+}
+
+// Limits are the initial and maximum sizes of a table or memory.
+type Limits struct {
+	Min uint32
+	Max *uint32 // Maximum size, nil if unbounded
+}
+
+// Global is a global variable defined by a module.
+type Global struct {
+	Type    ValueType
+	Mutable bool
+	Init    uint64 // Initial value
+}
+
+// Element is a segment initializing the function table.
+type Element struct {
+	Offset uint32
+	Funcs  []uint32
+}
+
+// Data is a segment initializing the memory.
+type Data struct {
+	Offset uint32
+	Init   []byte
+}
+
+// Function is the body of a function defined by a module.
+type Function struct {
+	Type   uint32      // Index of the signature of the function
+	Locals []ValueType // Local variables, excluding the parameters
+	Code   []byte      // Instructions of the function, including the final end
+}
+
+// Module is a decoded WebAssembly binary module.
+type Module struct {
+	Types     []FuncType
+	Imports   []Import
+	Functions []Function
+	Table     *Limits
+	Memory    *Limits
+	Globals   []Global
+	Exports   []Export
+	Start     *uint32
+	Elements  []Element
+	Data      []Data
+
+	compiled [][]instr // Compiled bodies of the functions
+}
+
+// Section identifiers, in the order the sections must appear in.
+const (
+	sectionCustom   = 0
+	sectionType     = 1
+	sectionImport   = 2
+	sectionFunction = 3
+	sectionTable    = 4
+	sectionMemory   = 5
+	sectionGlobal   = 6
+	sectionExport   = 7
+	sectionStart    = 8
+	sectionElement  = 9
+	sectionCode     = 10
+	sectionData     = 11
+)
+
+const (
+	maxPages     = 65536 // Maximum number of 64KiB pages of a memory
+	maxTableSize = 65536 // Maximum number of elements of a table, below the format's limit
+)
+
+// IsModule returns whether the code is a WebAssembly binary module, based on
+// its magic prefix.
+func IsModule(code []byte) bool {
+	return bytes.HasPrefix(code, Magic)
+}
+
+// Decode decodes, validates and compiles a WebAssembly binary module.
+func Decode(code []byte) (*Module, error) {
+	if !IsModule(code) {
+		return nil, fmt.Errorf("%w: missing magic prefix", ErrInvalidModule)
+	}
+	r := &reader{data: code, pos: len(Magic)}
+	if version, err := r.uint32le(); err != nil || version != Version {
+		return nil, fmt.Errorf("%w: unsupported version", ErrInvalidModule)
+	}
+	var (
+		m     = new(Module)
+		funcs []uint32 // Signatures of the functions, until their bodies are decoded
+		last  byte
+	)
+	for !r.done() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.uvarint32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(size)
+		if err != nil {
+			return nil, err
+		}
+		if id == sectionCustom {
+			continue
+		}
+		if id > sectionData || id <= last {
+			return nil, fmt.Errorf("%w: unexpected section %d", ErrInvalidModule, id)
+		}
+		last = id
+
+		s := &reader{data: payload}
+		switch id {
+		case sectionType:
+			err = s.vector(func() error {
+				t, err := s.funcType()
+				m.Types = append(m.Types, t)
+				return err
+			})
+		case sectionImport:
+			err = s.vector(func() error {
+				imp, err := s.imp(len(m.Types))
+				m.Imports = append(m.Imports, imp)
+				return err
+			})
+		case sectionFunction:
+			err = s.vector(func() error {
+				index, err := s.typeIndex(len(m.Types))
+				funcs = append(funcs, index)
+				return err
+			})
+		case sectionTable:
+			err = s.vector(func() error {
+				if m.Table != nil {
+					return fmt.Errorf("%w: multiple tables", ErrInvalidModule)
+				}
+				if kind, err := s.byte(); err != nil || kind != 0x70 {
+					return fmt.Errorf("%w: invalid table element type", ErrInvalidModule)
+				}
+				limits, err := s.limits(maxTableSize)
+				m.Table = &limits
+				return err
+			})
+		case sectionMemory:
+			err = s.vector(func() error {
+				if m.Memory != nil {
+					return fmt.Errorf("%w: multiple memories", ErrInvalidModule)
+				}
+				limits, err := s.limits(maxPages)
+				m.Memory = &limits
+				return err
+			})
+		case sectionGlobal:
+			err = s.vector(func() error {
+				global, err := s.global()
+				m.Globals = append(m.Globals, global)
+				return err
+			})
+		case sectionExport:
+			names := make(map[string]bool)
+			err = s.vector(func() error {
+				export, err := s.export()
+				if err == nil && names[export.Name] {
+					err = fmt.Errorf("%w: duplicate export %q", ErrInvalidModule, export.Name)
+				}
+				names[export.Name] = true
+				m.Exports = append(m.Exports, export)
+				return err
+			})
+		case sectionStart:
+			var start uint32
+			if start, err = s.uvarint32(); err == nil {
+				m.Start = &start
+			}
+		case sectionElement:
+			err = s.vector(func() error {
+				elem, err := s.element()
+				m.Elements = append(m.Elements, elem)
+				return err
+			})
+		case sectionCode:
+			if s.peekCount() != len(funcs) {
+				return nil, fmt.Errorf("%w: function and code section mismatch", ErrInvalidModule)
+			}
+			err = s.vector(func() error {
+				fn, err := s.function()
+				fn.Type = funcs[len(m.Functions)]
+				m.Functions = append(m.Functions, fn)
+				return err
+			})
+		case sectionData:
+			err = s.vector(func() error {
+				data, err := s.segment()
+				m.Data = append(m.Data, data)
+				return err
+			})
+		}
+		if err == nil && !s.done() {
+			err = fmt.Errorf("%w: trailing bytes in section %d", ErrInvalidModule, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(m.Functions) != len(funcs) {
+		return nil, fmt.Errorf("%w: function and code section mismatch", ErrInvalidModule)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, m.compile()
+}
+
+// validate checks the references between the sections of the module.
+func (m *Module) validate() error {
+	var (
+		funcCount   = uint32(len(m.Imports) + len(m.Functions))
+		globalCount = uint32(len(m.Globals))
+	)
+	for _, export := range m.Exports {
+		var ok bool
+		switch export.Kind {
+		case ExternalFunction:
+			ok = export.Index < funcCount
+		case ExternalTable:
+			ok = m.Table != nil && export.Index == 0
+		case ExternalMemory:
+			ok = m.Memory != nil && export.Index == 0
+		case ExternalGlobal:
+			ok = export.Index < globalCount
+		}
+		if !ok {
+			return fmt.Errorf("%w: export %q of unknown entity", ErrInvalidModule, export.Name)
+		}
+	}
+	if m.Start != nil {
+		if *m.Start >= funcCount {
+			return fmt.Errorf("%w: unknown start function", ErrInvalidModule)
+		}
+		if t := m.Types[m.funcType(*m.Start)]; len(t.Params) > 0 || len(t.Results) > 0 {
+			return fmt.Errorf("%w: invalid start function signature", ErrInvalidModule)
+		}
+	}
+	for _, elem := range m.Elements {
+		if m.Table == nil {
+			return fmt.Errorf("%w: element segment without table", ErrInvalidModule)
+		}
+		for _, fn := range elem.Funcs {
+			if fn >= funcCount {
+				return fmt.Errorf("%w: element of unknown function", ErrInvalidModule)
+			}
+		}
+	}
+	if len(m.Data) > 0 && m.Memory == nil {
+		return fmt.Errorf("%w: data segment without memory", ErrInvalidModule)
+	}
+	return nil
+}
+
+// funcType returns the signature index of a function, imported or defined.
+func (m *Module) funcType(index uint32) uint32 {
+	if int(index) < len(m.Imports) {
+		return m.Imports[index].Type
+	}
+	return m.Functions[int(index)-len(m.Imports)].Type
+}
+
+// Export returns the entity exported with the given name and kind.
+func (m *Module) Export(name string, kind byte) (uint32, bool) {
+	for _, export := range m.Exports {
+		if export.Name == name && export.Kind == kind {
+			return export.Index, true
+		}
+	}
+	return 0, false
+}
+
+// FuncType returns the signature of a function, imported or defined.
+func (m *Module) FuncType(index uint32) (FuncType, bool) {
+	if int(index) >= len(m.Imports)+len(m.Functions) {
+		return FuncType{}, false
+	}
+	return m.Types[m.funcType(index)], true
+}
+
+// reader decodes the primitive values of the binary format.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+func (r *reader) errEOF() error {
+	return fmt.Errorf("%w: unexpected end at offset %d", ErrInvalidModule, r.pos)
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.done() {
+		return 0, r.errEOF()
+	}
+	r.pos++
+	return r.data[r.pos-1], nil
+}
+
+func (r *reader) bytes(n uint32) ([]byte, error) {
+	if uint64(len(r.data)-r.pos) < uint64(n) {
+		return nil, r.errEOF()
+	}
+	r.pos += int(n)
+	return r.data[r.pos-int(n) : r.pos], nil
+}
+
+func (r *reader) uint32le() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// uvarint decodes an unsigned LEB128 integer of the given bit size.
+func (r *reader) uvarint(bits uint) (uint64, error) {
+	var result uint64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		if shift+7 > bits && uint64(b&0x7f)>>(bits-shift) != 0 {
+			return 0, fmt.Errorf("%w: integer overflow at offset %d", ErrInvalidModule, r.pos)
+		}
+		result |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result, nil
+		}
+		if shift+7 >= bits {
+			return 0, fmt.Errorf("%w: integer too long at offset %d", ErrInvalidModule, r.pos)
+		}
+	}
+}
+
+// varint decodes a signed LEB128 integer of the given bit size.
+func (r *reader) varint(bits uint) (int64, error) {
+	var result int64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		result |= int64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			shift += 7
+			if shift < 64 && b&0x40 != 0 {
+				result |= -1 << shift
+			}
+			if shift > bits {
+				// The unused bits of the last byte must extend the sign bit
+				if used := bits - (shift - 7); used < 7 {
+					mask := byte(0x7f) >> (used - 1) << (used - 1)
+					if ext := b & mask; ext != 0 && ext != mask {
+						return 0, fmt.Errorf("%w: integer overflow at offset %d", ErrInvalidModule, r.pos)
+					}
+				}
+			}
+			return result, nil
+		}
+		if shift+7 >= bits {
+			return 0, fmt.Errorf("%w: integer too long at offset %d", ErrInvalidModule, r.pos)
+		}
+	}
+}
+
+func (r *reader) uvarint32() (uint32, error) {
+	v, err := r.uvarint(32)
+	return uint32(v), err
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.uvarint32()
+	if err != nil {
+		return "", err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", fmt.Errorf("%w: invalid utf-8 name", ErrInvalidModule)
+	}
+	return string(b), nil
+}
+
+// peekCount returns the element count of the vector at the current position.
+func (r *reader) peekCount() int {
+	pos := r.pos
+	defer func() { r.pos = pos }()
+
+	n, err := r.uvarint32()
+	if err != nil {
+		return -1
+	}
+	return int(n)
+}
+
+// vector decodes a vector, calling the element decoder for each of its items.
+func (r *reader) vector(elem func() error) error {
+	n, err := r.uvarint32()
+	if err != nil {
+		return err
+	}
+	if uint64(n) > uint64(len(r.data)-r.pos) {
+		return r.errEOF() // every element takes at least one byte
+	}
+	for i := uint32(0); i < n; i++ {
+		if err := elem(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch t := ValueType(b); t {
+	case I32, I64:
+		return t, nil
+	case 0x7d, 0x7c:
+		return 0, fmt.Errorf("%w: floating point types not supported", ErrInvalidModule)
+	default:
+		return 0, fmt.Errorf("%w: invalid value type 0x%x", ErrInvalidModule, b)
+	}
+}
+
+func (r *reader) funcType() (FuncType, error) {
+	if form, err := r.byte(); err != nil || form != 0x60 {
+		return FuncType{}, fmt.Errorf("%w: invalid function type", ErrInvalidModule)
+	}
+	var t FuncType
+	err := r.vector(func() error {
+		v, err := r.valueType()
+		t.Params = append(t.Params, v)
+		return err
+	})
+	if err != nil {
+		return t, err
+	}
+	err = r.vector(func() error {
+		v, err := r.valueType()
+		t.Results = append(t.Results, v)
+		return err
+	})
+	if err == nil && len(t.Results) > 1 {
+		err = fmt.Errorf("%w: multiple results not supported", ErrInvalidModule)
+	}
+	return t, err
+}
+
+func (r *reader) typeIndex(types int) (uint32, error) {
+	index, err := r.uvarint32()
+	if err == nil && int(index) >= types {
+		err = fmt.Errorf("%w: unknown type %d", ErrInvalidModule, index)
+	}
+	return index, err
+}
+
+func (r *reader) imp(types int) (Import, error) {
+	var (
+		imp Import
+		err error
+	)
+	if imp.Module, err = r.name(); err != nil {
+		return imp, err
+	}
+	if imp.Name, err = r.name(); err != nil {
+		return imp, err
+	}
+	kind, err := r.byte()
+	if err != nil {
+		return imp, err
+	}
+	if kind != ExternalFunction {
+		return imp, fmt.Errorf("%w: import %s.%s: only functions can be imported", ErrInvalidModule, imp.Module, imp.Name)
+	}
+	imp.Type, err = r.typeIndex(types)
+	return imp, err
+}
+
+func (r *reader) limits(max uint32) (Limits, error) {
+	flags, err := r.byte()
+	if err != nil {
+		return Limits{}, err
+	}
+	var limits Limits
+	if limits.Min, err = r.uvarint32(); err != nil {
+		return limits, err
+	}
+	switch flags {
+	case 0x00:
+	case 0x01:
+		limit, err := r.uvarint32()
+		if err != nil {
+			return limits, err
+		}
+		if limit < limits.Min {
+			return limits, fmt.Errorf("%w: maximum size below minimum", ErrInvalidModule)
+		}
+		limits.Max = &limit
+	default:
+		return limits, fmt.Errorf("%w: invalid limits flags 0x%x", ErrInvalidModule, flags)
+	}
+	if limits.Min > max || (limits.Max != nil && *limits.Max > max) {
+		return limits, fmt.Errorf("%w: size limit exceeded", ErrInvalidModule)
+	}
+	return limits, nil
+}
+
+// constExpr decodes a constant initializer expression of the given type. Only
+// constants are supported, as globals can't be imported.
+func (r *reader) constExpr(t ValueType) (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	switch {
+	case op == opI32Const && t == I32:
+		v, err := r.varint(32)
+		if err != nil {
+			return 0, err
+		}
+		value = uint64(uint32(v))
+	case op == opI64Const && t == I64:
+		v, err := r.varint(64)
+		if err != nil {
+			return 0, err
+		}
+		value = uint64(v)
+	default:
+		return 0, fmt.Errorf("%w: invalid constant expression", ErrInvalidModule)
+	}
+	if end, err := r.byte(); err != nil || end != opEnd {
+		return 0, fmt.Errorf("%w: unterminated constant expression", ErrInvalidModule)
+	}
+	return value, nil
+}
+
+func (r *reader) global() (Global, error) {
+	t, err := r.valueType()
+	if err != nil {
+		return Global{}, err
+	}
+	mutable, err := r.byte()
+	if err != nil || mutable > 1 {
+		return Global{}, fmt.Errorf("%w: invalid global mutability", ErrInvalidModule)
+	}
+	init, err := r.constExpr(t)
+	return Global{Type: t, Mutable: mutable == 1, Init: init}, err
+}
+
+func (r *reader) export() (Export, error) {
+	var (
+		export Export
+		err    error
+	)
+	if export.Name, err = r.name(); err != nil {
+		return export, err
+	}
+	if export.Kind, err = r.byte(); err != nil {
+		return export, err
+	}
+	if export.Kind > ExternalGlobal {
+		return export, fmt.Errorf("%w: invalid export kind 0x%x", ErrInvalidModule, export.Kind)
+	}
+	export.Index, err = r.uvarint32()
+	return export, err
+}
+
+func (r *reader) element() (Element, error) {
+	if table, err := r.uvarint32(); err != nil || table != 0 {
+		return Element{}, fmt.Errorf("%w: unknown table", ErrInvalidModule)
+	}
+	offset, err := r.constExpr(I32)
+	if err != nil {
+		return Element{}, err
+	}
+	elem := Element{Offset: uint32(offset)}
+	err = r.vector(func() error {
+		fn, err := r.uvarint32()
+		elem.Funcs = append(elem.Funcs, fn)
+		return err
+	})
+	return elem, err
+}
+
+// maxLocals is the maximum number of local variables of a function, preventing
+// tiny modules from allocating huge frames.
+const maxLocals = 50000
+
+func (r *reader) function() (Function, error) {
+	size, err := r.uvarint32()
+	if err != nil {
+		return Function{}, err
+	}
+	body, err := r.bytes(size)
+	if err != nil {
+		return Function{}, err
+	}
+	var (
+		fn    Function
+		b     = &reader{data: body}
+		total uint64
+	)
+	err = b.vector(func() error {
+		n, err := b.uvarint32()
+		if err != nil {
+			return err
+		}
+		if total += uint64(n); total > maxLocals {
+			return fmt.Errorf("%w: too many locals", ErrInvalidModule)
+		}
+		t, err := b.valueType()
+		for i := uint32(0); i < n; i++ {
+			fn.Locals = append(fn.Locals, t)
+		}
+		return err
+	})
+	fn.Code = body[b.pos:]
+	return fn, err
+}
+
+func (r *reader) segment() (Data, error) {
+	if memory, err := r.uvarint32(); err != nil || memory != 0 {
+		return Data{}, fmt.Errorf("%w: unknown memory", ErrInvalidModule)
+	}
+	offset, err := r.constExpr(I32)
+	if err != nil {
+		return Data{}, err
+	}
+	n, err := r.uvarint32()
+	if err != nil {
+		return Data{}, err
+	}
+	init, err := r.bytes(n)
+	return Data{Offset: uint32(offset), Init: init}, err
+}
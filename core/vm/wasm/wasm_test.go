@@ -0,0 +1,357 @@
+
+
+package wasm
+
+import (
+	"errors"
+	"testing"
+)
+
+// Helpers assembling binary modules in tests.
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		if v >>= 7; v != 0 {
+			out = append(out, b|0x80)
+			continue
+		}
+		return append(out, b)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(uleb(uint64(len(items))), concat(items...))
+}
+
+func str(s string) []byte {
+	return concat(uleb(uint64(len(s))), []byte(s))
+}
+
+func section(id byte, items ...[]byte) []byte {
+	payload := vec(items...)
+	return concat([]byte{id}, uleb(uint64(len(payload))), payload)
+}
+
+func module(sections ...[]byte) []byte {
+	return concat(Magic, []byte{1, 0, 0, 0}, concat(sections...))
+}
+
+func functype(params, results []ValueType) []byte {
+	return concat([]byte{0x60}, vec(types(params)...), vec(types(results)...))
+}
+
+func types(ts []ValueType) [][]byte {
+	out := make([][]byte, len(ts))
+	for i, t := range ts {
+		out[i] = []byte{byte(t)}
+	}
+	return out
+}
+
+func body(locals []ValueType, code ...[]byte) []byte {
+	var decls [][]byte
+	for _, t := range locals {
+		decls = append(decls, []byte{1, byte(t)})
+	}
+	b := concat(vec(decls...), concat(code...), []byte{opEnd})
+	return concat(uleb(uint64(len(b))), b)
+}
+
+func export(name string, kind byte, index uint32) []byte {
+	return concat(str(name), []byte{kind}, uleb(uint64(index)))
+}
+
+func i32(v int32) []byte { return concat([]byte{opI32Const}, sleb(int64(v))) }
+func i64(v int64) []byte { return concat([]byte{opI64Const}, sleb(v)) }
+
+func op(code byte, imms ...uint64) []byte {
+	out := []byte{code}
+	for _, imm := range imms {
+		out = append(out, uleb(imm)...)
+	}
+	return out
+}
+
+// single builds a module exporting a single function "f" with the given
+// signature and body, and a one page memory.
+func single(params, results []ValueType, locals []ValueType, code ...[]byte) []byte {
+	return module(
+		section(sectionType, functype(params, results)),
+		section(sectionFunction, []byte{0}),
+		section(sectionMemory, []byte{0x00, 0x01}),
+		section(sectionExport, export("f", ExternalFunction, 0), export("memory", ExternalMemory, 0)),
+		section(sectionCode, body(locals, code...)),
+	)
+}
+
+var (
+	none   []ValueType
+	oneI32 = []ValueType{I32}
+	oneI64 = []ValueType{I64}
+	twoI32 = []ValueType{I32, I32}
+)
+
+// Tests that functions compute the expected results.
+func TestExecution(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    []byte
+		args    []uint64
+		result  uint64
+		wantErr error
+	}{
+		{
+			name:   "add",
+			code:   single(twoI32, oneI32, nil, op(opLocalGet, 0), op(opLocalGet, 1), op(opI32Add)),
+			args:   []uint64{40, 2},
+			result: 42,
+		},
+		{
+			name:   "i32 wraps",
+			code:   single(nil, oneI32, nil, i32(-1), i32(2), op(opI32Add)),
+			result: 1,
+		},
+		{
+			name:   "signed comparison",
+			code:   single(nil, oneI32, nil, i32(-1), i32(1), op(opI32LtS)),
+			result: 1,
+		},
+		{
+			name:   "extension",
+			code:   single(nil, oneI64, nil, i32(-2), op(opI64ExtendS)),
+			result: 0xfffffffffffffffe,
+		},
+		{
+			name:    "divide by zero",
+			code:    single(nil, oneI32, nil, i32(1), i32(0), op(opI32DivU)),
+			wantErr: ErrDivideByZero,
+		},
+		{
+			name:    "signed overflow",
+			code:    single(nil, oneI64, nil, i64(-1<<63), i64(-1), op(opI64DivS)),
+			wantErr: ErrIntegerOverflow,
+		},
+		{
+			// Sum of 1..n, with a loop branching back while the counter is positive
+			name: "loop",
+			code: single(oneI32, oneI32, oneI32,
+				op(opLoop), []byte{0x40},
+				op(opLocalGet, 1), op(opLocalGet, 0), op(opI32Add), op(opLocalSet, 1),
+				op(opLocalGet, 0), i32(1), op(opI32Sub), op(opLocalTee, 0),
+				op(opBrIf, 0),
+				op(opEnd),
+				op(opLocalGet, 1),
+			),
+			args:   []uint64{100},
+			result: 5050,
+		},
+		{
+			name: "if else",
+			code: single(oneI32, oneI32, nil,
+				op(opLocalGet, 0), op(opIf), []byte{byte(I32)}, i32(10), op(opElse), i32(20), op(opEnd),
+			),
+			args:   []uint64{0},
+			result: 20,
+		},
+		{
+			// Branch out of nested blocks, carrying a value
+			name: "block result",
+			code: single(nil, oneI32, nil,
+				op(opBlock), []byte{byte(I32)},
+				op(opBlock), []byte{0x40}, i32(7), op(opBr, 1), op(opEnd),
+				i32(8),
+				op(opEnd),
+			),
+			result: 7,
+		},
+		{
+			name: "br_table",
+			code: single(oneI32, oneI32, nil,
+				op(opBlock), []byte{0x40},
+				op(opBlock), []byte{0x40},
+				op(opLocalGet, 0), op(opBrTable), vec([]byte{0}, []byte{1}), []byte{1},
+				op(opEnd),
+				i32(100), op(opReturn),
+				op(opEnd),
+				i32(200),
+			),
+			args:   []uint64{0},
+			result: 100,
+		},
+		{
+			name:   "memory",
+			code:   single(nil, oneI64, nil, i32(8), i64(-1), op(opI64Store32, 2, 0), i32(4), op(opI64Load, 3, 4)),
+			result: 0xffffffff,
+		},
+		{
+			name:    "memory out of bounds",
+			code:    single(nil, oneI32, nil, i32(PageSize-2), op(opI32Load, 2, 0)),
+			wantErr: ErrOutOfBounds,
+		},
+		{
+			name:   "memory grow",
+			code:   single(nil, oneI32, nil, i32(2), op(opMemoryGrow, 0), op(opDrop), op(opMemorySize, 0)),
+			result: 3,
+		},
+		{
+			name:    "unreachable",
+			code:    single(nil, none, nil, op(opUnreachable)),
+			wantErr: ErrUnreachable,
+		},
+	}
+	for _, tt := range tests {
+		m, err := Decode(tt.code)
+		if err != nil {
+			t.Errorf("%s: failed to decode: %v", tt.name, err)
+			continue
+		}
+		in, err := Instantiate(m, nil, Config{})
+		if err != nil {
+			t.Errorf("%s: failed to instantiate: %v", tt.name, err)
+			continue
+		}
+		result, err := in.Call("f", tt.args...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && result != tt.result {
+			t.Errorf("%s: result mismatch: have %d, want %d", tt.name, result, tt.result)
+		}
+	}
+}
+
+// Tests that calls to defined, imported and indirect functions pass arguments
+// and results, and that infinite recursion traps.
+func TestCalls(t *testing.T) {
+	code := module(
+		section(sectionType, functype(twoI32, oneI32), functype(none, oneI32), functype(oneI32, oneI32)),
+		section(sectionImport, concat(str("env"), str("mul"), []byte{ExternalFunction, 0})),
+		section(sectionFunction, []byte{1}, []byte{0}, []byte{1}, []byte{2}),
+		section(sectionTable, []byte{0x70, 0x00, 0x03}),
+		section(sectionExport, export("direct", ExternalFunction, 1), export("indirect", ExternalFunction, 3), export("recurse", ExternalFunction, 4)),
+		section(sectionElement, concat([]byte{0}, i32(1), []byte{opEnd}, vec(uleb(1), uleb(0)))),
+		section(sectionCode,
+			body(nil, i32(6), i32(7), op(opCall, 2)),                       // direct: 6*7 through a defined function
+			body(nil, op(opLocalGet, 0), op(opLocalGet, 1), op(opCall, 0)), // calls the imported mul
+			body(nil, i32(3), i32(5), i32(2), op(opCallIndirect, 0, 0)),    // indirect: table[2] is mul
+			body(nil, op(opLocalGet, 0), op(opCall, 4)),                    // recurse: never returns
+		),
+	)
+	m, err := Decode(code)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	imports := Imports{"env": {"mul": {
+		Type: FuncType{Params: twoI32, Results: oneI32},
+		Call: func(in *Instance, args []uint64) (uint64, error) { return args[0] * args[1], nil },
+	}}}
+	in, err := Instantiate(m, imports, Config{})
+	if err != nil {
+		t.Fatalf("failed to instantiate: %v", err)
+	}
+	if result, err := in.Call("direct"); err != nil || result != 42 {
+		t.Errorf("direct call mismatch: have %d, %v, want 42", result, err)
+	}
+	if result, err := in.Call("indirect"); err != nil || result != 15 {
+		t.Errorf("indirect call mismatch: have %d, %v, want 15", result, err)
+	}
+	if _, err := in.Call("recurse", 1); !errors.Is(err, ErrCallStackExhausted) {
+		t.Errorf("recursion error mismatch: have %v, want %v", err, ErrCallStackExhausted)
+	}
+	if _, err := Instantiate(m, nil, Config{}); !errors.Is(err, ErrMissingImport) {
+		t.Errorf("missing import error mismatch: have %v, want %v", err, ErrMissingImport)
+	}
+}
+
+// Tests that malformed modules, ill typed code and unsupported features are
+// rejected when decoding.
+func TestInvalidModules(t *testing.T) {
+	tests := map[string][]byte{
+		"magic":            []byte("\x00asn\x01\x00\x00\x00"),
+		"version":          concat(Magic, []byte{2, 0, 0, 0}),
+		"truncated":        single(nil, none, nil, op(opNop))[:20],
+		"float type":       single([]ValueType{0x7d}, none, nil),
+		"float constant":   single(nil, none, nil, []byte{0x43, 0, 0, 0, 0}, op(opDrop)),
+		"unknown opcode":   single(nil, none, nil, []byte{0xc0}),
+		"type mismatch":    single(nil, oneI32, nil, i64(1)),
+		"stack underflow":  single(nil, oneI32, nil, op(opI32Add)),
+		"unknown local":    single(nil, none, nil, op(opLocalGet, 0), op(opDrop)),
+		"unknown label":    single(nil, none, nil, op(opBr, 1)),
+		"unknown function": single(nil, none, nil, op(opCall, 1)),
+		"if result":        single(nil, oneI32, nil, i32(1), op(opIf), []byte{byte(I32)}, i32(1), op(opEnd)),
+		"section order": module(
+			section(sectionFunction, []byte{0}),
+			section(sectionType, functype(nil, nil)),
+		),
+	}
+	for name, code := range tests {
+		if _, err := Decode(code); !errors.Is(err, ErrInvalidModule) {
+			t.Errorf("%s: error mismatch: have %v, want %v", name, err, ErrInvalidModule)
+		}
+	}
+}
+
+// Tests that the gas of basic blocks is charged before their execution, and that
+// running out of gas aborts the execution.
+func TestMetering(t *testing.T) {
+	code := single(oneI32, none, oneI32,
+		op(opLoop), []byte{0x40},
+		op(opLocalGet, 0), i32(1), op(opI32Sub), op(opLocalTee, 0), // 4 gas per iteration
+		op(opBrIf, 0), // 1 gas
+		op(opEnd),
+		i32(0), i32(42), op(opI32Store, 2, 0), // 3 gas after the loop
+	)
+	m, err := Decode(code)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	var used uint64
+	useGas := func(limit uint64) func(uint64) error {
+		used = 0
+		return func(gas uint64) error {
+			if used+gas > limit {
+				return errors.New("out of gas")
+			}
+			used += gas
+			return nil
+		}
+	}
+	in, _ := Instantiate(m, nil, Config{UseGas: useGas(1000)})
+	if _, err := in.Call("f", 10); err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if want := 10*(4+1) + GasBase*2 + GasMemory; used != want {
+		t.Errorf("gas mismatch: have %d, want %d", used, want)
+	}
+	// Running out of gas before the store leaves the memory untouched
+	in, _ = Instantiate(m, nil, Config{UseGas: useGas(50)})
+	if _, err := in.Call("f", 10); err == nil {
+		t.Fatalf("execution succeeded without gas")
+	}
+	if used != 50 || in.Memory()[0] != 0 {
+		t.Errorf("gas or memory mismatch after running out of gas: used %d, memory %x", used, in.Memory()[0])
+	}
+}
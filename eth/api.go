@@ -3,6 +3,7 @@
 package eth
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"errors"
@@ -140,6 +141,43 @@ func (api *PrivateMinerAPI) GetHashrate() uint64 {
 	return api.e.miner.HashRate()
 }
 
+// PrivateTxPoolAPI is the collection of transaction pool related APIs exposed
+// over the private txpool endpoint.
+type PrivateTxPoolAPI struct {
+	eth *Ethereum
+}
+
+// NewPrivateTxPoolAPI creates a new API definition for the full node private
+// transaction pool methods of the Ethereum service.
+func NewPrivateTxPoolAPI(eth *Ethereum) *PrivateTxPoolAPI {
+	return &PrivateTxPoolAPI{eth: eth}
+}
+
+// Export returns all remote transactions currently in the pool as an RLP stream,
+// which can be fed back into a pool with Import. The dump is returned over RPC
+// instead of being written to a local file, as the txpool namespace is commonly
+// exposed remotely and must not grant access to the file system of the node.
+func (api *PrivateTxPoolAPI) Export() (hexutil.Bytes, error) {
+	var buf bytes.Buffer
+	if _, err := api.eth.TxPool().Export(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Import injects a set of previously exported transactions into the pool,
+// subject to the usual validation rules.
+func (api *PrivateTxPoolAPI) Import(blob hexutil.Bytes) (map[string]hexutil.Uint, error) {
+	total, dropped, err := api.eth.TxPool().Import(bytes.NewReader(blob))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]hexutil.Uint{
+		"imported": hexutil.Uint(total - dropped),
+		"dropped":  hexutil.Uint(dropped),
+	}, nil
+}
+
 // PrivateAdminAPI is the collection of Ethereum full node-related APIs
 // exposed over the private admin endpoint.
 type PrivateAdminAPI struct {
@@ -290,7 +328,8 @@ func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error
 // PrivateDebugAPI is the collection of Ethereum full node APIs exposed over
 // the private debugging endpoint.
 type PrivateDebugAPI struct {
-	eth *Ethereum
+	eth         *Ethereum
+	checkpoints replayCheckpoints // Intermediate states retained by chain replays
 }
 
 // NewPrivateDebugAPI creates a new API definition for the full node-related
@@ -307,23 +346,50 @@ func (api *PrivateDebugAPI) Preimage(ctx context.Context, hash common.Hash) (hex
 	return nil, errors.New("unknown preimage")
 }
 
+// GetBlockWitness returns the RLP encoded execution witness of a block, which
+// contains the ancestor headers, state trie nodes and contract code needed to
+// re-execute the block without the state database. If witness generation was
+// not enabled when the block was imported, the block is re-executed to assemble
+// the witness, which requires its parent state to be available.
+func (api *PrivateDebugAPI) GetBlockWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	witness, err := api.eth.blockchain.BlockWitness(block)
+	if err != nil {
+		return nil, err
+	}
+	return rlp.EncodeToBytes(witness)
+}
+
 // BadBlockArgs represents the entries in the list returned when bad blocks are queried.
 type BadBlockArgs struct {
-	Hash  common.Hash            `json:"hash"`
-	Block map[string]interface{} `json:"block"`
-	RLP   string                 `json:"rlp"`
+	Hash       common.Hash            `json:"hash"`
+	Block      map[string]interface{} `json:"block"`
+	RLP        string                 `json:"rlp"`
+	ParentRoot common.Hash            `json:"parentStateRoot"`
+	Stage      string                 `json:"stage"`
+	Error      string                 `json:"error"`
 }
 
 // GetBadBlocks returns a list of the last 'bad blocks' that the client has seen on the network
 // and returns them as a JSON list of block-hashes
 func (api *PrivateDebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error) {
-	blocks := api.eth.BlockChain().BadBlocks()
-	results := make([]*BadBlockArgs, len(blocks))
+	bads := rawdb.ReadAllBadBlocks(api.eth.ChainDb())
+	results := make([]*BadBlockArgs, len(bads))
 
 	var err error
-	for i, block := range blocks {
+	for i, bad := range bads {
+		block := bad.Block
 		results[i] = &BadBlockArgs{
-			Hash: block.Hash(),
+			Hash:       block.Hash(),
+			ParentRoot: bad.ParentRoot,
+			Stage:      bad.Stage,
+			Error:      bad.Error,
 		}
 		if rlpBytes, err := rlp.EncodeToBytes(block); err != nil {
 			results[i].RLP = err.Error() // Hacky, but hey, it works
@@ -0,0 +1,142 @@
+
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// chainLogBatchLimit is the maximum number of chain event log entries
+	// retrieved from the database at once when feeding a subscription.
+	chainLogBatchLimit = 256
+
+	// chainLogEventChanSize is the size of the channel listening to the new
+	// entries of the chain event log.
+	chainLogEventChanSize = 16
+)
+
+// errChainLogDisabled is returned if the chain event log is requested, but the
+// node was not configured to maintain it.
+var errChainLogDisabled = errors.New("chain event log disabled, enable it with --chainevents")
+
+// PublicChainLogAPI provides access to the persistent log of the changes of the
+// canonical chain, allowing downstream indexers to resume from where they left.
+type PublicChainLogAPI struct {
+	e *Ethereum
+}
+
+// NewPublicChainLogAPI creates a new chain event log API.
+func NewPublicChainLogAPI(e *Ethereum) *PublicChainLogAPI {
+	return &PublicChainLogAPI{e}
+}
+
+// ChainLogResult is a chain event log entry as delivered to subscribers.
+type ChainLogResult struct {
+	Cursor      hexutil.Uint64 `json:"cursor"`
+	Type        string         `json:"type"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Receipts    types.Receipts `json:"receipts"`
+}
+
+// newChainLogResult converts a chain event log entry into its RPC form.
+func newChainLogResult(entry *core.ChainLogEntry) *ChainLogResult {
+	result := &ChainLogResult{
+		Cursor:      hexutil.Uint64(entry.Cursor),
+		Type:        "apply",
+		BlockHash:   entry.Hash,
+		BlockNumber: hexutil.Uint64(entry.Number),
+		Receipts:    entry.Receipts,
+	}
+	if entry.Reverted {
+		result.Type = "revert"
+	}
+	return result
+}
+
+// ChainLogCursor returns the cursor of the last entry in the chain event log.
+func (api *PublicChainLogAPI) ChainLogCursor() (hexutil.Uint64, error) {
+	if !api.e.config.ChainEvents {
+		return 0, errChainLogDisabled
+	}
+	return hexutil.Uint64(api.e.blockchain.ChainLogCursor()), nil
+}
+
+// ChainLog creates a subscription delivering the entries of the chain event log
+// following the given cursor in order, first the stored ones, then the new ones
+// as they are appended. If no cursor is given, only new entries are delivered.
+//
+// Blocks leaving the canonical chain are always announced before the ones
+// replacing them, so consumers can unwind their derived data by applying the
+// entries in order and persisting the cursor of the last one processed.
+func (api *PublicChainLogAPI) ChainLog(ctx context.Context, cursor *hexutil.Uint64) (*rpc.Subscription, error) {
+	if !api.e.config.ChainEvents {
+		return &rpc.Subscription{}, errChainLogDisabled
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	var (
+		chain = api.e.blockchain
+		head  = chain.ChainLogCursor()
+		next  = head
+	)
+	if cursor != nil {
+		if uint64(*cursor) > head {
+			return &rpc.Subscription{}, fmt.Errorf("cursor %d beyond chain event log head %d", uint64(*cursor), head)
+		}
+		next = uint64(*cursor)
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		// Subscribe before the replay, so no entries are missed in between
+		events := make(chan core.ChainLogEvent, chainLogEventChanSize)
+		sub := chain.SubscribeChainLogEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			// Deliver all the entries available. Notifications already pending
+			// are dropped beforehand, as the read picks up their entries anyway.
+			for {
+				for drained := false; !drained; {
+					select {
+					case <-events:
+					default:
+						drained = true
+					}
+				}
+				entries := chain.ChainLog(next, chainLogBatchLimit)
+				for _, entry := range entries {
+					notifier.Notify(rpcSub.ID, newChainLogResult(entry))
+					next = entry.Cursor
+				}
+				if len(entries) < chainLogBatchLimit {
+					break
+				}
+			}
+			// Wait for new entries to be appended
+			select {
+			case <-events:
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
@@ -0,0 +1,608 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// defaultReplayCheckpoint is the number of blocks between the intermediate
+	// states retained by a chain replay, if not configured otherwise.
+	defaultReplayCheckpoint = uint64(1024)
+
+	// replayCheckpointLimit is the maximum number of intermediate states kept in
+	// memory for later replays. Older ones are released as new ones are added.
+	replayCheckpointLimit = 16
+)
+
+// ReplayConfig holds the parameters of a chain replay.
+type ReplayConfig struct {
+	Tracers    map[string]*TraceConfig // Tracers to run on each transaction, by result name
+	Output     string                  // Name of the JSON lines file in the node's replay directory to write the results to
+	Resume     bool                    // Continue the replay after the last block in the output
+	Checkpoint *uint64                 // Number of blocks between retained intermediate states
+	Persist    bool                    // Whether to write the retained states to disk instead
+	Reexec     *uint64                 // Number of blocks to reexecute for a missing start state
+}
+
+// ReplayProgress is the notification sent to the subscriber of a chain replay
+// once the results of a block were written to the output.
+type ReplayProgress struct {
+	Block        hexutil.Uint64 `json:"block"`
+	Hash         common.Hash    `json:"hash"`
+	Transactions int            `json:"transactions"`
+	Error        string         `json:"error,omitempty"` // Failure ending the replay
+}
+
+// replayBlockResult is the line written to the output of a chain replay for each
+// replayed block.
+type replayBlockResult struct {
+	Block  hexutil.Uint64    `json:"block"`
+	Hash   common.Hash       `json:"hash"`
+	Traces []*replayTxResult `json:"traces"`
+}
+
+// replayTxResult is the result of all the tracers run on a single transaction.
+type replayTxResult struct {
+	Hash    common.Hash            `json:"hash"`
+	Results map[string]interface{} `json:"results,omitempty"` // Results by tracer name
+	Errors  map[string]string      `json:"errors,omitempty"`  // Failures by tracer name
+}
+
+// replayCheckpoints tracks the intermediate states retained in memory by chain
+// replays, so that later ones covering the same blocks start from the closest one
+// instead of reexecuting the chain from scratch.
+type replayCheckpoints struct {
+	once     sync.Once
+	database state.Database // State database shared by all replays
+
+	roots []common.Hash // Referenced checkpoint roots, oldest first
+	lock  sync.Mutex
+}
+
+// replayDatabase returns the state database shared by all chain replays.
+func (api *PrivateDebugAPI) replayDatabase() state.Database {
+	api.checkpoints.once.Do(func() {
+		api.checkpoints.database = api.stateDatabase()
+	})
+	return api.checkpoints.database
+}
+
+// checkpoint retains the state with the given root for later replays, releasing
+// the oldest one if too many are held. If persist is set, the state is written to
+// the database instead.
+func (api *PrivateDebugAPI) checkpoint(root common.Hash, persist bool) error {
+	triedb := api.replayDatabase().TrieDB()
+	if persist {
+		return triedb.Commit(root, false, nil)
+	}
+	api.checkpoints.lock.Lock()
+	defer api.checkpoints.lock.Unlock()
+
+	triedb.Reference(root, common.Hash{})
+	api.checkpoints.roots = append(api.checkpoints.roots, root)
+	if len(api.checkpoints.roots) > replayCheckpointLimit {
+		triedb.Dereference(api.checkpoints.roots[0])
+		api.checkpoints.roots = api.checkpoints.roots[1:]
+	}
+	return nil
+}
+
+// chainReplay is a single walk over a range of blocks, tracing the transactions
+// of each with all the configured tracers.
+type chainReplay struct {
+	api     *PrivateDebugAPI
+	config  *ReplayConfig
+	tracers []string // Names of the configured tracers, sorted
+
+	base       *types.Block   // Block whose state the walk starts from
+	statedb    *state.StateDB // State of the base block
+	start, end uint64         // Range of blocks to trace, inclusive
+	interval   uint64         // Number of blocks between checkpoints
+
+	output *os.File // File the results are appended to
+}
+
+// ReplayChain walks the chain between two blocks (inclusive) once, running all
+// the configured tracers on each transaction and appending the results to the
+// output file as one JSON object per line and block. Output files are kept in the
+// replays directory of the node. A subscription notifies the progress after each
+// block.
+//
+// Intermediate states are retained at regular intervals and once the replay
+// stops, so later replays of overlapping ranges, as well as resumed ones, start
+// from the closest one. If the replay is aborted or fails, it can be resumed from
+// the block following the last one in the output.
+func (api *PrivateDebugAPI) ReplayChain(ctx context.Context, start, end rpc.BlockNumber, config *ReplayConfig) (*rpc.Subscription, error) {
+	// Replaying a chain is a **long** operation, only do with subscriptions
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	replay, err := api.newChainReplay(api.blockNumber(start), api.blockNumber(end), config)
+	if err != nil {
+		return nil, err
+	}
+	sub := notifier.CreateSubscription()
+
+	go func() {
+		defer replay.output.Close()
+
+		err := replay.run(notifier.Closed(), sub.Err(), func(progress *ReplayProgress) {
+			notifier.Notify(sub.ID, progress)
+		})
+		if err != nil {
+			notifier.Notify(sub.ID, &ReplayProgress{Error: err.Error()})
+		}
+	}()
+	return sub, nil
+}
+
+// blockNumber resolves the special block numbers of the RPC API.
+func (api *PrivateDebugAPI) blockNumber(number rpc.BlockNumber) uint64 {
+	switch number {
+	case rpc.PendingBlockNumber, rpc.LatestBlockNumber:
+		return api.eth.blockchain.CurrentBlock().NumberU64()
+	default:
+		return uint64(number)
+	}
+}
+
+// newChainReplay validates the configuration of a replay, opens its output and
+// finds the state to start from.
+func (api *PrivateDebugAPI) newChainReplay(start, end uint64, config *ReplayConfig) (*chainReplay, error) {
+	if config == nil || len(config.Tracers) == 0 {
+		return nil, errors.New("no tracers configured")
+	}
+	if config.Output == "" {
+		return nil, errors.New("no output file configured")
+	}
+	// Only allow writing files in the replay directory of the node
+	if api.eth.replayDir == "" {
+		return nil, errors.New("chain replays need a data directory")
+	}
+	if name := config.Output; filepath.Base(name) != name || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid output file name %q", name)
+	}
+	// The genesis block has no transactions to trace
+	if start == 0 {
+		start = 1
+	}
+	if start > end {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", end, start)
+	}
+	if head := api.eth.blockchain.CurrentBlock().NumberU64(); end > head {
+		return nil, fmt.Errorf("end block #%d not found", end)
+	}
+	replay := &chainReplay{
+		api:      api,
+		config:   config,
+		start:    start,
+		end:      end,
+		interval: defaultReplayCheckpoint,
+	}
+	if config.Checkpoint != nil && *config.Checkpoint > 0 {
+		replay.interval = *config.Checkpoint
+	}
+	// Ensure all the tracers can be created before doing any work
+	for name, traceConfig := range config.Tracers {
+		_, cancel, err := newTracer(context.Background(), traceConfig)
+		if err != nil {
+			return nil, fmt.Errorf("tracer %q: %v", name, err)
+		}
+		cancel()
+		replay.tracers = append(replay.tracers, name)
+	}
+	sort.Strings(replay.tracers)
+
+	// Open the output, continuing after its last block if resuming
+	if err := replay.openOutput(); err != nil {
+		return nil, err
+	}
+	// Find the most recent block before the range that has the state available
+	reexec := defaultTraceReexec
+	if config.Reexec != nil {
+		reexec = *config.Reexec
+	}
+	block := api.eth.blockchain.GetBlockByNumber(replay.start - 1)
+	for i := uint64(0); block != nil; i++ {
+		if statedb, err := state.New(block.Root(), api.replayDatabase(), nil); err == nil {
+			replay.base, replay.statedb = block, statedb
+			break
+		}
+		if i == reexec || block.NumberU64() == 0 {
+			break
+		}
+		block = api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+	}
+	if replay.base == nil {
+		replay.output.Close()
+		return nil, fmt.Errorf("required historical state unavailable (reexec=%d)", reexec)
+	}
+	return replay, nil
+}
+
+// openOutput opens the output file of the replay. If resuming, the start of the
+// replay is moved after the last block written to it, otherwise the file must be
+// empty. Non-empty files not ending in a block result are never modified.
+func (r *chainReplay) openOutput() error {
+	if err := os.MkdirAll(r.api.eth.replayDir, 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(r.api.eth.replayDir, r.config.Output), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	var size int64
+	if info.Size() > 0 {
+		if !r.config.Resume {
+			f.Close()
+			return fmt.Errorf("output file %s not empty", r.config.Output)
+		}
+		line, end, err := readLastLine(f)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if line == nil {
+			f.Close()
+			return fmt.Errorf("invalid output file %s: no complete line", r.config.Output)
+		}
+		if err := r.resume(line); err != nil {
+			f.Close()
+			return err
+		}
+		size = end
+	}
+	// Drop any partially written line, and append after the complete ones
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	r.output = f
+	return nil
+}
+
+// resume moves the start of the replay after the block of the given output line,
+// ensuring the previous replay didn't leave a gap and wasn't reorged.
+func (r *chainReplay) resume(line []byte) error {
+	var last replayBlockResult
+	if err := json.Unmarshal(line, &last); err != nil {
+		return fmt.Errorf("invalid output file %s: %v", r.config.Output, err)
+	}
+	number := uint64(last.Block)
+	if block := r.api.eth.blockchain.GetBlockByNumber(number); block == nil || block.Hash() != last.Hash {
+		return fmt.Errorf("block #%d %#x of output not canonical", number, last.Hash)
+	}
+	if number+1 < r.start {
+		return fmt.Errorf("output ends at block #%d, before start block #%d", number, r.start)
+	}
+	if number >= r.end {
+		return fmt.Errorf("output already complete up to block #%d", number)
+	}
+	r.start = number + 1
+	return nil
+}
+
+// run walks the chain from the base block to the end of the range, processing the
+// blocks before the range untraced, and writing the traces of the ones in it to
+// the output. The walk stops early if the connection is closed or the subscriber
+// unsubscribes.
+func (r *chainReplay) run(closed <-chan interface{}, unsubscribed <-chan error, notify func(*ReplayProgress)) error {
+	var (
+		api      = r.api
+		database = api.replayDatabase()
+		statedb  = r.statedb
+		begin    = time.Now()
+		logged   time.Time
+		traced   int
+		proot    common.Hash
+	)
+	// Retain the state the replay stopped at, so resuming starts from there
+	defer func() {
+		if proot != (common.Hash{}) {
+			if err := api.checkpoint(proot, r.config.Persist); err != nil {
+				log.Warn("Failed to retain replay state", "root", proot, "err", err)
+			}
+			database.TrieDB().Dereference(proot)
+		}
+	}()
+	for number := r.base.NumberU64() + 1; number <= r.end; number++ {
+		// Stop replaying if interruption was requested
+		select {
+		case <-closed:
+			log.Warn("Chain replay aborted", "start", r.start, "end", r.end, "abort", number, "transactions", traced, "elapsed", time.Since(begin))
+			return nil
+		case <-unsubscribed:
+			log.Warn("Chain replay unsubscribed", "start", r.start, "end", r.end, "abort", number, "transactions", traced, "elapsed", time.Since(begin))
+			return nil
+		default:
+		}
+		// Print progress logs if long enough time elapsed
+		if time.Since(logged) > 8*time.Second {
+			if number >= r.start {
+				nodes, imgs := database.TrieDB().Size()
+				log.Info("Replaying chain segment", "start", r.start, "end", r.end, "current", number, "transactions", traced, "elapsed", time.Since(begin), "memory", nodes+imgs)
+			} else {
+				log.Info("Preparing state for chain replay", "block", number, "start", r.start, "elapsed", time.Since(begin))
+			}
+			logged = time.Now()
+		}
+		block := api.eth.blockchain.GetBlockByNumber(number)
+		if block == nil {
+			return fmt.Errorf("block #%d not found", number)
+		}
+		// Generate the state fast before the range, or trace the block in it
+		if number < r.start {
+			if _, _, _, err := api.eth.blockchain.Processor().Process(block, statedb, vm.Config{}); err != nil {
+				return fmt.Errorf("processing block %d failed: %v", number, err)
+			}
+		} else {
+			result, err := r.traceBlock(block, statedb)
+			if err != nil {
+				return err
+			}
+			if err := r.write(result); err != nil {
+				return err
+			}
+			traced += len(result.Traces)
+			notify(&ReplayProgress{Block: result.Block, Hash: result.Hash, Transactions: len(result.Traces)})
+		}
+		// Finalize the state so any modifications are written to the trie
+		root, err := statedb.Commit(api.eth.blockchain.Config().IsEIP158(block.Number()))
+		if err != nil {
+			return err
+		}
+		if number >= r.start && root != block.Root() {
+			return fmt.Errorf("replayed state of block #%d diverged: have %#x, want %#x", number, root, block.Root())
+		}
+		if err := statedb.Reset(root); err != nil {
+			return fmt.Errorf("state reset after block %d failed: %v", number, err)
+		}
+		database.TrieDB().Reference(root, common.Hash{})
+		if proot != (common.Hash{}) {
+			database.TrieDB().Dereference(proot)
+		}
+		proot = root
+
+		// Retain the intermediate state and flush the output at regular intervals
+		if number%r.interval == 0 && number < r.end {
+			if err := api.checkpoint(root, r.config.Persist); err != nil {
+				return err
+			}
+			if err := r.output.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+	log.Info("Chain replay finished", "start", r.start, "end", r.end, "transactions", traced, "elapsed", time.Since(begin))
+	return r.output.Sync()
+}
+
+// traceBlock executes all the transactions of a block on top of the state of its
+// parent, running all the configured tracers on each, and finalizes the block.
+func (r *chainReplay) traceBlock(block *types.Block, statedb *state.StateDB) (*replayBlockResult, error) {
+	var (
+		chain    = r.api.eth.blockchain
+		config   = chain.Config()
+		signer   = types.MakeSigner(config, block.Number())
+		blockCtx = core.NewEVMBlockContext(block.Header(), chain, nil)
+		txs      = block.Transactions()
+		result   = &replayBlockResult{
+			Block:  hexutil.Uint64(block.NumberU64()),
+			Hash:   block.Hash(),
+			Traces: make([]*replayTxResult, len(txs)),
+		}
+	)
+	// Mutate the state if we're at the DAO hard fork block, like block processing
+	if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(block.Number()) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+	for i, tx := range txs {
+		msg, err := tx.AsMessage(signer)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %#x invalid: %v", tx.Hash(), err)
+		}
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+
+		tracers, release, err := r.newTracers()
+		if err != nil {
+			return nil, err
+		}
+		// Execute the transaction once, feeding all the tracers
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, config, vm.Config{Debug: true, Tracer: tracers})
+		res, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.Gas()))
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+		}
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(config.IsEIP158(block.Number()))
+
+		traces := &replayTxResult{Hash: tx.Hash()}
+		for j, name := range r.tracers {
+			output, err := tracerResult(tracers[j], res)
+			if err != nil {
+				if traces.Errors == nil {
+					traces.Errors = make(map[string]string)
+				}
+				traces.Errors[name] = err.Error()
+				continue
+			}
+			if traces.Results == nil {
+				traces.Results = make(map[string]interface{})
+			}
+			traces.Results[name] = output
+		}
+		// Releasing stops the tracers, so only do it once their results are read
+		release()
+		result.Traces[i] = traces
+	}
+	// Apply the block rewards
+	r.api.eth.engine.Finalize(chain, block.Header(), statedb, txs, block.Uncles())
+	return result, nil
+}
+
+// newTracers creates all the configured tracers for tracing a transaction. The
+// returned function releases their resources.
+func (r *chainReplay) newTracers() (multiTracer, func(), error) {
+	var (
+		tracers = make(multiTracer, 0, len(r.tracers))
+		cancels = make([]context.CancelFunc, 0, len(r.tracers))
+	)
+	release := func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+	for _, name := range r.tracers {
+		// The request context is long gone, only rely on the tracer timeouts
+		tracer, cancel, err := newTracer(context.Background(), r.config.Tracers[name])
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("tracer %q: %v", name, err)
+		}
+		tracers, cancels = append(tracers, tracer), append(cancels, cancel)
+	}
+	return tracers, release, nil
+}
+
+// write appends the results of a block to the output as a single line.
+func (r *chainReplay) write(result *replayBlockResult) error {
+	blob, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	_, err = r.output.Write(append(blob, '\n'))
+	return err
+}
+
+// readLastLine returns the last complete line of a file, or nil if there's none,
+// along with the size of the file up to the end of that line.
+func readLastLine(f *os.File) ([]byte, int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	end, err := lastIndexByte(f, info.Size(), '\n')
+	if err != nil || end < 0 {
+		return nil, 0, err
+	}
+	begin, err := lastIndexByte(f, end, '\n')
+	if err != nil {
+		return nil, 0, err
+	}
+	line := make([]byte, end-begin-1)
+	if _, err := f.ReadAt(line, begin+1); err != nil {
+		return nil, 0, err
+	}
+	return line, end + 1, nil
+}
+
+// lastIndexByte returns the offset of the last occurrence of a byte in a file
+// before the given offset, or -1 if there's none.
+func lastIndexByte(f *os.File, before int64, c byte) (int64, error) {
+	buf := make([]byte, 64*1024)
+	for before > 0 {
+		n := int64(len(buf))
+		if n > before {
+			n = before
+		}
+		if _, err := f.ReadAt(buf[:n], before-n); err != nil {
+			return 0, err
+		}
+		if i := bytes.LastIndexByte(buf[:n], c); i >= 0 {
+			return before - n + int64(i), nil
+		}
+		before -= n
+	}
+	return -1, nil
+}
+
+// multiTracer feeds the execution of a transaction into several tracers.
+type multiTracer []vm.Tracer
+
+func (t multiTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	var failed error
+	for _, tracer := range t {
+		if err := tracer.CaptureStart(from, to, create, input, gas, value); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (t multiTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	var failed error
+	for _, tracer := range t {
+		if err := tracer.CaptureState(env, pc, op, gas, cost, memory, stack, rStack, rData, contract, depth, err); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (t multiTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, contract *vm.Contract, depth int, err error) error {
+	var failed error
+	for _, tracer := range t {
+		if err := tracer.CaptureFault(env, pc, op, gas, cost, memory, stack, rStack, contract, depth, err); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
+
+func (t multiTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	var failed error
+	for _, tracer := range t {
+		if err := tracer.CaptureEnd(output, gasUsed, d, err); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	return failed
+}
@@ -0,0 +1,306 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// newReplayTestAPI creates a debug API on top of a chain of the given length,
+// with a value transfer in each block.
+func newReplayTestAPI(t *testing.T, n int) (*PrivateDebugAPI, []*types.Block) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		db     = rawdb.NewMemoryDatabase()
+		gspec  = &core.Genesis{Config: params.TestChainConfig, Alloc: core.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}}}
+		signer = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	// Generate the chain in a separate database, so only the genesis state is on disk
+	gspec.MustCommit(db)
+	gendb := rawdb.NewMemoryDatabase()
+	blocks, _ := core.GenerateChain(gspec.Config, gspec.MustCommit(gendb), ethash.NewFaker(), gendb, n, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0xaa}, big.NewInt(1000), params.TxGas, big.NewInt(1), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	return NewPrivateDebugAPI(&Ethereum{blockchain: chain, engine: ethash.NewFaker(), chainDb: db}), blocks
+}
+
+// replayTestConfig returns a replay configuration running the struct logger and
+// the call tracer, writing to the given file of the replay directory.
+func replayTestConfig(output string) *ReplayConfig {
+	tracer := "callTracer"
+	return &ReplayConfig{
+		Tracers: map[string]*TraceConfig{
+			"structLogs": nil,
+			"calls":      {Tracer: &tracer},
+		},
+		Output: output,
+	}
+}
+
+// replay runs a chain replay to its end.
+func replay(api *PrivateDebugAPI, start, end uint64, config *ReplayConfig) ([]*ReplayProgress, error) {
+	r, err := api.newChainReplay(start, end, config)
+	if err != nil {
+		return nil, err
+	}
+	defer r.output.Close()
+
+	var progress []*ReplayProgress
+	err = r.run(nil, nil, func(p *ReplayProgress) { progress = append(progress, p) })
+	return progress, err
+}
+
+// readReplayOutput parses all the lines of a replay output.
+func readReplayOutput(t *testing.T, path string) []*replayBlockResult {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open output: %v", err)
+	}
+	defer f.Close()
+
+	var results []*replayBlockResult
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		result := new(replayBlockResult)
+		if err := json.Unmarshal(scanner.Bytes(), result); err != nil {
+			t.Fatalf("invalid output line %d: %v", len(results), err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Tests that a chain replay traces the transactions of each block with all the
+// tracers, and that it resumes after the last written block from the retained
+// intermediate state.
+func TestReplayChain(t *testing.T) {
+	api, blocks := newReplayTestAPI(t, 10)
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	api.eth.replayDir = dir
+	output := filepath.Join(dir, "traces.jsonl")
+
+	progress, err := replay(api, 1, 5, replayTestConfig("traces.jsonl"))
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if len(progress) != 5 {
+		t.Fatalf("progress notification count mismatch: have %d, want %d", len(progress), 5)
+	}
+	// A non resumed replay refuses to overwrite the output
+	if _, err := replay(api, 6, 10, replayTestConfig("traces.jsonl")); err == nil {
+		t.Fatalf("non-empty output overwritten")
+	}
+	// Simulate a partially written line, and resume without any reexecution
+	f, _ := os.OpenFile(output, os.O_WRONLY|os.O_APPEND, 0644)
+	f.WriteString(`{"block":"0x6","ha`)
+	f.Close()
+
+	config := replayTestConfig("traces.jsonl")
+	config.Resume = true
+	config.Reexec = new(uint64)
+	if _, err := replay(api, 1, 10, config); err != nil {
+		t.Fatalf("resumed replay failed: %v", err)
+	}
+	if _, err := replay(api, 1, 10, config); err == nil {
+		t.Fatalf("complete replay resumed")
+	}
+	results := readReplayOutput(t, output)
+	if len(results) != len(blocks) {
+		t.Fatalf("output line count mismatch: have %d, want %d", len(results), len(blocks))
+	}
+	for i, result := range results {
+		block := blocks[i]
+		if uint64(result.Block) != block.NumberU64() || result.Hash != block.Hash() {
+			t.Errorf("block %d: have #%d %x, want #%d %x", i, result.Block, result.Hash, block.NumberU64(), block.Hash())
+		}
+		if len(result.Traces) != 1 {
+			t.Fatalf("block %d: trace count mismatch: have %d, want 1", i, len(result.Traces))
+		}
+		trace := result.Traces[0]
+		if trace.Hash != block.Transactions()[0].Hash() {
+			t.Errorf("block %d: transaction hash mismatch: have %x, want %x", i, trace.Hash, block.Transactions()[0].Hash())
+		}
+		if len(trace.Errors) != 0 {
+			t.Errorf("block %d: tracing failed: %v", i, trace.Errors)
+		}
+		for _, name := range []string{"structLogs", "calls"} {
+			if trace.Results[name] == nil {
+				t.Errorf("block %d: missing %s result", i, name)
+			}
+		}
+	}
+}
+
+// Tests that persisted replay states are written to the database.
+func TestReplayChainPersist(t *testing.T) {
+	api, blocks := newReplayTestAPI(t, 4)
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	api.eth.replayDir = dir
+
+	config := replayTestConfig("traces.jsonl")
+	config.Checkpoint, config.Persist = new(uint64), true
+	*config.Checkpoint = 2
+
+	if _, err := replay(api, 1, 4, config); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	database := state.NewDatabase(api.eth.ChainDb())
+	for _, block := range blocks {
+		_, err := state.New(block.Root(), database, nil)
+		if persisted := block.NumberU64()%2 == 0; persisted != (err == nil) {
+			t.Errorf("block #%d: state persisted mismatch: have %v, want %v", block.NumberU64(), err == nil, persisted)
+		}
+	}
+}
+
+// Tests that outputs not written by a replay are refused and left untouched,
+// whether resuming or not.
+func TestReplayChainForeignOutput(t *testing.T) {
+	api, _ := newReplayTestAPI(t, 4)
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	api.eth.replayDir = dir
+
+	tests := []string{
+		"important data without a newline",
+		"important data\n",
+		`{"block":"0x1","ha`,
+		"{\"block\":\"0x1\"}\nmore data",
+	}
+	for i, content := range tests {
+		for _, resume := range []bool{false, true} {
+			output := filepath.Join(dir, "foreign.txt")
+			if err := ioutil.WriteFile(output, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			config := replayTestConfig("foreign.txt")
+			config.Resume = resume
+			if _, err := replay(api, 1, 4, config); err == nil {
+				t.Errorf("test %d, resume %v: foreign output accepted", i, resume)
+			}
+			if blob, _ := ioutil.ReadFile(output); string(blob) != content {
+				t.Errorf("test %d, resume %v: output modified: have %q, want %q", i, resume, blob, content)
+			}
+		}
+	}
+}
+
+// Tests that replays only write files in the replay directory of the node.
+func TestReplayChainOutputPath(t *testing.T) {
+	api, _ := newReplayTestAPI(t, 2)
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Nodes without a data directory refuse replays
+	if _, err := replay(api, 1, 2, replayTestConfig("traces.jsonl")); err == nil {
+		t.Fatalf("replay accepted without a data directory")
+	}
+	api.eth.replayDir = filepath.Join(dir, "replays")
+
+	for _, name := range []string{
+		filepath.Join(dir, "traces.jsonl"),
+		filepath.Join("..", "traces.jsonl"),
+		filepath.Join("sub", "traces.jsonl"),
+		".",
+		"..",
+	} {
+		if _, err := replay(api, 1, 2, replayTestConfig(name)); err == nil {
+			t.Errorf("output %q accepted", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "traces.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("file written outside the replay directory: %v", err)
+	}
+	// Plain file names are created in the replay directory
+	if _, err := replay(api, 1, 2, replayTestConfig("traces.jsonl")); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if results := readReplayOutput(t, filepath.Join(dir, "replays", "traces.jsonl")); len(results) != 2 {
+		t.Errorf("output line count mismatch: have %d, want 2", len(results))
+	}
+}
+
+// Tests that a replay stops once its subscriber unsubscribes.
+func TestReplayChainUnsubscribe(t *testing.T) {
+	api, _ := newReplayTestAPI(t, 4)
+
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	api.eth.replayDir = dir
+
+	r, err := api.newChainReplay(1, 4, replayTestConfig("traces.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to create replay: %v", err)
+	}
+	defer r.output.Close()
+
+	unsubscribed := make(chan error)
+	close(unsubscribed)
+	if err := r.run(nil, unsubscribed, func(p *ReplayProgress) {
+		t.Errorf("block #%d replayed after unsubscribing", p.Block)
+	}); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if results := readReplayOutput(t, filepath.Join(dir, "traces.jsonl")); len(results) != 0 {
+		t.Errorf("output written after unsubscribing: %d blocks", len(results))
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -35,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/srcmap"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/internal/fafapi"
 	"github.com/ethereum/go-ethereum/log"
@@ -58,9 +60,35 @@ const (
 // TraceConfig holds extra parameters to trace functions.
 type TraceConfig struct {
 	*vm.LogConfig
-	Tracer  *string
-	Timeout *string
-	Reexec  *uint64
+	Tracer    *string
+	Timeout   *string
+	Reexec    *uint64
+	SourceMap *SourceMapConfig
+}
+
+// SourceMapConfig holds the compiler output used to map the structured logs to
+// the sources of the executed contracts.
+type SourceMapConfig struct {
+	CombinedJSON json.RawMessage   `json:"combinedJson"` // Output of solc --combined-json
+	Sources      map[string]string `json:"sources"`      // Contents of the files in its source list
+
+	once   sync.Once
+	mapper *srcmap.Mapper
+	err    error
+}
+
+// sourceMapper creates the source mapper on first use, sharing it between all
+// the transactions traced with the same configuration.
+func (c *SourceMapConfig) sourceMapper() (*srcmap.Mapper, error) {
+	c.once.Do(func() {
+		// Never fall back to reading the sources from the local file system
+		sources := c.Sources
+		if sources == nil {
+			sources = make(map[string]string)
+		}
+		c.mapper, c.err = srcmap.FromCombinedJSON(c.CombinedJSON, sources)
+	})
+	return c.mapper, c.err
 }
 
 // StdTraceConfig holds extra parameters to standard-json trace functions.
@@ -100,6 +128,16 @@ type txTraceTask struct {
 	index   int            // Transaction offset in the block
 }
 
+// stateDatabase creates an ephemeral state database for re-executing blocks,
+// storing the state in the trie format of the chain.
+func (api *PrivateDebugAPI) stateDatabase() state.Database {
+	config := &trie.Config{Cache: 16}
+	if api.eth.blockchain.Config().BinaryTrie {
+		return state.NewBinaryDatabaseWithConfig(api.eth.ChainDb(), config)
+	}
+	return state.NewDatabaseWithConfig(api.eth.ChainDb(), config)
+}
+
 // TraceChain returns the structured logs created during the execution of EVM
 // between two blocks (excluding start) and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
@@ -148,7 +186,7 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 
 	// Ensure we have a valid starting state before doing any work
 	origin := start.NumberU64()
-	database := state.NewDatabaseWithConfig(api.eth.ChainDb(), &trie.Config{Cache: 16, Preimages: true})
+	database := api.stateDatabase()
 
 	if number := start.NumberU64(); number > 0 {
 		start = api.eth.blockchain.GetBlock(start.ParentHash(), start.NumberU64()-1)
@@ -657,7 +695,7 @@ func (api *PrivateDebugAPI) computeStateDB(block *types.Block, reexec uint64) (*
 	}
 	// Otherwise try to reexec blocks until we find a state or reach our limit
 	origin := block.NumberU64()
-	database := state.NewDatabaseWithConfig(api.eth.ChainDb(), &trie.Config{Cache: 16, Preimages: true})
+	database := api.stateDatabase()
 
 	for i := uint64(0); i < reexec; i++ {
 		block = api.eth.blockchain.GetBlock(block.ParentHash(), block.NumberU64()-1)
@@ -778,62 +816,91 @@ func (api *PrivateDebugAPI) TraceCall(ctx context.Context, args fafapi.CallArgs,
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
-	// Assemble the structured logger or the JavaScript tracer
-	var (
-		tracer    vm.Tracer
-		err       error
-		txContext = core.NewEVMTxContext(message)
-	)
+	tracer, cancel, err := newTracer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	// Run the transaction with tracing enabled.
+	txContext := core.NewEVMTxContext(message)
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+
+	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %v", err)
+	}
+	return tracerResult(tracer, result)
+}
+
+// newTracer assembles the structured logger or the JavaScript or native tracer
+// requested by the configuration. The returned function releases the resources
+// of the tracer, and must be called once the traced transaction finished.
+func newTracer(ctx context.Context, config *TraceConfig) (vm.Tracer, context.CancelFunc, error) {
 	switch {
+	case config != nil && config.Tracer != nil && config.SourceMap != nil:
+		return nil, nil, errors.New("source maps are only supported by the struct logger")
+
 	case config != nil && config.Tracer != nil:
 		// Define a meaningful timeout of a single transaction trace
 		timeout := defaultTraceTimeout
 		if config.Timeout != nil {
+			var err error
 			if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
-		// Constuct the JavaScript tracer to execute with
-		if tracer, err = tracers.New(*config.Tracer); err != nil {
-			return nil, err
+		// Construct the native or JavaScript tracer to execute with
+		var (
+			stoppable tracers.NativeTracer
+			err       error
+		)
+		if native, ok := tracers.NewNative(*config.Tracer); ok {
+			stoppable = native
+		} else if stoppable, err = tracers.New(*config.Tracer); err != nil {
+			return nil, nil, err
 		}
 		// Handle timeouts and RPC cancellations
 		deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
 		go func() {
 			<-deadlineCtx.Done()
-			tracer.(*tracers.Tracer).Stop(errors.New("execution timeout"))
+			stoppable.Stop(errors.New("execution timeout"))
 		}()
-		defer cancel()
+		return stoppable, cancel, nil
 
 	case config == nil:
-		tracer = vm.NewStructLogger(nil)
+		return vm.NewStructLogger(nil), func() {}, nil
+
+	case config.SourceMap != nil:
+		mapper, err := config.SourceMap.sourceMapper()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid source map: %v", err)
+		}
+		return srcmap.NewTracer(mapper, config.LogConfig), func() {}, nil
 
 	default:
-		tracer = vm.NewStructLogger(config.LogConfig)
+		return vm.NewStructLogger(config.LogConfig), func() {}, nil
 	}
-	// Run the transaction with tracing enabled.
-	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.eth.blockchain.Config(), vm.Config{Debug: true, Tracer: tracer})
+}
 
-	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.Gas()))
-	if err != nil {
-		return nil, fmt.Errorf("tracing failed: %v", err)
-	}
-	// Depending on the tracer type, format and return the output
+// tracerResult formats the output of a tracer created by newTracer, once the
+// traced transaction finished with the given result.
+func tracerResult(tracer vm.Tracer, result *core.ExecutionResult) (interface{}, error) {
 	switch tracer := tracer.(type) {
 	case *vm.StructLogger:
-		// If the result contains a revert reason, return it.
-		returnVal := fmt.Sprintf("%x", result.Return())
-		if len(result.Revert()) > 0 {
-			returnVal = fmt.Sprintf("%x", result.Revert())
-		}
-		return &fafapi.ExecutionResult{
-			Gas:         result.UsedGas,
-			Failed:      result.Failed(),
-			ReturnValue: returnVal,
-			StructLogs:  fafapi.FormatLogs(tracer.StructLogs()),
-		}, nil
-
-	case *tracers.Tracer:
+		return structLogResult(result, tracer.StructLogs()), nil
+
+	case *srcmap.Tracer:
+		res, trace := structLogResult(result, tracer.StructLogs()), tracer.Trace()
+		for i, step := range trace.Steps {
+			res.StructLogs[i].Source = step.Source
+		}
+		for _, index := range trace.Reverts {
+			res.Reverts = append(res.Reverts, fafapi.RevertRes{Step: index, Source: trace.Steps[index].Source})
+		}
+		return res, nil
+
+	case tracers.NativeTracer:
 		return tracer.GetResult()
 
 	default:
@@ -841,6 +908,21 @@ func (api *PrivateDebugAPI) traceTx(ctx context.Context, message core.Message, v
 	}
 }
 
+// structLogResult assembles the result of a transaction traced by the struct logger.
+func structLogResult(result *core.ExecutionResult, logs []vm.StructLog) *fafapi.ExecutionResult {
+	// If the result contains a revert reason, return it.
+	returnVal := fmt.Sprintf("%x", result.Return())
+	if len(result.Revert()) > 0 {
+		returnVal = fmt.Sprintf("%x", result.Revert())
+	}
+	return &fafapi.ExecutionResult{
+		Gas:         result.UsedGas,
+		Failed:      result.Failed(),
+		ReturnValue: returnVal,
+		StructLogs:  fafapi.FormatLogs(logs),
+	}
+}
+
 // computeTxEnv returns the execution environment of a certain transaction.
 func (api *PrivateDebugAPI) computeTxEnv(block *types.Block, txIndex int, reexec uint64) (core.Message, vm.BlockContext, *state.StateDB, error) {
 	// Create the parent state database
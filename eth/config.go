@@ -117,8 +117,14 @@ type Config struct {
 	// for nodes to connect to.
 	DiscoveryURLs []string
 
-	NoPruning  bool // Whether to disable pruning and flush everything to disk
-	NoPrefetch bool // Whether to disable prefetching and only load state on demand
+	NoPruning      bool // Whether to disable pruning and flush everything to disk
+	NoPrefetch     bool // Whether to disable prefetching and only load state on demand
+	NoTriePrefetch bool // Whether to disable concurrent trie node prefetching during block processing
+	StateDiffs     bool // Whether to persist per-block state diffs for historical state queries
+	Witnesses      bool // Whether to generate and persist execution witnesses of imported blocks
+	ChainEvents    bool // Whether to persist the log of canonical chain changes
+
+	ParallelExec int `toml:",omitempty"` // Number of workers executing block transactions in parallel (0 = sequential)
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
@@ -149,7 +155,7 @@ type Config struct {
 	TrieDirtyCache          int
 	TrieTimeout             time.Duration
 	SnapshotCache           int
-	Preimages               bool
+	Preimages               bool `toml:",omitempty"` // Deprecated: preimages are always recorded, kept for config file compatibility
 
 	// Mining options
 	Miner miner.Config
@@ -23,6 +23,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DiscoveryURLs           []string
 		NoPruning               bool
 		NoPrefetch              bool
+		NoTriePrefetch          bool
+		StateDiffs              bool
+		Witnesses               bool
+		ChainEvents             bool
+		ParallelExec            int                    `toml:",omitempty"`
 		TxLookupLimit           uint64                 `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               int                    `toml:",omitempty"`
@@ -43,7 +48,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		TrieDirtyCache          int
 		TrieTimeout             time.Duration
 		SnapshotCache           int
-		Preimages               bool
+		Preimages               bool `toml:",omitempty"`
 		Miner                   miner.Config
 		Ethash                  ethash.Config
 		TxPool                  core.TxPoolConfig
@@ -64,6 +69,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DiscoveryURLs = c.DiscoveryURLs
 	enc.NoPruning = c.NoPruning
 	enc.NoPrefetch = c.NoPrefetch
+	enc.NoTriePrefetch = c.NoTriePrefetch
+	enc.StateDiffs = c.StateDiffs
+	enc.Witnesses = c.Witnesses
+	enc.ChainEvents = c.ChainEvents
+	enc.ParallelExec = c.ParallelExec
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.Whitelist = c.Whitelist
 	enc.LightServ = c.LightServ
@@ -109,6 +119,11 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DiscoveryURLs           []string
 		NoPruning               *bool
 		NoPrefetch              *bool
+		NoTriePrefetch          *bool
+		StateDiffs              *bool
+		Witnesses               *bool
+		ChainEvents             *bool
+		ParallelExec            *int                   `toml:",omitempty"`
 		TxLookupLimit           *uint64                `toml:",omitempty"`
 		Whitelist               map[uint64]common.Hash `toml:"-"`
 		LightServ               *int                   `toml:",omitempty"`
@@ -129,7 +144,7 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		TrieDirtyCache          *int
 		TrieTimeout             *time.Duration
 		SnapshotCache           *int
-		Preimages               *bool
+		Preimages               *bool `toml:",omitempty"`
 		Miner                   *miner.Config
 		Ethash                  *ethash.Config
 		TxPool                  *core.TxPoolConfig
@@ -165,6 +180,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.NoPrefetch != nil {
 		c.NoPrefetch = *dec.NoPrefetch
 	}
+	if dec.NoTriePrefetch != nil {
+		c.NoTriePrefetch = *dec.NoTriePrefetch
+	}
+	if dec.StateDiffs != nil {
+		c.StateDiffs = *dec.StateDiffs
+	}
+	if dec.Witnesses != nil {
+		c.Witnesses = *dec.Witnesses
+	}
+	if dec.ChainEvents != nil {
+		c.ChainEvents = *dec.ChainEvents
+	}
+	if dec.ParallelExec != nil {
+		c.ParallelExec = *dec.ParallelExec
+	}
 	if dec.TxLookupLimit != nil {
 		c.TxLookupLimit = *dec.TxLookupLimit
 	}
@@ -201,7 +201,7 @@ func (pm *ProtocolManager) makeProtocol(version uint) p2p.Protocol {
 		Version: version,
 		Length:  length,
 		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
-			return pm.runPeer(pm.newPeer(int(version), p, rw, pm.txpool.Get))
+			return pm.runPeer(pm.newPeer(int(version), p, rw, pm.getPublicTx))
 		},
 		NodeInfo: func() interface{} {
 			return pm.NodeInfo()
@@ -764,7 +764,7 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return errResp(ErrDecode, "msg %v: %v", msg, err)
 			}
 			// Retrieve the requested transaction, skipping if unknown to us
-			tx := pm.txpool.Get(hash)
+			tx := pm.getPublicTx(hash)
 			if tx == nil {
 				continue
 			}
@@ -837,6 +837,27 @@ func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
 	}
 }
 
+// getPublicTx retrieves a transaction from the local txpool if it's allowed to
+// be propagated to the network, or nil if it's unknown or private.
+func (pm *ProtocolManager) getPublicTx(hash common.Hash) *types.Transaction {
+	if pm.txpool.IsPrivate(hash) {
+		return nil
+	}
+	return pm.txpool.Get(hash)
+}
+
+// publicTxs filters out all the private transactions from a batch, which must
+// never be propagated to the network.
+func (pm *ProtocolManager) publicTxs(txs types.Transactions) types.Transactions {
+	filtered := make(types.Transactions, 0, len(txs))
+	for _, tx := range txs {
+		if !pm.txpool.IsPrivate(tx.Hash()) {
+			filtered = append(filtered, tx)
+		}
+	}
+	return filtered
+}
+
 // BroadcastTransactions will propagate a batch of transactions to all peers which are not known to
 // already have the given transaction.
 func (pm *ProtocolManager) BroadcastTransactions(txs types.Transactions, propagate bool) {
@@ -896,13 +917,18 @@ func (pm *ProtocolManager) txBroadcastLoop() {
 	for {
 		select {
 		case event := <-pm.txsCh:
+			// Private transactions are kept for the local miner only
+			txs := pm.publicTxs(event.Txs)
+			if len(txs) == 0 {
+				continue
+			}
 			// For testing purpose only, disable propagation
 			if pm.broadcastTxAnnouncesOnly {
-				pm.BroadcastTransactions(event.Txs, false)
+				pm.BroadcastTransactions(txs, false)
 				continue
 			}
-			pm.BroadcastTransactions(event.Txs, true)  // First propagate transactions to peers
-			pm.BroadcastTransactions(event.Txs, false) // Only then announce to the rest
+			pm.BroadcastTransactions(txs, true)  // First propagate transactions to peers
+			pm.BroadcastTransactions(txs, false) // Only then announce to the rest
 
 		case <-pm.txsSub.Err():
 			return
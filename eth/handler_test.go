@@ -678,3 +678,82 @@ func TestBroadcastMalformedBlock(t *testing.T) {
 		}
 	}
 }
+
+// Tests that private transactions are never announced, broadcast or served to
+// remote peers, only the public ones.
+func TestPrivateTransactions(t *testing.T) {
+	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
+	defer pm.Stop()
+
+	pool := pm.txpool.(*testTxPool)
+
+	key, _ := crypto.GenerateKey()
+	private := newTestTransaction(key, 0, 0)
+	public := newTestTransaction(testBankKey, 0, 0)
+
+	pool.addPrivate([]*types.Transaction{private})
+	pool.AddRemotes([]*types.Transaction{public})
+	time.Sleep(100 * time.Millisecond) // Wait until new tx even gets out of the system (lame)
+
+	// Check the filters the networking layer relies on
+	if txs := pm.publicTxs(types.Transactions{private, public}); len(txs) != 1 || txs[0] != public {
+		t.Fatalf("public transaction filtering mismatch: have %v, want [%x]", txs, public.Hash())
+	}
+	if tx := pm.getPublicTx(private.Hash()); tx != nil {
+		t.Fatalf("private transaction retrievable: %x", tx.Hash())
+	}
+	if tx := pm.getPublicTx(public.Hash()); tx != public {
+		t.Fatalf("public transaction retrieval mismatch: have %v, want %x", tx, public.Hash())
+	}
+	// Connect a peer and ensure only the public transactions are announced on sync
+	p, _ := newTestPeer("peer", eth65, pm, true)
+	defer p.close()
+
+	readHashes := func() []common.Hash {
+		msg, err := p.app.ReadMsg()
+		if err != nil {
+			t.Fatalf("failed to read message: %v", err)
+		}
+		defer msg.Discard()
+
+		switch msg.Code {
+		case NewPooledTransactionHashesMsg:
+			var hashes []common.Hash
+			if err := msg.Decode(&hashes); err != nil {
+				t.Fatalf("failed to decode announcement: %v", err)
+			}
+			return hashes
+		case TransactionMsg, PooledTransactionsMsg:
+			var txs []*types.Transaction
+			if err := msg.Decode(&txs); err != nil {
+				t.Fatalf("failed to decode transactions: %v", err)
+			}
+			hashes := make([]common.Hash, len(txs))
+			for i, tx := range txs {
+				hashes[i] = tx.Hash()
+			}
+			return hashes
+		}
+		t.Fatalf("unexpected message: %v", msg.Code)
+		return nil
+	}
+	if hashes := readHashes(); len(hashes) != 1 || hashes[0] != public.Hash() {
+		t.Fatalf("sync announcement mismatch: have %x, want [%x]", hashes, public.Hash())
+	}
+	// Request both transactions and ensure only the public one is served
+	if err := p2p.Send(p.app, GetPooledTransactionsMsg, []common.Hash{private.Hash(), public.Hash()}); err != nil {
+		t.Fatalf("failed to request transactions: %v", err)
+	}
+	if hashes := readHashes(); len(hashes) != 1 || hashes[0] != public.Hash() {
+		t.Fatalf("retrieval response mismatch: have %x, want [%x]", hashes, public.Hash())
+	}
+	// Leak a private transaction into the event feed and ensure it's not broadcast
+	leaked, next := newTestTransaction(key, 1, 0), newTestTransaction(testBankKey, 1, 0)
+	pool.addPrivate([]*types.Transaction{leaked})
+	pool.txFeed.Send(core.NewTxsEvent{Txs: []*types.Transaction{leaked}})
+	pool.AddRemotes([]*types.Transaction{next})
+
+	if hashes := readHashes(); len(hashes) != 1 || hashes[0] != next.Hash() {
+		t.Fatalf("broadcast mismatch: have %x, want [%x]", hashes, next.Hash())
+	}
+}
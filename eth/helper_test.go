@@ -76,9 +76,10 @@ func newTestProtocolManagerMust(t *testing.T, mode downloader.SyncMode, blocks i
 
 // testTxPool is a fake, helper transaction pool for testing purposes
 type testTxPool struct {
-	txFeed event.Feed
-	pool   map[common.Hash]*types.Transaction // Hash map of collected transactions
-	added  chan<- []*types.Transaction        // Notification channel for new transactions
+	txFeed  event.Feed
+	pool    map[common.Hash]*types.Transaction // Hash map of collected transactions
+	private map[common.Hash]bool               // Set of collected transactions submitted privately
+	added   chan<- []*types.Transaction        // Notification channel for new transactions
 
 	lock sync.RWMutex // Protects the transaction pool
 }
@@ -117,6 +118,30 @@ func (p *testTxPool) AddRemotes(txs []*types.Transaction) []error {
 	return make([]error, len(txs))
 }
 
+// addPrivate inserts a batch of transactions into the pool, marking them private
+// without notifying any listeners.
+func (p *testTxPool) addPrivate(txs []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.private == nil {
+		p.private = make(map[common.Hash]bool)
+	}
+	for _, tx := range txs {
+		p.pool[tx.Hash()] = tx
+		p.private[tx.Hash()] = true
+	}
+}
+
+// IsPrivate returns whether the transaction with the given hash was submitted
+// privately.
+func (p *testTxPool) IsPrivate(hash common.Hash) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.private[hash]
+}
+
 // Pending returns all the transactions known to the pool
 func (p *testTxPool) Pending() (map[common.Address]types.Transactions, error) {
 	p.lock.RLock()
@@ -100,6 +100,10 @@ type txPool interface {
 	// AddRemotes should add the given transactions to the pool.
 	AddRemotes([]*types.Transaction) []error
 
+	// IsPrivate returns whether the transaction with the given hash was
+	// submitted privately and must not be propagated to the network.
+	IsPrivate(hash common.Hash) bool
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending() (map[common.Address]types.Transactions, error)
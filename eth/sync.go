@@ -41,7 +41,7 @@ func (pm *ProtocolManager) syncTransactions(p *peer) {
 	var txs types.Transactions
 	pending, _ := pm.txpool.Pending()
 	for _, batch := range pending {
-		txs = append(txs, batch...)
+		txs = append(txs, pm.publicTxs(batch)...)
 	}
 	if len(txs) == 0 {
 		return
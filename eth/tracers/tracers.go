@@ -14,19 +14,47 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-// Package tracers is a collection of JavaScript transaction tracers.
+// Package tracers is a collection of JavaScript and native transaction tracers.
 package tracers
 
 import (
+	"encoding/json"
 	"strings"
 	"unicode"
 
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/tracers/internal/tracers"
 )
 
 // all contains all the built in JavaScript tracers by name.
 var all = make(map[string]string)
 
+// NativeTracer is a transaction tracer implemented in Go, producing a JSON result
+// like the JavaScript tracers do.
+type NativeTracer interface {
+	vm.Tracer
+
+	// GetResult returns the result of the tracing, or any error that occurred.
+	GetResult() (json.RawMessage, error)
+
+	// Stop terminates the tracing, the result reporting the given error instead.
+	Stop(err error)
+}
+
+// native contains the constructors of all the built in native tracers by name.
+var native = map[string]func() NativeTracer{
+	"profileTracer": func() NativeTracer { return vm.NewProfiler() },
+}
+
+// NewNative creates a built in native tracer by name, returning false if there
+// isn't any with the given name.
+func NewNative(name string) (NativeTracer, bool) {
+	if ctor, ok := native[name]; ok {
+		return ctor(), true
+	}
+	return nil, false
+}
+
 // camel converts a snake cased input string into a camel cased output.
 func camel(str string) string {
 	pieces := strings.Split(str, "_")
@@ -300,3 +300,18 @@ func jsonEqual(x, y interface{}) bool {
 	}
 	return reflect.DeepEqual(xTrace, yTrace)
 }
+
+// Tests that the native tracers are resolved by name, without shadowing the
+// JavaScript ones.
+func TestNativeTracers(t *testing.T) {
+	tracer, ok := NewNative("profileTracer")
+	if !ok {
+		t.Fatalf("profile tracer not found")
+	}
+	if _, ok := tracer.(*vm.Profiler); !ok {
+		t.Errorf("profile tracer type mismatch: have %T", tracer)
+	}
+	if _, ok := NewNative("callTracer"); ok {
+		t.Errorf("JavaScript call tracer resolved as native")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/accounts/scwallet"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/compiler"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/clique"
@@ -160,6 +161,210 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// ContentFrom returns the transactions contained within the transaction pool
+// that were sent by the given address.
+func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCTransaction {
+	content := make(map[string]map[string]*RPCTransaction, 2)
+	pending, queue := s.b.TxPoolContentFrom(addr)
+
+	// Build the pending transactions
+	dump := make(map[string]*RPCTransaction, len(pending))
+	for _, tx := range pending {
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	content["pending"] = dump
+
+	// Build the queued transactions
+	dump = make(map[string]*RPCTransaction, len(queue))
+	for _, tx := range queue {
+		dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+	}
+	content["queued"] = dump
+
+	return content
+}
+
+const (
+	defaultTxPoolPageSize = 100  // Number of accounts returned in a content page if unspecified
+	maxTxPoolPageSize     = 1000 // Maximum number of accounts returned in a content page
+)
+
+// TxPoolPage is a single page of the transaction pool content, covering a limited
+// number of sender accounts ordered by address.
+type TxPoolPage struct {
+	Pending map[string]map[string]*RPCTransaction `json:"pending"`
+	Queued  map[string]map[string]*RPCTransaction `json:"queued"`
+	Next    *common.Address                       `json:"next"` // Cursor of the following page, nil if this was the last
+}
+
+// ContentPage returns the transactions contained within the transaction pool for
+// at most limit sender accounts, ordered by address and starting right after the
+// cursor account. A nil cursor starts from the beginning of the pool.
+func (s *PublicTxPoolAPI) ContentPage(cursor *common.Address, limit *hexutil.Uint) *TxPoolPage {
+	size := defaultTxPoolPageSize
+	if limit != nil && *limit > 0 {
+		size = int(*limit)
+	}
+	if size > maxTxPoolPageSize {
+		size = maxTxPoolPageSize
+	}
+	pending, queue, next := s.b.TxPoolContentPage(cursor, size)
+
+	page := &TxPoolPage{
+		Pending: make(map[string]map[string]*RPCTransaction, len(pending)),
+		Queued:  make(map[string]map[string]*RPCTransaction, len(queue)),
+		Next:    next,
+	}
+	for account, txs := range pending {
+		dump := make(map[string]*RPCTransaction, len(txs))
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		}
+		page.Pending[account.Hex()] = dump
+	}
+	for account, txs := range queue {
+		dump := make(map[string]*RPCTransaction, len(txs))
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce())] = newRPCPendingTransaction(tx)
+		}
+		page.Queued[account.Hex()] = dump
+	}
+	return page
+}
+
+// PendingTxFilter is the criteria for the full pending transaction subscription.
+// Every non-empty field must match, while any element within a list field may.
+type PendingTxFilter struct {
+	From        []common.Address `json:"from"`        // Accepted senders
+	To          []common.Address `json:"to"`          // Accepted recipients
+	Selectors   []hexutil.Bytes  `json:"selectors"`   // Accepted 4 byte method selectors of the call data
+	MinGasPrice *hexutil.Big     `json:"minGasPrice"` // Minimum gas price to report
+}
+
+// matches checks whether a transaction satisfies the filter criteria.
+func (f *PendingTxFilter) matches(signer types.Signer, tx *types.Transaction) bool {
+	if f == nil {
+		return true
+	}
+	if f.MinGasPrice != nil && tx.GasPriceIntCmp(f.MinGasPrice.ToInt()) < 0 {
+		return false
+	}
+	if len(f.From) > 0 {
+		from, err := types.Sender(signer, tx)
+		if err != nil || !containsAddress(f.From, from) {
+			return false
+		}
+	}
+	if len(f.To) > 0 && (tx.To() == nil || !containsAddress(f.To, *tx.To())) {
+		return false
+	}
+	if len(f.Selectors) > 0 {
+		data, found := tx.Data(), false
+		for _, selector := range f.Selectors {
+			if len(data) >= 4 && bytes.Equal(data[:4], selector) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAddress checks whether an address is contained in a list.
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingTransactions creates a subscription that streams every transaction
+// entering the pool in full, provided it matches the optional filter criteria.
+func (s *PublicTxPoolAPI) PendingTransactions(ctx context.Context, crit *PendingTxFilter) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		var (
+			txs    = make(chan core.NewTxsEvent, 128)
+			txSub  = s.b.SubscribeNewTxsEvent(txs)
+			signer = types.NewEIP155Signer(s.b.ChainConfig().ChainID)
+		)
+		defer txSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-txs:
+				for _, tx := range ev.Txs {
+					if crit.matches(signer, tx) {
+						notifier.Notify(rpcSub.ID, newRPCPendingTransaction(tx))
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// RPCDroppedTransaction is the notification sent for a transaction leaving the
+// pool without being included in the chain.
+type RPCDroppedTransaction struct {
+	Hash        common.Hash  `json:"hash"`
+	Reason      string       `json:"reason"`
+	Replacement *common.Hash `json:"replacement,omitempty"`
+}
+
+// DroppedTransactions creates a subscription that streams the hash and reason
+// code of every transaction dropped or replaced within the pool.
+func (s *PublicTxPoolAPI) DroppedTransactions(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		drops := make(chan core.DropTxsEvent, 128)
+		dropSub := s.b.SubscribeDropTxsEvent(drops)
+		defer dropSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-drops:
+				var replacement *common.Hash
+				if ev.Replacement != nil {
+					hash := ev.Replacement.Hash()
+					replacement = &hash
+				}
+				for _, tx := range ev.Txs {
+					notifier.Notify(rpcSub.ID, &RPCDroppedTransaction{
+						Hash:        tx.Hash(),
+						Reason:      ev.Reason.String(),
+						Replacement: replacement,
+					})
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -373,7 +578,7 @@ func (s *PrivateAccountAPI) SendTransaction(ctx context.Context, args SendTxArgs
 		log.Warn("Failed transaction send attempt", "from", args.From, "to", args.To, "value", args.Value.ToInt(), "err", err)
 		return common.Hash{}, err
 	}
-	return SubmitTransaction(ctx, s.b, signed)
+	return submitTransaction(ctx, s.b, signed, args.Private != nil && *args.Private)
 }
 
 // SignTransaction will create a transaction from the given arguments and
@@ -535,13 +740,38 @@ func (s *PublicBlockChainAPI) BlockNumber() hexutil.Uint64 {
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
 func (s *PublicBlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, err := stateReaderAt(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
 	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
 }
 
+// stateReader is the set of state accessors shared by the live state database
+// and the historical state reconstructed from the persisted state diffs.
+type stateReader interface {
+	GetBalance(addr common.Address) *big.Int
+	GetNonce(addr common.Address) uint64
+	GetCode(addr common.Address) []byte
+	GetState(addr common.Address, key common.Hash) common.Hash
+	Error() error
+}
+
+// stateReaderAt retrieves the state of the requested block. If the state trie
+// was already pruned, it falls back to reconstructing it from state diffs.
+func stateReaderAt(ctx context.Context, b Backend, blockNrOrHash rpc.BlockNumberOrHash) (stateReader, error) {
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state != nil && err == nil {
+		return state, nil
+	}
+	if header != nil && err != nil {
+		if historical, herr := b.HistoricalState(ctx, header); herr == nil {
+			return historical, nil
+		}
+	}
+	return nil, err
+}
+
 // Result structs for GetProof
 type AccountResult struct {
 	Address      common.Address  `json:"address"`
@@ -717,7 +947,7 @@ func (s *PublicBlockChainAPI) GetUncleCountByBlockHash(ctx context.Context, bloc
 
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, err := stateReaderAt(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -729,7 +959,7 @@ func (s *PublicBlockChainAPI) GetCode(ctx context.Context, address common.Addres
 // block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
 // numbers are also allowed.
 func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, err := stateReaderAt(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -1056,6 +1286,7 @@ type ExecutionResult struct {
 	Failed      bool           `json:"failed"`
 	ReturnValue string         `json:"returnValue"`
 	StructLogs  []StructLogRes `json:"structLogs"`
+	Reverts     []RevertRes    `json:"reverts,omitempty"`
 }
 
 // StructLogRes stores a structured log emitted by the EVM while replaying a
@@ -1070,6 +1301,14 @@ type StructLogRes struct {
 	Stack   *[]string          `json:"stack,omitempty"`
 	Memory  *[]string          `json:"memory,omitempty"`
 	Storage *map[string]string `json:"storage,omitempty"`
+
+	Source *compiler.SourceLocation `json:"source,omitempty"`
+}
+
+// RevertRes locates a structured log reverting or failing its call frame
+type RevertRes struct {
+	Step   int                      `json:"step"`
+	Source *compiler.SourceLocation `json:"source,omitempty"`
 }
 
 // FormatLogs formats EVM returned structured logs for json output
@@ -1344,7 +1583,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionCount(ctx context.Context, addr
 		return (*hexutil.Uint64)(&nonce), nil
 	}
 	// Resolve block number and use its state to ask for the nonce
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, err := stateReaderAt(ctx, s.b, blockNrOrHash)
 	if state == nil || err != nil {
 		return nil, err
 	}
@@ -1464,6 +1703,10 @@ type SendTxArgs struct {
 	// newer name and should be preferred by clients.
 	Data  *hexutil.Bytes `json:"data"`
 	Input *hexutil.Bytes `json:"input"`
+
+	// Private transactions are kept in the local pool for inclusion by the
+	// local miner, but are never announced to the network.
+	Private *bool `json:"private,omitempty"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1541,12 +1784,22 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 
 // SubmitTransaction is a helper function that submits tx to txPool and logs a message.
 func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	return submitTransaction(ctx, b, tx, false)
+}
+
+// submitTransaction is a helper function that submits tx to the txPool, either
+// publicly or privately, and logs a message.
+func submitTransaction(ctx context.Context, b Backend, tx *types.Transaction, private bool) (common.Hash, error) {
 	// If the transaction fee cap is already specified, ensure the
 	// fee of the given transaction is _reasonable_.
 	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
 		return common.Hash{}, err
 	}
-	if err := b.SendTx(ctx, tx); err != nil {
+	send := b.SendTx
+	if private {
+		send = b.SendPrivateTx
+	}
+	if err := send(ctx, tx); err != nil {
 		return common.Hash{}, err
 	}
 	if tx.To() == nil {
@@ -1592,7 +1845,7 @@ func (s *PublicTransactionPoolAPI) SendTransaction(ctx context.Context, args Sen
 	if err != nil {
 		return common.Hash{}, err
 	}
-	return SubmitTransaction(ctx, s.b, signed)
+	return submitTransaction(ctx, s.b, signed, args.Private != nil && *args.Private)
 }
 
 // FillTransaction fills the defaults (nonce, gas, gasPrice) on a given unsigned transaction,
@@ -1621,6 +1874,17 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// SendPrivateRawTransaction will add the signed transaction to the transaction
+// pool as a private one: it is eligible for inclusion by the local miner, but is
+// never announced to the network until it expires.
+func (s *PublicTransactionPoolAPI) SendPrivateRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return submitTransaction(ctx, s.b, tx, true)
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
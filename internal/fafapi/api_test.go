@@ -0,0 +1,225 @@
+package fafapi
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testPoolBackend is a backend serving a fixed transaction pool content, all
+// the other methods are left unimplemented.
+type testPoolBackend struct {
+	Backend
+
+	pending map[common.Address]types.Transactions
+	queued  map[common.Address]types.Transactions
+	limit   int // Last page size requested
+}
+
+func (b *testPoolBackend) TxPoolContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	return b.pending[addr], b.queued[addr]
+}
+
+func (b *testPoolBackend) TxPoolContentPage(cursor *common.Address, limit int) (map[common.Address]types.Transactions, map[common.Address]types.Transactions, *common.Address) {
+	b.limit = limit
+
+	set := make(map[common.Address]struct{})
+	for _, content := range []map[common.Address]types.Transactions{b.pending, b.queued} {
+		for addr := range content {
+			if cursor == nil || bytes.Compare(addr[:], cursor[:]) > 0 {
+				set[addr] = struct{}{}
+			}
+		}
+	}
+	accounts := make([]common.Address, 0, len(set))
+	for addr := range set {
+		accounts = append(accounts, addr)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return bytes.Compare(accounts[i][:], accounts[j][:]) < 0 })
+	var next *common.Address
+	if len(accounts) > limit {
+		next = &accounts[limit-1]
+		accounts = accounts[:limit]
+	}
+	pending, queued := make(map[common.Address]types.Transactions), make(map[common.Address]types.Transactions)
+	for _, addr := range accounts {
+		if txs, ok := b.pending[addr]; ok {
+			pending[addr] = txs
+		}
+		if txs, ok := b.queued[addr]; ok {
+			queued[addr] = txs
+		}
+	}
+	return pending, queued, next
+}
+
+// newTestPoolBackend creates a pool backend with the given number of accounts,
+// each with a pending transaction and every other one with a queued one too.
+func newTestPoolBackend(t *testing.T, accounts int) (*testPoolBackend, []common.Address) {
+	signer := types.NewEIP155Signer(big.NewInt(1))
+
+	backend := &testPoolBackend{
+		pending: make(map[common.Address]types.Transactions),
+		queued:  make(map[common.Address]types.Transactions),
+	}
+	addrs := make([]common.Address, accounts)
+	for i := range addrs {
+		key, _ := crypto.GenerateKey()
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+
+		backend.pending[addrs[i]] = types.Transactions{signTestTx(t, signer, key, 0, nil)}
+		if i%2 == 0 {
+			backend.queued[addrs[i]] = types.Transactions{signTestTx(t, signer, key, 2, nil)}
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return backend, addrs
+}
+
+// signTestTx creates a signed value transfer with the given nonce and data.
+func signTestTx(t *testing.T, signer types.Signer, key *ecdsa.PrivateKey, nonce uint64, data []byte) *types.Transaction {
+	tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{0xaa}, big.NewInt(1), 100000, big.NewInt(10), data), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	return tx
+}
+
+// Tests that the content of a single account is returned keyed by nonce.
+func TestTxPoolContentFrom(t *testing.T) {
+	backend, addrs := newTestPoolBackend(t, 2)
+	api := NewPublicTxPoolAPI(backend)
+
+	for _, addr := range addrs {
+		content := api.ContentFrom(addr)
+		if len(content) != 2 {
+			t.Fatalf("account %x: content section count mismatch: have %d, want 2", addr, len(content))
+		}
+		for section, txs := range map[string]types.Transactions{"pending": backend.pending[addr], "queued": backend.queued[addr]} {
+			if len(content[section]) != len(txs) {
+				t.Fatalf("account %x: %s count mismatch: have %d, want %d", addr, section, len(content[section]), len(txs))
+			}
+			for _, tx := range txs {
+				rpcTx := content[section][fmt.Sprintf("%d", tx.Nonce())]
+				if rpcTx == nil || rpcTx.Hash != tx.Hash() || rpcTx.From != addr {
+					t.Errorf("account %x: %s transaction %d mismatch: have %v", addr, section, tx.Nonce(), rpcTx)
+				}
+			}
+		}
+	}
+	// Unknown accounts have empty, but present sections
+	content := api.ContentFrom(common.Address{0xff})
+	if len(content["pending"]) != 0 || len(content["queued"]) != 0 || content["pending"] == nil || content["queued"] == nil {
+		t.Errorf("unknown account content mismatch: %v", content)
+	}
+}
+
+// Tests that the pool content can be paged through, and that the page size is
+// defaulted and capped.
+func TestTxPoolContentPage(t *testing.T) {
+	backend, addrs := newTestPoolBackend(t, 5)
+	api := NewPublicTxPoolAPI(backend)
+
+	// Page through the pool two accounts at a time
+	var (
+		cursor *common.Address
+		limit  = hexutil.Uint(2)
+		seen   []string
+	)
+	for pages := 0; ; pages++ {
+		if pages > len(addrs) {
+			t.Fatalf("paging did not terminate")
+		}
+		page := api.ContentPage(cursor, &limit)
+		if len(page.Pending) > int(limit) || len(page.Queued) > int(limit) {
+			t.Fatalf("page %d: oversized: %d pending, %d queued", pages, len(page.Pending), len(page.Queued))
+		}
+		for addr := range page.Pending {
+			seen = append(seen, addr)
+		}
+		for addr := range page.Queued {
+			if _, ok := page.Pending[addr]; !ok {
+				t.Errorf("page %d: queued account %s missing pending transactions", pages, addr)
+			}
+		}
+		if page.Next == nil {
+			break
+		}
+		cursor = page.Next
+	}
+	sort.Strings(seen)
+	if len(seen) != len(addrs) {
+		t.Fatalf("paged account count mismatch: have %d, want %d", len(seen), len(addrs))
+	}
+	for i, addr := range addrs {
+		if seen[i] != addr.Hex() {
+			t.Errorf("account %d mismatch: have %s, want %x", i, seen[i], addr)
+		}
+	}
+	// Ensure the page size limits are enforced
+	tests := []struct {
+		limit *hexutil.Uint
+		want  int
+	}{
+		{nil, defaultTxPoolPageSize},
+		{new(hexutil.Uint), defaultTxPoolPageSize},
+		{func() *hexutil.Uint { l := hexutil.Uint(7); return &l }(), 7},
+		{func() *hexutil.Uint { l := hexutil.Uint(maxTxPoolPageSize + 1); return &l }(), maxTxPoolPageSize},
+	}
+	for i, tt := range tests {
+		page := api.ContentPage(nil, tt.limit)
+		if backend.limit != tt.want {
+			t.Errorf("test %d: page size mismatch: have %d, want %d", i, backend.limit, tt.want)
+		}
+		if page.Next != nil || len(page.Pending) != len(addrs) {
+			t.Errorf("test %d: single page mismatch: %d pending, next %v", i, len(page.Pending), page.Next)
+		}
+	}
+	// A cursor past the last account yields an empty final page
+	if page := api.ContentPage(&addrs[len(addrs)-1], nil); page.Next != nil || len(page.Pending)+len(page.Queued) != 0 {
+		t.Errorf("trailing page mismatch: %d pending, %d queued, next %v", len(page.Pending), len(page.Queued), page.Next)
+	}
+}
+
+// Tests that the pending transaction filter requires every set criteria to match.
+func TestPendingTxFilter(t *testing.T) {
+	var (
+		signer = types.NewEIP155Signer(big.NewInt(1))
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		tx     = signTestTx(t, signer, key, 0, []byte{0xa9, 0x05, 0x9c, 0xbb, 0x01})
+		short  = signTestTx(t, signer, key, 1, []byte{0xa9, 0x05})
+	)
+	tests := []struct {
+		filter *PendingTxFilter
+		tx     *types.Transaction
+		want   bool
+	}{
+		{nil, tx, true},
+		{&PendingTxFilter{}, tx, true},
+		{&PendingTxFilter{From: []common.Address{{0x01}, from}}, tx, true},
+		{&PendingTxFilter{From: []common.Address{{0x01}}}, tx, false},
+		{&PendingTxFilter{To: []common.Address{{0xaa}}}, tx, true},
+		{&PendingTxFilter{To: []common.Address{{0xbb}}}, tx, false},
+		{&PendingTxFilter{Selectors: []hexutil.Bytes{{0x01, 0x02, 0x03, 0x04}, {0xa9, 0x05, 0x9c, 0xbb}}}, tx, true},
+		{&PendingTxFilter{Selectors: []hexutil.Bytes{{0x01, 0x02, 0x03, 0x04}}}, tx, false},
+		{&PendingTxFilter{Selectors: []hexutil.Bytes{{0xa9, 0x05, 0x9c, 0xbb}}}, short, false},
+		{&PendingTxFilter{MinGasPrice: (*hexutil.Big)(big.NewInt(10))}, tx, true},
+		{&PendingTxFilter{MinGasPrice: (*hexutil.Big)(big.NewInt(11))}, tx, false},
+		{&PendingTxFilter{From: []common.Address{from}, To: []common.Address{{0xbb}}}, tx, false},
+		{&PendingTxFilter{From: []common.Address{from}, To: []common.Address{{0xaa}}, MinGasPrice: (*hexutil.Big)(big.NewInt(1))}, tx, true},
+	}
+	for i, tt := range tests {
+		if have := tt.filter.matches(signer, tt.tx); have != tt.want {
+			t.Errorf("test %d: match mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -434,6 +434,12 @@ web3._extend({
 			call: 'debug_getBadBlocks',
 			params: 0,
 		}),
+		new web3._extend.Method({
+			name: 'getBlockWitness',
+			call: 'debug_getBlockWitness',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'storageRangeAt',
 			call: 'debug_storageRangeAt',
@@ -488,6 +494,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendPrivateRawTransaction',
+			call: 'eth_sendPrivateRawTransaction',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'estimateGas',
 			call: 'eth_estimateGas',
@@ -563,6 +574,11 @@ web3._extend({
 				return formatted;
 			}
 		}),
+		new web3._extend.Property({
+			name: 'chainLogCursor',
+			getter: 'eth_chainLogCursor',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
 	]
 });
 `
@@ -741,7 +757,29 @@ web3._extend({
 const TxpoolJs = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'export',
+			call: 'txpool_export',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'import',
+			call: 'txpool_import',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'contentFrom',
+			call: 'txpool_contentFrom',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'contentPage',
+			call: 'txpool_contentPage',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
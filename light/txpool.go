@@ -3,9 +3,11 @@
 package light
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -491,6 +493,63 @@ func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common
 	return pending, queued
 }
 
+// ContentFrom retrieves the data content of the transaction pool, returning the
+// pending as well as queued transactions of this address, grouped by nonce.
+func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	// Retrieve the pending transactions and sort by nonce
+	var pending types.Transactions
+	for _, tx := range pool.pending {
+		account, _ := types.Sender(pool.signer, tx)
+		if account != addr {
+			continue
+		}
+		pending = append(pending, tx)
+	}
+	sort.Sort(types.TxByNonce(pending))
+
+	// There are no queued transactions in a light pool, just return an empty list
+	return pending, types.Transactions{}
+}
+
+// ContentPage retrieves the data content of the transaction pool for at most
+// limit accounts, ordered by address and starting right after the cursor, along
+// with the cursor of the following page, nil if there's none.
+func (pool *TxPool) ContentPage(cursor *common.Address, limit int) (map[common.Address]types.Transactions, map[common.Address]types.Transactions, *common.Address) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	// Group the pending transactions by account, skipping the ones before the cursor
+	content := make(map[common.Address]types.Transactions)
+	for _, tx := range pool.pending {
+		account, _ := types.Sender(pool.signer, tx)
+		if cursor != nil && bytes.Compare(account[:], cursor[:]) <= 0 {
+			continue
+		}
+		content[account] = append(content[account], tx)
+	}
+	senders := make([]common.Address, 0, len(content))
+	for account := range content {
+		senders = append(senders, account)
+	}
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+
+	var next *common.Address
+	if len(senders) > limit {
+		next = &senders[limit-1]
+		senders = senders[:limit]
+	}
+	pending := make(map[common.Address]types.Transactions)
+	for _, account := range senders {
+		pending[account] = content[account]
+		sort.Sort(types.TxByNonce(pending[account]))
+	}
+	// There are no queued transactions in a light pool, just return an empty map
+	return pending, make(map[common.Address]types.Transactions), next
+}
+
 // RemoveTransactions removes all given transactions from the pool.
 func (pool *TxPool) RemoveTransactions(txs types.Transactions) {
 	pool.mu.Lock()
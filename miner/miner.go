@@ -36,6 +36,10 @@ type Config struct {
 	GasPrice  *big.Int       // Minimum gas price for mining a transaction
 	Recommit  time.Duration  // The time interval for miner to re-create mining work.
 	Noverify  bool           // Disable remote mining solution verification(only useful in ethash).
+
+	MaxBlockSize     uint64 `toml:",omitempty"` // Approximate maximum RLP size of mined blocks in bytes (0 = unlimited)
+	MaxBlockCalldata uint64 `toml:",omitempty"` // Maximum total transaction calldata of mined blocks in bytes (0 = unlimited)
+	MaxBlockTxs      uint64 `toml:",omitempty"` // Maximum number of transactions in mined blocks (0 = unlimited)
 }
 
 // Miner creates blocks and searches for proof-of-work values.
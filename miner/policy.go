@@ -0,0 +1,76 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	// Metrics for transactions skipped while building blocks, by reason
+	skipGasMeter      = metrics.NewRegisteredMeter("miner/skip/gas", nil)      // Not enough gas left in the block
+	skipSizeMeter     = metrics.NewRegisteredMeter("miner/skip/size", nil)     // Block size limit would be exceeded
+	skipCalldataMeter = metrics.NewRegisteredMeter("miner/skip/calldata", nil) // Block calldata limit would be exceeded
+	skipTxCountMeter  = metrics.NewRegisteredMeter("miner/skip/txcount", nil)  // Block transaction count limit reached
+)
+
+// skipReason is the cause of a transaction not being included into a locally
+// built block by the block building policy.
+type skipReason int
+
+const (
+	skipNone     skipReason = iota // Transaction fits into the block
+	skipSize                       // Transaction would exceed the block size limit
+	skipCalldata                   // Transaction would exceed the block calldata limit
+	skipTxCount                    // Block transaction count limit reached
+)
+
+// String implements fmt.Stringer.
+func (r skipReason) String() string {
+	switch r {
+	case skipNone:
+		return "none"
+	case skipSize:
+		return "size"
+	case skipCalldata:
+		return "calldata"
+	case skipTxCount:
+		return "txcount"
+	default:
+		return "unknown"
+	}
+}
+
+// checkPolicy verifies whether a transaction fits into the block being built
+// according to the local block building policy. These limits are not consensus
+// rules, they only restrict the blocks produced by this node.
+func (w *worker) checkPolicy(env *environment, tx *types.Transaction) skipReason {
+	if limit := w.config.MaxBlockTxs; limit > 0 && uint64(env.tcount) >= limit {
+		skipTxCountMeter.Mark(1)
+		return skipTxCount
+	}
+	if limit := w.config.MaxBlockSize; limit > 0 && env.size+uint64(tx.Size()) > limit {
+		skipSizeMeter.Mark(1)
+		return skipSize
+	}
+	if limit := w.config.MaxBlockCalldata; limit > 0 && env.calldata+uint64(len(tx.Data())) > limit {
+		skipCalldataMeter.Mark(1)
+		return skipCalldata
+	}
+	return skipNone
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the block building policy rejects transactions exceeding the
+// configured size, calldata and transaction count limits.
+func TestBlockBuildingPolicy(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 100000, big.NewInt(1), make([]byte, 100))
+	size := uint64(tx.Size())
+
+	tests := []struct {
+		config Config
+		env    environment
+		want   skipReason
+	}{
+		// Unlimited policy accepts everything
+		{Config{}, environment{tcount: 1000, size: 1 << 30, calldata: 1 << 30}, skipNone},
+
+		// Transaction count limit
+		{Config{MaxBlockTxs: 2}, environment{tcount: 1}, skipNone},
+		{Config{MaxBlockTxs: 2}, environment{tcount: 2}, skipTxCount},
+
+		// Block size limit
+		{Config{MaxBlockSize: 1000}, environment{size: 1000 - size}, skipNone},
+		{Config{MaxBlockSize: 1000}, environment{size: 1000 - size + 1}, skipSize},
+
+		// Calldata limit
+		{Config{MaxBlockCalldata: 150}, environment{calldata: 50}, skipNone},
+		{Config{MaxBlockCalldata: 150}, environment{calldata: 51}, skipCalldata},
+	}
+	for i, tt := range tests {
+		w := &worker{config: &tt.config}
+		if have := w.checkPolicy(&tt.env, tx); have != tt.want {
+			t.Errorf("test %d: skip reason mismatch: have %v, want %v", i, have, tt.want)
+		}
+	}
+}
@@ -73,6 +73,8 @@ type environment struct {
 	uncles    mapset.Set     // uncle set
 	tcount    int            // tx count in cycle
 	gasPool   *core.GasPool  // available gas used to pack transactions
+	size      uint64         // approximate RLP size of the block being built
+	calldata  uint64         // total calldata of the included transactions
 
 	header   *types.Header
 	txs      []*types.Transaction
@@ -122,6 +124,7 @@ type worker struct {
 	mux          *event.TypeMux
 	txsCh        chan core.NewTxsEvent
 	txsSub       event.Subscription
+	privTxsSub   event.Subscription // Private transactions are delivered on txsCh too
 	chainHeadCh  chan core.ChainHeadEvent
 	chainHeadSub event.Subscription
 	chainSideCh  chan core.ChainSideEvent
@@ -199,6 +202,7 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 	}
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
+	worker.privTxsSub = eth.TxPool().SubscribePrivateTxsEvent(worker.txsCh)
 	// Subscribe events for blockchain
 	worker.chainHeadSub = eth.BlockChain().SubscribeChainHeadEvent(worker.chainHeadCh)
 	worker.chainSideSub = eth.BlockChain().SubscribeChainSideEvent(worker.chainSideCh)
@@ -411,6 +415,7 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 // mainLoop is a standalone goroutine to regenerate the sealing task based on the received event.
 func (w *worker) mainLoop() {
 	defer w.txsSub.Unsubscribe()
+	defer w.privTxsSub.Unsubscribe()
 	defer w.chainHeadSub.Unsubscribe()
 	defer w.chainSideSub.Unsubscribe()
 
@@ -501,6 +506,8 @@ func (w *worker) mainLoop() {
 			return
 		case <-w.txsSub.Err():
 			return
+		case <-w.privTxsSub.Err():
+			return
 		case <-w.chainHeadSub.Err():
 			return
 		case <-w.chainSideSub.Err():
@@ -635,6 +642,7 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 		family:    mapset.NewSet(),
 		uncles:    mapset.NewSet(),
 		header:    header,
+		size:      uint64(header.Size()),
 	}
 
 	// when 08 is processed ancestors contain 07 (quick block)
@@ -668,6 +676,7 @@ func (w *worker) commitUncle(env *environment, uncle *types.Header) error {
 		return errors.New("uncle already included")
 	}
 	env.uncles.Add(uncle.Hash())
+	env.size += uint64(uncle.Size())
 	return nil
 }
 
@@ -699,7 +708,7 @@ func (w *worker) updateSnapshot() {
 		w.current.txs,
 		uncles,
 		w.current.receipts,
-		new(trie.Trie),
+		trie.NewStackTrie(nil),
 	)
 
 	w.snapshotState = w.current.state.Copy()
@@ -731,6 +740,7 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 
 	var coalescedLogs []*types.Log
 
+loop:
 	for {
 		// In the following three cases, we will interrupt the execution of the transaction.
 		// (1) new head block event arrival, the interrupt signal is 1
@@ -775,6 +785,18 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			txs.Pop()
 			continue
 		}
+		// Check the transaction against the local block building policy
+		switch reason := w.checkPolicy(w.current, tx); reason {
+		case skipTxCount:
+			log.Trace("Transaction count limit reached for current block", "limit", w.config.MaxBlockTxs)
+			break loop
+
+		case skipSize, skipCalldata:
+			// Pop the current oversized transaction without shifting in the next from the account
+			log.Trace("Skipping transaction exceeding block policy", "hash", tx.Hash(), "reason", reason)
+			txs.Pop()
+			continue
+		}
 		// Start executing the transaction
 		w.current.state.Prepare(tx.Hash(), common.Hash{}, w.current.tcount)
 
@@ -783,6 +805,7 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 		case errors.Is(err, core.ErrGasLimitReached):
 			// Pop the current out-of-gas transaction without shifting in the next from the account
 			log.Trace("Gas limit exceeded for current block", "sender", from)
+			skipGasMeter.Mark(1)
 			txs.Pop()
 
 		case errors.Is(err, core.ErrNonceTooLow):
@@ -799,6 +822,8 @@ func (w *worker) commitTransactions(txs *types.TransactionsByPriceAndNonce, coin
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
 			w.current.tcount++
+			w.current.size += uint64(tx.Size())
+			w.current.calldata += uint64(len(tx.Data()))
 			txs.Shift()
 
 		default:
@@ -487,3 +487,49 @@ func testAdjustInterval(t *testing.T, chainConfig *params.ChainConfig, engine co
 		t.Error("interval reset timeout")
 	}
 }
+
+// Tests that hitting the block transaction count limit while filling the pending
+// block still delivers the logs of the already committed transactions.
+func TestPendingLogsTxCountLimit(t *testing.T) {
+	backend := newTestWorkerBackend(t, ethashChainConfig, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	config := *testConfig
+	config.MaxBlockTxs = 1
+
+	w := newWorker(&config, ethashChainConfig, ethash.NewFaker(), backend, new(event.TypeMux), nil, true)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	// Wait until the initial pending block is assembled
+	for start := time.Now(); w.pendingBlock() == nil; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatalf("pending block not assembled")
+		}
+	}
+	logsCh := make(chan []*types.Log, 1)
+	sub := w.pendingLogsFeed.Subscribe(logsCh)
+	defer sub.Unsubscribe()
+
+	// Submit two log emitting contract creations at once, only one of which fits
+	code := common.FromHex("fx60006000a0") // PUSH1 0 PUSH1 0 LOG0
+	txs := make([]*types.Transaction, 2)
+	for i := range txs {
+		txs[i], _ = types.SignTx(types.NewContractCreation(uint64(i), big.NewInt(0), 100000, big.NewInt(1), code), types.HomesteadSigner{}, testBankKey)
+	}
+	for i, err := range backend.txPool.AddLocals(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction %d: %v", i, err)
+		}
+	}
+	select {
+	case logs := <-logsCh:
+		if len(logs) != 1 || logs[0].TxHash != txs[0].Hash() {
+			t.Fatalf("pending logs mismatch: have %v, want 1 log of %x", logs, txs[0].Hash())
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("pending logs not delivered")
+	}
+	if block := w.pendingBlock(); block == nil || len(block.Transactions()) != 1 {
+		t.Fatalf("pending block transaction count mismatch: have %v, want 1", block)
+	}
+}
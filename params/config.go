@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -226,16 +227,16 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, false, nil, new(EthashConfig), nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, false, nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(EthashConfig), nil}
+	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, false, nil, new(EthashConfig), nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 )
 
@@ -310,11 +311,26 @@ type ChainConfig struct {
 	YoloV2Block *big.Int `json:"yoloV2Block,omitempty"` // YOLO v2: Gas repricings TODO @holiman add EIP references
 	EWASMBlock  *big.Int `json:"ewasmBlock,omitempty"`  // EWASM switch block (nil = no fork, 0 = already activated)
 
+	// BinaryTrie selects the experimental binary Merkle trie instead of the hexary
+	// Merkle Patricia trie as the state trie format. It is fixed at genesis.
+	BinaryTrie bool `json:"binaryTrie,omitempty"`
+
+	// Precompiles enables custom precompiled contracts registered with the EVM,
+	// keyed by the name they were registered with.
+	Precompiles map[string]*PrecompileConfig `json:"precompiles,omitempty"`
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
 }
 
+// PrecompileConfig is the address and activation block of a custom precompiled
+// contract.
+type PrecompileConfig struct {
+	Address common.Address `json:"address"`         // Address the precompiled contract is callable at
+	Block   *big.Int       `json:"block,omitempty"` // Activation block (nil = not activated, 0 = active from genesis)
+}
+
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
 type EthashConfig struct{}
 
@@ -425,6 +441,15 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsPrecompileActive returns whether the custom precompiled contract with the
+// given name is active at the given block.
+func (c *ChainConfig) IsPrecompileActive(name string, num *big.Int) bool {
+	if p := c.Precompiles[name]; p != nil {
+		return isForked(p.Block, num)
+	}
+	return false
+}
+
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64) *ConfigCompatError {
@@ -533,6 +558,54 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if c.BinaryTrie != newcfg.BinaryTrie {
+		// The state trie format is fixed at genesis, the flag is reported as 0 or 1
+		return &ConfigCompatError{"binary trie flag", flagNumber(c.BinaryTrie), flagNumber(newcfg.BinaryTrie), 0}
+	}
+	return checkPrecompilesCompatible(c.Precompiles, newcfg.Precompiles, head)
+}
+
+// flagNumber converts a boolean config flag into a number for ConfigCompatError.
+func flagNumber(flag bool) *big.Int {
+	if flag {
+		return big.NewInt(1)
+	}
+	return big.NewInt(0)
+}
+
+// checkPrecompilesCompatible checks whether the activation of custom precompiled
+// contracts, or their addresses, changed before the head block.
+func checkPrecompilesCompatible(stored, config map[string]*PrecompileConfig, head *big.Int) *ConfigCompatError {
+	names := make([]string, 0, len(stored)+len(config))
+	for name := range stored {
+		names = append(names, name)
+	}
+	for name := range config {
+		if _, ok := stored[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var (
+			oldp, newp = stored[name], config[name]
+			oldblock   *big.Int
+			newblock   *big.Int
+		)
+		if oldp != nil {
+			oldblock = oldp.Block
+		}
+		if newp != nil {
+			newblock = newp.Block
+		}
+		if isForkIncompatible(oldblock, newblock, head) {
+			return newCompatError(fmt.Sprintf("%s precompile block", name), oldblock, newblock)
+		}
+		if isForked(oldblock, head) && oldp.Address != newp.Address {
+			return newCompatError(fmt.Sprintf("%s precompile address", name), oldblock, newblock)
+		}
+	}
 	return nil
 }
 
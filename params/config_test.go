@@ -20,6 +20,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestCheckCompatible(t *testing.T) {
@@ -87,6 +89,57 @@ func TestCheckCompatible(t *testing.T) {
 				RewindTo:     30,
 			},
 		},
+		{
+			stored:  &ChainConfig{Precompiles: map[string]*PrecompileConfig{"p": {Address: common.Address{0xff}, Block: big.NewInt(10)}}},
+			new:     &ChainConfig{Precompiles: map[string]*PrecompileConfig{"p": {Address: common.Address{0xfe}, Block: big.NewInt(20)}}},
+			head:    9,
+			wantErr: nil,
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{Precompiles: map[string]*PrecompileConfig{"p": {Address: common.Address{0xff}, Block: big.NewInt(5)}}},
+			head:   9,
+			wantErr: &ConfigCompatError{
+				What:         "p precompile block",
+				StoredConfig: nil,
+				NewConfig:    big.NewInt(5),
+				RewindTo:     4,
+			},
+		},
+		{
+			stored: &ChainConfig{Precompiles: map[string]*PrecompileConfig{"p": {Address: common.Address{0xff}, Block: big.NewInt(5)}}},
+			new:    &ChainConfig{Precompiles: map[string]*PrecompileConfig{"p": {Address: common.Address{0xfe}, Block: big.NewInt(5)}}},
+			head:   9,
+			wantErr: &ConfigCompatError{
+				What:         "p precompile address",
+				StoredConfig: big.NewInt(5),
+				NewConfig:    big.NewInt(5),
+				RewindTo:     4,
+			},
+		},
+		{
+			stored: &ChainConfig{},
+			new:    &ChainConfig{BinaryTrie: true},
+			head:   0,
+			wantErr: &ConfigCompatError{
+				What:         "binary trie flag",
+				StoredConfig: big.NewInt(0),
+				NewConfig:    big.NewInt(1),
+				RewindTo:     0,
+			},
+		},
+		{
+			stored: &ChainConfig{BinaryTrie: true},
+			new:    &ChainConfig{},
+			head:   100,
+			wantErr: &ConfigCompatError{
+				What:         "binary trie flag",
+				StoredConfig: big.NewInt(1),
+				NewConfig:    big.NewInt(0),
+				RewindTo:     0,
+			},
+		},
+		{stored: &ChainConfig{BinaryTrie: true}, new: &ChainConfig{BinaryTrie: true}, head: 100, wantErr: nil},
 	}
 
 	for _, test := range tests {
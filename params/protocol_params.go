@@ -120,6 +120,11 @@ const (
 	Bls12381PairingPerPairGas uint64 = 23000  // Per-point pair gas price for BLS12-381 elliptic curve pairing check
 	Bls12381MapG1Gas          uint64 = 5500   // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 110000 // Gas price for BLS12-381 mapping field element to G2 operation
+
+	Ed25519VerifyBaseGas    uint64 = 2000 // Base price for an Ed25519 signature verification
+	Ed25519VerifyPerWordGas uint64 = 12   // Per-word price of the message of an Ed25519 signature verification
+
+	EWASMMemoryPageGas uint64 = 16384 // Price of each 64KiB memory page allocated by an ewasm contract
 )
 
 // Gas discount table for BLS12-381 G1 and G2 multi exponentiation operations
@@ -3,7 +3,13 @@
 package tests
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestBlockchain(t *testing.T) {
@@ -37,8 +43,49 @@ func TestBlockchain(t *testing.T) {
 		if err := bt.checkFailure(t, name+"/snap", test.Run(true)); err != nil {
 			t.Errorf("test with snapshotter failed: %v", err)
 		}
+		if err := bt.checkFailure(t, name+"/parallel", test.RunParallel(false, 4)); err != nil {
+			t.Errorf("test with parallel execution failed: %v", err)
+		}
 	})
 	// There is also a LegacyTests folder, containing blockchain tests generated
 	// prior to Istanbul. However, they are all derived from GeneralStateTests,
 	// which run natively, so there's no reason to run them here.
 }
+
+// Tests that executing blocks with conflicting transactions in parallel yields
+// the same post state as the sequential import, and that both validate it.
+func TestBlockchainParallel(t *testing.T) {
+	blob, err := ioutil.ReadFile(filepath.Join("blocktests", "ParallelConflicts.json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var tests map[string]*BlockTest
+	if err := json.Unmarshal(blob, &tests); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	for name, test := range tests {
+		if err := test.Run(false); err != nil {
+			t.Errorf("%s: sequential run failed: %v", name, err)
+		}
+		if err := test.Run(true); err != nil {
+			t.Errorf("%s: sequential run with snapshotter failed: %v", name, err)
+		}
+		for _, workers := range []int{2, 4, 8} {
+			if err := test.RunParallel(false, workers); err != nil {
+				t.Errorf("%s: parallel run with %d workers failed: %v", name, workers, err)
+			}
+		}
+		// Tamper with the expected post state and ensure both modes notice
+		for addr, account := range test.json.Post {
+			account.Balance = new(big.Int).Add(account.Balance, common.Big1)
+			test.json.Post[addr] = account
+			break
+		}
+		if err := test.Run(false); err == nil {
+			t.Errorf("%s: sequential run accepted tampered post state", name)
+		}
+		if err := test.RunParallel(false, 4); err == nil {
+			t.Errorf("%s: parallel run accepted tampered post state", name)
+		}
+	}
+}
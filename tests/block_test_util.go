@@ -35,6 +35,11 @@ func (t *BlockTest) UnmarshalJSON(in []byte) error {
 	return json.Unmarshal(in, &t.json)
 }
 
+// MarshalJSON implements json.Marshaler interface.
+func (t *BlockTest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.json)
+}
+
 type btJSON struct {
 	Blocks     []btBlock             `json:"blocks"`
 	Genesis    btHeader              `json:"genesisBlockHeader"`
@@ -46,30 +51,30 @@ type btJSON struct {
 }
 
 type btBlock struct {
-	BlockHeader  *btHeader
-	Rlp          string
-	UncleHeaders []*btHeader
+	BlockHeader  *btHeader   `json:"blockHeader,omitempty"`
+	Rlp          string      `json:"rlp"`
+	UncleHeaders []*btHeader `json:"uncleHeaders"`
 }
 
 //go:generate gencodec -type btHeader -field-override btHeaderMarshaling -out gen_btheader.go
 
 type btHeader struct {
-	Bloom            types.Bloom
-	Coinbase         common.Address
-	MixHash          common.Hash
-	Nonce            types.BlockNonce
-	Number           *big.Int
-	Hash             common.Hash
-	ParentHash       common.Hash
-	ReceiptTrie      common.Hash
-	StateRoot        common.Hash
-	TransactionsTrie common.Hash
-	UncleHash        common.Hash
-	ExtraData        []byte
-	Difficulty       *big.Int
-	GasLimit         uint64
-	GasUsed          uint64
-	Timestamp        uint64
+	Bloom            types.Bloom      `json:"bloom"`
+	Coinbase         common.Address   `json:"coinbase"`
+	MixHash          common.Hash      `json:"mixHash"`
+	Nonce            types.BlockNonce `json:"nonce"`
+	Number           *big.Int         `json:"number"`
+	Hash             common.Hash      `json:"hash"`
+	ParentHash       common.Hash      `json:"parentHash"`
+	ReceiptTrie      common.Hash      `json:"receiptTrie"`
+	StateRoot        common.Hash      `json:"stateRoot"`
+	TransactionsTrie common.Hash      `json:"transactionsTrie"`
+	UncleHash        common.Hash      `json:"uncleHash"`
+	ExtraData        []byte           `json:"extraData"`
+	Difficulty       *big.Int         `json:"difficulty"`
+	GasLimit         uint64           `json:"gasLimit"`
+	GasUsed          uint64           `json:"gasUsed"`
+	Timestamp        uint64           `json:"timestamp"`
 }
 
 type btHeaderMarshaling struct {
@@ -81,7 +86,18 @@ type btHeaderMarshaling struct {
 	Timestamp  math.HexOrDecimal64
 }
 
+// Run executes the blockchain test, importing the blocks sequentially.
 func (t *BlockTest) Run(snapshotter bool) error {
+	return t.run(snapshotter, 0)
+}
+
+// RunParallel executes the blockchain test like Run, but processes the block
+// transactions with the given number of parallel execution workers.
+func (t *BlockTest) RunParallel(snapshotter bool, workers int) error {
+	return t.run(snapshotter, workers)
+}
+
+func (t *BlockTest) run(snapshotter bool, workers int) error {
 	config, ok := Forks[t.json.Network]
 	if !ok {
 		return UnsupportedForkError{t.json.Network}
@@ -110,6 +126,9 @@ func (t *BlockTest) Run(snapshotter bool) error {
 		cache.SnapshotLimit = 1
 		cache.SnapshotWait = true
 	}
+	if workers > 1 {
+		cache.ParallelExecution = workers
+	}
 	chain, err := core.NewBlockChain(db, cache, config, engine, vm.Config{}, nil, nil)
 	if err != nil {
 		return err
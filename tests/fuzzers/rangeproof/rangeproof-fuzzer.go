@@ -0,0 +1,188 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rangeproof
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+type kv struct {
+	k, v []byte
+}
+
+type entrySlice []*kv
+
+func (p entrySlice) Len() int           { return len(p) }
+func (p entrySlice) Less(i, j int) bool { return bytes.Compare(p[i].k, p[j].k) < 0 }
+func (p entrySlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+type fuzzer struct {
+	input     io.Reader
+	exhausted bool
+}
+
+func (f *fuzzer) randBytes(n int) []byte {
+	r := make([]byte, n)
+	if _, err := f.input.Read(r); err != nil {
+		f.exhausted = true
+	}
+	return r
+}
+
+func (f *fuzzer) readInt() uint64 {
+	var x uint64
+	if err := binary.Read(f.input, binary.LittleEndian, &x); err != nil {
+		f.exhausted = true
+	}
+	return x
+}
+
+func (f *fuzzer) randomTrie(n int) (*trie.Trie, map[string]*kv) {
+	trie := new(trie.Trie)
+	vals := make(map[string]*kv)
+	size := f.readInt()
+	// Fill it with some fluff
+	for i := byte(0); i < byte(size); i++ {
+		value := &kv{common.LeftPadBytes([]byte{i}, 32), []byte{i}}
+		value2 := &kv{common.LeftPadBytes([]byte{i + 10}, 32), []byte{i}}
+		trie.Update(value.k, value.v)
+		trie.Update(value2.k, value2.v)
+		vals[string(value.k)] = value
+		vals[string(value2.k)] = value2
+	}
+	if f.exhausted {
+		return nil, nil
+	}
+	// And now fill with some random
+	for i := 0; i < n; i++ {
+		k := f.randBytes(32)
+		v := f.randBytes(20)
+		value := &kv{k, v}
+		trie.Update(k, v)
+		vals[string(k)] = value
+		if f.exhausted {
+			return nil, nil
+		}
+	}
+	return trie, vals
+}
+
+func (f *fuzzer) fuzz() int {
+	maxSize := 200
+	tr, vals := f.randomTrie(1 + int(f.readInt()%uint64(maxSize)))
+	if f.exhausted {
+		return 0 // input too short
+	}
+	var entries entrySlice
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	if len(entries) <= 1 {
+		return 0
+	}
+	sort.Sort(entries)
+	root := tr.Hash()
+
+	var ok = 0
+	for i := 0; !f.exhausted; i++ {
+		// Generate a range starting at an existing or a non-existent key
+		origin := common.CopyBytes(entries[int(f.readInt()%uint64(len(entries)))].k)
+		if f.readInt()%2 == 0 {
+			origin = f.randBytes(32)
+		}
+		max := 1 + int(f.readInt()%uint64(len(entries)))
+		if f.exhausted {
+			break
+		}
+		proof := memorydb.New()
+		keys, vals, err := tr.ProveRange(origin, max, proof)
+		if err != nil {
+			panic(fmt.Sprintf("case %d: failed to prove range: %v", i, err))
+		}
+		// An empty range is only provable at the end of the trie
+		if len(keys) == 0 {
+			if err, _ := trie.VerifyRangeProof(root, origin, nil, nil, nil, proof); err != nil {
+				panic(fmt.Sprintf("case %d: empty range rejected: %v", i, err))
+			}
+			continue
+		}
+		last := keys[len(keys)-1]
+		if err, _ := trie.VerifyRangeProof(root, origin, last, keys, vals, proof); err != nil {
+			panic(fmt.Sprintf("case %d: valid range rejected: %v", i, err))
+		}
+		ok = 1
+		// Tamper with the range in some way, verification must fail
+		switch testcase := f.readInt() % 3; testcase {
+		case 0:
+			// Modify a random value
+			index := int(f.readInt() % uint64(len(vals)))
+			vals[index] = append(common.CopyBytes(vals[index]), 0x01)
+
+		case 1:
+			// Drop an element from the middle of the range
+			if len(keys) < 3 {
+				continue
+			}
+			index := 1 + int(f.readInt()%uint64(len(keys)-2))
+			keys = append(keys[:index:index], keys[index+1:]...)
+			vals = append(vals[:index:index], vals[index+1:]...)
+
+		case 2:
+			// Extend the range with an element not covered by the proof
+			if len(keys) >= max || bytes.Equal(last, entries[len(entries)-1].k) {
+				continue
+			}
+			extra := common.CopyBytes(last)
+			extra[len(extra)-1]++
+			if extra[len(extra)-1] == 0 {
+				continue
+			}
+			keys = append(keys, extra)
+			vals = append(vals, []byte{0x01})
+		}
+		if err, _ := trie.VerifyRangeProof(root, origin, last, keys, vals, proof); err == nil {
+			panic(fmt.Sprintf("case %d: tampered range accepted", i))
+		}
+	}
+	return ok
+}
+
+// The function must return
+// 1 if the fuzzer should increase priority of the
+//    given input during subsequent fuzzing (for example, the input is lexically
+//    correct and was parsed successfully);
+// -1 if the input must not be added to corpus even if gives new coverage; and
+// 0  otherwise; other values are reserved for future use.
+func Fuzz(input []byte) int {
+	if len(input) < 100 {
+		return 0
+	}
+	r := bytes.NewReader(input)
+	f := fuzzer{
+		input:     r,
+		exhausted: false,
+	}
+	return f.fuzz()
+}
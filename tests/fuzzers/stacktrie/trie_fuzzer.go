@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/fafdb"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/crypto/sha3"
 )
@@ -145,6 +146,8 @@ func (f *fuzzer) fuzz() int {
 		trieA, _    = trie.New(common.Hash{}, dbA)
 		spongeB     = &spongeDb{sponge: sha3.NewLegacyKeccak256()}
 		trieB       = trie.NewStackTrie(spongeB)
+		dbC         = memorydb.New()
+		trieC       = trie.NewStackTrie(dbC)
 		vals        kvs
 		useful      bool
 		maxElements = 10000
@@ -187,6 +190,7 @@ func (f *fuzzer) fuzz() int {
 			fmt.Printf("{\"0x%x\" , \"0x%x\"} // stacktrie.Update\n", kv.k, kv.v)
 		}
 		trieB.Update(kv.k, kv.v)
+		trieC.Update(kv.k, kv.v)
 	}
 	rootB := trieB.Hash()
 	if _, err := trieB.Commit(); err != nil {
@@ -200,5 +204,18 @@ func (f *fuzzer) fuzz() int {
 	if !bytes.Equal(sumA, sumB) {
 		panic(fmt.Sprintf("sequence differ: (trie) %x != %x (stacktrie)", sumA, sumB))
 	}
+	// Ensure the nodes committed by the stacktrie can be read back by a trie
+	if _, err := trieC.Commit(); err != nil {
+		panic(err)
+	}
+	trieD, err := trie.New(rootB, trie.NewDatabase(dbC))
+	if err != nil {
+		panic(fmt.Sprintf("failed to open committed stacktrie: %v", err))
+	}
+	for _, kv := range vals {
+		if have, err := trieD.TryGet(kv.k); err != nil || !bytes.Equal(have, kv.v) {
+			panic(fmt.Sprintf("value mismatch for %x: (trie) %x != %x (stacktrie), err %v", kv.k, have, kv.v, err))
+		}
+	}
 	return 1
 }
@@ -0,0 +1,391 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vmdiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/tests"
+)
+
+var (
+	// forks are the rule sets the generated programs are executed on.
+	forks = []string{"Frontier", "Homestead", "EIP150", "EIP158", "Byzantium", "Constantinople", "ConstantinopleFix", "Istanbul", "Berlin"}
+
+	// forkEips are the EIPs already active in a fork, which must be no-ops when
+	// enabled again through the vm config.
+	forkEips = map[string][]int{
+		"Istanbul": {1344, 1884, 2200},
+		"Berlin":   {1344, 1884, 2200, 2315, 2929},
+	}
+	// forkEquivalents are forks whose instruction set can be assembled from an
+	// older fork and a list of EIPs. The precompiles still differ, so executions
+	// calling into them are not compared.
+	forkEquivalents = map[string]variant{
+		"Istanbul": {fork: "ConstantinopleFix", eips: []int{1344, 1884, 2200}},
+	}
+
+	origin   = common.BytesToAddress([]byte("origin"))
+	contract = common.BytesToAddress([]byte("contract"))
+)
+
+const (
+	maxCodeSize  = 1024
+	maxInputSize = 256
+	maxAccounts  = 4
+	maxSlots     = 4
+)
+
+// variant is an EVM configuration which must execute a program identically to
+// the other variants of the same fork.
+type variant struct {
+	fork   string
+	eips   []int
+	tracer string // Tracer to run with: "", "struct", "json" or "gas"
+
+	precompileSensitive bool // Whether calls into precompiles may diverge
+}
+
+func (v variant) String() string {
+	s := v.fork
+	for _, eip := range v.eips {
+		s += fmt.Sprintf("+%d", eip)
+	}
+	if v.tracer != "" {
+		s += "/" + v.tracer
+	}
+	return s
+}
+
+// variants returns the configurations to compare on the given fork. The first
+// one runs with the gas checking tracer and acts as the reference.
+func variants(fork string) []variant {
+	vs := []variant{
+		{fork: fork, tracer: "gas"},
+		{fork: fork},
+		{fork: fork, tracer: "struct"},
+		{fork: fork, tracer: "json"},
+	}
+	if eips, ok := forkEips[fork]; ok {
+		vs = append(vs, variant{fork: fork, eips: eips})
+	}
+	if equiv, ok := forkEquivalents[fork]; ok {
+		equiv.precompileSensitive = true
+		vs = append(vs, equiv)
+	}
+	return vs
+}
+
+// program is a generated piece of code along with the pre-state and the call
+// parameters to execute it with.
+type program struct {
+	fork  string
+	code  []byte
+	input []byte
+	value *big.Int
+	gas   uint64
+	alloc core.GenesisAlloc
+}
+
+// outcome is the observable result of executing a program.
+type outcome struct {
+	ret  []byte
+	err  error
+	gas  uint64
+	root common.Hash
+	logs common.Hash
+}
+
+type fuzzer struct {
+	input     io.Reader
+	exhausted bool
+}
+
+func (f *fuzzer) readByte() byte {
+	var b [1]byte
+	if _, err := f.input.Read(b[:]); err != nil {
+		f.exhausted = true
+	}
+	return b[0]
+}
+
+func (f *fuzzer) readSlice(max int) []byte {
+	var size uint16
+	if err := binary.Read(f.input, binary.LittleEndian, &size); err != nil {
+		f.exhausted = true
+	}
+	out := make([]byte, int(size)%(max+1))
+	if _, err := io.ReadFull(f.input, out); err != nil {
+		f.exhausted = true
+	}
+	return out
+}
+
+// generate reads a program from the fuzzer input.
+func (f *fuzzer) generate() *program {
+	p := &program{
+		fork:  forks[int(f.readByte())%len(forks)],
+		code:  f.readSlice(maxCodeSize),
+		input: f.readSlice(maxInputSize),
+		value: big.NewInt(int64(f.readByte())),
+		alloc: make(core.GenesisAlloc),
+	}
+	var gas uint32
+	binary.Read(f.input, binary.LittleEndian, &gas)
+	p.gas = uint64(gas % 10000000)
+
+	// Assemble the pre-state: the origin, the contract and a few accounts the
+	// code may interact with, at addresses easily pushed on the stack
+	p.alloc[origin] = core.GenesisAccount{Balance: new(big.Int).Lsh(common.Big1, 128)}
+	p.alloc[contract] = core.GenesisAccount{Balance: new(big.Int), Code: p.code, Storage: f.readStorage()}
+
+	for i, n := 0, int(f.readByte())%(maxAccounts+1); i < n; i++ {
+		addr := common.BytesToAddress([]byte{0xa0 + byte(i)})
+		p.alloc[addr] = core.GenesisAccount{
+			Balance: big.NewInt(int64(f.readByte())),
+			Nonce:   uint64(f.readByte() % 2),
+			Code:    f.readSlice(maxCodeSize),
+			Storage: f.readStorage(),
+		}
+	}
+	return p
+}
+
+func (f *fuzzer) readStorage() map[common.Hash]common.Hash {
+	storage := make(map[common.Hash]common.Hash)
+	for i, n := 0, int(f.readByte())%(maxSlots+1); i < n; i++ {
+		storage[common.BytesToHash([]byte{f.readByte()})] = common.BytesToHash([]byte{f.readByte()})
+	}
+	return storage
+}
+
+// execute runs the program on the given variant.
+func (p *program) execute(v variant, tracer vm.Tracer) outcome {
+	config := tests.Forks[v.fork]
+	_, statedb := tests.MakePreState(rawdb.NewMemoryDatabase(), p.alloc, false)
+
+	cfg := &runtime.Config{
+		ChainConfig: config,
+		Origin:      origin,
+		BlockNumber: new(big.Int),
+		Time:        big.NewInt(1),
+		Difficulty:  big.NewInt(1),
+		GasLimit:    p.gas,
+		GasPrice:    new(big.Int),
+		Value:       p.value,
+		State:       statedb,
+		GetHashFn: func(n uint64) common.Hash {
+			return common.BytesToHash(crypto.Keccak256([]byte(new(big.Int).SetUint64(n).String())))
+		},
+		EVMConfig: vm.Config{
+			Debug:     tracer != nil,
+			Tracer:    tracer,
+			ExtraEips: append([]int{}, v.eips...),
+		},
+	}
+	evm := runtime.NewEnv(cfg)
+	if config.IsYoloV2(cfg.BlockNumber) {
+		statedb.AddAddressToAccessList(origin)
+		statedb.AddAddressToAccessList(contract)
+		for _, addr := range evm.ActivePrecompiles() {
+			statedb.AddAddressToAccessList(addr)
+		}
+	}
+	ret, gas, err := evm.Call(vm.AccountRef(origin), contract, p.input, p.gas, p.value)
+
+	return outcome{
+		ret:  ret,
+		err:  err,
+		gas:  gas,
+		root: statedb.IntermediateRoot(config.IsEIP158(cfg.BlockNumber)),
+		logs: rlpHash(statedb.Logs()),
+	}
+}
+
+func rlpHash(logs []*types.Log) common.Hash {
+	blob, _ := rlp.EncodeToBytes(logs)
+	return crypto.Keccak256Hash(blob)
+}
+
+// diff returns a description of the difference between two outcomes, or an
+// empty string if they are identical.
+func (o outcome) diff(other outcome) string {
+	switch {
+	case (o.err == nil) != (other.err == nil) || (o.err != nil && o.err.Error() != other.err.Error()):
+		return fmt.Sprintf("error mismatch: %v != %v", o.err, other.err)
+	case o.gas != other.gas:
+		return fmt.Sprintf("leftover gas mismatch: %d != %d", o.gas, other.gas)
+	case !bytes.Equal(o.ret, other.ret):
+		return fmt.Sprintf("return data mismatch: %x != %x", o.ret, other.ret)
+	case o.root != other.root:
+		return fmt.Sprintf("state root mismatch: %x != %x", o.root, other.root)
+	case o.logs != other.logs:
+		return fmt.Sprintf("logs mismatch: %x != %x", o.logs, other.logs)
+	}
+	return ""
+}
+
+// gasChecker is a tracer verifying that the gas accounting reported during the
+// execution is self-consistent. It also notes calls into precompiles.
+type gasChecker struct {
+	supplied uint64 // Gas supplied to the top level call
+	used     uint64 // Gas reported as used by the top level call
+	ended    bool   // Whether the top level call ended
+
+	precompiles bool  // Whether any precompile was called
+	err         error // First inconsistency found
+}
+
+func (c *gasChecker) fail(format string, args ...interface{}) {
+	if c.err == nil {
+		c.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (c *gasChecker) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	c.supplied = gas
+	return nil
+}
+
+func (c *gasChecker) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, rData []byte, contract *vm.Contract, depth int, err error) error {
+	// Failing operations are reported with the cost they couldn't pay
+	if err == nil && cost > gas {
+		c.fail("op %v at pc %d: cost %d exceeds available gas %d", op, pc, cost, gas)
+	}
+	if depth == 1 && gas > c.supplied {
+		c.fail("op %v at pc %d: available gas %d exceeds supplied %d", op, pc, gas, c.supplied)
+	}
+	switch op {
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		if len(stack.Data()) > 1 {
+			if _, ok := vm.PrecompiledContractsYoloV2[common.Address(stack.Back(1).Bytes20())]; ok {
+				c.precompiles = true
+			}
+		}
+	}
+	return nil
+}
+
+func (c *gasChecker) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, rStack *vm.ReturnStack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (c *gasChecker) CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error {
+	c.used, c.ended = gasUsed, true
+	return nil
+}
+
+// verify checks the gas reported at the end of the call against the outcome.
+func (c *gasChecker) verify(p *program, o outcome) error {
+	if c.err != nil {
+		return c.err
+	}
+	if o.gas > p.gas {
+		return fmt.Errorf("leftover gas %d exceeds supplied %d", o.gas, p.gas)
+	}
+	if c.ended && c.used != p.gas-o.gas {
+		return fmt.Errorf("reported gas used %d, have %d", c.used, p.gas-o.gas)
+	}
+	return nil
+}
+
+func newTracer(name string) vm.Tracer {
+	switch name {
+	case "struct":
+		return vm.NewStructLogger(&vm.LogConfig{Limit: 1024})
+	case "json":
+		return vm.NewJSONLogger(nil, ioutil.Discard)
+	case "gas":
+		return new(gasChecker)
+	}
+	return nil
+}
+
+// run executes the program on all variants of its fork, returning the outcome
+// of the reference execution and a description of the first divergence.
+func (p *program) run(debug bool) (outcome, string) {
+	var (
+		vs      = variants(p.fork)
+		checker = new(gasChecker)
+		ref     = p.execute(vs[0], checker)
+	)
+	if debug {
+		fmt.Printf("%v: err %v, gas %d, root %x\n", vs[0], ref.err, ref.gas, ref.root)
+	}
+	if err := checker.verify(p, ref); err != nil {
+		return ref, fmt.Sprintf("%v: gas inconsistency: %v", vs[0], err)
+	}
+	for _, v := range vs[1:] {
+		if v.precompileSensitive && checker.precompiles {
+			continue
+		}
+		out := p.execute(v, newTracer(v.tracer))
+		if debug {
+			fmt.Printf("%v: err %v, gas %d, root %x\n", v, out.err, out.gas, out.root)
+		}
+		if diff := ref.diff(out); diff != "" {
+			return ref, fmt.Sprintf("%v vs %v: %s", vs[0], v, diff)
+		}
+	}
+	return ref, ""
+}
+
+// Fuzz is the go-fuzz entry point. It generates a program from the input and
+// executes it on equivalent EVM configurations, panicking if they diverge or
+// the gas accounting is inconsistent. Programs executing successfully are
+// prioritized, inputs too short to generate a program are rejected.
+func Fuzz(input []byte) int {
+	return fuzz(input, false)
+}
+
+// Debug executes the given input printing the outcome of every configuration.
+func Debug(input []byte) int {
+	return fuzz(input, true)
+}
+
+func fuzz(input []byte, debug bool) int {
+	f := &fuzzer{input: bytes.NewReader(input)}
+	p := f.generate()
+	if f.exhausted {
+		return -1
+	}
+	if debug {
+		fmt.Printf("fork %s, code %x, input %x, value %v, gas %d\n", p.fork, p.code, p.input, p.value, p.gas)
+	}
+	out, diff := p.run(debug)
+	if diff != "" {
+		panic(diff)
+	}
+	if out.err != nil {
+		return 0
+	}
+	return 1
+}
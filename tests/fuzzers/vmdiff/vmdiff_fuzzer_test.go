@@ -0,0 +1,72 @@
+// Copyright 2020 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vmdiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// encodeProgram creates a fuzzer input executing the code on the given fork,
+// with a single pre-set storage slot and no extra accounts.
+func encodeProgram(fork int, code []byte, gas uint32) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(byte(fork))
+	binary.Write(buf, binary.LittleEndian, uint16(len(code)))
+	buf.Write(code)
+	binary.Write(buf, binary.LittleEndian, uint16(2))
+	buf.Write([]byte{0x01, 0x02}) // call input
+	buf.WriteByte(3)              // call value
+	binary.Write(buf, binary.LittleEndian, gas)
+	buf.Write([]byte{1, 0x00, 0x05}) // contract storage: slot 0 = 5
+	buf.WriteByte(0)                 // no extra accounts
+	return buf.Bytes()
+}
+
+// Tests that programs touching the fork specific gas rules execute identically
+// on all the equivalent configurations of every fork.
+func TestEquivalentConfigs(t *testing.T) {
+	programs := map[string][]byte{
+		// SSTORE over the pre-set slot, then SLOAD, BALANCE and SELFBALANCE-free ops
+		"sstore": common.FromHex("fx600160005560005460015530316002553660035500"),
+		// CALL into the identity and blake2f precompiles, diverging between forks
+		"precompile": common.FromHex("fx6000600060006000600060046161a8f1506000600060006000600060096161a8f100"),
+		// Out of gas halfway through
+		"oog": common.FromHex("fx5b600160005401600055600056"),
+	}
+	for name, code := range programs {
+		for fork := range forks {
+			if out := Fuzz(encodeProgram(fork, code, 100000)); out < 0 {
+				t.Errorf("%s on %s: program rejected", name, forks[fork])
+			}
+		}
+	}
+}
+
+// Tests that random inputs don't produce divergences.
+func TestRandomPrograms(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		input := make([]byte, 64+rng.Intn(4096))
+		rng.Read(input)
+		Fuzz(input)
+	}
+}
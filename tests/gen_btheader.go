@@ -17,22 +17,22 @@ var _ = (*btHeaderMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (b btHeader) MarshalJSON() ([]byte, error) {
 	type btHeader struct {
-		Bloom            types.Bloom
-		Coinbase         common.Address
-		MixHash          common.Hash
-		Nonce            types.BlockNonce
-		Number           *math.HexOrDecimal256
-		Hash             common.Hash
-		ParentHash       common.Hash
-		ReceiptTrie      common.Hash
-		StateRoot        common.Hash
-		TransactionsTrie common.Hash
-		UncleHash        common.Hash
-		ExtraData        hexutil.Bytes
-		Difficulty       *math.HexOrDecimal256
-		GasLimit         math.HexOrDecimal64
-		GasUsed          math.HexOrDecimal64
-		Timestamp        math.HexOrDecimal64
+		Bloom            types.Bloom           `json:"bloom"`
+		Coinbase         common.Address        `json:"coinbase"`
+		MixHash          common.Hash           `json:"mixHash"`
+		Nonce            types.BlockNonce      `json:"nonce"`
+		Number           *math.HexOrDecimal256 `json:"number"`
+		Hash             common.Hash           `json:"hash"`
+		ParentHash       common.Hash           `json:"parentHash"`
+		ReceiptTrie      common.Hash           `json:"receiptTrie"`
+		StateRoot        common.Hash           `json:"stateRoot"`
+		TransactionsTrie common.Hash           `json:"transactionsTrie"`
+		UncleHash        common.Hash           `json:"uncleHash"`
+		ExtraData        hexutil.Bytes         `json:"extraData"`
+		Difficulty       *math.HexOrDecimal256 `json:"difficulty"`
+		GasLimit         math.HexOrDecimal64   `json:"gasLimit"`
+		GasUsed          math.HexOrDecimal64   `json:"gasUsed"`
+		Timestamp        math.HexOrDecimal64   `json:"timestamp"`
 	}
 	var enc btHeader
 	enc.Bloom = b.Bloom
@@ -57,22 +57,22 @@ func (b btHeader) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (b *btHeader) UnmarshalJSON(input []byte) error {
 	type btHeader struct {
-		Bloom            *types.Bloom
-		Coinbase         *common.Address
-		MixHash          *common.Hash
-		Nonce            *types.BlockNonce
-		Number           *math.HexOrDecimal256
-		Hash             *common.Hash
-		ParentHash       *common.Hash
-		ReceiptTrie      *common.Hash
-		StateRoot        *common.Hash
-		TransactionsTrie *common.Hash
-		UncleHash        *common.Hash
-		ExtraData        *hexutil.Bytes
-		Difficulty       *math.HexOrDecimal256
-		GasLimit         *math.HexOrDecimal64
-		GasUsed          *math.HexOrDecimal64
-		Timestamp        *math.HexOrDecimal64
+		Bloom            *types.Bloom          `json:"bloom"`
+		Coinbase         *common.Address       `json:"coinbase"`
+		MixHash          *common.Hash          `json:"mixHash"`
+		Nonce            *types.BlockNonce     `json:"nonce"`
+		Number           *math.HexOrDecimal256 `json:"number"`
+		Hash             *common.Hash          `json:"hash"`
+		ParentHash       *common.Hash          `json:"parentHash"`
+		ReceiptTrie      *common.Hash          `json:"receiptTrie"`
+		StateRoot        *common.Hash          `json:"stateRoot"`
+		TransactionsTrie *common.Hash          `json:"transactionsTrie"`
+		UncleHash        *common.Hash          `json:"uncleHash"`
+		ExtraData        *hexutil.Bytes        `json:"extraData"`
+		Difficulty       *math.HexOrDecimal256 `json:"difficulty"`
+		GasLimit         *math.HexOrDecimal64  `json:"gasLimit"`
+		GasUsed          *math.HexOrDecimal64  `json:"gasUsed"`
+		Timestamp        *math.HexOrDecimal64  `json:"timestamp"`
 	}
 	var dec btHeader
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -0,0 +1,460 @@
+
+
+package tests
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// fillForks are the forks a filler network range may span, in activation order.
+var fillForks = []string{
+	"Frontier",
+	"Homestead",
+	"EIP150",
+	"EIP158",
+	"Byzantium",
+	"Constantinople",
+	"ConstantinopleFix",
+	"Istanbul",
+	"Berlin",
+}
+
+// StateTestFiller is the high level description of a state test: the pre-state,
+// the transaction matrix and the expected outcome on a set of forks. Filling it
+// executes every combination and records the resulting post-state roots.
+type StateTestFiller struct {
+	json stFillerJSON
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (f *StateTestFiller) UnmarshalJSON(in []byte) error {
+	return json.Unmarshal(in, &f.json)
+}
+
+type stFillerJSON struct {
+	Env    stEnv             `json:"env"`
+	Pre    core.GenesisAlloc `json:"pre"`
+	Tx     stTransaction     `json:"transaction"`
+	Expect []stExpect        `json:"expect"`
+}
+
+// stExpect is a set of predicates the post-state of the matching subtests must
+// satisfy. Missing indexes, or indexes of -1, match any transaction variant.
+type stExpect struct {
+	Indexes struct {
+		Data  *fillIndex `json:"data"`
+		Gas   *fillIndex `json:"gas"`
+		Value *fillIndex `json:"value"`
+	} `json:"indexes"`
+	Network []string                          `json:"network"`
+	Result  map[common.Address]stAccountCheck `json:"result"`
+}
+
+// stAccountCheck is the expected post-state of an account. Only the fields set
+// are checked, storage slots not listed are ignored.
+type stAccountCheck struct {
+	Balance        *math.HexOrDecimal256 `json:"balance"`
+	Nonce          *math.HexOrDecimal64  `json:"nonce"`
+	Code           *hexutil.Bytes        `json:"code"`
+	Storage        map[string]string     `json:"storage"`
+	ShouldNotExist bool                  `json:"shouldnotexist"`
+}
+
+// fillIndex is an index into the transaction matrix, accepted both as a JSON
+// number and as a string.
+type fillIndex int
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (i *fillIndex) UnmarshalJSON(in []byte) error {
+	n, err := strconv.Atoi(strings.Trim(string(in), `"`))
+	if err != nil {
+		return fmt.Errorf("invalid index %s", in)
+	}
+	*i = fillIndex(n)
+	return nil
+}
+
+// matches reports whether the index selects the given transaction variant.
+func (i *fillIndex) matches(n int) bool {
+	return i == nil || *i < 0 || int(*i) == n
+}
+
+// expandNetworks resolves a list of fork names and ranges (e.g. ">=Byzantium",
+// "<Istanbul") into the list of forks they cover.
+func expandNetworks(networks []string) ([]string, error) {
+	var forks []string
+	for _, network := range networks {
+		var op string
+		for _, prefix := range []string{">=", "<=", ">", "<"} {
+			if strings.HasPrefix(network, prefix) {
+				op, network = prefix, strings.TrimSpace(network[len(prefix):])
+				break
+			}
+		}
+		if op == "" {
+			if _, _, err := GetChainConfig(network); err != nil {
+				return nil, err
+			}
+			forks = append(forks, network)
+			continue
+		}
+		pivot := -1
+		for i, fork := range fillForks {
+			if fork == network {
+				pivot = i
+			}
+		}
+		if pivot < 0 {
+			return nil, fmt.Errorf("fork %q not usable in a range", network)
+		}
+		for i, fork := range fillForks {
+			if (op == ">=" && i >= pivot) || (op == ">" && i > pivot) || (op == "<=" && i <= pivot) || (op == "<" && i < pivot) {
+				forks = append(forks, fork)
+			}
+		}
+	}
+	return forks, nil
+}
+
+// fillCase is a single subtest selected by the expectations of a filler.
+type fillCase struct {
+	fork   string
+	post   stPostState
+	expect *stExpect
+}
+
+func (c *fillCase) String() string {
+	return fmt.Sprintf("%s/d%dg%dv%d", c.fork, c.post.Indexes.Data, c.post.Indexes.Gas, c.post.Indexes.Value)
+}
+
+// cases enumerates the subtests covered by the expectations of the filler. Every
+// subtest may only be covered by a single expectation.
+func (f *StateTestFiller) cases() ([]*fillCase, error) {
+	var (
+		cases []*fillCase
+		seen  = make(map[string]bool)
+	)
+	for i := range f.json.Expect {
+		expect := &f.json.Expect[i]
+
+		forks, err := expandNetworks(expect.Network)
+		if err != nil {
+			return nil, fmt.Errorf("expectation %d: %v", i, err)
+		}
+		for _, fork := range forks {
+			for d := range f.json.Tx.Data {
+				for g := range f.json.Tx.GasLimit {
+					for v := range f.json.Tx.Value {
+						if !expect.Indexes.Data.matches(d) || !expect.Indexes.Gas.matches(g) || !expect.Indexes.Value.matches(v) {
+							continue
+						}
+						c := &fillCase{fork: fork, expect: expect}
+						c.post.Indexes.Data, c.post.Indexes.Gas, c.post.Indexes.Value = d, g, v
+
+						if seen[c.String()] {
+							return nil, fmt.Errorf("expectation %d: subtest %v already covered", i, c)
+						}
+						seen[c.String()] = true
+						cases = append(cases, c)
+					}
+				}
+			}
+		}
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("no subtests covered by the expectations")
+	}
+	return cases, nil
+}
+
+// verify checks the post-state against the expectation.
+func (e *stExpect) verify(statedb *state.StateDB) error {
+	for addr, check := range e.Result {
+		if check.ShouldNotExist {
+			if statedb.Exist(addr) {
+				return fmt.Errorf("account %x exists", addr)
+			}
+			continue
+		}
+		if !statedb.Exist(addr) {
+			return fmt.Errorf("account %x missing", addr)
+		}
+		if check.Balance != nil {
+			if have, want := statedb.GetBalance(addr), (*big.Int)(check.Balance); have.Cmp(want) != 0 {
+				return fmt.Errorf("account %x balance mismatch: have %v, want %v", addr, have, want)
+			}
+		}
+		if check.Nonce != nil {
+			if have, want := statedb.GetNonce(addr), uint64(*check.Nonce); have != want {
+				return fmt.Errorf("account %x nonce mismatch: have %d, want %d", addr, have, want)
+			}
+		}
+		if check.Code != nil {
+			if have, want := statedb.GetCode(addr), []byte(*check.Code); string(have) != string(want) {
+				return fmt.Errorf("account %x code mismatch: have %x, want %x", addr, have, want)
+			}
+		}
+		for key, val := range check.Storage {
+			slot, err := parseSlot(key)
+			if err != nil {
+				return fmt.Errorf("account %x: %v", addr, err)
+			}
+			want, err := parseSlot(val)
+			if err != nil {
+				return fmt.Errorf("account %x: %v", addr, err)
+			}
+			if have := statedb.GetState(addr, slot); have != want {
+				return fmt.Errorf("account %x slot %x mismatch: have %x, want %x", addr, slot, have, want)
+			}
+		}
+	}
+	return nil
+}
+
+// parseSlot parses a storage key or value given as a hex or decimal number.
+func parseSlot(s string) (common.Hash, error) {
+	n, ok := math.ParseBig256(s)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("invalid storage slot %q", s)
+	}
+	return common.BigToHash(n), nil
+}
+
+// FillStateTest executes every subtest covered by the expectations, verifies
+// the post-states and returns the filled state test.
+func (f *StateTestFiller) FillStateTest() (*StateTest, error) {
+	cases, err := f.cases()
+	if err != nil {
+		return nil, err
+	}
+	filled := &StateTest{json: stJSON{
+		Env:  f.json.Env,
+		Pre:  f.json.Pre,
+		Tx:   f.json.Tx,
+		Post: make(map[string][]stPostState),
+	}}
+	for _, c := range cases {
+		// Run the single subtest on its own, the post roots are still unknown
+		test := &StateTest{json: filled.json}
+		test.json.Post = map[string][]stPostState{c.fork: {c.post}}
+
+		_, statedb, root, err := test.RunNoVerify(StateSubtest{Fork: c.fork}, vm.Config{}, false)
+		if err != nil {
+			return nil, fmt.Errorf("subtest %v: %v", c, err)
+		}
+		if err := c.expect.verify(statedb); err != nil {
+			return nil, fmt.Errorf("subtest %v: %v", c, err)
+		}
+		post := c.post
+		post.Root = common.UnprefixedHash(root)
+		post.Logs = common.UnprefixedHash(rlpHash(statedb.Logs()))
+		filled.json.Post[c.fork] = append(filled.json.Post[c.fork], post)
+	}
+	return filled, nil
+}
+
+// FillBlockTests executes every subtest covered by the expectations as the
+// single transaction of a block on top of the pre-state, verifies the post-states
+// and returns the filled blockchain tests, keyed by subtest (e.g. d0g0v0_Istanbul).
+func (f *StateTestFiller) FillBlockTests() (map[string]*BlockTest, error) {
+	cases, err := f.cases()
+	if err != nil {
+		return nil, err
+	}
+	if len(f.json.Tx.PrivateKey) == 0 {
+		return nil, errors.New("transaction secret key required for blockchain tests")
+	}
+	key, err := crypto.ToECDSA(f.json.Tx.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	filled := make(map[string]*BlockTest)
+	for _, c := range cases {
+		test, err := f.fillBlockTest(c, key)
+		if err != nil {
+			return nil, fmt.Errorf("subtest %v: %v", c, err)
+		}
+		filled[fmt.Sprintf("d%dg%dv%d_%s", c.post.Indexes.Data, c.post.Indexes.Gas, c.post.Indexes.Value, c.fork)] = test
+	}
+	return filled, nil
+}
+
+func (f *StateTestFiller) fillBlockTest(c *fillCase, key *ecdsa.PrivateKey) (*BlockTest, error) {
+	config, ok := Forks[c.fork]
+	if !ok {
+		return nil, UnsupportedForkError{c.fork}
+	}
+	// Assemble the signed transaction of the subtest
+	msg, err := f.json.Tx.toMessage(c.post)
+	if err != nil {
+		return nil, err
+	}
+	var tx *types.Transaction
+	if msg.To() == nil {
+		tx = types.NewContractCreation(msg.Nonce(), msg.Value(), msg.Gas(), msg.GasPrice(), msg.Data())
+	} else {
+		tx = types.NewTransaction(msg.Nonce(), *msg.To(), msg.Value(), msg.Gas(), msg.GasPrice(), msg.Data())
+	}
+	if tx, err = types.SignTx(tx, types.MakeSigner(config, common.Big1), key); err != nil {
+		return nil, err
+	}
+	// Generate the block including it on top of the pre-state
+	gspec := &core.Genesis{
+		Config:     config,
+		Difficulty: f.json.Env.Difficulty,
+		GasLimit:   f.json.Env.GasLimit,
+		Alloc:      f.json.Pre,
+	}
+	db := rawdb.NewMemoryDatabase()
+	genesis := gspec.MustCommit(db)
+
+	block, err := generateFillBlock(config, genesis, db, f.json.Env.Coinbase, tx)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := core.NewBlockChain(db, nil, config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		return nil, err
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.expect.verify(statedb); err != nil {
+		return nil, err
+	}
+	blob, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockTest{json: btJSON{
+		Blocks:     []btBlock{{BlockHeader: newBtHeader(block.Header()), Rlp: "0x" + common.Bytes2Hex(blob)}},
+		Genesis:    *newBtHeader(genesis.Header()),
+		Pre:        f.json.Pre,
+		Post:       f.postAlloc(statedb, tx, c.expect),
+		BestBlock:  common.UnprefixedHash(block.Hash()),
+		Network:    c.fork,
+		SealEngine: "NoProof",
+	}}, nil
+}
+
+// generateFillBlock generates a block containing a single transaction, returning
+// an error instead of panicking if the transaction is invalid.
+func generateFillBlock(config *params.ChainConfig, genesis *types.Block, db fafdb.Database, coinbase common.Address, tx *types.Transaction) (block *types.Block, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid transaction: %v", r)
+		}
+	}()
+	blocks, _ := core.GenerateChain(config, genesis, ethash.NewFaker(), db, 1, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(coinbase)
+		gen.AddTx(tx)
+	})
+	return blocks[0], nil
+}
+
+// postAlloc collects the post-state of the accounts touched by the subtest: the
+// pre-state, the sender, recipient and coinbase, and the expected accounts.
+// Storage is gathered for the slots known from the pre-state and expectations.
+func (f *StateTestFiller) postAlloc(statedb *state.StateDB, tx *types.Transaction, expect *stExpect) core.GenesisAlloc {
+	slots := make(map[common.Address]map[common.Hash]struct{})
+	track := func(addr common.Address, slot *common.Hash) {
+		if slots[addr] == nil {
+			slots[addr] = make(map[common.Hash]struct{})
+		}
+		if slot != nil {
+			slots[addr][*slot] = struct{}{}
+		}
+	}
+	for addr, account := range f.json.Pre {
+		track(addr, nil)
+		for slot := range account.Storage {
+			slot := slot
+			track(addr, &slot)
+		}
+	}
+	for addr, check := range expect.Result {
+		track(addr, nil)
+		for key := range check.Storage {
+			if slot, err := parseSlot(key); err == nil {
+				track(addr, &slot)
+			}
+		}
+	}
+	sender, _ := crypto.ToECDSA(f.json.Tx.PrivateKey)
+	track(crypto.PubkeyToAddress(sender.PublicKey), nil)
+	track(f.json.Env.Coinbase, nil)
+	if to := tx.To(); to != nil {
+		track(*to, nil)
+	} else {
+		track(crypto.CreateAddress(crypto.PubkeyToAddress(sender.PublicKey), tx.Nonce()), nil)
+	}
+	alloc := make(core.GenesisAlloc)
+	for addr, keys := range slots {
+		if !statedb.Exist(addr) {
+			continue
+		}
+		account := core.GenesisAccount{
+			Balance: statedb.GetBalance(addr),
+			Nonce:   statedb.GetNonce(addr),
+			Code:    statedb.GetCode(addr),
+		}
+		for slot := range keys {
+			if value := statedb.GetState(addr, slot); value != (common.Hash{}) {
+				if account.Storage == nil {
+					account.Storage = make(map[common.Hash]common.Hash)
+				}
+				account.Storage[slot] = value
+			}
+		}
+		alloc[addr] = account
+	}
+	return alloc
+}
+
+// newBtHeader converts a block header into its blockchain test representation.
+func newBtHeader(h *types.Header) *btHeader {
+	return &btHeader{
+		Bloom:            h.Bloom,
+		Coinbase:         h.Coinbase,
+		MixHash:          h.MixDigest,
+		Nonce:            h.Nonce,
+		Number:           h.Number,
+		Hash:             h.Hash(),
+		ParentHash:       h.ParentHash,
+		ReceiptTrie:      h.ReceiptHash,
+		StateRoot:        h.Root,
+		TransactionsTrie: h.TxHash,
+		UncleHash:        h.UncleHash,
+		ExtraData:        h.Extra,
+		Difficulty:       h.Difficulty,
+		GasLimit:         h.GasLimit,
+		GasUsed:          h.GasUsed,
+		Timestamp:        h.Time,
+	}
+}
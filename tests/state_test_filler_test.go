@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// testFiller stores the call value in slot 0 and the calldata size in slot 1 of
+// the called contract.
+const testFiller = `{
+	"env": {
+		"currentCoinbase": "0x2adc25665018aa1fe0e6bc666dac8fc2697ff9ba",
+		"currentDifficulty": "0x020000",
+		"currentGasLimit": "0x7fffffff",
+		"currentNumber": "1",
+		"currentTimestamp": "1000"
+	},
+	"pre": {
+		"0x095e7baea6a6c7c4c2dfeb977efac326af552d87": {
+			"balance": "0",
+			"code": "0x3460005536600155",
+			"nonce": "0",
+			"storage": {}
+		},
+		"0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b": {
+			"balance": "1000000000000000000",
+			"code": "0x",
+			"nonce": "0",
+			"storage": {}
+		}
+	},
+	"transaction": {
+		"data": ["0x", "0x0102"],
+		"gasLimit": ["400000"],
+		"gasPrice": "10",
+		"nonce": "0",
+		"secretKey": "0x45a915e4d060149eb4365960e6a7a45f334393093061116b197e3240065ff2d8",
+		"to": "0x095e7baea6a6c7c4c2dfeb977efac326af552d87",
+		"value": ["0", "7"]
+	},
+	"expect": [
+		{
+			"indexes": {"data": 0, "value": -1},
+			"network": [">=Byzantium"],
+			"result": {
+				"0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b": {"nonce": "1"},
+				"0x095e7baea6a6c7c4c2dfeb977efac326af552d87": {"storage": {"0x01": "0"}}
+			}
+		},
+		{
+			"indexes": {"data": "1", "value": 1},
+			"network": ["Istanbul", "Berlin"],
+			"result": {
+				"0x095e7baea6a6c7c4c2dfeb977efac326af552d87": {"balance": "7", "storage": {"0x00": "7", "0x01": "2"}}
+			}
+		}
+	]
+}`
+
+func loadTestFiller(t *testing.T, filler string) *StateTestFiller {
+	var f StateTestFiller
+	if err := json.Unmarshal([]byte(filler), &f); err != nil {
+		t.Fatalf("failed to parse filler: %v", err)
+	}
+	return &f
+}
+
+// Tests that state tests filled from a filler pass when executed.
+func TestFillStateTest(t *testing.T) {
+	filled, err := loadTestFiller(t, testFiller).FillStateTest()
+	if err != nil {
+		t.Fatalf("failed to fill state test: %v", err)
+	}
+	blob, err := json.Marshal(filled)
+	if err != nil {
+		t.Fatalf("failed to marshal state test: %v", err)
+	}
+	var test StateTest
+	if err := json.Unmarshal(blob, &test); err != nil {
+		t.Fatalf("failed to unmarshal state test: %v", err)
+	}
+	// Byzantium onwards with both values, plus the second data on two forks
+	if subtests := test.Subtests(); len(subtests) != 5*2+2 {
+		t.Fatalf("subtest count mismatch: have %d, want %d", len(subtests), 5*2+2)
+	}
+	for _, subtest := range test.Subtests() {
+		if _, _, err := test.Run(subtest, vm.Config{}, false); err != nil {
+			t.Errorf("subtest %s/%d failed: %v", subtest.Fork, subtest.Index, err)
+		}
+	}
+}
+
+// Tests that blockchain tests filled from a filler pass when executed.
+func TestFillBlockTests(t *testing.T) {
+	// The test forks between Byzantium and Istanbul activate the DAO fork at genesis
+	// and can't have an empty extra-data in the first block, avoid them
+	filler := strings.Replace(testFiller, `[">=Byzantium"]`, `["<Byzantium"]`, 1)
+	filler = strings.Replace(filler, `["Istanbul", "Berlin"]`, `["Berlin", "YOLOv2"]`, 1)
+
+	filled, err := loadTestFiller(t, filler).FillBlockTests()
+	if err != nil {
+		t.Fatalf("failed to fill blockchain tests: %v", err)
+	}
+	if len(filled) != 4*2+2 {
+		t.Fatalf("test count mismatch: have %d, want %d", len(filled), 4*2+2)
+	}
+	for name, test := range filled {
+		blob, err := json.Marshal(test)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal blockchain test: %v", name, err)
+		}
+		var loaded BlockTest
+		if err := json.Unmarshal(blob, &loaded); err != nil {
+			t.Fatalf("%s: failed to unmarshal blockchain test: %v", name, err)
+		}
+		if err := loaded.Run(false); err != nil {
+			t.Errorf("%s: test failed: %v", name, err)
+		}
+	}
+}
+
+// Tests that fillers with unmet or ambiguous expectations are rejected.
+func TestFillInvalidExpectations(t *testing.T) {
+	tests := []struct {
+		from, to string
+		err      string
+	}{
+		{`{"nonce": "1"}`, `{"nonce": "2"}`, "nonce mismatch"},
+		{`"indexes": {"data": "1", "value": 1}`, `"indexes": {"data": "0", "value": 1}`, "already covered"},
+		{`"network": [">=Byzantium"]`, `"network": [">=Unknown"]`, "not usable in a range"},
+	}
+	for i, tt := range tests {
+		filler := loadTestFiller(t, strings.Replace(testFiller, tt.from, tt.to, 1))
+		if _, err := filler.FillStateTest(); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %q", i, err, tt.err)
+		}
+	}
+}
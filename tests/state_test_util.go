@@ -43,6 +43,11 @@ func (t *StateTest) UnmarshalJSON(in []byte) error {
 	return json.Unmarshal(in, &t.json)
 }
 
+// MarshalJSON implements json.Marshaler interface.
+func (t *StateTest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.json)
+}
+
 type stJSON struct {
 	Env  stEnv                    `json:"env"`
 	Pre  core.GenesisAlloc        `json:"pre"`
@@ -58,7 +63,7 @@ type stPostState struct {
 		Data  int `json:"data"`
 		Gas   int `json:"gas"`
 		Value int `json:"value"`
-	}
+	} `json:"indexes"`
 }
 
 //go:generate gencodec -type stEnv -field-override stEnvMarshaling -out gen_stenv.go
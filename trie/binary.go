@@ -0,0 +1,904 @@
+
+
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/fafdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Binary trie node encoding prefixes.
+const (
+	binaryLeafPrefix   = 0x00
+	binaryBranchPrefix = 0x01
+)
+
+// binaryBranchSize is the size of an encoded binary branch: the prefix and the
+// hashes of the two children.
+const binaryBranchSize = 1 + 2*common.HashLength
+
+// binaryNode is a node of a binary trie: nil for an empty subtrie, a resolved
+// *binaryBranch or *binaryLeaf, or an unresolved binaryHash.
+type binaryNode interface{}
+
+type (
+	// binaryBranch is an internal node of the binary trie, with the children
+	// selected by the bit of the key at the branch's depth.
+	binaryBranch struct {
+		children [2]binaryNode
+		hash     []byte // Cached hash of the node, nil if not yet hashed
+		dirty    bool   // Whether the node was not yet written to disk
+	}
+	// binaryLeaf is a value stored in the binary trie. Leaves contain the full
+	// hashed key and are placed at the shallowest depth where the key prefix is
+	// unique, so the trie shape only depends on the set of keys it contains.
+	binaryLeaf struct {
+		key   []byte // Hashed key of the leaf
+		value []byte // Value stored under the key
+		hash  []byte // Cached hash of the node, nil if not yet hashed
+		dirty bool   // Whether the node was not yet written to disk
+	}
+	// binaryHash is a reference to a node not yet loaded from the database.
+	binaryHash []byte
+)
+
+func (n *binaryBranch) copy() *binaryBranch { cpy := *n; return &cpy }
+func (n *binaryLeaf) copy() *binaryLeaf     { cpy := *n; return &cpy }
+
+// encode returns the database encoding of a hashed branch.
+func (n *binaryBranch) encode() []byte {
+	enc := make([]byte, binaryBranchSize)
+	enc[0] = binaryBranchPrefix
+	for i, child := range n.children {
+		if child != nil {
+			copy(enc[1+i*common.HashLength:], binaryNodeHash(child))
+		}
+	}
+	return enc
+}
+
+// encode returns the database encoding of a leaf.
+func (n *binaryLeaf) encode() []byte {
+	enc := make([]byte, 0, 1+len(n.key)+len(n.value))
+	enc = append(enc, binaryLeafPrefix)
+	enc = append(enc, n.key...)
+	return append(enc, n.value...)
+}
+
+// binaryNodeHash returns the hash of an already hashed binary node.
+func binaryNodeHash(n binaryNode) []byte {
+	switch n := n.(type) {
+	case *binaryBranch:
+		return n.hash
+	case *binaryLeaf:
+		return n.hash
+	case binaryHash:
+		return n
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// decodeBinaryNode parses the database encoding of a binary trie node.
+func decodeBinaryNode(hash, buf []byte) (binaryNode, error) {
+	if len(buf) == 0 {
+		return nil, errors.New("empty binary node")
+	}
+	switch buf[0] {
+	case binaryBranchPrefix:
+		if len(buf) != binaryBranchSize {
+			return nil, fmt.Errorf("invalid binary branch size %d", len(buf))
+		}
+		n := &binaryBranch{hash: hash}
+		for i := range n.children {
+			child := buf[1+i*common.HashLength : 1+(i+1)*common.HashLength]
+			if !bytes.Equal(child, common.Hash{}.Bytes()) {
+				n.children[i] = binaryHash(common.CopyBytes(child))
+			}
+		}
+		return n, nil
+
+	case binaryLeafPrefix:
+		if len(buf) < 1+common.HashLength {
+			return nil, fmt.Errorf("invalid binary leaf size %d", len(buf))
+		}
+		return &binaryLeaf{
+			key:   common.CopyBytes(buf[1 : 1+common.HashLength]),
+			value: common.CopyBytes(buf[1+common.HashLength:]),
+			hash:  hash,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid binary node prefix %#x", buf[0])
+	}
+}
+
+// errBinaryDepth is returned if a branch is found at a depth where the key has no
+// bits left to select a child, which only happens with corrupt or forged nodes.
+var errBinaryDepth = errors.New("binary branch below maximum trie depth")
+
+// checkBinaryDepth returns an error if a branch at the given depth can't select a
+// child by a bit of the key.
+func checkBinaryDepth(key []byte, depth int) error {
+	if depth >= 8*len(key) {
+		return fmt.Errorf("%v: depth %d, key length %d", errBinaryDepth, depth, len(key))
+	}
+	return nil
+}
+
+// binaryKeyBit returns the bit of the key at the given depth. The depth must be
+// below the bit length of the key, see checkBinaryDepth.
+func binaryKeyBit(key []byte, depth int) int {
+	return int(key[depth/8]>>(7-uint(depth%8))) & 1
+}
+
+// binaryKeyPath returns the first depth bits of the key, one bit per byte.
+func binaryKeyPath(key []byte, depth int) []byte {
+	path := make([]byte, depth)
+	for i := range path {
+		path[i] = byte(binaryKeyBit(key, i))
+	}
+	return path
+}
+
+// BinaryTrie is an experimental binary Merkle trie, an alternative to the hexary
+// Merkle Patricia trie wrapped in SecureTrie. Keys are hashed with keccak256 and
+// stored in a binary tree walking the bits of the hashed key, trading deeper
+// paths for much smaller proofs: every level only contributes a sibling hash.
+//
+// Committed nodes are written directly to the disk database and are not tracked
+// for garbage collection by the intermediate node database.
+//
+// BinaryTrie is not safe for concurrent use.
+type BinaryTrie struct {
+	db   *Database
+	root binaryNode
+
+	recorder         *Recorder // Optional recorder of all the nodes resolved from db
+	hashKeyBuf       [common.HashLength]byte
+	secKeyCache      map[string][]byte
+	secKeyCacheOwner *BinaryTrie // Pointer to self, replace the key cache on mismatch
+}
+
+// NewBinary creates a binary trie with an existing root node from a backing
+// database. If root is the zero hash or the empty trie root hash, the trie is
+// initially empty. Otherwise, a MissingNodeError is returned if the root node
+// cannot be found.
+func NewBinary(root common.Hash, db *Database) (*BinaryTrie, error) {
+	if db == nil {
+		panic("trie.NewBinary called without a database")
+	}
+	t := &BinaryTrie{db: db}
+	if root != (common.Hash{}) && root != emptyRoot {
+		n, err := t.resolve(root[:], nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		t.root = n
+	}
+	return t, nil
+}
+
+// resolve loads the node with the given hash from the database.
+func (t *BinaryTrie) resolve(hash binaryHash, key []byte, depth int) (binaryNode, error) {
+	blob, _ := t.db.Node(common.BytesToHash(hash))
+	if len(blob) == 0 {
+		return nil, &MissingNodeError{NodeHash: common.BytesToHash(hash), Path: binaryKeyPath(key, depth)}
+	}
+	if t.recorder != nil {
+		t.recorder.record(common.BytesToHash(hash), blob)
+	}
+	return decodeBinaryNode(hash, blob)
+}
+
+// SetRecorder attaches a recorder collecting all the nodes resolved from the
+// database from now on. The root node, if already resolved, is recorded too.
+func (t *BinaryTrie) SetRecorder(recorder *Recorder) {
+	t.recorder = recorder
+
+	var hash []byte
+	switch n := t.root.(type) {
+	case *binaryBranch:
+		if !n.dirty {
+			hash = n.hash
+		}
+	case *binaryLeaf:
+		if !n.dirty {
+			hash = n.hash
+		}
+	}
+	if hash != nil {
+		if blob, _ := t.db.Node(common.BytesToHash(hash)); len(blob) != 0 {
+			recorder.record(common.BytesToHash(hash), blob)
+		}
+	}
+}
+
+// Get returns the value for key stored in the trie.
+// The value bytes must not be modified by the caller.
+func (t *BinaryTrie) Get(key []byte) []byte {
+	res, err := t.TryGet(key)
+	if err != nil {
+		log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+	}
+	return res
+}
+
+// TryGet returns the value for key stored in the trie.
+// The value bytes must not be modified by the caller.
+// If a node was not found in the database, a MissingNodeError is returned.
+func (t *BinaryTrie) TryGet(key []byte) ([]byte, error) {
+	value, newroot, didResolve, err := t.tryGet(t.root, t.hashKey(key), 0)
+	if err == nil && didResolve {
+		t.root = newroot
+	}
+	return value, err
+}
+
+func (t *BinaryTrie) tryGet(n binaryNode, key []byte, depth int) ([]byte, binaryNode, bool, error) {
+	switch n := n.(type) {
+	case nil:
+		return nil, nil, false, nil
+
+	case *binaryLeaf:
+		if bytes.Equal(n.key, key) {
+			return n.value, n, false, nil
+		}
+		return nil, n, false, nil
+
+	case *binaryBranch:
+		if err := checkBinaryDepth(key, depth); err != nil {
+			return nil, n, false, err
+		}
+		bit := binaryKeyBit(key, depth)
+		value, child, didResolve, err := t.tryGet(n.children[bit], key, depth+1)
+		if err == nil && didResolve {
+			n = n.copy()
+			n.children[bit] = child
+		}
+		return value, n, didResolve, err
+
+	case binaryHash:
+		child, err := t.resolve(n, key, depth)
+		if err != nil {
+			return nil, n, true, err
+		}
+		value, newnode, _, err := t.tryGet(child, key, depth)
+		return value, newnode, true, err
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// Update associates key with value in the trie. Subsequent calls to
+// Get will return value. If value has length zero, any existing value
+// is deleted from the trie and calls to Get will return nil.
+//
+// The value bytes must not be modified by the caller while they are
+// stored in the trie.
+func (t *BinaryTrie) Update(key, value []byte) {
+	if err := t.TryUpdate(key, value); err != nil {
+		log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+	}
+}
+
+// TryUpdate associates key with value in the trie. Subsequent calls to
+// Get will return value. If value has length zero, any existing value
+// is deleted from the trie and calls to Get will return nil.
+//
+// The value bytes must not be modified by the caller while they are
+// stored in the trie.
+//
+// If a node was not found in the database, a MissingNodeError is returned.
+func (t *BinaryTrie) TryUpdate(key, value []byte) error {
+	hk := t.hashKey(key)
+	if err := t.TryUpdateHashed(hk, value); err != nil {
+		return err
+	}
+	if len(value) != 0 {
+		t.getSecKeyCache()[string(hk)] = common.CopyBytes(key)
+	}
+	return nil
+}
+
+// TryUpdateHashed associates the already hashed key with value in the trie. It
+// is meant for converting existing state, which only retains hashed keys, into
+// a binary trie.
+func (t *BinaryTrie) TryUpdateHashed(hashedKey, value []byte) error {
+	if len(hashedKey) != common.HashLength {
+		return fmt.Errorf("invalid hashed key length %d", len(hashedKey))
+	}
+	if len(value) == 0 {
+		return t.tryDelete(hashedKey)
+	}
+	n, err := t.insert(t.root, common.CopyBytes(hashedKey), value, 0)
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+func (t *BinaryTrie) insert(n binaryNode, key, value []byte, depth int) (binaryNode, error) {
+	switch n := n.(type) {
+	case nil:
+		return &binaryLeaf{key: key, value: value, dirty: true}, nil
+
+	case *binaryLeaf:
+		if bytes.Equal(n.key, key) {
+			if bytes.Equal(n.value, value) {
+				return n, nil
+			}
+			return &binaryLeaf{key: n.key, value: value, dirty: true}, nil
+		}
+		// Push both leaves down until their keys diverge, the existing leaf
+		// retains its hash as the leaf encoding doesn't depend on its depth
+		return splitBinaryLeaves(n, &binaryLeaf{key: key, value: value, dirty: true}, depth)
+
+	case *binaryBranch:
+		if err := checkBinaryDepth(key, depth); err != nil {
+			return n, err
+		}
+		bit := binaryKeyBit(key, depth)
+		child, err := t.insert(n.children[bit], key, value, depth+1)
+		if err != nil {
+			return n, err
+		}
+		n = n.copy()
+		n.children[bit], n.hash, n.dirty = child, nil, true
+		return n, nil
+
+	case binaryHash:
+		child, err := t.resolve(n, key, depth)
+		if err != nil {
+			return n, err
+		}
+		return t.insert(child, key, value, depth)
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// splitBinaryLeaves creates the branches needed to hold two leaves with distinct
+// keys sharing the key prefix up to the given depth. An error is returned if the
+// keys don't diverge below the depth, i.e. if the existing leaf was misplaced.
+func splitBinaryLeaves(a, b *binaryLeaf, depth int) (binaryNode, error) {
+	if err := checkBinaryDepth(a.key, depth); err != nil {
+		return nil, err
+	}
+	if err := checkBinaryDepth(b.key, depth); err != nil {
+		return nil, err
+	}
+	branch := &binaryBranch{dirty: true}
+	abit, bbit := binaryKeyBit(a.key, depth), binaryKeyBit(b.key, depth)
+	if abit != bbit {
+		branch.children[abit], branch.children[bbit] = a, b
+		return branch, nil
+	}
+	child, err := splitBinaryLeaves(a, b, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	branch.children[abit] = child
+	return branch, nil
+}
+
+// Delete removes any existing value for key from the trie.
+func (t *BinaryTrie) Delete(key []byte) {
+	if err := t.TryDelete(key); err != nil {
+		log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+	}
+}
+
+// TryDelete removes any existing value for key from the trie.
+// If a node was not found in the database, a MissingNodeError is returned.
+func (t *BinaryTrie) TryDelete(key []byte) error {
+	hk := t.hashKey(key)
+	delete(t.getSecKeyCache(), string(hk))
+	return t.tryDelete(hk)
+}
+
+func (t *BinaryTrie) tryDelete(key []byte) error {
+	_, n, err := t.delete(t.root, key, 0)
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+// delete removes the key from the subtrie, collapsing any branch left with a
+// single leaf below it to keep the trie shape canonical.
+func (t *BinaryTrie) delete(n binaryNode, key []byte, depth int) (bool, binaryNode, error) {
+	switch n := n.(type) {
+	case nil:
+		return false, nil, nil
+
+	case *binaryLeaf:
+		if bytes.Equal(n.key, key) {
+			return true, nil, nil
+		}
+		return false, n, nil
+
+	case *binaryBranch:
+		if err := checkBinaryDepth(key, depth); err != nil {
+			return false, n, err
+		}
+		bit := binaryKeyBit(key, depth)
+		changed, child, err := t.delete(n.children[bit], key, depth+1)
+		if !changed || err != nil {
+			return false, n, err
+		}
+		sibling := n.children[1-bit]
+		switch {
+		case child == nil && sibling == nil:
+			return true, nil, nil
+
+		case child == nil:
+			// The sibling subtrie remains, if it's a single leaf it needs to
+			// be pulled up into the place of the branch
+			if hash, ok := sibling.(binaryHash); ok {
+				if sibling, err = t.resolve(hash, key, depth+1); err != nil {
+					return false, n, err
+				}
+			}
+			if leaf, ok := sibling.(*binaryLeaf); ok {
+				return true, leaf, nil
+			}
+
+		case sibling == nil:
+			// The branch only has the modified child, pull it up if it's a leaf
+			if leaf, ok := child.(*binaryLeaf); ok {
+				return true, leaf, nil
+			}
+		}
+		n = n.copy()
+		n.children[bit], n.children[1-bit] = child, sibling
+		n.hash, n.dirty = nil, true
+		return true, n, nil
+
+	case binaryHash:
+		child, err := t.resolve(n, key, depth)
+		if err != nil {
+			return false, n, err
+		}
+		return t.delete(child, key, depth)
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// GetKey returns the sha3 preimage of a hashed key that was
+// previously used to store a value.
+func (t *BinaryTrie) GetKey(shaKey []byte) []byte {
+	if key, ok := t.getSecKeyCache()[string(shaKey)]; ok {
+		return key
+	}
+	return t.db.preimages.Preimage(common.BytesToHash(shaKey))
+}
+
+// Hash returns the root hash of the trie. It does not write to the
+// database and can be used even if the trie doesn't have one.
+func (t *BinaryTrie) Hash() common.Hash {
+	if t.root == nil {
+		return emptyRoot
+	}
+	h := newHasher(false)
+	defer returnHasherToPool(h)
+
+	hash, root := t.hash(t.root, h)
+	t.root = root
+	return common.BytesToHash(hash)
+}
+
+// hash returns the hash of the node, along with a copy of the node with all the
+// hashes cached.
+func (t *BinaryTrie) hash(n binaryNode, h *hasher) ([]byte, binaryNode) {
+	switch n := n.(type) {
+	case *binaryBranch:
+		if n.hash != nil {
+			return n.hash, n
+		}
+		cpy := n.copy()
+		for i, child := range n.children {
+			if child != nil {
+				_, cpy.children[i] = t.hash(child, h)
+			}
+		}
+		cpy.hash = h.hashData(cpy.encode())
+		return cpy.hash, cpy
+
+	case *binaryLeaf:
+		if n.hash != nil {
+			return n.hash, n
+		}
+		cpy := n.copy()
+		cpy.hash = h.hashData(cpy.encode())
+		return cpy.hash, cpy
+
+	case binaryHash:
+		return n, n
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// Commit writes all dirty nodes and the secure hash pre-images to the trie's
+// disk database. Nodes are stored with their sha3 hash as the key.
+//
+// Committing flushes nodes from memory. Subsequent Get calls will load nodes
+// from the database.
+func (t *BinaryTrie) Commit(onleaf LeafCallback) (root common.Hash, err error) {
+	// Write all the pre-images to the preimage store
+	if len(t.getSecKeyCache()) > 0 {
+		t.db.preimages.insertSecKeys(t.secKeyCache)
+		t.secKeyCache = make(map[string][]byte)
+	}
+	root = t.Hash()
+	if t.root == nil {
+		return root, nil
+	}
+	batch := t.db.diskdb.NewBatch()
+	n, err := t.commit(t.root, batch, onleaf, common.Hash{})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := batch.Write(); err != nil {
+		return common.Hash{}, err
+	}
+	t.root = n
+	return root, nil
+}
+
+// commit writes the dirty nodes of the subtrie into the batch, returning the
+// subtrie collapsed into a hash reference.
+func (t *BinaryTrie) commit(n binaryNode, batch fafdb.Batch, onleaf LeafCallback, parent common.Hash) (binaryNode, error) {
+	var (
+		hash []byte
+		enc  []byte
+	)
+	switch n := n.(type) {
+	case *binaryBranch:
+		if !n.dirty {
+			return binaryHash(n.hash), nil
+		}
+		for _, child := range n.children {
+			if child != nil {
+				if _, err := t.commit(child, batch, onleaf, common.BytesToHash(n.hash)); err != nil {
+					return n, err
+				}
+			}
+		}
+		hash, enc = n.hash, n.encode()
+
+	case *binaryLeaf:
+		if !n.dirty {
+			return binaryHash(n.hash), nil
+		}
+		if onleaf != nil {
+			if err := onleaf(nil, n.value, parent); err != nil {
+				return n, err
+			}
+		}
+		hash, enc = n.hash, n.encode()
+
+	case binaryHash:
+		return n, nil
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+	rawdb.WriteTrieNode(batch, common.BytesToHash(hash), enc)
+	if t.db.cleans != nil {
+		t.db.cleans.Set(hash, enc)
+	}
+	if batch.ValueSize() >= fafdb.IdealBatchSize {
+		if err := batch.Write(); err != nil {
+			return n, err
+		}
+		batch.Reset()
+	}
+	return binaryHash(hash), nil
+}
+
+// Copy returns a copy of the binary trie.
+func (t *BinaryTrie) Copy() *BinaryTrie {
+	cpy := *t
+	return &cpy
+}
+
+// NodeIterator returns an iterator that returns nodes of the trie. Iteration
+// starts at the hashed key after the given start key.
+func (t *BinaryTrie) NodeIterator(start []byte) NodeIterator {
+	t.Hash()
+	return &binaryIterator{trie: t, start: start}
+}
+
+// Prove constructs a merkle proof for the hashed key. The result contains all
+// encoded nodes on the path to the value at key. The value itself is also
+// included in the last node and can be retrieved by verifying the proof.
+//
+// As with SecureTrie.Prove, the key must already be hashed. If the trie does not
+// contain a value for key, the returned proof contains all nodes of the longest
+// existing prefix of the key (at least the root node), ending with the node that
+// proves the absence of the key.
+func (t *BinaryTrie) Prove(hashedKey []byte, fromLevel uint, proofDb fafdb.KeyValueWriter) error {
+	if len(hashedKey) != common.HashLength {
+		return fmt.Errorf("invalid hashed key length %d", len(hashedKey))
+	}
+	t.Hash()
+
+	hk := hashedKey
+	for n, depth := t.root, 0; n != nil; depth++ {
+		if hash, ok := n.(binaryHash); ok {
+			var err error
+			if n, err = t.resolve(hash, hk, depth); err != nil {
+				log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+				return err
+			}
+		}
+		var next binaryNode
+		switch rn := n.(type) {
+		case *binaryBranch:
+			if fromLevel == 0 {
+				proofDb.Put(rn.hash, rn.encode())
+			}
+			if err := checkBinaryDepth(hk, depth); err != nil {
+				log.Error(fmt.Sprintf("Unhandled trie error: %v", err))
+				return err
+			}
+			next = rn.children[binaryKeyBit(hk, depth)]
+		case *binaryLeaf:
+			if fromLevel == 0 {
+				proofDb.Put(rn.hash, rn.encode())
+			}
+		}
+		if fromLevel > 0 {
+			fromLevel--
+		}
+		n = next
+	}
+	return nil
+}
+
+// VerifyBinaryProof checks merkle proofs of a binary trie. The given proof must
+// contain the value for the hashed key in a trie with the given root hash, or
+// prove that the key is absent. VerifyBinaryProof returns an error if the proof
+// contains invalid trie nodes or the wrong value.
+func VerifyBinaryProof(rootHash common.Hash, hashedKey []byte, proofDb fafdb.KeyValueReader) (value []byte, err error) {
+	if len(hashedKey) != common.HashLength {
+		return nil, fmt.Errorf("invalid hashed key length %d", len(hashedKey))
+	}
+	if rootHash == emptyRoot {
+		return nil, nil
+	}
+	h := newHasher(false)
+	defer returnHasherToPool(h)
+
+	hk := hashedKey
+	wantHash := rootHash[:]
+	for depth := 0; ; depth++ {
+		buf, _ := proofDb.Get(wantHash)
+		if buf == nil {
+			return nil, fmt.Errorf("proof node %d (hash %064x) missing", depth, wantHash)
+		}
+		if !bytes.Equal(h.hashData(buf), wantHash) {
+			return nil, fmt.Errorf("proof node %d (hash %064x) mismatch", depth, wantHash)
+		}
+		n, err := decodeBinaryNode(wantHash, buf)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %d: %v", depth, err)
+		}
+		switch n := n.(type) {
+		case *binaryLeaf:
+			if bytes.Equal(n.key, hk) {
+				return n.value, nil
+			}
+			return nil, nil // The trie doesn't contain the key
+		case *binaryBranch:
+			if err := checkBinaryDepth(hk, depth); err != nil {
+				return nil, fmt.Errorf("bad proof node %d: %v", depth, err)
+			}
+			child := n.children[binaryKeyBit(hk, depth)]
+			if child == nil {
+				return nil, nil // The trie doesn't contain the key
+			}
+			wantHash = child.(binaryHash)
+		}
+	}
+}
+
+// hashKey returns the hash of key as an ephemeral buffer.
+// The caller must not hold onto the return value because it will become
+// invalid on the next call to hashKey.
+func (t *BinaryTrie) hashKey(key []byte) []byte {
+	h := newHasher(false)
+	h.sha.Reset()
+	h.sha.Write(key)
+	h.sha.Read(t.hashKeyBuf[:])
+	returnHasherToPool(h)
+	return t.hashKeyBuf[:]
+}
+
+// getSecKeyCache returns the current secure key cache, creating a new one if
+// ownership changed (i.e. the current binary trie is a copy of another owning
+// the actual cache).
+func (t *BinaryTrie) getSecKeyCache() map[string][]byte {
+	if t != t.secKeyCacheOwner {
+		t.secKeyCacheOwner = t
+		t.secKeyCache = make(map[string][]byte)
+	}
+	return t.secKeyCache
+}
+
+// binaryIteratorState is a node on the path to the current position of a
+// binary trie iterator.
+type binaryIteratorState struct {
+	node   binaryNode // Resolved node at this position
+	parent []byte     // Hash of the parent node
+	path   []byte     // Bit path of the node, one bit per byte
+	index  int        // Next child of a branch to visit
+}
+
+// binaryIterator is a pre-order iterator over the nodes of a binary trie.
+type binaryIterator struct {
+	trie    *BinaryTrie
+	start   []byte
+	stack   []*binaryIteratorState
+	started bool
+	err     error
+}
+
+// Next moves the iterator to the next node. If the parameter is false, any
+// child nodes will be skipped.
+func (it *binaryIterator) Next(descend bool) bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if it.trie.root == nil {
+			return false
+		}
+		return it.push(it.trie.root, nil, nil)
+	}
+	if !descend && len(it.stack) > 0 {
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	for len(it.stack) > 0 {
+		top := it.stack[len(it.stack)-1]
+		if branch, ok := top.node.(*binaryBranch); ok {
+			for top.index < len(branch.children) {
+				index := top.index
+				top.index++
+
+				if child := branch.children[index]; child != nil {
+					path := append(append([]byte{}, top.path...), byte(index))
+					if it.push(child, branch.hash, path) {
+						return true
+					}
+					if it.err != nil {
+						return false
+					}
+				}
+			}
+		}
+		it.stack = it.stack[:len(it.stack)-1]
+	}
+	return false
+}
+
+// push resolves the node and moves the iterator onto it, unless all the keys
+// below it are before the iterator's start key.
+func (it *binaryIterator) push(n binaryNode, parent []byte, path []byte) bool {
+	if it.start != nil && bytes.Compare(path, binaryKeyPath(common.RightPadBytes(it.start, common.HashLength), len(path))) < 0 {
+		return false
+	}
+	if hash, ok := n.(binaryHash); ok {
+		var err error
+		if n, err = it.trie.resolve(hash, nil, 0); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	if leaf, ok := n.(*binaryLeaf); ok && it.start != nil && bytes.Compare(leaf.key, it.start) < 0 {
+		return false
+	}
+	it.stack = append(it.stack, &binaryIteratorState{node: n, parent: parent, path: path})
+	return true
+}
+
+func (it *binaryIterator) current() *binaryIteratorState {
+	if len(it.stack) == 0 {
+		return nil
+	}
+	return it.stack[len(it.stack)-1]
+}
+
+// Error returns the error status of the iterator.
+func (it *binaryIterator) Error() error {
+	return it.err
+}
+
+// Hash returns the hash of the current node.
+func (it *binaryIterator) Hash() common.Hash {
+	if st := it.current(); st != nil {
+		return common.BytesToHash(binaryNodeHash(st.node))
+	}
+	return common.Hash{}
+}
+
+// Parent returns the hash of the parent of the current node.
+func (it *binaryIterator) Parent() common.Hash {
+	if st := it.current(); st != nil {
+		return common.BytesToHash(st.parent)
+	}
+	return common.Hash{}
+}
+
+// Path returns the bit path to the current node, one bit per byte.
+func (it *binaryIterator) Path() []byte {
+	if st := it.current(); st != nil {
+		return st.path
+	}
+	return nil
+}
+
+// Leaf returns true iff the current node is a leaf node.
+func (it *binaryIterator) Leaf() bool {
+	if st := it.current(); st != nil {
+		_, ok := st.node.(*binaryLeaf)
+		return ok
+	}
+	return false
+}
+
+// LeafKey returns the hashed key of the leaf. The method panics if the iterator
+// is not positioned at a leaf.
+func (it *binaryIterator) LeafKey() []byte {
+	if st := it.current(); st != nil {
+		if leaf, ok := st.node.(*binaryLeaf); ok {
+			return leaf.key
+		}
+	}
+	panic("not at leaf")
+}
+
+// LeafBlob returns the content of the leaf. The method panics if the iterator
+// is not positioned at a leaf.
+func (it *binaryIterator) LeafBlob() []byte {
+	if st := it.current(); st != nil {
+		if leaf, ok := st.node.(*binaryLeaf); ok {
+			return leaf.value
+		}
+	}
+	panic("not at leaf")
+}
+
+// LeafProof returns the encoded nodes on the path to the current leaf. The
+// method panics if the iterator is not positioned at a leaf.
+func (it *binaryIterator) LeafProof() [][]byte {
+	if !it.Leaf() {
+		panic("not at leaf")
+	}
+	proofs := make([][]byte, 0, len(it.stack))
+	for _, st := range it.stack {
+		switch n := st.node.(type) {
+		case *binaryBranch:
+			proofs = append(proofs, n.encode())
+		case *binaryLeaf:
+			proofs = append(proofs, n.encode())
+		}
+	}
+	return proofs
+}
@@ -0,0 +1,290 @@
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	mrand "math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+)
+
+func newEmptyBinary() *BinaryTrie {
+	trie, _ := NewBinary(common.Hash{}, NewDatabase(memorydb.New()))
+	return trie
+}
+
+// makeTestBinaryTrie creates a binary trie with a set of random and structured
+// entries, returning the entries too.
+func makeTestBinaryTrie(n int) (*BinaryTrie, map[string][]byte) {
+	trie := newEmptyBinary()
+	content := make(map[string][]byte)
+	for i := 0; i < n; i++ {
+		key, val := []byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d", i))
+		content[string(key)] = val
+		trie.Update(key, val)
+	}
+	return trie, content
+}
+
+// Tests that the binary trie root only depends on the content, not on the order
+// of insertions or any deleted items.
+func TestBinaryCanonicalRoot(t *testing.T) {
+	trie, content := makeTestBinaryTrie(500)
+	root := trie.Hash()
+	if root == emptyRoot {
+		t.Fatalf("non-empty trie with empty root")
+	}
+	var keys []string
+	for key := range content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	mrand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+
+	// Insert the same content shuffled and interleaved with junk to delete
+	other := newEmptyBinary()
+	for i, key := range keys {
+		other.Update([]byte(key), content[key])
+		if i%3 == 0 {
+			other.Update([]byte(fmt.Sprintf("junk-%d", i)), []byte{0x01})
+		}
+		if i%10 == 0 {
+			other.Hash() // Interleave hashing with modifications
+		}
+	}
+	for i := range keys {
+		if i%3 == 0 {
+			other.Delete([]byte(fmt.Sprintf("junk-%d", i)))
+		}
+	}
+	if have := other.Hash(); have != root {
+		t.Fatalf("root mismatch: have %x, want %x", have, root)
+	}
+	// Deleting everything should yield the empty trie
+	for _, key := range keys {
+		other.Delete([]byte(key))
+	}
+	if have := other.Hash(); have != emptyRoot {
+		t.Fatalf("emptied trie root mismatch: have %x, want %x", have, emptyRoot)
+	}
+}
+
+// Tests that a committed binary trie can be reopened from the database and that
+// it can be further modified.
+func TestBinaryCommitReopen(t *testing.T) {
+	trie, content := makeTestBinaryTrie(500)
+
+	root, err := trie.Commit(nil)
+	if err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	reopened, err := NewBinary(root, trie.db)
+	if err != nil {
+		t.Fatalf("failed to reopen trie: %v", err)
+	}
+	for key, want := range content {
+		if have := reopened.Get([]byte(key)); !bytes.Equal(have, want) {
+			t.Fatalf("value mismatch for %q: have %x, want %x", key, have, want)
+		}
+		if have := trie.Get([]byte(key)); !bytes.Equal(have, want) {
+			t.Fatalf("committed value mismatch for %q: have %x, want %x", key, have, want)
+		}
+	}
+	// Modify both tries the same way and make sure they end up identical
+	for i := 0; i < 100; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		if i%2 == 0 {
+			trie.Delete(key)
+			reopened.Delete(key)
+		} else {
+			trie.Update(key, []byte{byte(i)})
+			reopened.Update(key, []byte{byte(i)})
+		}
+	}
+	if trie.Hash() != reopened.Hash() {
+		t.Fatalf("root mismatch after modification: %x != %x", trie.Hash(), reopened.Hash())
+	}
+	if _, err := NewBinary(common.Hash{0x01}, trie.db); err == nil {
+		t.Fatalf("opened trie with missing root")
+	}
+}
+
+// Tests that the proofs of present and absent keys can be verified.
+func TestBinaryProof(t *testing.T) {
+	trie, content := makeTestBinaryTrie(500)
+	root := trie.Hash()
+
+	for key, want := range content {
+		proof := memorydb.New()
+		if err := trie.Prove(crypto.Keccak256([]byte(key)), 0, proof); err != nil {
+			t.Fatalf("failed to prove %q: %v", key, err)
+		}
+		have, err := VerifyBinaryProof(root, crypto.Keccak256([]byte(key)), proof)
+		if err != nil {
+			t.Fatalf("failed to verify proof for %q: %v", key, err)
+		}
+		if !bytes.Equal(have, want) {
+			t.Fatalf("proven value mismatch for %q: have %x, want %x", key, have, want)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		key := crypto.Keccak256([]byte(fmt.Sprintf("missing-%d", i)))
+		proof := memorydb.New()
+		if err := trie.Prove(key, 0, proof); err != nil {
+			t.Fatalf("failed to prove %q: %v", key, err)
+		}
+		if have, err := VerifyBinaryProof(root, key, proof); err != nil || have != nil {
+			t.Fatalf("failed to prove absence of %q: value %x, err %v", key, have, err)
+		}
+	}
+	// Corrupt a proof and ensure it's rejected
+	proof := memorydb.New()
+	trie.Prove(crypto.Keccak256([]byte("key-1")), 0, proof)
+
+	it := proof.NewIterator(nil, nil)
+	it.Next()
+	blob := common.CopyBytes(it.Value())
+	blob[len(blob)-1]++
+	proof.Put(it.Key(), blob)
+	it.Release()
+
+	if _, err := VerifyBinaryProof(root, crypto.Keccak256([]byte("key-1")), proof); err == nil {
+		t.Fatalf("corrupted proof accepted")
+	}
+}
+
+// makeDeepBinaryNodes forges a chain of branches deeper than the bit length of a
+// hashed key, each referencing the next one in both children, ending in a leaf.
+// The node blobs are returned keyed by hash, along with the root hash.
+func makeDeepBinaryNodes(depth int) (map[common.Hash][]byte, common.Hash) {
+	nodes := make(map[common.Hash][]byte)
+
+	blob := append([]byte{binaryLeafPrefix}, make([]byte, common.HashLength)...)
+	blob = append(blob, 0x01)
+	hash := crypto.Keccak256Hash(blob)
+	nodes[hash] = blob
+	for i := 0; i < depth; i++ {
+		blob = append([]byte{binaryBranchPrefix}, hash[:]...)
+		blob = append(blob, hash[:]...)
+		hash = crypto.Keccak256Hash(blob)
+		nodes[hash] = blob
+	}
+	return nodes, hash
+}
+
+// Tests that forged proofs and nodes nesting branches deeper than the key length
+// are rejected with an error instead of indexing past the end of the key.
+func TestBinaryMaliciousDepth(t *testing.T) {
+	nodes, root := makeDeepBinaryNodes(8*common.HashLength + 1)
+	key := crypto.Keccak256([]byte("key"))
+
+	proof := memorydb.New()
+	for hash, blob := range nodes {
+		proof.Put(hash[:], blob)
+	}
+	if _, err := VerifyBinaryProof(root, key, proof); err == nil || !strings.Contains(err.Error(), errBinaryDepth.Error()) {
+		t.Fatalf("deep proof error mismatch: have %v, want %v", err, errBinaryDepth)
+	}
+	// Ensure the trie operations reject the forged nodes as well
+	diskdb := memorydb.New()
+	for hash, blob := range nodes {
+		diskdb.Put(hash[:], blob)
+	}
+	db := NewDatabase(diskdb)
+	for name, op := range map[string]func(trie *BinaryTrie) error{
+		"get":    func(trie *BinaryTrie) error { _, err := trie.TryGet([]byte("key")); return err },
+		"update": func(trie *BinaryTrie) error { return trie.TryUpdate([]byte("key"), []byte("value")) },
+		"delete": func(trie *BinaryTrie) error { return trie.TryDelete([]byte("key")) },
+		"prove":  func(trie *BinaryTrie) error { return trie.Prove(key, 0, memorydb.New()) },
+	} {
+		trie, err := NewBinary(root, db)
+		if err != nil {
+			t.Fatalf("failed to open forged trie: %v", err)
+		}
+		if err := op(trie); err == nil || !strings.Contains(err.Error(), errBinaryDepth.Error()) {
+			t.Errorf("%s: error mismatch: have %v, want %v", name, err, errBinaryDepth)
+		}
+	}
+	// Forge a leaf misplaced under the wrong branch child: inserting a key that
+	// only differs from it in the first bit can never split the two
+	leafKey := common.Hash{0x80, 0x01}
+	leaf := append([]byte{binaryLeafPrefix}, leafKey[:]...)
+	leaf = append(leaf, 0x01)
+	leafHash := crypto.Keccak256Hash(leaf)
+	branch := append([]byte{binaryBranchPrefix}, leafHash[:]...)
+	branch = append(branch, make([]byte, common.HashLength)...)
+	branchHash := crypto.Keccak256Hash(branch)
+	diskdb.Put(leafHash[:], leaf)
+	diskdb.Put(branchHash[:], branch)
+
+	trie, err := NewBinary(branchHash, db)
+	if err != nil {
+		t.Fatalf("failed to open forged trie: %v", err)
+	}
+	if err := trie.TryUpdateHashed(common.Hash{0x00, 0x01}.Bytes(), []byte("value")); err == nil || !strings.Contains(err.Error(), errBinaryDepth.Error()) {
+		t.Errorf("misplaced leaf split error mismatch: have %v, want %v", err, errBinaryDepth)
+	}
+}
+
+// Tests that the binary trie iterator visits all the leaves in hashed key order,
+// starting from the requested key.
+func TestBinaryIterator(t *testing.T) {
+	trie, content := makeTestBinaryTrie(500)
+	root, _ := trie.Commit(nil)
+	trie, _ = NewBinary(root, trie.db)
+
+	var hashes []string
+	for key := range content {
+		hashes = append(hashes, string(crypto.Keccak256([]byte(key))))
+	}
+	sort.Strings(hashes)
+
+	for _, start := range []int{0, 1, 250, len(hashes) - 1} {
+		var (
+			it   = NewIterator(trie.NodeIterator([]byte(hashes[start])))
+			have []string
+		)
+		for it.Next() {
+			have = append(have, string(it.Key))
+			if want := content[string(trie.GetKey(it.Key))]; !bytes.Equal(it.Value, want) {
+				t.Fatalf("start %d: value mismatch for %x: have %x, want %x", start, it.Key, it.Value, want)
+			}
+		}
+		if it.Err != nil {
+			t.Fatalf("start %d: iteration failed: %v", start, it.Err)
+		}
+		if len(have) != len(hashes)-start {
+			t.Fatalf("start %d: leaf count mismatch: have %d, want %d", start, len(have), len(hashes)-start)
+		}
+		for i := range have {
+			if have[i] != hashes[start+i] {
+				t.Fatalf("start %d: leaf %d mismatch: have %x, want %x", start, i, have[i], hashes[start+i])
+			}
+		}
+	}
+}
+
+// Tests that modifications to a copied binary trie don't affect the original.
+func TestBinaryCopy(t *testing.T) {
+	trie, _ := makeTestBinaryTrie(100)
+	root := trie.Hash()
+
+	cpy := trie.Copy()
+	cpy.Update([]byte("key-1"), []byte("changed"))
+	cpy.Delete([]byte("key-2"))
+
+	if have := trie.Hash(); have != root {
+		t.Fatalf("original root changed: have %x, want %x", have, root)
+	}
+	if cpy.Hash() == root {
+		t.Fatalf("copy root unchanged")
+	}
+	if have := trie.Get([]byte("key-1")); !bytes.Equal(have, []byte("value-1")) {
+		t.Fatalf("original value changed: %q", have)
+	}
+}
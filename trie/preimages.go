@@ -0,0 +1,131 @@
+
+
+package trie
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb"
+)
+
+// preimageFlushThreshold is the size of the preimages accumulated in memory
+// above which they get flushed to disk when the trie database is capped.
+const preimageFlushThreshold = 4 * 1024 * 1024
+
+// PreimageStore is the store of the sha3 preimages of the hashed keys in the
+// secure tries, i.e. the addresses of the accounts and the storage slot keys.
+// New preimages are accumulated in memory and written out to the persistent
+// database together with the trie nodes, lookups fall back to the database
+// for anything not in memory.
+type PreimageStore struct {
+	disk  fafdb.KeyValueStore    // Persistent storage of the preimages
+	cache map[common.Hash][]byte // Preimages not yet written to disk
+	size  common.StorageSize     // Storage size of the preimages cache
+
+	lock sync.RWMutex
+}
+
+// newPreimageStore creates a preimage store on top of the given database.
+func newPreimageStore(disk fafdb.KeyValueStore) *PreimageStore {
+	return &PreimageStore{
+		disk:  disk,
+		cache: make(map[common.Hash][]byte),
+	}
+}
+
+// insertSecKeys collects the preimages cached by a secure trie being committed.
+func (store *PreimageStore) insertSecKeys(keys map[string][]byte) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for hk, key := range keys {
+		store.insert(common.BytesToHash([]byte(hk)), key)
+	}
+}
+
+// insert adds a preimage to the memory cache if it's yet unknown. The method
+// will NOT make a copy of the slice and assumes the store's lock is held.
+func (store *PreimageStore) insert(hash common.Hash, preimage []byte) {
+	if _, ok := store.cache[hash]; ok {
+		return
+	}
+	store.cache[hash] = preimage
+	store.size += common.StorageSize(common.HashLength + len(preimage))
+}
+
+// Insert adds the given keys to the store. The keys are copied.
+func (store *PreimageStore) Insert(keys ...[]byte) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	for _, key := range keys {
+		store.insert(crypto.Keccak256Hash(key), common.CopyBytes(key))
+	}
+}
+
+// Preimage retrieves the preimage of a hashed key, or nil if it's unknown.
+func (store *PreimageStore) Preimage(hash common.Hash) []byte {
+	store.lock.RLock()
+	preimage := store.cache[hash]
+	store.lock.RUnlock()
+
+	if preimage != nil {
+		return preimage
+	}
+	return rawdb.ReadPreimage(store.disk, hash)
+}
+
+// Has reports whether the preimage of a hashed key is known.
+func (store *PreimageStore) Has(hash common.Hash) bool {
+	store.lock.RLock()
+	_, ok := store.cache[hash]
+	store.lock.RUnlock()
+
+	if ok {
+		return true
+	}
+	return rawdb.HasPreimage(store.disk, hash)
+}
+
+// Size returns the storage size of the preimages cached in memory.
+func (store *PreimageStore) Size() common.StorageSize {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	return store.size
+}
+
+// Commit writes all the preimages cached in memory to disk.
+func (store *PreimageStore) Commit() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.flush(true)
+}
+
+// cap writes the preimages cached in memory to disk if they got large enough.
+func (store *PreimageStore) cap() error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	return store.flush(false)
+}
+
+// flush writes the preimages cached in memory to disk, if forced or if they got
+// large enough. Small sets are left for later to deduplicate writes. The method
+// assumes the store's lock is held.
+func (store *PreimageStore) flush(force bool) error {
+	if len(store.cache) == 0 || (!force && store.size <= preimageFlushThreshold) {
+		return nil
+	}
+	batch := store.disk.NewBatch()
+	rawdb.WritePreimages(batch, store.cache)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	store.cache, store.size = make(map[common.Hash][]byte), 0
+	return nil
+}
@@ -0,0 +1,51 @@
+package trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/fafdb/memorydb"
+)
+
+// Tests that the preimages of committed secure tries are always collected, and
+// that they survive a restart.
+func TestPreimageStore(t *testing.T) {
+	diskdb := memorydb.New()
+
+	// Commit a trie without any preimage specific configuration
+	triedb := NewDatabase(diskdb)
+	tr, _ := NewSecure(common.Hash{}, triedb)
+	tr.Update([]byte("foo"), []byte("bar"))
+	root, _ := tr.Commit(nil)
+
+	tr, _ = NewSecure(root, triedb)
+	if key := tr.GetKey(crypto.Keccak256([]byte("foo"))); !bytes.Equal(key, []byte("foo")) {
+		t.Fatalf("committed preimage mismatch: have %x, want %x", key, []byte("foo"))
+	}
+	// Explicitly inserted preimages are cached until committed
+	triedb.Preimages().Insert([]byte("baz"))
+	if key := tr.GetKey(crypto.Keccak256([]byte("baz"))); !bytes.Equal(key, []byte("baz")) {
+		t.Fatalf("inserted preimage mismatch: have %x, want %x", key, []byte("baz"))
+	}
+	if size := triedb.Preimages().Size(); size == 0 {
+		t.Fatalf("preimages not cached")
+	}
+	if err := triedb.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	if size := triedb.Preimages().Size(); size != 0 {
+		t.Fatalf("preimage cache not flushed: %v", size)
+	}
+	// Stored preimages are retrievable by a fresh database
+	triedb = NewDatabase(diskdb)
+	for _, key := range [][]byte{[]byte("foo"), []byte("baz")} {
+		if !triedb.Preimages().Has(crypto.Keccak256Hash(key)) {
+			t.Fatalf("stored preimage of %q missing", key)
+		}
+		if preimage := triedb.Preimages().Preimage(crypto.Keccak256Hash(key)); !bytes.Equal(preimage, key) {
+			t.Fatalf("stored preimage mismatch: have %x, want %x", preimage, key)
+		}
+	}
+}
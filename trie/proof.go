@@ -86,6 +86,42 @@ func (t *SecureTrie) Prove(key []byte, fromLevel uint, proofDb fafdb.KeyValueWri
 	return t.trie.Prove(key, fromLevel, proofDb)
 }
 
+// ProveRange collects at most max consecutive leaves starting at origin (which
+// may or may not exist in the trie) and constructs the edge proofs needed to
+// verify them as a contiguous range. The proof contains the path to origin and,
+// if different, the path to the last returned key, so the batch can be checked
+// with VerifyRangeProof(root, origin, keys[len(keys)-1], keys, values, proof).
+//
+// If no leaves are returned, the proof is a non-existence proof of origin, which
+// only verifies if there are no more leaves after origin in the trie. Note, the
+// origin must be of the same length as the keys in the trie.
+func (t *Trie) ProveRange(origin []byte, max int, proofDb fafdb.KeyValueWriter) (keys [][]byte, values [][]byte, err error) {
+	it := NewIterator(t.NodeIterator(origin))
+	for len(keys) < max && it.Next() {
+		keys = append(keys, common.CopyBytes(it.Key))
+		values = append(values, common.CopyBytes(it.Value))
+	}
+	if it.Err != nil {
+		return nil, nil, it.Err
+	}
+	if err := t.Prove(origin, 0, proofDb); err != nil {
+		return nil, nil, err
+	}
+	if len(keys) > 0 && !bytes.Equal(origin, keys[len(keys)-1]) {
+		if err := t.Prove(keys[len(keys)-1], 0, proofDb); err != nil {
+			return nil, nil, err
+		}
+	}
+	return keys, values, nil
+}
+
+// ProveRange collects at most max consecutive leaves starting at the hashed key
+// origin and constructs the edge proofs needed to verify them as a contiguous
+// range. The returned keys are the hashed keys of the leaves.
+func (t *SecureTrie) ProveRange(origin []byte, max int, proofDb fafdb.KeyValueWriter) (keys [][]byte, values [][]byte, err error) {
+	return t.trie.ProveRange(origin, max, proofDb)
+}
+
 // VerifyProof checks merkle proofs. The given proof must contain the value for
 // key in a trie with the given root hash. VerifyProof returns an error if the
 // proof contains invalid trie nodes or the wrong value.
@@ -501,6 +537,11 @@ func VerifyRangeProof(rootHash common.Hash, firstKey []byte, lastKey []byte, key
 	if len(firstKey) != len(lastKey) {
 		return errors.New("inconsistent edge keys"), false
 	}
+	// Ensure the leaves are within the proven boundaries, anything outside
+	// would be inserted into unresolved parts of the trie.
+	if bytes.Compare(keys[0], firstKey) < 0 || bytes.Compare(keys[len(keys)-1], lastKey) > 0 {
+		return errors.New("range out of edge keys"), false
+	}
 	// Convert the edge proofs to edge trie paths. Then we can
 	// have the same tree architecture with the original one.
 	// For the first edge proof, non-existent proof is allowed.
@@ -524,7 +565,9 @@ func VerifyRangeProof(rootHash common.Hash, firstKey []byte, lastKey []byte, key
 	// should be same with the original one.
 	newtrie := &Trie{root: root, db: NewDatabase(memorydb.New())}
 	for index, key := range keys {
-		newtrie.TryUpdate(key, values[index])
+		if err := newtrie.TryUpdate(key, values[index]); err != nil {
+			return fmt.Errorf("invalid range: %v", err), false
+		}
 	}
 	if newtrie.Hash() != rootHash {
 		return fmt.Errorf("invalid proof, want hash %x, got %x", rootHash, newtrie.Hash()), false
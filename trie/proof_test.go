@@ -744,6 +744,102 @@ func TestEmptyRangeProof(t *testing.T) {
 	}
 }
 
+// TestProveRange tests that the ranges and edge proofs generated by ProveRange
+// are verifiable, including single element and empty ranges at the end of the
+// trie.
+func TestProveRange(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	var entries entrySlice
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	sort.Sort(entries)
+
+	for i := 0; i < 500; i++ {
+		var (
+			pos    = mrand.Intn(len(entries))
+			max    = mrand.Intn(100) + 1
+			origin = common.CopyBytes(entries[pos].k)
+		)
+		if i%2 == 0 {
+			// Start the range from a non-existent key, if there's a gap
+			if prev := decreseKey(common.CopyBytes(origin)); pos == 0 || bytes.Compare(prev, entries[pos-1].k) > 0 {
+				origin = prev
+			}
+		}
+		proof := memorydb.New()
+		keys, values, err := trie.ProveRange(origin, max, proof)
+		if err != nil {
+			t.Fatalf("Case %d: failed to prove range: %v", i, err)
+		}
+		want := len(entries) - pos
+		if want > max {
+			want = max
+		}
+		if len(keys) != want {
+			t.Fatalf("Case %d: range length mismatch: have %d, want %d", i, len(keys), want)
+		}
+		for j := range keys {
+			if !bytes.Equal(keys[j], entries[pos+j].k) || !bytes.Equal(values[j], entries[pos+j].v) {
+				t.Fatalf("Case %d: entry %d mismatch", i, j)
+			}
+		}
+		err, more := VerifyRangeProof(trie.Hash(), origin, keys[len(keys)-1], keys, values, proof)
+		if err != nil {
+			t.Fatalf("Case %d: failed to verify range: %v", i, err)
+		}
+		if have := pos+len(keys) < len(entries); more != have {
+			t.Fatalf("Case %d: continuation flag mismatch: have %v, want %v", i, more, have)
+		}
+	}
+	// Ensure an empty range after the last element is proven correctly
+	proof := memorydb.New()
+	origin := increseKey(common.CopyBytes(entries[len(entries)-1].k))
+	keys, values, err := trie.ProveRange(origin, 10, proof)
+	if err != nil {
+		t.Fatalf("Failed to prove empty range: %v", err)
+	}
+	if len(keys) != 0 || len(values) != 0 {
+		t.Fatalf("Unexpected entries after the last element: %d", len(keys))
+	}
+	if err, _ := VerifyRangeProof(trie.Hash(), origin, nil, keys, values, proof); err != nil {
+		t.Fatalf("Failed to verify empty range: %v", err)
+	}
+}
+
+// TestRangeProofOutOfEdges tests that leaves outside of the proven edge keys are
+// rejected, even if they exist in the trie.
+func TestRangeProofOutOfEdges(t *testing.T) {
+	trie, vals := randomTrie(4096)
+	var entries entrySlice
+	for _, kv := range vals {
+		entries = append(entries, kv)
+	}
+	sort.Sort(entries)
+
+	for i := 0; i < 100; i++ {
+		start := mrand.Intn(len(entries) - 2)
+		end := mrand.Intn(len(entries)-start-2) + start + 2
+
+		proof := memorydb.New()
+		if err := trie.Prove(entries[start].k, 0, proof); err != nil {
+			t.Fatalf("Failed to prove the first node %v", err)
+		}
+		if err := trie.Prove(entries[end-1].k, 0, proof); err != nil {
+			t.Fatalf("Failed to prove the last node %v", err)
+		}
+		var keys [][]byte
+		var vals [][]byte
+		for i := start; i <= end; i++ {
+			keys = append(keys, entries[i].k)
+			vals = append(vals, entries[i].v)
+		}
+		if err, _ := VerifyRangeProof(trie.Hash(), keys[0], entries[end-1].k, keys, vals, proof); err == nil {
+			t.Fatalf("Case %d(%d->%d) expected error, got nil", i, start, end)
+		}
+	}
+}
+
 // mutateByte changes one byte in b.
 func mutateByte(b []byte) {
 	for r := mrand.Intn(len(b)); ; {
@@ -0,0 +1,48 @@
+
+
+package trie
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Recorder collects the RLP encoded trie nodes resolved from the database by the
+// tries it is attached to. The recorded nodes are sufficient to replay the same
+// trie accesses (and modifications) without the database, e.g. to assemble the
+// witness of a block execution.
+//
+// Recorder is safe for concurrent use, so it can be shared by all the tries
+// accessed during an execution, including copies handed to prefetchers.
+type Recorder struct {
+	nodes map[common.Hash][]byte
+	lock  sync.Mutex
+}
+
+// NewRecorder creates an empty trie node recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{nodes: make(map[common.Hash][]byte)}
+}
+
+// record inserts a resolved trie node into the recorded set.
+func (r *Recorder) record(hash common.Hash, blob []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.nodes[hash]; !ok {
+		r.nodes[hash] = common.CopyBytes(blob)
+	}
+}
+
+// Nodes returns the recorded trie nodes, keyed by their hash.
+func (r *Recorder) Nodes() map[common.Hash][]byte {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	nodes := make(map[common.Hash][]byte, len(r.nodes))
+	for hash, blob := range r.nodes {
+		nodes[hash] = blob
+	}
+	return nodes
+}
@@ -35,7 +35,7 @@ var stPool = sync.Pool{
 	},
 }
 
-func stackTrieFromPool(db fafdb.KeyValueStore) *StackTrie {
+func stackTrieFromPool(db fafdb.KeyValueWriter) *StackTrie {
 	st := stPool.Get().(*StackTrie)
 	st.db = db
 	return st
@@ -43,6 +43,7 @@ func stackTrieFromPool(db fafdb.KeyValueStore) *StackTrie {
 
 func returnToPool(st *StackTrie) {
 	st.Reset()
+	st.db = nil
 	stPool.Put(st)
 }
 
@@ -56,18 +57,20 @@ type StackTrie struct {
 	keyOffset int            // offset of the key chunk inside a full key
 	children  [16]*StackTrie // list of children (for fullnodes and exts)
 
-	db fafdb.KeyValueStore // Pointer to the commit db, can be nil
+	db fafdb.KeyValueWriter // Pointer to the commit db, can be nil
 }
 
-// NewStackTrie allocates and initializes an empty trie.
-func NewStackTrie(db fafdb.KeyValueStore) *StackTrie {
+// NewStackTrie allocates and initializes an empty trie. If a database writer is
+// specified, every hashed node is written into it as soon as its subtrie is
+// complete.
+func NewStackTrie(db fafdb.KeyValueWriter) *StackTrie {
 	return &StackTrie{
 		nodeType: emptyNode,
 		db:       db,
 	}
 }
 
-func newLeaf(ko int, key, val []byte, db fafdb.KeyValueStore) *StackTrie {
+func newLeaf(ko int, key, val []byte, db fafdb.KeyValueWriter) *StackTrie {
 	st := stackTrieFromPool(db)
 	st.nodeType = leafNode
 	st.keyOffset = ko
@@ -76,7 +79,7 @@ func newLeaf(ko int, key, val []byte, db fafdb.KeyValueStore) *StackTrie {
 	return st
 }
 
-func newExt(ko int, key []byte, child *StackTrie, db fafdb.KeyValueStore) *StackTrie {
+func newExt(ko int, key []byte, child *StackTrie, db fafdb.KeyValueWriter) *StackTrie {
 	st := stackTrieFromPool(db)
 	st.nodeType = extNode
 	st.keyOffset = ko
@@ -110,8 +113,8 @@ func (st *StackTrie) Update(key, value []byte) {
 	}
 }
 
+// Reset clears the trie, retaining the database writer it commits into.
 func (st *StackTrie) Reset() {
-	st.db = nil
 	st.key = st.key[:0]
 	st.val = nil
 	for i := range st.children {
@@ -347,8 +350,7 @@ func (st *StackTrie) hash() {
 			panic(err)
 		}
 	case emptyNode:
-		st.val = st.val[:0]
-		st.val = append(st.val, emptyRoot[:]...)
+		st.val = emptyRoot.Bytes()
 		st.key = st.key[:0]
 		st.nodeType = hashedNode
 		return
@@ -358,23 +360,18 @@ func (st *StackTrie) hash() {
 	st.key = st.key[:0]
 	st.nodeType = hashedNode
 	if len(h.tmp) < 32 {
-		st.val = st.val[:0]
-		st.val = append(st.val, h.tmp...)
+		st.val = common.CopyBytes(h.tmp)
 		return
 	}
-	// Going to write the hash to the 'val'. Need to ensure it's properly sized first
-	// Typically, 'branchNode's will have no 'val', and require this allocation
-	if required := 32 - len(st.val); required > 0 {
-		buf := make([]byte, required)
-		st.val = append(st.val, buf...)
-	}
-	st.val = st.val[:32]
+	// Write the hash to the 'val'. A new slice is allocated to avoid mutating
+	// the leaf value, which is owned by the caller
+	st.val = make([]byte, 32)
 	h.sha.Reset()
 	h.sha.Write(h.tmp)
 	h.sha.Read(st.val)
 	if st.db != nil {
-		// TODO! Is it safe to Put the slice here?
-		// Do all db implementations copy the value provided?
+		// The hasher buffer is reused after returning, the database writers are
+		// expected to copy the written data (all fafdb implementations do).
 		st.db.Put(st.val, h.tmp)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	mrand "math/rand"
+	"sort"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -289,3 +290,66 @@ func TestUpdateVariableKeys(t *testing.T) {
 		t.Fatalf("error %x != %x", st.Hash(), nt.Hash())
 	}
 }
+
+// Tests that the nodes committed by a stack trie form a complete trie which can
+// be opened and read back, and that resetting the stack trie retains its writer.
+func TestStackTrieCommit(t *testing.T) {
+	var (
+		db  = memorydb.New()
+		st  = NewStackTrie(db)
+		kvs = make(map[string][]byte)
+	)
+	for run := 0; run < 2; run++ {
+		for i := 0; i < 1000; i++ {
+			key := crypto.Keccak256([]byte{byte(run), byte(i), byte(i >> 8)})
+			kvs[string(key)] = []byte(fmt.Sprintf("value-%d-%d", run, i))
+		}
+		keys := make([]string, 0, len(kvs))
+		for key := range kvs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		st.Reset()
+		nt, _ := New(common.Hash{}, NewDatabase(memorydb.New()))
+		for _, key := range keys {
+			st.Update([]byte(key), kvs[key])
+			nt.Update([]byte(key), kvs[key])
+		}
+		root, err := st.Commit()
+		if err != nil {
+			t.Fatalf("run %d: failed to commit stack trie: %v", run, err)
+		}
+		if want := nt.Hash(); root != want {
+			t.Fatalf("run %d: root mismatch: have %x, want %x", run, root, want)
+		}
+		tr, err := New(root, NewDatabase(db))
+		if err != nil {
+			t.Fatalf("run %d: failed to open committed trie: %v", run, err)
+		}
+		for key, want := range kvs {
+			if have, err := tr.TryGet([]byte(key)); err != nil || !bytes.Equal(have, want) {
+				t.Fatalf("run %d: value mismatch for %x: have %x, want %x, err %v", run, key, have, want, err)
+			}
+		}
+	}
+}
+
+// Tests that hashing the stack trie doesn't mutate the values inserted into it,
+// which are owned by the caller.
+func TestStackTrieValueOwnership(t *testing.T) {
+	st := NewStackTrie(nil)
+
+	var vals [][]byte
+	for i := 0; i < 16; i++ {
+		val := bytes.Repeat([]byte{byte(i)}, 33)
+		vals = append(vals, val)
+		st.Update([]byte{byte(i << 4)}, val)
+	}
+	st.Hash()
+	for i, val := range vals {
+		if want := bytes.Repeat([]byte{byte(i)}, 33); !bytes.Equal(val, want) {
+			t.Errorf("value %d mutated: have %x, want %x", i, val, want)
+		}
+	}
+}
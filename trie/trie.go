@@ -39,6 +39,8 @@ type Trie struct {
 	// hashing operation. This number will not directly map to the number of
 	// actually unhashed nodes
 	unhashed int
+
+	recorder *Recorder // Optional recorder of all the nodes resolved from db
 }
 
 // newFlag returns the cache flag value for a newly created node.
@@ -69,6 +71,20 @@ func New(root common.Hash, db *Database) (*Trie, error) {
 	return trie, nil
 }
 
+// SetRecorder attaches a recorder collecting all the nodes resolved from the
+// database from now on. The root node, if already resolved, is recorded too.
+func (t *Trie) SetRecorder(recorder *Recorder) {
+	t.recorder = recorder
+	if t.root == nil {
+		return
+	}
+	if hash, dirty := t.root.cache(); hash != nil && !dirty {
+		if blob, _ := t.db.Node(common.BytesToHash(hash)); len(blob) != 0 {
+			recorder.record(common.BytesToHash(hash), blob)
+		}
+	}
+}
+
 // NodeIterator returns an iterator that returns nodes of the trie. Iteration starts at
 // the key after the given start key.
 func (t *Trie) NodeIterator(start []byte) NodeIterator {
@@ -468,6 +484,16 @@ func (t *Trie) resolve(n node, prefix []byte) (node, error) {
 
 func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
 	hash := common.BytesToHash(n)
+	if t.recorder != nil {
+		// Recording needs the encoded node, decode it instead of using the
+		// cached node object
+		blob, _ := t.db.Node(hash)
+		if len(blob) == 0 {
+			return nil, &MissingNodeError{NodeHash: hash, Path: prefix}
+		}
+		t.recorder.record(hash, blob)
+		return mustDecodeNode(n, blob), nil
+	}
 	if node := t.db.node(hash); node != nil {
 		return node, nil
 	}